@@ -0,0 +1,246 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/eth/tracers/debugger"
+	"github.com/urfave/cli/v2"
+)
+
+var DebuggerFlag = &cli.BoolFlag{
+	Name:     "debugger",
+	Usage:    "Step through the execution in an interactive debugger",
+	Category: traceCategory,
+}
+
+const debuggerHelp = `Commands:
+  s, step                 execute the next opcode
+  n, next                 execute the next opcode, stepping over calls
+  o, out                  run until the current call returns
+  c, continue             run until the next breakpoint
+  b, break <cond>...      add a breakpoint matching all conditions:
+                          pc=<n> op=<name> address=<hex> slot=<hex> depth=<n>
+  d, delete <id>          delete a breakpoint
+  l, list                 list the breakpoints
+  st, stack               print the stack
+  m, memory [off] [size]  print the memory
+  sl, storage <slot> [address]
+                          print a storage slot of the current or given contract
+  q, quit                 detach and run to completion
+  h, help                 print this help`
+
+// runDebugger drives the debugger from the terminal until the execution finishes.
+func runDebugger(d *debugger.Debugger) error {
+	defer d.Close()
+
+	fmt.Println("Type 'help' for the available commands.")
+	ev, err := d.Wait(context.Background())
+	for err == nil {
+		fmt.Println(ev)
+		if ev.Reason == debugger.ReasonFinished {
+			return nil
+		}
+		var resumed bool
+		for !resumed {
+			line, perr := prompt.Stdin.PromptInput("(evm) ")
+			if perr != nil {
+				if errors.Is(perr, io.EOF) {
+					return nil
+				}
+				return perr
+			}
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			prompt.Stdin.AppendHistory(line)
+			if resumed, err = debuggerCommand(d, ev, strings.Fields(line)); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil // quit
+				}
+				fmt.Println("error:", err)
+				resumed, err = false, nil
+			}
+		}
+		ev, err = d.Wait(context.Background())
+	}
+	return err
+}
+
+// debuggerCommand executes a command of the user, reporting whether it resumed the
+// execution. It returns io.EOF if the user quit.
+func debuggerCommand(d *debugger.Debugger, ev *debugger.Event, args []string) (bool, error) {
+	switch args[0] {
+	case "s", "step":
+		return true, d.Step()
+	case "n", "next":
+		return true, d.StepOver()
+	case "o", "out":
+		return true, d.StepOut()
+	case "c", "continue":
+		return true, d.Continue()
+	case "q", "quit":
+		return false, io.EOF
+	case "b", "break":
+		bp, err := parseBreakpoint(args[1:])
+		if err != nil {
+			return false, err
+		}
+		id, err := d.AddBreakpoint(bp)
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("breakpoint %d: %v\n", id, &bp)
+	case "d", "delete":
+		if len(args) != 2 {
+			return false, errors.New("usage: delete <id>")
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return false, err
+		}
+		if !d.RemoveBreakpoint(id) {
+			return false, fmt.Errorf("no breakpoint %d", id)
+		}
+	case "l", "list":
+		bps := d.Breakpoints()
+		ids := make([]int, 0, len(bps))
+		for id := range bps {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			bp := bps[id]
+			fmt.Printf("%d: %v\n", id, &bp)
+		}
+	case "st", "stack":
+		for i := len(ev.Stack) - 1; i >= 0; i-- {
+			fmt.Printf("%4d: %s\n", len(ev.Stack)-1-i, ev.Stack[i].String())
+		}
+	case "m", "memory":
+		offset, size := uint64(0), uint64(ev.MemorySize)
+		var err error
+		if len(args) > 1 {
+			if offset, err = strconv.ParseUint(args[1], 0, 64); err != nil {
+				return false, err
+			}
+			size = 32
+		}
+		if len(args) > 2 {
+			if size, err = strconv.ParseUint(args[2], 0, 64); err != nil {
+				return false, err
+			}
+		}
+		mem, err := d.Memory(offset, size)
+		if err != nil {
+			return false, err
+		}
+		fmt.Print(hex.Dump(mem))
+	case "sl", "storage":
+		if len(args) < 2 || len(args) > 3 {
+			return false, errors.New("usage: storage <slot> [address]")
+		}
+		addr := ev.Address
+		if len(args) == 3 {
+			if !common.IsHexAddress(args[2]) {
+				return false, fmt.Errorf("invalid address %q", args[2])
+			}
+			addr = common.HexToAddress(args[2])
+		}
+		value, err := d.Storage(addr, common.HexToHash(args[1]))
+		if err != nil {
+			return false, err
+		}
+		fmt.Println(value.Hex())
+	case "h", "help":
+		fmt.Println(debuggerHelp)
+	default:
+		return false, fmt.Errorf("unknown command %q, type 'help' for the available commands", args[0])
+	}
+	return false, nil
+}
+
+// parseBreakpoint parses the conditions of a breakpoint given as key=value pairs.
+func parseBreakpoint(conds []string) (debugger.Breakpoint, error) {
+	var bp debugger.Breakpoint
+	for _, cond := range conds {
+		key, value, ok := strings.Cut(cond, "=")
+		if !ok {
+			return bp, fmt.Errorf("invalid condition %q, want key=value", cond)
+		}
+		switch key {
+		case "pc":
+			pc, err := strconv.ParseUint(value, 0, 64)
+			if err != nil {
+				return bp, fmt.Errorf("invalid pc: %v", err)
+			}
+			bp.PC = &pc
+		case "op":
+			bp.Op = strings.ToUpper(value)
+		case "address", "addr":
+			if !common.IsHexAddress(value) {
+				return bp, fmt.Errorf("invalid address %q", value)
+			}
+			addr := common.HexToAddress(value)
+			bp.Address = &addr
+		case "slot":
+			slot := common.HexToHash(value)
+			bp.Slot = &slot
+		case "depth":
+			depth, err := strconv.Atoi(value)
+			if err != nil {
+				return bp, fmt.Errorf("invalid depth: %v", err)
+			}
+			bp.Depth = &depth
+		default:
+			return bp, fmt.Errorf("unknown condition %q", key)
+		}
+	}
+	return bp, nil
+}
+
+// debugExec wraps the execution to run in the background, while the debugger is
+// driven from the terminal.
+func debugExec(d *debugger.Debugger, exec func() ([]byte, uint64, error)) func() ([]byte, uint64, error) {
+	return func() ([]byte, uint64, error) {
+		var (
+			output  []byte
+			gasUsed uint64
+			err     error
+			done    = make(chan struct{})
+		)
+		go func() {
+			defer close(done)
+			output, gasUsed, err = exec()
+		}()
+		if derr := runDebugger(d); derr != nil {
+			fmt.Println("debugger failed:", derr)
+		}
+		<-done
+		return output, gasUsed, err
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	hexFlag = &cli.StringFlag{
+		Name:     "hex",
+		Usage:    "Single container data to parse and validate",
+		Category: flags.VMCategory,
+	}
+	eofTestFlag = &cli.StringFlag{
+		Name:     "test",
+		Usage:    "Path to an EOF validation test file",
+		Category: flags.VMCategory,
+	}
+	initcodeFlag = &cli.BoolFlag{
+		Name:     "initcode",
+		Usage:    "Validate the containers as initcode instead of runtime code",
+		Category: flags.VMCategory,
+	}
+	eofDumpFlag = &cli.BoolFlag{
+		Name:     "dump",
+		Usage:    "Print the sections of valid containers",
+		Category: flags.VMCategory,
+	}
+)
+
+var eofParseCommand = &cli.Command{
+	Action: eofParseCmd,
+	Name:   "eofparse",
+	Usage:  "Parses and validates EOF containers",
+	Description: `The eofparse command parses and validates hex encoded EOF containers, given
+with --hex or line by line on standard input, and prints OK or the validation
+error for each of them. With --test, the vectors of an EOF validation test file
+from the execution-spec fixtures are checked instead.`,
+	Flags: []cli.Flag{
+		hexFlag,
+		eofTestFlag,
+		initcodeFlag,
+		eofDumpFlag,
+	},
+}
+
+func eofParseCmd(ctx *cli.Context) error {
+	if ctx.IsSet(eofTestFlag.Name) {
+		return runEOFTest(ctx.String(eofTestFlag.Name))
+	}
+	if ctx.IsSet(hexFlag.Name) {
+		c, err := parseEOF(ctx.String(hexFlag.Name), ctx.Bool(initcodeFlag.Name))
+		if err != nil {
+			return fmt.Errorf("err: %w", err)
+		}
+		printEOF(ctx, c)
+		return nil
+	}
+	// Otherwise, read containers from stdin and validate them one by one.
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c, err := parseEOF(line, ctx.Bool(initcodeFlag.Name))
+		if err != nil {
+			fmt.Printf("err: %v\n", err)
+			continue
+		}
+		printEOF(ctx, c)
+	}
+	return scanner.Err()
+}
+
+// parseEOF decodes and validates a hex encoded container.
+func parseEOF(input string, isInitCode bool) (*vm.Container, error) {
+	code, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return vm.ValidateEOF(code, isInitCode)
+}
+
+func printEOF(ctx *cli.Context, c *vm.Container) {
+	fmt.Println("OK")
+	if ctx.Bool(eofDumpFlag.Name) {
+		fmt.Print(c)
+	}
+}
+
+// runEOFTest checks the vectors of all tests in an EOF validation test file.
+func runEOFTest(fname string) error {
+	src, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	var testsByName map[string]*tests.EOFTest
+	if err := json.Unmarshal(src, &testsByName); err != nil {
+		return fmt.Errorf("unable to read test file %s: %w", fname, err)
+	}
+	var failed int
+	for name, test := range testsByName {
+		if err := test.Run(); err != nil {
+			fmt.Printf("%s: %v\n", name, err)
+			failed++
+		}
+	}
+	fmt.Printf("%d tests passed, %d failed\n", len(testsByName)-failed, failed)
+	if failed > 0 {
+		return errors.New("eof tests failed")
+	}
+	return nil
+}
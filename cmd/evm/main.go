@@ -263,6 +263,8 @@ func init() {
 		transactionCommand,
 		blockBuilderCommand,
 		verkleCommand,
+		replayCommand,
+		eofParseCommand,
 	}
 	app.Before = func(ctx *cli.Context) error {
 		flags.MigrateGlobalFlags(ctx)
@@ -0,0 +1,666 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/internal/version"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	witnessesFlag = &cli.StringFlag{
+		Name:     "witnesses",
+		Usage:    "JSON file with the blocks to replay and their execution witnesses",
+		Category: flags.VMCategory,
+	}
+	eraFlag = &cli.StringFlag{
+		Name:     "era",
+		Usage:    "Directory with the era1 files containing the blocks to replay",
+		Category: flags.VMCategory,
+	}
+	chainFlag = &cli.StringFlag{
+		Name:     "chain",
+		Usage:    "Network of the replayed blocks (mainnet, sepolia, holesky or hoodi)",
+		Value:    "mainnet",
+		Category: flags.VMCategory,
+	}
+	fromFlag = &cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "Number of the first block to replay",
+		Category: flags.VMCategory,
+	}
+	toFlag = &cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "Number of the last block to replay",
+		Category: flags.VMCategory,
+	}
+	runsFlag = &cli.IntFlag{
+		Name:     "runs",
+		Usage:    "Number of times the blocks are replayed",
+		Value:    3,
+		Category: flags.VMCategory,
+	}
+	opcodesFlag = &cli.BoolFlag{
+		Name:     "opcodes",
+		Usage:    "Profile the time spent per opcode in an additional run",
+		Category: flags.VMCategory,
+	}
+	blockAnalysisFlag = &cli.BoolFlag{
+		Name:     "blockanalysis",
+		Usage:    "Execute contract code in pre-analyzed basic blocks with superinstructions",
+		Category: flags.VMCategory,
+	}
+	outputFlag = &cli.StringFlag{
+		Name:     "output",
+		Usage:    "File to write the report to as JSON",
+		Category: flags.VMCategory,
+	}
+	rpcFlag = &cli.StringFlag{
+		Name:     "rpc",
+		Usage:    "RPC endpoint of the node to fetch the blocks from",
+		Value:    "http://localhost:8545",
+		Category: flags.VMCategory,
+	}
+)
+
+var replayCommand = &cli.Command{
+	Action: replayCmd,
+	Name:   "replay",
+	Usage:  "Benchmarks the execution of real blocks",
+	Description: `The replay command executes a range of blocks through the state processor and
+reports the execution time, gas/s and allocations. The prestate is taken either
+from the execution witnesses of the blocks (--witnesses) or from a genesis file
+with the state before the first block of a range read from era1 files (--era and
+--prestate). All inputs are local files, so replays are reproducible offline and
+comparable across revisions.`,
+	Flags: []cli.Flag{
+		witnessesFlag,
+		eraFlag,
+		GenesisFlag,
+		chainFlag,
+		fromFlag,
+		toFlag,
+		runsFlag,
+		opcodesFlag,
+		blockAnalysisFlag,
+		outputFlag,
+	},
+	Subcommands: []*cli.Command{
+		{
+			Action:    replayFetchCmd,
+			Name:      "fetch",
+			Usage:     "Downloads blocks and their execution witnesses from a node",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				rpcFlag,
+				fromFlag,
+				toFlag,
+			},
+		},
+	},
+}
+
+// replayFixture is a block along with the execution witness of its prestate, as
+// stored in a witnesses file.
+type replayFixture struct {
+	Block   hexutil.Bytes         `json:"block"`
+	Witness *stateless.ExtWitness `json:"witness"`
+}
+
+// replayBlock is a block ready to be replayed.
+type replayBlock struct {
+	block *types.Block
+	db    state.Database // Database with the prestate, nil if continuing the previous block
+	root  common.Hash    // Root of the prestate in db
+}
+
+// replayChain is a chain context serving a fixed set of headers.
+type replayChain struct {
+	config  *params.ChainConfig
+	engine  consensus.Engine
+	headers map[common.Hash]*types.Header
+	numbers map[uint64]*types.Header
+	head    *types.Header
+}
+
+func newReplayChain(config *params.ChainConfig) *replayChain {
+	return &replayChain{
+		config:  config,
+		engine:  beacon.New(ethash.NewFaker()),
+		headers: make(map[common.Hash]*types.Header),
+		numbers: make(map[uint64]*types.Header),
+	}
+}
+
+func (c *replayChain) add(header *types.Header) {
+	c.headers[header.Hash()] = header
+	c.numbers[header.Number.Uint64()] = header
+	if c.head == nil || header.Number.Cmp(c.head.Number) > 0 {
+		c.head = header
+	}
+}
+
+// Config implements consensus.ChainHeaderReader.
+func (c *replayChain) Config() *params.ChainConfig {
+	return c.config
+}
+
+// CurrentHeader implements consensus.ChainHeaderReader.
+func (c *replayChain) CurrentHeader() *types.Header {
+	return c.head
+}
+
+// GetHeader implements consensus.ChainHeaderReader.
+func (c *replayChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.headers[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+// GetHeaderByNumber implements consensus.ChainHeaderReader.
+func (c *replayChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.numbers[number]
+}
+
+// GetHeaderByHash implements consensus.ChainHeaderReader.
+func (c *replayChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.headers[hash]
+}
+
+// Engine implements core.ChainContext.
+func (c *replayChain) Engine() consensus.Engine {
+	return c.engine
+}
+
+// replayer executes a range of blocks.
+type replayer struct {
+	chain  *replayChain
+	blocks []*replayBlock
+
+	db   state.Database // Database with the prestate of sequentially replayed blocks
+	root common.Hash    // Root of the prestate of sequentially replayed blocks
+}
+
+// newWitnessReplayer creates a replayer executing the blocks of a witnesses file,
+// each on top of the state in its witness.
+func newWitnessReplayer(path string, config *params.ChainConfig, from, to uint64) (*replayer, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*replayFixture
+	if err := json.Unmarshal(blob, &fixtures); err != nil {
+		return nil, fmt.Errorf("invalid witnesses file: %v", err)
+	}
+	r := &replayer{chain: newReplayChain(config)}
+	for i, fixture := range fixtures {
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(fixture.Block, block); err != nil {
+			return nil, fmt.Errorf("invalid block %d: %v", i, err)
+		}
+		if number := block.NumberU64(); number < from || (to != 0 && number > to) {
+			continue
+		}
+		if fixture.Witness == nil {
+			return nil, fmt.Errorf("block %d: missing witness", block.NumberU64())
+		}
+		witness, err := fixture.Witness.ToWitness()
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %v", block.NumberU64(), err)
+		}
+		if witness.Headers[0].Hash() != block.ParentHash() {
+			return nil, fmt.Errorf("block %d: witness is not based on the parent", block.NumberU64())
+		}
+		for _, header := range witness.Headers {
+			r.chain.add(header)
+		}
+		r.chain.add(block.Header())
+		r.blocks = append(r.blocks, &replayBlock{
+			block: block,
+			db:    state.NewDatabase(triedb.NewDatabase(witness.MakeHashDB(), triedb.HashDefaults), nil),
+			root:  witness.Root(),
+		})
+	}
+	if len(r.blocks) == 0 {
+		return nil, errors.New("no blocks to replay")
+	}
+	return r, nil
+}
+
+// newEraReplayer creates a replayer executing the blocks from..to of the era1 files
+// in a directory sequentially, on top of the state given by the genesis alloc.
+func newEraReplayer(dir, network string, genesis *core.Genesis, from, to uint64) (*replayer, error) {
+	if from == 0 {
+		return nil, errors.New("the genesis block can't be replayed")
+	}
+	if to < from {
+		return nil, fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	entries, err := era.ReadDir(dir, network)
+	if err != nil {
+		return nil, err
+	}
+	// Read the blocks of the range, as well as the headers accessible via BLOCKHASH.
+	var (
+		r     = &replayer{chain: newReplayChain(genesis.Config)}
+		first = from - min(from, 256)
+	)
+	for epoch := first / uint64(era.MaxEra1Size); epoch <= to/uint64(era.MaxEra1Size); epoch++ {
+		if epoch >= uint64(len(entries)) {
+			return nil, fmt.Errorf("missing era1 file of epoch %d", epoch)
+		}
+		e, err := era.Open(filepath.Join(dir, entries[epoch]))
+		if err != nil {
+			return nil, err
+		}
+		start := max(first, e.Start())
+		end := min(to, e.Start()+e.Count()-1)
+		for number := start; number <= end; number++ {
+			block, err := e.GetBlockByNumber(number)
+			if err != nil {
+				e.Close()
+				return nil, fmt.Errorf("failed to read block %d: %v", number, err)
+			}
+			r.chain.add(block.Header())
+			if number >= from {
+				r.blocks = append(r.blocks, &replayBlock{block: block})
+			}
+		}
+		e.Close()
+	}
+	// Load the prestate and make sure it belongs to the parent of the first block.
+	prestate := tests.MakePreState(rawdb.NewMemoryDatabase(), genesis.Alloc, false, rawdb.HashScheme)
+	r.db = prestate.StateDB.Database()
+	r.root = prestate.StateDB.IntermediateRoot(false)
+	if parent := r.chain.GetHeaderByNumber(from - 1); parent == nil {
+		return nil, fmt.Errorf("missing header %d", from-1)
+	} else if parent.Root != r.root {
+		return nil, fmt.Errorf("prestate root %x doesn't match the root %x of block %d", r.root, parent.Root, from-1)
+	}
+	return r, nil
+}
+
+// replayStats contains the totals of the replayed blocks.
+type replayStats struct {
+	First  uint64 `json:"first"`
+	Last   uint64 `json:"last"`
+	Blocks int    `json:"blocks"`
+	Txs    int    `json:"txs"`
+	Gas    uint64 `json:"gas"`
+}
+
+func (r *replayer) stats() replayStats {
+	stats := replayStats{
+		First:  r.blocks[0].block.NumberU64(),
+		Last:   r.blocks[len(r.blocks)-1].block.NumberU64(),
+		Blocks: len(r.blocks),
+	}
+	for _, b := range r.blocks {
+		stats.Txs += len(b.block.Transactions())
+		stats.Gas += b.block.GasUsed()
+	}
+	return stats
+}
+
+// run replays all blocks, validating the results. Only the processing of the
+// blocks is measured, not the validation and committing of the state.
+func (r *replayer) run(vmConfig vm.Config) (*execStats, error) {
+	var (
+		config    = r.chain.config
+		processor = core.NewStateProcessor(r.chain)
+		validator = core.NewBlockValidator(config, nil)
+		stats     = new(execStats)
+		statedb   *state.StateDB
+		err       error
+	)
+	for _, b := range r.blocks {
+		number := b.block.NumberU64()
+		switch {
+		case b.db != nil:
+			statedb, err = state.New(b.root, b.db)
+		case statedb == nil:
+			statedb, err = state.New(r.root, r.db)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("block %d: failed to open prestate: %v", number, err)
+		}
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		res, err := processor.Process(b.block, statedb, vmConfig)
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %v", number, err)
+		}
+		stats.Time += elapsed
+		stats.Allocs += int64(after.Mallocs - before.Mallocs)
+		stats.BytesAllocated += int64(after.TotalAlloc - before.TotalAlloc)
+		stats.GasUsed += res.GasUsed
+
+		if err := validator.ValidateState(b.block, statedb, res, false); err != nil {
+			return nil, fmt.Errorf("block %d: %v", number, err)
+		}
+		// Continue the next block on top of the post state, unless it has its own.
+		if b.db == nil {
+			root, err := statedb.Commit(number, config.IsEIP158(b.block.Number()), config.IsCancun(b.block.Number(), b.block.Time()))
+			if err != nil {
+				return nil, fmt.Errorf("block %d: failed to commit state: %v", number, err)
+			}
+			if statedb, err = state.New(root, r.db); err != nil {
+				return nil, fmt.Errorf("block %d: failed to open post state: %v", number, err)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// opcodeStats is the time spent executing an opcode.
+type opcodeStats struct {
+	Op    string        `json:"op"`
+	Count uint64        `json:"count"`
+	Time  time.Duration `json:"time"`
+}
+
+// opcodeProfiler measures the time spent per opcode. The time of an opcode is
+// the time until the next opcode starts or the call frame is left, which includes
+// the overhead of the tracing hooks.
+type opcodeProfiler struct {
+	counts  [256]uint64
+	times   [256]time.Duration
+	pending bool
+	last    byte
+	start   time.Time
+}
+
+func (p *opcodeProfiler) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnOpcode: p.onOpcode,
+		OnExit:   p.onExit,
+	}
+}
+
+func (p *opcodeProfiler) onOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	now := time.Now()
+	p.flush(now)
+	p.counts[op]++
+	p.pending, p.last, p.start = true, op, now
+}
+
+func (p *opcodeProfiler) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	p.flush(time.Now())
+}
+
+func (p *opcodeProfiler) flush(now time.Time) {
+	if p.pending {
+		p.times[p.last] += now.Sub(p.start)
+		p.pending = false
+	}
+}
+
+// result returns the opcodes executed, most time consuming first.
+func (p *opcodeProfiler) result() []*opcodeStats {
+	var ops []*opcodeStats
+	for op, count := range p.counts {
+		if count > 0 {
+			ops = append(ops, &opcodeStats{Op: vm.OpCode(op).String(), Count: count, Time: p.times[op]})
+		}
+	}
+	slices.SortFunc(ops, func(a, b *opcodeStats) int {
+		return int(b.Time - a.Time)
+	})
+	return ops
+}
+
+// replayReport is the result of a replay.
+type replayReport struct {
+	Version   string         `json:"version"`
+	VCS       string         `json:"vcs,omitempty"`
+	GoVersion string         `json:"goVersion"`
+	Platform  string         `json:"platform"`
+	Blocks    replayStats    `json:"blocks"`
+	VMConfig  string         `json:"vmConfig,omitempty"`
+	Runs      []*execStats   `json:"runs"`
+	Opcodes   []*opcodeStats `json:"opcodes,omitempty"`
+}
+
+// median returns the run with the median execution time.
+func (r *replayReport) median() *execStats {
+	runs := slices.Clone(r.Runs)
+	slices.SortFunc(runs, func(a, b *execStats) int {
+		return int(a.Time - b.Time)
+	})
+	return runs[len(runs)/2]
+}
+
+func mgasPerSec(stats *execStats) float64 {
+	return float64(stats.GasUsed) / stats.Time.Seconds() / 1e6
+}
+
+// print writes the report in human readable form.
+func (r *replayReport) print(w io.Writer) {
+	fmt.Fprintf(w, "Replayed blocks %d..%d (%d blocks, %d txs, %d gas)\n", r.Blocks.First, r.Blocks.Last, r.Blocks.Blocks, r.Blocks.Txs, r.Blocks.Gas)
+	fmt.Fprintf(w, "%s %s, %s %s\n", r.Version, r.VCS, r.GoVersion, r.Platform)
+	if r.VMConfig != "" {
+		fmt.Fprintf(w, "VM: %s\n", r.VMConfig)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "run\ttime\tMgas/s\tallocs\tbytes\t")
+	for i, run := range r.Runs {
+		fmt.Fprintf(tw, "%d\t%v\t%.2f\t%d\t%d\t\n", i+1, run.Time, mgasPerSec(run), run.Allocs, run.BytesAllocated)
+	}
+	median := r.median()
+	fmt.Fprintf(tw, "median\t%v\t%.2f\t%d\t%d\t\n", median.Time, mgasPerSec(median), median.Allocs, median.BytesAllocated)
+	tw.Flush()
+
+	if len(r.Opcodes) > 0 {
+		var total time.Duration
+		for _, op := range r.Opcodes {
+			total += op.Time
+		}
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "opcode\tcount\ttime\tns/op\tshare\t")
+		for _, op := range r.Opcodes {
+			fmt.Fprintf(tw, "%s\t%d\t%v\t%.1f\t%.2f%%\t\n", op.Op, op.Count, op.Time, float64(op.Time)/float64(op.Count), 100*float64(op.Time)/float64(total))
+		}
+		tw.Flush()
+	}
+}
+
+func replayCmd(ctx *cli.Context) error {
+	genesis, err := replayGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		r        *replayer
+		from, to = ctx.Uint64(fromFlag.Name), ctx.Uint64(toFlag.Name)
+	)
+	switch {
+	case ctx.IsSet(witnessesFlag.Name) && ctx.IsSet(eraFlag.Name):
+		return errors.New("--witnesses and --era are mutually exclusive")
+	case ctx.IsSet(witnessesFlag.Name):
+		r, err = newWitnessReplayer(ctx.String(witnessesFlag.Name), genesis.Config, from, to)
+	case ctx.IsSet(eraFlag.Name):
+		if !ctx.IsSet(fromFlag.Name) {
+			from = 1
+		}
+		if !ctx.IsSet(toFlag.Name) {
+			to = from
+		}
+		r, err = newEraReplayer(ctx.String(eraFlag.Name), ctx.String(chainFlag.Name), genesis, from, to)
+	default:
+		return errors.New("either --witnesses or --era is required")
+	}
+	if err != nil {
+		return err
+	}
+	runs := ctx.Int(runsFlag.Name)
+	if runs < 1 {
+		return errors.New("at least one run is required")
+	}
+	report := &replayReport{
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Blocks:    r.stats(),
+	}
+	report.Version, report.VCS = version.Info()
+
+	var vmConfig vm.Config
+	if ctx.Bool(blockAnalysisFlag.Name) {
+		vmConfig.EnableBlockAnalysis = true
+		report.VMConfig = "block analysis"
+	}
+	for i := 0; i < runs; i++ {
+		runtime.GC()
+		stats, err := r.run(vmConfig)
+		if err != nil {
+			return err
+		}
+		log.Info("Replayed blocks", "run", i+1, "time", stats.Time, "mgas/s", fmt.Sprintf("%.2f", mgasPerSec(stats)))
+		report.Runs = append(report.Runs, stats)
+	}
+	if ctx.Bool(opcodesFlag.Name) {
+		profiler := new(opcodeProfiler)
+		if _, err := r.run(vm.Config{Tracer: profiler.hooks()}); err != nil {
+			return err
+		}
+		report.Opcodes = profiler.result()
+	}
+	report.print(os.Stdout)
+
+	if path := ctx.String(outputFlag.Name); path != "" {
+		blob, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, blob, 0644)
+	}
+	return nil
+}
+
+// replayGenesis returns the genesis given via --prestate, or the genesis of the
+// network given via --chain.
+func replayGenesis(ctx *cli.Context) (*core.Genesis, error) {
+	var genesis *core.Genesis
+	switch network := ctx.String(chainFlag.Name); network {
+	case "mainnet":
+		genesis = core.DefaultGenesisBlock()
+	case "sepolia":
+		genesis = core.DefaultSepoliaGenesisBlock()
+	case "holesky":
+		genesis = core.DefaultHoleskyGenesisBlock()
+	case "hoodi":
+		genesis = core.DefaultHoodiGenesisBlock()
+	default:
+		return nil, fmt.Errorf("unknown network %q", network)
+	}
+	if ctx.IsSet(GenesisFlag.Name) {
+		config := genesis.Config
+		genesis = readGenesis(ctx.String(GenesisFlag.Name))
+		if genesis.Config == nil {
+			genesis.Config = config
+		}
+	}
+	return genesis, nil
+}
+
+func replayFetchCmd(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("output file required")
+	}
+	from := ctx.Uint64(fromFlag.Name)
+	to := from
+	if ctx.IsSet(toFlag.Name) {
+		to = ctx.Uint64(toFlag.Name)
+	}
+	if from == 0 || to < from {
+		return fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	client, err := rpc.DialContext(ctx.Context, ctx.String(rpcFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var fixtures []*replayFixture
+	for number := from; number <= to; number++ {
+		fixture, err := fetchReplayFixture(ctx.Context, client, number)
+		if err != nil {
+			return fmt.Errorf("block %d: %v", number, err)
+		}
+		fixtures = append(fixtures, fixture)
+		log.Info("Fetched block", "number", number, "witness", len(fixture.Witness.State))
+	}
+	blob, err := json.Marshal(fixtures)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, blob, 0644)
+}
+
+// fetchReplayFixture downloads a block and its execution witness.
+func fetchReplayFixture(ctx context.Context, client *rpc.Client, number uint64) (*replayFixture, error) {
+	var block hexutil.Bytes
+	if err := client.CallContext(ctx, &block, "debug_getRawBlock", hexutil.Uint64(number)); err != nil {
+		return nil, err
+	}
+	witness, err := gethclient.New(client).ExecutionWitness(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+	return &replayFixture{Block: block, Witness: witness}, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// makeReplayChain generates a chain of blocks calling a contract which stores the
+// calldata and hashes the block number of the parent.
+func makeReplayChain(t *testing.T, n int) (*core.Genesis, []*types.Block, []types.Receipts) {
+	t.Helper()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		contract = common.HexToAddress("0xc0de")
+		signer   = types.LatestSigner(params.AllEthashProtocolChanges)
+	)
+	code := program.New().
+		Push(0).Op(vm.CALLDATALOAD).Op(vm.NUMBER).Op(vm.SSTORE).
+		Push(1).Op(vm.NUMBER).Op(vm.SUB).Op(vm.BLOCKHASH).Push(0).Op(vm.MSTORE).
+		Push(32).Push(0).Op(vm.KECCAK256).Op(vm.NUMBER).Op(vm.SSTORE).
+		Bytes()
+	genesis := &core.Genesis{
+		Config: params.AllEthashProtocolChanges,
+		Alloc: types.GenesisAlloc{
+			sender:   {Balance: big.NewInt(params.Ether)},
+			contract: {Code: code},
+		},
+	}
+	_, blocks, receipts := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), n, func(i int, b *core.BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &contract,
+			Gas:      100000,
+			GasPrice: b.BaseFee(),
+			Data:     common.LeftPadBytes([]byte{byte(i + 1)}, 32),
+		})
+		b.AddTx(tx)
+	})
+	return genesis, blocks, receipts
+}
+
+func TestReplayEra(t *testing.T) {
+	genesis, blocks, receipts := makeReplayChain(t, 8)
+
+	// Write the chain into an era1 file.
+	dir := t.TempDir()
+	f, err := os.CreateTemp(dir, "era1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		builder = era.NewBuilder(f)
+		gblock  = genesis.ToBlock()
+		td      = new(big.Int).Set(gblock.Difficulty())
+	)
+	if err := builder.Add(gblock, nil, td); err != nil {
+		t.Fatal(err)
+	}
+	for i, block := range blocks {
+		td.Add(td, block.Difficulty())
+		if err := builder.Add(block, receipts[i], td); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := os.Rename(f.Name(), filepath.Join(dir, era.Filename("test", 0, root))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay a range in the middle of the chain on top of its prestate.
+	r, err := newEraReplayer(dir, "test", genesis, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := r.stats(); stats.First != 1 || stats.Last != 8 || stats.Txs != 8 {
+		t.Fatalf("wrong replay stats: %+v", stats)
+	}
+	for i, config := range []vm.Config{{}, {}, {EnableBlockAnalysis: true}} {
+		stats, err := r.run(config)
+		if err != nil {
+			t.Fatalf("run %d failed: %v", i, err)
+		}
+		if stats.GasUsed != r.stats().Gas || stats.Time == 0 {
+			t.Fatalf("wrong run stats: %+v", stats)
+		}
+	}
+	profiler := new(opcodeProfiler)
+	if _, err := r.run(vm.Config{Tracer: profiler.hooks()}); err != nil {
+		t.Fatal(err)
+	}
+	ops := make(map[string]*opcodeStats)
+	for _, op := range profiler.result() {
+		ops[op.Op] = op
+	}
+	if ops["SSTORE"] == nil || ops["SSTORE"].Count != 16 || ops["BLOCKHASH"].Count != 8 {
+		t.Fatalf("wrong opcode profile: %v", ops)
+	}
+
+	// A prestate which doesn't belong to the parent block is rejected.
+	if _, err := newEraReplayer(dir, "test", genesis, 2, 8); err == nil {
+		t.Fatal("replay with wrong prestate succeeded")
+	}
+}
+
+func TestReplayWitnesses(t *testing.T) {
+	genesis, blocks, _ := makeReplayChain(t, 4)
+
+	// Import the chain, collecting the witnesses of the blocks.
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), genesis, ethash.NewFaker(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	var fixtures []*replayFixture
+	for _, block := range blocks {
+		witness, err := chain.InsertBlockWithoutSetHead(block, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.SetCanonical(block); err != nil {
+			t.Fatal(err)
+		}
+		enc, _ := rlp.EncodeToBytes(block)
+		fixtures = append(fixtures, &replayFixture{Block: enc, Witness: witness.ToExtWitness()})
+	}
+	blob, err := json.Marshal(fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "witnesses.json")
+	if err := os.WriteFile(path, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newWitnessReplayer(path, genesis.Config, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := r.stats(); stats.First != 2 || stats.Last != 4 {
+		t.Fatalf("wrong replay stats: %+v", stats)
+	}
+	if _, err := r.run(vm.Config{}); err != nil {
+		t.Fatal(err)
+	}
+}
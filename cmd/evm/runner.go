@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -39,6 +40,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/debugger"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -66,6 +69,8 @@ var runCommand = &cli.Command{
 		ValueFlag,
 		StatDumpFlag,
 		DumpFlag,
+		DebuggerFlag,
+		GasProfileFlag,
 	}, traceFlags),
 }
 
@@ -123,6 +128,11 @@ var (
 		Value:    new(big.Int),
 		Category: flags.VMCategory,
 	}
+	GasProfileFlag = &cli.StringFlag{
+		Name:     "trace.gasprofile",
+		Usage:    "Write the gas profile of the execution in folded stack format (flamegraph input) to the given file",
+		Category: traceCategory,
+	}
 )
 
 // readGenesis will read the given JSON format genesis file and return
@@ -208,6 +218,14 @@ func runCmd(ctx *cli.Context) error {
 		blobBaseFee = new(big.Int) // TODO (MariusVanDerWijden) implement blob fee in state tests
 	)
 	tracer = tracerFromFlags(ctx)
+	var dbg *debugger.Debugger
+	if ctx.Bool(DebuggerFlag.Name) {
+		if tracer != nil || ctx.Bool(BenchFlag.Name) {
+			return errors.New("--debugger can't be combined with tracing or benchmarking")
+		}
+		dbg = debugger.New()
+		tracer = dbg.Hooks()
+	}
 	initialGas := ctx.Uint64(GasFlag.Name)
 	genesisConfig := new(core.Genesis)
 	genesisConfig.GasLimit = initialGas
@@ -294,6 +312,19 @@ func runCmd(ctx *cli.Context) error {
 		runtimeConfig.ChainConfig = params.AllEthashProtocolChanges
 	}
 
+	var profiler *tracers.Tracer
+	if ctx.IsSet(GasProfileFlag.Name) {
+		if tracer != nil || ctx.Bool(BenchFlag.Name) {
+			return errors.New("--trace.gasprofile can't be combined with tracing, debugging or benchmarking")
+		}
+		var err error
+		profiler, err = tracers.DefaultDirectory.New("gasProfilerTracer", new(tracers.Context), json.RawMessage(`{"format":"folded"}`), runtimeConfig.ChainConfig)
+		if err != nil {
+			return err
+		}
+		runtimeConfig.EVMConfig.Tracer = profiler.Hooks
+	}
+
 	var hexInput []byte
 	if inputFileFlag := ctx.String(InputFileFlag.Name); inputFileFlag != "" {
 		var err error
@@ -333,6 +364,10 @@ func runCmd(ctx *cli.Context) error {
 	}
 
 	bench := ctx.Bool(BenchFlag.Name)
+	if dbg != nil {
+		// Run the execution in the background, driven by the debugger.
+		execFunc = debugExec(dbg, execFunc)
+	}
 	output, stats, err := timedExec(bench, execFunc)
 
 	if ctx.Bool(DumpFlag.Name) {
@@ -356,6 +391,12 @@ func runCmd(ctx *cli.Context) error {
 		}
 	}
 
+	if profiler != nil {
+		if err := writeGasProfile(ctx.String(GasProfileFlag.Name), profiler); err != nil {
+			return err
+		}
+	}
+
 	if bench || ctx.Bool(StatDumpFlag.Name) {
 		fmt.Fprintf(os.Stderr, `EVM gas used:    %d
 execution time:  %v
@@ -363,7 +404,7 @@ allocations:     %d
 allocated bytes: %d
 `, stats.GasUsed, stats.Time, stats.Allocs, stats.BytesAllocated)
 	}
-	if tracer == nil {
+	if tracer == nil || dbg != nil {
 		fmt.Printf("%#x\n", output)
 		if err != nil {
 			fmt.Printf(" error: %v\n", err)
@@ -373,6 +414,20 @@ allocated bytes: %d
 	return nil
 }
 
+// writeGasProfile writes the folded stacks collected by the gas profiler to
+// the given file.
+func writeGasProfile(path string, profiler *tracers.Tracer) error {
+	res, err := profiler.GetResult()
+	if err != nil {
+		return err
+	}
+	var folded string
+	if err := json.Unmarshal(res, &folded); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(folded), 0644)
+}
+
 // writeLogs writes vm logs in a readable format to the given writer
 func writeLogs(writer io.Writer, logs []*types.Log) {
 	for _, log := range logs {
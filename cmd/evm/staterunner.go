@@ -19,6 +19,7 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -27,6 +28,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/tests"
 	"github.com/urfave/cli/v2"
@@ -44,6 +47,11 @@ var (
 		Category: flags.VMCategory,
 		Value:    -1, // default to select all subtest indices
 	}
+	coverageFlag = &cli.StringFlag{
+		Name:     "trace.coverage",
+		Usage:    "Write the execution coverage of the tests to the given file, merged with the coverage already in it",
+		Category: traceCategory,
+	}
 )
 var stateTestCommand = &cli.Command{
 	Action:    stateTestCmd,
@@ -57,12 +65,26 @@ var stateTestCommand = &cli.Command{
 		HumanReadableFlag,
 		idxFlag,
 		RunFlag,
+		coverageFlag,
 	}, traceFlags),
 }
 
 func stateTestCmd(ctx *cli.Context) error {
-	path := ctx.Args().First()
-
+	var (
+		path     = ctx.Args().First()
+		cfg      = vm.Config{Tracer: tracerFromFlags(ctx)}
+		coverage *tracers.Tracer
+	)
+	if ctx.IsSet(coverageFlag.Name) {
+		if cfg.Tracer != nil || ctx.Bool(BenchFlag.Name) {
+			return errors.New("--trace.coverage can't be combined with tracing or benchmarking")
+		}
+		var err error
+		if coverage, err = tracers.DefaultDirectory.New("coverageTracer", new(tracers.Context), nil, nil); err != nil {
+			return err
+		}
+		cfg.Tracer = coverage.Hooks
+	}
 	// If path is provided, run the tests at that path.
 	if len(path) != 0 {
 		var (
@@ -70,33 +92,63 @@ func stateTestCmd(ctx *cli.Context) error {
 			results   []testResult
 		)
 		for _, fname := range collected {
-			r, err := runStateTest(ctx, fname)
+			r, err := runStateTest(ctx, fname, cfg)
 			if err != nil {
 				return err
 			}
 			results = append(results, r...)
 		}
 		report(ctx, results)
-		return nil
-	}
-	// Otherwise, read filenames from stdin and execute back-to-back.
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		fname := scanner.Text()
-		if len(fname) == 0 {
-			return nil
-		}
-		results, err := runStateTest(ctx, fname)
-		if err != nil {
-			return err
+	} else {
+		// Otherwise, read filenames from stdin and execute back-to-back.
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			fname := scanner.Text()
+			if len(fname) == 0 {
+				break
+			}
+			results, err := runStateTest(ctx, fname, cfg)
+			if err != nil {
+				return err
+			}
+			report(ctx, results)
 		}
-		report(ctx, results)
+	}
+	if coverage != nil {
+		return writeCoverage(ctx.String(coverageFlag.Name), coverage)
 	}
 	return nil
 }
 
+// writeCoverage merges the coverage collected by the tracer into the given
+// file, so that the coverage of multiple runs can be accumulated.
+func writeCoverage(path string, tracer *tracers.Tracer) error {
+	res, err := tracer.GetResult()
+	if err != nil {
+		return err
+	}
+	var coverage native.Coverage
+	if err := json.Unmarshal(res, &coverage); err != nil {
+		return err
+	}
+	if src, err := os.ReadFile(path); err == nil {
+		var existing native.Coverage
+		if err := json.Unmarshal(src, &existing); err != nil {
+			return fmt.Errorf("invalid coverage file %s: %w", path, err)
+		}
+		coverage.Merge(existing)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	out, err := json.Marshal(coverage)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
 // runStateTest loads the state-test given by fname, and executes the test.
-func runStateTest(ctx *cli.Context, fname string) ([]testResult, error) {
+func runStateTest(ctx *cli.Context, fname string, cfg vm.Config) ([]testResult, error) {
 	src, err := os.ReadFile(fname)
 	if err != nil {
 		return nil, err
@@ -106,7 +158,6 @@ func runStateTest(ctx *cli.Context, fname string) ([]testResult, error) {
 		return nil, fmt.Errorf("unable to read test file %s: %w", fname, err)
 	}
 
-	cfg := vm.Config{Tracer: tracerFromFlags(ctx)}
 	re, err := regexp.Compile(ctx.String(RunFlag.Name))
 	if err != nil {
 		return nil, fmt.Errorf("invalid regex -%s: %v", RunFlag.Name, err)
@@ -29,6 +29,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/cmd/evm/internal/t8ntool"
+	"github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/internal/cmdtest"
 	"github.com/ethereum/go-ethereum/internal/reexec"
 )
@@ -734,6 +735,43 @@ func TestEvmRunRegEx(t *testing.T) {
 	}
 }
 
+// TestStateTestCoverage checks that the coverage of multiple statetest runs
+// is accumulated in the coverage file.
+func TestStateTestCoverage(t *testing.T) {
+	t.Parallel()
+	var (
+		tt   = cmdtest.NewTestCmd(t, nil)
+		path = filepath.Join(t.TempDir(), "coverage.json")
+		runs []native.Coverage
+	)
+	for i := 0; i < 2; i++ {
+		tt.Run("evm-test", "statetest", "--trace.coverage", path, "./testdata/statetest.json")
+		tt.WaitExit()
+		if tt.ExitStatus() != 0 {
+			t.Fatalf("run %d: unexpected exit status %d: %v", i, tt.ExitStatus(), tt.StderrText())
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("run %d: failed to read coverage: %v", i, err)
+		}
+		var coverage native.Coverage
+		if err := json.Unmarshal(src, &coverage); err != nil {
+			t.Fatalf("run %d: invalid coverage: %v", i, err)
+		}
+		if len(coverage) == 0 {
+			t.Fatalf("run %d: no coverage collected", i)
+		}
+		runs = append(runs, coverage)
+	}
+	for hash, code := range runs[0] {
+		for pc, n := range code.PCs {
+			if have := runs[1][hash].PCs[pc]; have != 2*n {
+				t.Fatalf("code %x pc %d: have count %d, want %d", hash, pc, have, 2*n)
+			}
+		}
+	}
+}
+
 // cmpJson compares the JSON in two byte slices.
 func cmpJson(a, b []byte) (bool, error) {
 	var j, j2 interface{}
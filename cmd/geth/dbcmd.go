@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -32,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -53,6 +55,19 @@ var (
 		Name:  "remove.chain",
 		Usage: "If set, selects the state data for removal",
 	}
+	inspectCachedFlag = &cli.BoolFlag{
+		Name:  "cached",
+		Usage: "Display the statistics maintained in the background instead of iterating the database",
+	}
+	verifyRepairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Restore corrupted items from the era files where possible",
+	}
+	freezerCodecFlag = &cli.StringFlag{
+		Name:  "codec",
+		Usage: "Compression codec to re-encode the freezer table with (snappy, zstd)",
+		Value: "zstd",
+	}
 
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
@@ -77,20 +92,24 @@ Remove blockchain and state databases`,
 			dbPutCmd,
 			dbGetSlotsCmd,
 			dbDumpFreezerIndex,
+			dbMigrateFreezerCmd,
 			dbImportCmd,
 			dbExportCmd,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
+			dbVerifyAncientsCmd,
 			dbInspectHistoryCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
-		Action:      inspect,
-		Name:        "inspect",
-		ArgsUsage:   "<prefix> <start>",
-		Flags:       slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
-		Usage:       "Inspect the storage size for each type of data in the database",
-		Description: `This commands iterates the entire database. If the optional 'prefix' and 'start' arguments are provided, then the iteration is limited to the given subset of data.`,
+		Action:    inspect,
+		Name:      "inspect",
+		ArgsUsage: "<prefix> <start>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags, []cli.Flag{inspectCachedFlag}),
+		Usage:     "Inspect the storage size for each type of data in the database",
+		Description: `This commands iterates the entire database. If the optional 'prefix' and 'start' arguments are provided, then the iteration is limited to the given subset of data.
+If --cached is specified, the statistics maintained in the background by a node running with --db.stats are
+displayed instead, without iterating the database.`,
 	}
 	dbCheckStateContentCmd = &cli.Command{
 		Action:    checkStateContent,
@@ -101,6 +120,17 @@ Remove blockchain and state databases`,
 		Description: `This command iterates the entire database for 32-byte keys, looking for rlp-encoded trie nodes.
 For each trie node encountered, it checks that the key corresponds to the keccak256(value). If this is not true, this indicates
 a data corruption.`,
+	}
+	dbVerifyAncientsCmd = &cli.Command{
+		Action:    verifyAncients,
+		Name:      "verify-ancients",
+		ArgsUsage: "<start (optional)> <end (optional)>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags, []cli.Flag{verifyRepairFlag}),
+		Usage:     "Verify the integrity of the ancient chain data",
+		Description: `This command re-reads the headers, bodies and receipts in the ancient store, checking them
+against the canonical hashes and the transaction, uncle, withdrawal and receipt roots of the headers.
+Corrupted ranges are reported. If --repair is specified, corrupted bodies and receipts are restored from
+the era files, and corrupted hashes are recomputed from the headers.`,
 	}
 	dbStatCmd = &cli.Command{
 		Action: dbStats,
@@ -162,6 +192,16 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		Flags:       slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command displays information about the freezer index.",
 	}
+	dbMigrateFreezerCmd = &cli.Command{
+		Action:    freezerMigrate,
+		Name:      "freezer-migrate",
+		Usage:     "Re-encode a compressed freezer table with a different codec",
+		ArgsUsage: "<freezer-type> <table-type>",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags, []cli.Flag{freezerCodecFlag}),
+		Description: `This command re-encodes all items of a compressed freezer table with the codec given
+by --codec. For zstd, a dictionary is trained on items sampled across the table and stored
+in the table metadata. The node must not be running while the table is migrated.`,
+	}
 	dbImportCmd = &cli.Command{
 		Action:      importLDBdata,
 		Name:        "import",
@@ -305,6 +345,18 @@ func inspect(ctx *cli.Context) error {
 		prefix []byte
 		start  []byte
 	)
+	if ctx.Bool(inspectCachedFlag.Name) {
+		if ctx.NArg() > 0 {
+			return fmt.Errorf("--%s does not support the 'prefix' and 'start' arguments", inspectCachedFlag.Name)
+		}
+		stack, _ := makeConfigNode(ctx)
+		defer stack.Close()
+
+		db := utils.MakeChainDatabase(ctx, stack, true)
+		defer db.Close()
+
+		return rawdb.PrintDatabaseStats(db)
+	}
 	if ctx.NArg() > 2 {
 		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
 	}
@@ -385,6 +437,83 @@ func checkStateContent(ctx *cli.Context) error {
 	return nil
 }
 
+func verifyAncients(ctx *cli.Context) error {
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	repair := ctx.Bool(verifyRepairFlag.Name)
+	db := utils.MakeChainDatabase(ctx, stack, !repair)
+	defer db.Close()
+
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	start, end := uint64(0), frozen
+	if ctx.NArg() > 0 {
+		if start, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid start: %v", err)
+		}
+	}
+	if ctx.NArg() > 1 {
+		if end, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			return fmt.Errorf("invalid end: %v", err)
+		}
+		end = min(end+1, frozen)
+	}
+	var (
+		verifier  = core.NewAncientVerifier(db, core.NewEraAncientSource(db))
+		faults    []core.AncientFault
+		startTime = time.Now()
+		lastLog   = time.Now()
+	)
+	for number := start; number < end; number++ {
+		found, err := verifier.VerifyBlock(context.Background(), number, repair)
+		if err != nil {
+			return err
+		}
+		for _, fault := range found {
+			log.Error("Corrupted ancient item", "number", fault.Number, "kind", fault.Kind, "repaired", fault.Repaired, "err", fault.Err)
+		}
+		faults = append(faults, found...)
+
+		if time.Since(lastLog) > 8*time.Second {
+			log.Info("Verifying ancient store", "number", number, "end", end, "corrupted", len(faults), "elapsed", common.PrettyDuration(time.Since(startTime)))
+			lastLog = time.Now()
+		}
+	}
+	log.Info("Verified ancient store", "blocks", end-start, "corrupted", len(faults), "elapsed", common.PrettyDuration(time.Since(startTime)))
+
+	// Summarize the corrupted items as consecutive ranges per table
+	slices.SortStableFunc(faults, func(a, b core.AncientFault) int {
+		return strings.Compare(a.Kind, b.Kind)
+	})
+	var unrepaired int
+	for i := 0; i < len(faults); {
+		var (
+			first    = faults[i]
+			last     = first.Number
+			repaired int
+		)
+		for ; i < len(faults) && faults[i].Kind == first.Kind && faults[i].Number <= last+1; i++ {
+			last = faults[i].Number
+			if faults[i].Repaired != "" {
+				repaired++
+			} else {
+				unrepaired++
+			}
+		}
+		fmt.Printf("Corrupted %s: #%d-#%d (%d repaired)\n", first.Kind, first.Number, last, repaired)
+	}
+	if unrepaired > 0 {
+		return fmt.Errorf("%d corrupted ancient items left", unrepaired)
+	}
+	return nil
+}
+
 func showDBStats(db ethdb.KeyValueStater) {
 	stats, err := db.Stat()
 	if err != nil {
@@ -604,6 +733,16 @@ func freezerInspect(ctx *cli.Context) error {
 	return rawdb.InspectFreezerTable(ancient, freezer, table, start, end)
 }
 
+func freezerMigrate(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	ancient := stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
+	stack.Close()
+	return rawdb.MigrateFreezerTable(ancient, ctx.Args().Get(0), ctx.Args().Get(1), ctx.String(freezerCodecFlag.Name))
+}
+
 func importLDBdata(ctx *cli.Context) error {
 	start := 0
 	switch ctx.NArg() {
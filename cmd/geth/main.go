@@ -90,6 +90,7 @@ var (
 		utils.TxLookupLimitFlag, // deprecated
 		utils.TransactionHistoryFlag,
 		utils.ChainHistoryFlag,
+		utils.AncientScrubFlag,
 		utils.LogHistoryFlag,
 		utils.LogNoHistoryFlag,
 		utils.LogExportCheckpointsFlag,
@@ -141,6 +142,7 @@ var (
 		utils.VMTraceJsonConfigFlag,
 		utils.VMWitnessStatsFlag,
 		utils.VMStatelessSelfValidationFlag,
+		utils.VMBlockAnalysisFlag,
 		utils.NetworkIdFlag,
 		utils.EthStatsURLFlag,
 		utils.GpoBlocksFlag,
@@ -171,18 +173,30 @@ var (
 		utils.AuthPortFlag,
 		utils.AuthVirtualHostsFlag,
 		utils.JWTSecretFlag,
+		utils.AuthTLSCertFlag,
+		utils.AuthTLSKeyFlag,
+		utils.AuthTLSClientCAFlag,
 		utils.HTTPVirtualHostsFlag,
 		utils.GraphQLEnabledFlag,
 		utils.GraphQLCORSDomainFlag,
 		utils.GraphQLVirtualHostsFlag,
 		utils.HTTPApiFlag,
 		utils.HTTPPathPrefixFlag,
+		utils.HTTPTLSCertFlag,
+		utils.HTTPTLSKeyFlag,
+		utils.HTTPTLSClientCAFlag,
+		utils.HTTPBatchStreamingFlag,
+		utils.HTTPBatchStreamingMaxSizeFlag,
+		utils.HTTPH2CFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
 		utils.WSPathPrefixFlag,
+		utils.WSCompressionFlag,
+		utils.WSSubscriptionBufferFlag,
+		utils.WSSubscriptionOverflowFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
@@ -193,6 +207,7 @@ var (
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.RPCResultCacheFlag,
 		utils.RPCTxSyncDefaultTimeoutFlag,
 		utils.RPCTxSyncMaxTimeoutFlag,
 		utils.RPCGlobalRangeLimitFlag,
@@ -245,6 +260,7 @@ func init() {
 		// See misccmd.go:
 		versionCommand,
 		licenseCommand,
+		openRPCCommand,
 		// See config.go
 		dumpConfigCommand,
 		// see dbcmd.go
@@ -17,12 +17,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/internal/version"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/urfave/cli/v2"
 )
 
@@ -42,6 +46,18 @@ The output of this command is supposed to be machine-readable.
 		Usage:     "Display license information",
 		ArgsUsage: " ",
 	}
+	openRPCCommand = &cli.Command{
+		Action:    dumpOpenRPC,
+		Name:      "openrpc",
+		Usage:     "Export the OpenRPC description of the RPC APIs",
+		ArgsUsage: "<dumpfile (optional)>",
+		Flags:     slices.Concat(nodeFlags, rpcFlags),
+		Description: `
+Export the OpenRPC document describing all RPC methods provided by a node configured
+with the given flags, including the authenticated ones (to stdout by default).
+The document is also served by running nodes via the rpc_discover method.
+`,
+	}
 )
 
 func printVersion(ctx *cli.Context) error {
@@ -63,6 +79,42 @@ func printVersion(ctx *cli.Context) error {
 	return nil
 }
 
+func dumpOpenRPC(ctx *cli.Context) error {
+	// The API descriptions don't depend on the chain data, so run the node on
+	// memory databases instead of opening (and locking) the configured datadir.
+	if err := ctx.Set(utils.DataDirFlag.Name, ""); err != nil {
+		return err
+	}
+	stack := makeFullNode(ctx)
+	defer stack.Close()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	for _, api := range stack.APIs() {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var doc rpc.OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		return err
+	}
+	doc.Info.Version = version.WithMeta
+
+	out, err := json.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if ctx.NArg() > 0 {
+		return os.WriteFile(ctx.Args().Get(0), append(out, '\n'), 0644)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
 func license(_ *cli.Context) error {
 	fmt.Println(`Geth is free software: you can redistribute it and/or modify
 it under the terms of the GNU General Public License as published by
@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"slices"
 	"time"
 
@@ -29,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/flatstate"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -39,6 +42,23 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+var (
+	flatShardsFlag = &cli.IntFlag{
+		Name:  "shards",
+		Usage: "Number of shards to split the account hash range into",
+		Value: 16,
+	}
+	flatParallelFlag = &cli.IntFlag{
+		Name:  "parallel",
+		Usage: "Number of shards exported concurrently",
+		Value: runtime.NumCPU(),
+	}
+	flatPreimagesFlag = &cli.BoolFlag{
+		Name:  "preimages",
+		Usage: "Include the available hash preimages in the export",
+	}
+)
+
 var (
 	snapshotCommand = &cli.Command{
 		Name:        "snapshot",
@@ -159,6 +179,41 @@ block is used.
 				Description: `
 The export-preimages command exports hash preimages to a flat file, in exactly
 the expected order for the overlay tree migration.
+`,
+			},
+			{
+				Action:    snapshotExportFlat,
+				Name:      "export-flat",
+				Usage:     "Export the flat state into sharded binary files",
+				ArgsUsage: "<outdir> [<root>]",
+				Flags: slices.Concat([]cli.Flag{
+					flatShardsFlag,
+					flatParallelFlag,
+					flatPreimagesFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot export-flat <outdir> [<state-root>]
+will export all accounts, storage slots and contract codes of the given state
+into length-prefixed binary files, one for each shard of the account hash range.
+The shards are exported concurrently and the progress of every shard is tracked
+in the output directory, so an interrupted export can be resumed by running the
+same command again. The default export target is the HEAD state.
+
+The file format is documented in the core/state/flatstate package.
+`,
+			},
+			{
+				Action:    snapshotImportFlat,
+				Name:      "import-flat",
+				Usage:     "Import the flat state exported by export-flat",
+				ArgsUsage: "<dir>",
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot import-flat <dir>
+will rebuild the state tries and the flat state from the shard files in the
+given directory, verifying the exported state root. The target database must
+not contain any state yet. Only the state is imported, the chain itself needs
+to be imported separately.
 `,
 			},
 		},
@@ -659,6 +714,79 @@ func snapshotExportPreimages(ctx *cli.Context) error {
 	return utils.ExportSnapshotPreimages(chaindb, stateIt, ctx.Args().First(), root)
 }
 
+// snapshotExportFlat exports the flat state into sharded binary files.
+func snapshotExportFlat(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("this command requires one or two arguments")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, stack, chaindb, false, true, false)
+	defer triedb.Close()
+
+	var (
+		root common.Hash
+		err  error
+	)
+	if ctx.NArg() > 1 {
+		root, err = parseRoot(ctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+	} else {
+		headBlock := rawdb.ReadHeadBlock(chaindb)
+		if headBlock == nil {
+			log.Error("Failed to load head block")
+			return errors.New("no head block")
+		}
+		root = headBlock.Root()
+	}
+	stateIt, err := utils.NewStateIterator(triedb, chaindb, root)
+	if err != nil {
+		return err
+	}
+	return flatstate.Export(chaindb, stateIt, flatstate.ExportConfig{
+		Root:      root,
+		Dir:       ctx.Args().First(),
+		Shards:    ctx.Int(flatShardsFlag.Name),
+		Parallel:  ctx.Int(flatParallelFlag.Name),
+		Preimages: ctx.Bool(flatPreimagesFlag.Name),
+	})
+}
+
+// snapshotImportFlat rebuilds the state from the files of a flat state export.
+func snapshotImportFlat(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("this command requires an argument")
+	}
+	files, err := filepath.Glob(filepath.Join(ctx.Args().First(), "shard-*.flat"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no shard files found in %s", ctx.Args().First())
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	if scheme := rawdb.ReadStateScheme(chaindb); scheme != "" {
+		return fmt.Errorf("database already contains %s-based state", scheme)
+	}
+	scheme, err := rawdb.ParseStateScheme(ctx.String(utils.StateSchemeFlag.Name), chaindb)
+	if err != nil {
+		return err
+	}
+	_, err = flatstate.Import(chaindb, scheme, files)
+	return err
+}
+
 // checkAccount iterates the snap data layers, and looks up the given account
 // across all layers.
 func checkAccount(ctx *cli.Context) error {
@@ -104,6 +104,11 @@ var (
 		Value:    node.DefaultConfig.DBEngine,
 		Category: flags.EthCategory,
 	}
+	DBStatsFlag = &cli.BoolFlag{
+		Name:     "db.stats",
+		Usage:    "Maintain per-category database statistics in the background (debug_dbStats, metrics)",
+		Category: flags.EthCategory,
+	}
 	AncientFlag = &flags.DirectoryFlag{
 		Name:     "datadir.ancient",
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
@@ -325,6 +330,11 @@ var (
 		Value:    ethconfig.Defaults.LogHistory,
 		Category: flags.StateCategory,
 	}
+	AncientScrubFlag = &cli.BoolFlag{
+		Name:     "history.scrub",
+		Usage:    "Periodically verify the ancient chain data in the background, restoring corrupted items from era files or peers",
+		Category: flags.StateCategory,
+	}
 	LogNoHistoryFlag = &cli.BoolFlag{
 		Name:     "history.logs.disable",
 		Usage:    "Do not maintain log search index",
@@ -611,6 +621,11 @@ var (
 		Usage:    "Generate execution witnesses and self-check against them (testing purpose)",
 		Category: flags.VMCategory,
 	}
+	VMBlockAnalysisFlag = &cli.BoolFlag{
+		Name:     "vm.blockanalysis",
+		Usage:    "Execute contract code in pre-analyzed basic blocks with superinstructions (experimental)",
+		Category: flags.VMCategory,
+	}
 	// API options.
 	RPCGlobalGasCapFlag = &cli.Uint64Flag{
 		Name:     "rpc.gascap",
@@ -678,6 +693,21 @@ var (
 		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints",
 		Category: flags.APICategory,
 	}
+	AuthTLSCertFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.cert",
+		Usage:    "Path to a PEM encoded TLS certificate for the authenticated RPC endpoints",
+		Category: flags.APICategory,
+	}
+	AuthTLSKeyFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.key",
+		Usage:    "Path to the PEM encoded private key of the authenticated RPC TLS certificate",
+		Category: flags.APICategory,
+	}
+	AuthTLSClientCAFlag = &flags.DirectoryFlag{
+		Name:     "authrpc.tls.clientca",
+		Usage:    "Path to a PEM encoded CA bundle, requiring authenticated RPC clients to present a certificate signed by it",
+		Category: flags.APICategory,
+	}
 
 	// Logging and debug settings
 	EthStatsURLFlag = &cli.StringFlag{
@@ -757,6 +787,36 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	HTTPTLSCertFlag = &flags.DirectoryFlag{
+		Name:     "http.tls.cert",
+		Usage:    "Path to a PEM encoded TLS certificate for the HTTP and WebSocket RPC servers",
+		Category: flags.APICategory,
+	}
+	HTTPTLSKeyFlag = &flags.DirectoryFlag{
+		Name:     "http.tls.key",
+		Usage:    "Path to the PEM encoded private key of the HTTP and WebSocket RPC TLS certificate",
+		Category: flags.APICategory,
+	}
+	HTTPTLSClientCAFlag = &flags.DirectoryFlag{
+		Name:     "http.tls.clientca",
+		Usage:    "Path to a PEM encoded CA bundle, requiring HTTP and WebSocket RPC clients to present a certificate signed by it",
+		Category: flags.APICategory,
+	}
+	HTTPBatchStreamingFlag = &cli.BoolFlag{
+		Name:     "http.streambatch",
+		Usage:    "Stream HTTP-RPC batch responses as newline-delimited JSON to clients accepting it",
+		Category: flags.APICategory,
+	}
+	HTTPBatchStreamingMaxSizeFlag = &cli.IntFlag{
+		Name:     "http.streambatch.maxsize",
+		Usage:    "Maximum number of bytes returned from a streamed HTTP-RPC batch (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	HTTPH2CFlag = &cli.BoolFlag{
+		Name:     "http.h2c",
+		Usage:    "Accept HTTP/2 clients with prior knowledge on the plain text HTTP-RPC server",
+		Category: flags.APICategory,
+	}
 	GraphQLEnabledFlag = &cli.BoolFlag{
 		Name:     "graphql",
 		Usage:    "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
@@ -809,6 +869,22 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	WSCompressionFlag = &cli.BoolFlag{
+		Name:     "ws.compression",
+		Usage:    "Enable permessage-deflate compression of WS-RPC messages",
+		Category: flags.APICategory,
+	}
+	WSSubscriptionBufferFlag = &cli.IntFlag{
+		Name:     "ws.subbuffer",
+		Usage:    "Number of notifications queued per WS-RPC subscription (0 = write synchronously)",
+		Category: flags.APICategory,
+	}
+	WSSubscriptionOverflowFlag = &cli.StringFlag{
+		Name:     "ws.suboverflow",
+		Usage:    "Policy for WS-RPC subscribers falling behind the queue ('disconnect' or 'drop-oldest')",
+		Value:    string(rpc.OverflowDisconnect),
+		Category: flags.APICategory,
+	}
 	ExecFlag = &cli.StringFlag{
 		Name:     "exec",
 		Usage:    "Execute JavaScript statement",
@@ -836,6 +912,11 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCResultCacheFlag = &cli.Uint64Flag{
+		Name:     "rpc.resultcache",
+		Usage:    "Megabytes of memory allocated to caching RPC results of finalized blocks (0 = disabled)",
+		Category: flags.APICategory,
+	}
 
 	// Network Settings
 	MaxPeersFlag = &cli.IntFlag{
@@ -1061,6 +1142,7 @@ var (
 		EraFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
+		DBStatsFlag,
 		StateSchemeFlag,
 		HttpHeaderFlag,
 	}
@@ -1269,6 +1351,33 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(HTTPPathPrefixFlag.Name) {
 		cfg.HTTPPathPrefix = ctx.String(HTTPPathPrefixFlag.Name)
 	}
+	if ctx.IsSet(HTTPTLSCertFlag.Name) {
+		cfg.HTTPTLSCert = ctx.String(HTTPTLSCertFlag.Name)
+	}
+	if ctx.IsSet(HTTPTLSKeyFlag.Name) {
+		cfg.HTTPTLSKey = ctx.String(HTTPTLSKeyFlag.Name)
+	}
+	if ctx.IsSet(HTTPTLSClientCAFlag.Name) {
+		cfg.HTTPTLSClientCA = ctx.String(HTTPTLSClientCAFlag.Name)
+	}
+	if ctx.IsSet(HTTPBatchStreamingFlag.Name) {
+		cfg.HTTPBatchStreaming = ctx.Bool(HTTPBatchStreamingFlag.Name)
+	}
+	if ctx.IsSet(HTTPBatchStreamingMaxSizeFlag.Name) {
+		cfg.HTTPBatchStreamingMaxSize = ctx.Int(HTTPBatchStreamingMaxSizeFlag.Name)
+	}
+	if ctx.IsSet(HTTPH2CFlag.Name) {
+		cfg.HTTPH2C = ctx.Bool(HTTPH2CFlag.Name)
+	}
+	if ctx.IsSet(AuthTLSCertFlag.Name) {
+		cfg.AuthTLSCert = ctx.String(AuthTLSCertFlag.Name)
+	}
+	if ctx.IsSet(AuthTLSKeyFlag.Name) {
+		cfg.AuthTLSKey = ctx.String(AuthTLSKeyFlag.Name)
+	}
+	if ctx.IsSet(AuthTLSClientCAFlag.Name) {
+		cfg.AuthTLSClientCA = ctx.String(AuthTLSClientCAFlag.Name)
+	}
 	if ctx.IsSet(AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.Bool(AllowUnprotectedTxs.Name)
 	}
@@ -1280,6 +1389,10 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(RPCResultCacheFlag.Name) {
+		cfg.RPCResultCacheSize = ctx.Uint64(RPCResultCacheFlag.Name) * 1024 * 1024
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1319,6 +1432,15 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(WSPathPrefixFlag.Name) {
 		cfg.WSPathPrefix = ctx.String(WSPathPrefixFlag.Name)
 	}
+	if ctx.IsSet(WSCompressionFlag.Name) {
+		cfg.WSCompression = ctx.Bool(WSCompressionFlag.Name)
+	}
+	if ctx.IsSet(WSSubscriptionBufferFlag.Name) {
+		cfg.WSSubscriptionBuffer = ctx.Int(WSSubscriptionBufferFlag.Name)
+	}
+	if ctx.IsSet(WSSubscriptionOverflowFlag.Name) {
+		cfg.WSSubscriptionOverflow = ctx.String(WSSubscriptionOverflowFlag.Name)
+	}
 }
 
 // setIPC creates an IPC path configuration from the set command line flags,
@@ -1697,6 +1819,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(EraFlag.Name) {
 		cfg.DatabaseEra = ctx.String(EraFlag.Name)
 	}
+	if ctx.IsSet(DBStatsFlag.Name) {
+		cfg.DatabaseStats = ctx.Bool(DBStatsFlag.Name)
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1747,6 +1872,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(LogHistoryFlag.Name) {
 		cfg.LogHistory = ctx.Uint64(LogHistoryFlag.Name)
 	}
+	if ctx.IsSet(AncientScrubFlag.Name) {
+		cfg.AncientScrub = ctx.Bool(AncientScrubFlag.Name)
+	}
 	if ctx.IsSet(LogNoHistoryFlag.Name) {
 		cfg.LogNoHistory = ctx.Bool(LogNoHistoryFlag.Name)
 	}
@@ -1804,6 +1932,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(VMStatelessSelfValidationFlag.Name) {
 		cfg.StatelessSelfValidation = ctx.Bool(VMStatelessSelfValidationFlag.Name)
 	}
+	if ctx.IsSet(VMBlockAnalysisFlag.Name) {
+		cfg.EnableBlockAnalysis = ctx.Bool(VMBlockAnalysisFlag.Name)
+	}
 	// Auto-enable StatelessSelfValidation when witness stats are enabled
 	if ctx.Bool(VMWitnessStatsFlag.Name) {
 		cfg.StatelessSelfValidation = true
@@ -2382,6 +2513,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		EnablePreimageRecording: ctx.Bool(VMEnableDebugFlag.Name),
 		EnableWitnessStats:      ctx.Bool(VMWitnessStatsFlag.Name),
 		StatelessSelfValidation: ctx.Bool(VMStatelessSelfValidationFlag.Name) || ctx.Bool(VMWitnessStatsFlag.Name),
+		EnableBlockAnalysis:     ctx.Bool(VMBlockAnalysisFlag.Name),
 	}
 	if ctx.IsSet(VMTraceFlag.Name) {
 		if name := ctx.String(VMTraceFlag.Name); name != "" {
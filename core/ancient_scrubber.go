@@ -0,0 +1,152 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// ancientScrubBatch is the number of blocks verified by the scrubber
+	// between two pauses.
+	ancientScrubBatch = 256
+
+	// ancientScrubThrottle is the pause between two batches, limiting the load
+	// the scrubber puts on the disk.
+	ancientScrubThrottle = 100 * time.Millisecond
+
+	// ancientScrubRecheck is the interval after which the ancient store is
+	// scrubbed again once it has been fully verified.
+	ancientScrubRecheck = 24 * time.Hour
+)
+
+var (
+	ancientScrubVerifiedMeter  = metrics.NewRegisteredMeter("chain/scrub/verified", nil)
+	ancientScrubCorruptedMeter = metrics.NewRegisteredMeter("chain/scrub/corrupted", nil)
+	ancientScrubRepairedMeter  = metrics.NewRegisteredMeter("chain/scrub/repaired", nil)
+	ancientScrubProgressGauge  = metrics.NewRegisteredGauge("chain/scrub/progress", nil)
+)
+
+// ancientScrubber is the module responsible for periodically re-reading the
+// ancient store in the background, detecting and restoring items which got
+// corrupted on disk since they were frozen.
+type ancientScrubber struct {
+	db       ethdb.Database
+	verifier *AncientVerifier
+	ctx      context.Context    // cancelled on shutdown, aborting item retrievals
+	cancel   context.CancelFunc // cancels ctx
+	term     chan chan struct{}
+}
+
+// newAncientScrubber initializes the scrubber and starts it in the background.
+func newAncientScrubber(db ethdb.Database, sources ...AncientSource) *ancientScrubber {
+	ctx, cancel := context.WithCancel(context.Background())
+	scrubber := &ancientScrubber{
+		db:       db,
+		verifier: NewAncientVerifier(db, sources...),
+		ctx:      ctx,
+		cancel:   cancel,
+		term:     make(chan chan struct{}),
+	}
+	go scrubber.loop()
+
+	log.Info("Initialized ancient scrubber", "next", rawdb.ReadAncientScrubProgress(db))
+	return scrubber
+}
+
+// loop verifies the ancient store in small batches, persisting the progress
+// so that a restart continues where the scrubber stopped.
+func (s *ancientScrubber) loop() {
+	var (
+		next   = rawdb.ReadAncientScrubProgress(s.db)
+		start  = time.Now()
+		timer  = time.NewTimer(0)
+		faults int
+	)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			frozen, err := s.db.Ancients()
+			if err != nil {
+				log.Error("Failed to retrieve ancient items", "err", err)
+				timer.Reset(ancientScrubRecheck)
+				continue
+			}
+			if next >= frozen {
+				if next > 0 {
+					log.Info("Scrubbed ancient store", "blocks", frozen, "corrupted", faults, "elapsed", common.PrettyDuration(time.Since(start)))
+				}
+				// Start over after the recheck interval
+				next, faults = 0, 0
+				rawdb.WriteAncientScrubProgress(s.db, next)
+				timer.Reset(ancientScrubRecheck)
+				continue
+			}
+			if next == 0 {
+				start = time.Now()
+			}
+			limit := min(next+ancientScrubBatch, frozen)
+			for ; next < limit && s.ctx.Err() == nil; next++ {
+				found, err := s.verifier.VerifyBlock(s.ctx, next, true)
+				if err != nil {
+					if !errors.Is(err, context.Canceled) {
+						log.Error("Failed to scrub ancient block", "number", next, "err", err)
+					}
+					break
+				}
+				for _, fault := range found {
+					ancientScrubCorruptedMeter.Mark(1)
+					if fault.Repaired != "" {
+						ancientScrubRepairedMeter.Mark(1)
+						log.Warn("Repaired corrupted ancient item", "number", fault.Number, "kind", fault.Kind, "source", fault.Repaired, "err", fault.Err)
+					} else {
+						log.Error("Detected corrupted ancient item", "number", fault.Number, "kind", fault.Kind, "err", fault.Err)
+					}
+				}
+				faults += len(found)
+				ancientScrubVerifiedMeter.Mark(1)
+			}
+			rawdb.WriteAncientScrubProgress(s.db, next)
+			ancientScrubProgressGauge.Update(int64(next))
+			timer.Reset(ancientScrubThrottle)
+
+		case ch := <-s.term:
+			close(ch)
+			return
+		}
+	}
+}
+
+// close terminates the scrubber and waits until the background thread exits.
+// Running verifications are aborted, so the progress up to the interrupted
+// block is retained.
+func (s *ancientScrubber) close() {
+	s.cancel()
+	ch := make(chan struct{})
+	s.term <- ch
+	<-ch
+}
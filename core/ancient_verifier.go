@@ -0,0 +1,366 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// AncientSource provides copies of chain data for restoring corrupted items of
+// the ancient store.
+type AncientSource interface {
+	// Name returns a short identifier of the source for logging.
+	Name() string
+
+	// FetchAncient retrieves the item of the given freezer table belonging to
+	// the block with the given number and hash, in the encoding used by the
+	// ancient store. Nil is returned if the item is not available.
+	FetchAncient(ctx context.Context, kind string, number uint64, hash common.Hash) ([]byte, error)
+}
+
+// AncientFault describes a corrupted item found in the ancient store.
+type AncientFault struct {
+	Number   uint64 // Number of the block the item belongs to
+	Kind     string // Freezer table of the corrupted item
+	Err      error  // Reason why the item is considered corrupted
+	Repaired string // Name of the source the item was restored from, empty if not repaired
+}
+
+// AncientVerifier validates the chain segment in the ancient store, checking
+// every block against its canonical hash and the roots committed to by the
+// header. Corrupted items can optionally be restored from a set of sources,
+// as long as the restored content verifies and has the size of the original.
+type AncientVerifier struct {
+	db      ethdb.Database
+	sources []AncientSource
+	lock    sync.RWMutex
+}
+
+// NewAncientVerifier creates a verifier for the ancient store of the database,
+// repairing corrupted items from the given sources.
+func NewAncientVerifier(db ethdb.Database, sources ...AncientSource) *AncientVerifier {
+	return &AncientVerifier{db: db, sources: sources}
+}
+
+// AddSource registers an additional source for restoring corrupted items.
+func (v *AncientVerifier) AddSource(source AncientSource) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.sources = append(v.sources, source)
+}
+
+// VerifyBlock checks all ancient items of the block with the given number and
+// returns the corrupted ones. If repair is set, corrupted items are restored
+// from the configured sources where possible. The context aborts the retrieval
+// of items from the sources, in which case its error is returned.
+func (v *AncientVerifier) VerifyBlock(ctx context.Context, number uint64, repair bool) ([]AncientFault, error) {
+	frozen, err := v.db.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	if number >= frozen {
+		return nil, fmt.Errorf("block %d is not in the ancient store, frozen %d", number, frozen)
+	}
+	tail, err := v.db.Tail()
+	if err != nil {
+		return nil, err
+	}
+	var faults []AncientFault
+
+	// Verify the hash and the header against each other, using the parent hash
+	// of the subsequent block to tell which of them is corrupted.
+	header, hash, fault := v.verifyHeader(ctx, number, frozen, repair)
+	if fault != nil {
+		faults = append(faults, *fault)
+	}
+	if header == nil {
+		// Without a valid header, the body and receipts can't be checked. The
+		// ancient store might also have been truncated in the meantime.
+		return v.filterTruncated(ctx, number, faults)
+	}
+	// Bodies and receipts below the tail have been pruned from the freezer
+	if number < tail {
+		return faults, nil
+	}
+	var body *types.Body
+	blob, err := v.db.Ancient(rawdb.ChainFreezerBodiesTable, number)
+	if err == nil {
+		body, err = verifyBody(header, blob)
+	}
+	if err != nil {
+		fault := AncientFault{Number: number, Kind: rawdb.ChainFreezerBodiesTable, Err: err}
+		if repair {
+			blob := v.restore(ctx, &fault, hash, func(blob []byte) error {
+				_, err := verifyBody(header, blob)
+				return err
+			})
+			if blob != nil {
+				body, _ = verifyBody(header, blob)
+			}
+		}
+		faults = append(faults, fault)
+	}
+	if body == nil {
+		return v.filterTruncated(ctx, number, faults)
+	}
+	blob, err = v.db.Ancient(rawdb.ChainFreezerReceiptTable, number)
+	if err == nil {
+		err = verifyReceipts(header, body, blob)
+	}
+	if err != nil {
+		fault := AncientFault{Number: number, Kind: rawdb.ChainFreezerReceiptTable, Err: err}
+		if repair {
+			v.restore(ctx, &fault, hash, func(blob []byte) error {
+				return verifyReceipts(header, body, blob)
+			})
+		}
+		faults = append(faults, fault)
+	}
+	return v.filterTruncated(ctx, number, faults)
+}
+
+// filterTruncated drops the faults of a block which has been removed from the
+// ancient store during the verification, since they are caused by the removal.
+// If the context was cancelled, repairs might have been skipped, so the faults
+// are dropped in favor of the context error.
+func (v *AncientVerifier) filterTruncated(ctx context.Context, number uint64, faults []AncientFault) ([]AncientFault, error) {
+	if len(faults) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	frozen, err := v.db.Ancients()
+	if err != nil {
+		return nil, err
+	}
+	if number >= frozen {
+		return nil, nil
+	}
+	return faults, nil
+}
+
+// verifyHeader checks the canonical hash and the header of the given block,
+// returning the decoded header along with its hash if it's intact or has been
+// repaired.
+func (v *AncientVerifier) verifyHeader(ctx context.Context, number uint64, frozen uint64, repair bool) (*types.Header, common.Hash, *AncientFault) {
+	hashBlob, hashErr := v.db.Ancient(rawdb.ChainFreezerHashTable, number)
+	if hashErr == nil && len(hashBlob) != common.HashLength {
+		hashErr = fmt.Errorf("invalid hash length %d", len(hashBlob))
+	}
+	headerBlob, headerErr := v.db.Ancient(rawdb.ChainFreezerHeaderTable, number)
+
+	var (
+		stored = common.BytesToHash(hashBlob)
+		actual = crypto.Keccak256Hash(headerBlob)
+		child  = v.childParentHash(number, frozen)
+	)
+	switch {
+	case hashErr == nil && headerErr == nil && stored == actual:
+		// Both items are consistent, only the header encoding is left to check
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(headerBlob, header); err != nil {
+			return nil, common.Hash{}, &AncientFault{Number: number, Kind: rawdb.ChainFreezerHeaderTable, Err: err}
+		}
+		return header, stored, nil
+
+	case headerErr == nil && child != (common.Hash{}) && child == actual:
+		// The header is confirmed by the subsequent block, the hash is corrupted.
+		// It can be restored without any external source.
+		if hashErr == nil {
+			hashErr = fmt.Errorf("hash mismatch: have %x, want %x", stored, actual)
+		}
+		fault := &AncientFault{Number: number, Kind: rawdb.ChainFreezerHashTable, Err: hashErr}
+		if repair {
+			if err := rawdb.RewriteAncient(v.db, rawdb.ChainFreezerHashTable, number, actual.Bytes()); err != nil {
+				log.Warn("Failed to restore ancient hash", "number", number, "err", err)
+			} else {
+				fault.Repaired = "header"
+			}
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(headerBlob, header); err != nil {
+			return nil, common.Hash{}, fault
+		}
+		return header, actual, fault
+
+	default:
+		// The header is corrupted or unreadable, restore it given a trusted hash
+		want := stored
+		if hashErr != nil || (child != (common.Hash{}) && child != stored) {
+			want = child
+		}
+		if headerErr == nil {
+			headerErr = fmt.Errorf("hash mismatch: have %x, want %x", actual, want)
+		}
+		fault := &AncientFault{Number: number, Kind: rawdb.ChainFreezerHeaderTable, Err: headerErr}
+		if !repair || want == (common.Hash{}) {
+			return nil, common.Hash{}, fault
+		}
+		blob := v.restore(ctx, fault, want, func(blob []byte) error {
+			if have := crypto.Keccak256Hash(blob); have != want {
+				return fmt.Errorf("hash mismatch: have %x, want %x", have, want)
+			}
+			return nil
+		})
+		if blob == nil {
+			return nil, common.Hash{}, fault
+		}
+		if hashErr != nil || stored != want {
+			if err := rawdb.RewriteAncient(v.db, rawdb.ChainFreezerHashTable, number, want.Bytes()); err != nil {
+				fault.Repaired = ""
+				return nil, common.Hash{}, fault
+			}
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(blob, header); err != nil {
+			return nil, common.Hash{}, fault
+		}
+		return header, want, fault
+	}
+}
+
+// childParentHash returns the parent hash recorded by the block following the
+// given one, or an empty hash if it's unavailable.
+func (v *AncientVerifier) childParentHash(number uint64, frozen uint64) common.Hash {
+	var blob []byte
+	if number+1 < frozen {
+		blob, _ = v.db.Ancient(rawdb.ChainFreezerHeaderTable, number+1)
+	} else {
+		hash := rawdb.ReadCanonicalHash(v.db, number+1)
+		if hash == (common.Hash{}) {
+			return common.Hash{}
+		}
+		blob = rawdb.ReadHeaderRLP(v.db, hash, number+1)
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(blob, header); err != nil {
+		return common.Hash{}
+	}
+	return header.ParentHash
+}
+
+// restore attempts to fetch a valid copy of the faulty item from the sources
+// and to write it back into the ancient store. The restored item is returned,
+// or nil if none of the sources could provide it.
+func (v *AncientVerifier) restore(ctx context.Context, fault *AncientFault, hash common.Hash, validate func([]byte) error) []byte {
+	v.lock.RLock()
+	sources := v.sources
+	v.lock.RUnlock()
+
+	for _, source := range sources {
+		if ctx.Err() != nil {
+			return nil
+		}
+		blob, err := source.FetchAncient(ctx, fault.Kind, fault.Number, hash)
+		if err != nil {
+			log.Debug("Failed to fetch ancient item", "source", source.Name(), "kind", fault.Kind, "number", fault.Number, "err", err)
+			continue
+		}
+		if blob == nil {
+			continue
+		}
+		if err := validate(blob); err != nil {
+			log.Warn("Invalid ancient item from source", "source", source.Name(), "kind", fault.Kind, "number", fault.Number, "err", err)
+			continue
+		}
+		if err := rawdb.RewriteAncient(v.db, fault.Kind, fault.Number, blob); err != nil {
+			log.Warn("Failed to restore ancient item", "source", source.Name(), "kind", fault.Kind, "number", fault.Number, "err", err)
+			return nil
+		}
+		fault.Repaired = source.Name()
+		return blob
+	}
+	return nil
+}
+
+// verifyBody decodes the body and checks it against the roots in the header.
+func verifyBody(header *types.Header, blob []byte) (*types.Body, error) {
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(blob, body); err != nil {
+		return nil, err
+	}
+	hasher := trie.NewStackTrie(nil)
+	if hash := types.DeriveSha(types.Transactions(body.Transactions), hasher); hash != header.TxHash {
+		return nil, fmt.Errorf("transaction root mismatch: have %x, want %x", hash, header.TxHash)
+	}
+	if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+		return nil, fmt.Errorf("uncle hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	if header.WithdrawalsHash != nil {
+		if body.Withdrawals == nil {
+			return nil, errors.New("missing withdrawals")
+		}
+		if hash := types.DeriveSha(types.Withdrawals(body.Withdrawals), hasher); hash != *header.WithdrawalsHash {
+			return nil, fmt.Errorf("withdrawals root mismatch: have %x, want %x", hash, *header.WithdrawalsHash)
+		}
+	}
+	return body, nil
+}
+
+// verifyReceipts decodes the receipts in storage format and checks them against
+// the receipt root in the header.
+func verifyReceipts(header *types.Header, body *types.Body, blob []byte) error {
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return err
+	}
+	if len(stored) != len(body.Transactions) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(stored), len(body.Transactions))
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+		receipts[i].Type = body.Transactions[i].Type()
+		receipts[i].Bloom = types.CreateBloom(receipts[i])
+	}
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", hash, header.ReceiptHash)
+	}
+	return nil
+}
+
+// eraAncientSource restores bodies and receipts from the era files backing the
+// chain freezer.
+type eraAncientSource struct {
+	db ethdb.Database
+}
+
+// NewEraAncientSource creates a source restoring bodies and receipts from the
+// era backend of the given database.
+func NewEraAncientSource(db ethdb.Database) AncientSource {
+	return &eraAncientSource{db: db}
+}
+
+func (s *eraAncientSource) Name() string { return "era" }
+
+func (s *eraAncientSource) FetchAncient(ctx context.Context, kind string, number uint64, hash common.Hash) ([]byte, error) {
+	return rawdb.ReadAncientBackup(s.db, kind, number)
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// testAncientSource serves the original chain data for restoring items.
+type testAncientSource struct {
+	blocks   []*types.Block
+	receipts []rlp.RawValue
+}
+
+func (s *testAncientSource) Name() string { return "test" }
+
+func (s *testAncientSource) FetchAncient(ctx context.Context, kind string, number uint64, hash common.Hash) ([]byte, error) {
+	switch kind {
+	case rawdb.ChainFreezerHeaderTable:
+		return rlp.EncodeToBytes(s.blocks[number].Header())
+	case rawdb.ChainFreezerBodiesTable:
+		return rlp.EncodeToBytes(s.blocks[number].Body())
+	case rawdb.ChainFreezerReceiptTable:
+		return s.receipts[number], nil
+	}
+	return nil, nil
+}
+
+// corruptFile overwrites a part of the given freezer data file.
+func corruptFile(t *testing.T, path string, offset int64, data []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if offset < 0 {
+		stat, err := f.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset += stat.Size()
+	}
+	if _, err := f.WriteAt(data, offset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestAncientStore creates a database with a short chain moved into the
+// ancient store, returning the blocks, their encoded receipts and the ancient
+// directory.
+func newTestAncientStore(t *testing.T) (ethdb.Database, []*types.Block, []rlp.RawValue, string) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		nonce = uint64(0)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 32, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, key)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	blocks = append([]*types.Block{gspec.ToBlock()}, blocks...)
+	encReceipts := types.EncodeBlockReceiptLists(append([]types.Receipts{{}}, receipts...))
+
+	ancient := t.TempDir()
+	db, err := rawdb.Open(rawdb.NewMemoryDatabase(), rawdb.OpenOptions{Ancient: ancient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rawdb.WriteAncientBlocks(db, blocks, encReceipts); err != nil {
+		t.Fatal(err)
+	}
+	return db, blocks, encReceipts, ancient
+}
+
+func TestAncientVerifier(t *testing.T) {
+	db, blocks, encReceipts, ancient := newTestAncientStore(t)
+	defer db.Close()
+
+	verify := func(verifier *AncientVerifier, number uint64, repair bool) []AncientFault {
+		t.Helper()
+		faults, err := verifier.VerifyBlock(context.Background(), number, repair)
+		if err != nil {
+			t.Fatalf("block %d: verification failed: %v", number, err)
+		}
+		return faults
+	}
+	// An intact ancient store should verify cleanly
+	verifier := NewAncientVerifier(db)
+	for number := range blocks {
+		if faults := verify(verifier, uint64(number), false); len(faults) != 0 {
+			t.Fatalf("block %d: unexpected faults %v", number, faults)
+		}
+	}
+	// Corrupt the canonical hash of a block, it should be restored from the header
+	dir := filepath.Join(ancient, rawdb.ChainFreezerName)
+	corruptFile(t, filepath.Join(dir, "hashes.0000.rdat"), 5*common.HashLength, make([]byte, common.HashLength))
+
+	faults := verify(verifier, 5, false)
+	if len(faults) != 1 || faults[0].Kind != rawdb.ChainFreezerHashTable || faults[0].Repaired != "" {
+		t.Fatalf("unexpected faults for corrupted hash: %v", faults)
+	}
+	faults = verify(verifier, 5, true)
+	if len(faults) != 1 || faults[0].Repaired == "" {
+		t.Fatalf("corrupted hash not repaired: %v", faults)
+	}
+	if hash := rawdb.ReadCanonicalHash(db, 5); hash != blocks[5].Hash() {
+		t.Fatalf("hash not restored: have %x, want %x", hash, blocks[5].Hash())
+	}
+	// Corrupt the body of the last block, it can only be restored from a source
+	corruptFile(t, filepath.Join(dir, "bodies.0000.cdat"), -1, []byte{0xff})
+
+	head := uint64(len(blocks) - 1)
+	faults = verify(verifier, head, true)
+	if len(faults) != 1 || faults[0].Kind != rawdb.ChainFreezerBodiesTable || faults[0].Repaired != "" {
+		t.Fatalf("unexpected faults for corrupted body: %v", faults)
+	}
+	verifier.AddSource(&testAncientSource{blocks: blocks, receipts: encReceipts})
+
+	faults = verify(verifier, head, true)
+	if len(faults) != 1 || faults[0].Repaired != "test" {
+		t.Fatalf("corrupted body not repaired: %v", faults)
+	}
+	for number := range blocks {
+		if faults := verify(verifier, uint64(number), false); len(faults) != 0 {
+			t.Fatalf("block %d: unexpected faults after repair %v", number, faults)
+		}
+	}
+}
+
+// blockingAncientSource is a source whose retrievals only return once they are
+// cancelled.
+type blockingAncientSource struct {
+	fetching chan struct{}
+}
+
+func (s *blockingAncientSource) Name() string { return "blocking" }
+
+func (s *blockingAncientSource) FetchAncient(ctx context.Context, kind string, number uint64, hash common.Hash) ([]byte, error) {
+	s.fetching <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// Tests that closing the scrubber aborts a pending restoration instead of
+// waiting for the source to respond.
+func TestAncientScrubberClose(t *testing.T) {
+	db, blocks, _, ancient := newTestAncientStore(t)
+	defer db.Close()
+
+	// Corrupt the body of the last block, so the scrubber has to fetch it
+	corruptFile(t, filepath.Join(ancient, rawdb.ChainFreezerName, "bodies.0000.cdat"), -1, []byte{0xff})
+
+	source := &blockingAncientSource{fetching: make(chan struct{}, 1)}
+	scrubber := newAncientScrubber(db, source)
+	select {
+	case <-source.fetching:
+	case <-time.After(10 * time.Second):
+		t.Fatal("scrubber didn't fetch the corrupted body")
+	}
+	done := make(chan struct{})
+	go func() {
+		scrubber.close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scrubber didn't terminate on close")
+	}
+	// The interrupted block must be verified again after a restart
+	if next, head := rawdb.ReadAncientScrubProgress(db), uint64(len(blocks)-1); next != head {
+		t.Fatalf("scrub progress mismatch: have %d, want %d", next, head)
+	}
+}
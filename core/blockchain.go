@@ -213,6 +213,10 @@ type BlockChainConfig struct {
 	// StateSizeTracking indicates whether the state size tracking is enabled.
 	StateSizeTracking bool
 
+	// AncientScrub indicates whether the ancient store is periodically verified
+	// in the background, restoring corrupted items from the era files.
+	AncientScrub bool
+
 	// SlowBlockThreshold is the block execution time threshold beyond which
 	// detailed statistics will be logged. Negative value means disabled (default),
 	// zero logs all blocks, positive value filters blocks by execution time.
@@ -322,6 +326,7 @@ type BlockChain struct {
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	statedb       *state.CachingDB                 // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	scrubber      *ancientScrubber                 // Ancient store scrubber, might be nil if not enabled
 
 	hc               *HeaderChain
 	rmLogsFeed       event.Feed
@@ -389,6 +394,9 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	if err != nil {
 		return nil, err
 	}
+	if err := vm.CheckPrecompiles(chainConfig); err != nil {
+		return nil, err
+	}
 	log.Info("")
 	log.Info(strings.Repeat("-", 153))
 	for _, line := range strings.Split(chainConfig.Description(), "\n") {
@@ -557,6 +565,11 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		bc.txIndexer = newTxIndexer(uint64(bc.cfg.TxLookupLimit), bc)
 	}
 
+	// Start the ancient scrubber if it's enabled.
+	if bc.cfg.AncientScrub {
+		bc.scrubber = newAncientScrubber(bc.db, NewEraAncientSource(bc.db))
+	}
+
 	// Start state size tracker
 	if bc.cfg.StateSizeTracking {
 		stateSizer, err := state.NewSizeTracker(bc.db, bc.triedb)
@@ -1297,6 +1310,14 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	headBlockGauge.Update(int64(block.NumberU64()))
 }
 
+// AddAncientSource registers an additional source the ancient scrubber can
+// restore corrupted items from. It's a noop if the scrubber is not enabled.
+func (bc *BlockChain) AddAncientSource(source AncientSource) {
+	if bc.scrubber != nil {
+		bc.scrubber.verifier.AddSource(source)
+	}
+}
+
 // stopWithoutSaving stops the blockchain service. If any imports are currently in progress
 // it will abort them using the procInterrupt. This method stops all running
 // goroutines, but does not do all the post-stop work of persisting data.
@@ -1311,6 +1332,10 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	// Signal shutdown ancient scrubber.
+	if bc.scrubber != nil {
+		bc.scrubber.close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
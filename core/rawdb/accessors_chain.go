@@ -218,6 +218,24 @@ func WriteTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
 	}
 }
 
+// ReadAncientScrubProgress retrieves the number of the next ancient block to be
+// verified by the background scrubber.
+func ReadAncientScrubProgress(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(ancientScrubKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteAncientScrubProgress stores the number of the next ancient block to be
+// verified by the background scrubber.
+func WriteAncientScrubProgress(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(ancientScrubKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the ancient scrub progress", "err", err)
+	}
+}
+
 // DeleteTxIndexTail deletes the number of oldest indexed block
 // from database.
 func DeleteTxIndexTail(db ethdb.KeyValueWriter) {
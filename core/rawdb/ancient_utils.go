@@ -135,11 +135,10 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 	return infos, nil
 }
 
-// InspectFreezerTable dumps out the index of a specific freezer table. The passed
-// ancient indicates the path of root ancient directory where the chain freezer can
-// be opened. Start and end specify the range for dumping out indexes.
-// Note this function can only be used for debugging purposes.
-func InspectFreezerTable(ancient string, freezerName string, tableName string, start, end int64) error {
+// resolveFreezerTable resolves the directory and the configuration of a specific
+// table in the given freezer. The passed ancient indicates the path of the root
+// ancient directory.
+func resolveFreezerTable(ancient string, freezerName string, tableName string) (string, freezerTableConfig, error) {
 	var (
 		path   string
 		tables map[string]freezerTableConfig
@@ -152,20 +151,58 @@ func InspectFreezerTable(ancient string, freezerName string, tableName string, s
 	case MerkleTrienodeFreezerName, VerkleTrienodeFreezerName:
 		path, tables = filepath.Join(ancient, freezerName), trienodeFreezerTableConfigs
 	default:
-		return fmt.Errorf("unknown freezer, supported ones: %v", freezers)
+		return "", freezerTableConfig{}, fmt.Errorf("unknown freezer, supported ones: %v", freezers)
 	}
-	noSnappy, exist := tables[tableName]
+	config, exist := tables[tableName]
 	if !exist {
 		var names []string
 		for name := range tables {
 			names = append(names, name)
 		}
-		return fmt.Errorf("unknown table, supported ones: %v", names)
+		return "", freezerTableConfig{}, fmt.Errorf("unknown table, supported ones: %v", names)
 	}
-	table, err := newFreezerTable(path, tableName, noSnappy, true)
+	return path, config, nil
+}
+
+// InspectFreezerTable dumps out the index of a specific freezer table. The passed
+// ancient indicates the path of root ancient directory where the chain freezer can
+// be opened. Start and end specify the range for dumping out indexes.
+// Note this function can only be used for debugging purposes.
+func InspectFreezerTable(ancient string, freezerName string, tableName string, start, end int64) error {
+	path, config, err := resolveFreezerTable(ancient, freezerName, tableName)
+	if err != nil {
+		return err
+	}
+	table, err := newFreezerTable(path, tableName, config, true)
 	if err != nil {
 		return err
 	}
 	table.dumpIndexStdout(start, end)
 	return nil
 }
+
+// RewriteAncient overwrites a corrupted item in the chain freezer of the given
+// database in place. The content must have the exact encoded size of the stored
+// item, which holds for restored copies of the original data.
+func RewriteAncient(db ethdb.Database, kind string, number uint64, blob []byte) error {
+	rewriter, ok := db.(interface {
+		RewriteAncient(kind string, number uint64, blob []byte) error
+	})
+	if !ok {
+		return errNotSupported
+	}
+	return rewriter.RewriteAncient(kind, number, blob)
+}
+
+// ReadAncientBackup retrieves a body or receipts item of the chain freezer from
+// the era backend of the given database. It returns nil if the item is not
+// available in any era file.
+func ReadAncientBackup(db ethdb.Database, kind string, number uint64) ([]byte, error) {
+	reader, ok := db.(interface {
+		ReadAncientBackup(kind string, number uint64) ([]byte, error)
+	})
+	if !ok {
+		return nil, nil
+	}
+	return reader.ReadAncientBackup(kind, number)
+}
@@ -385,6 +385,32 @@ func (f *chainFreezer) ReadAncients(fn func(ethdb.AncientReaderOp) error) (err e
 	return fn(f)
 }
 
+// RewriteAncient overwrites a corrupted item of the underlying ancient store
+// in place, which is only supported by the file based freezer.
+func (f *chainFreezer) RewriteAncient(kind string, number uint64, blob []byte) error {
+	store, ok := f.ancients.(*Freezer)
+	if !ok {
+		return errNotSupported
+	}
+	return store.RewriteAncient(kind, number, blob)
+}
+
+// ReadAncientBackup retrieves a body or receipts item from the optional era
+// backend, regardless of whether the item is still present in the ancient
+// store. It returns nil if the item is not available.
+func (f *chainFreezer) ReadAncientBackup(kind string, number uint64) ([]byte, error) {
+	if f.eradb == nil {
+		return nil, nil
+	}
+	switch kind {
+	case ChainFreezerBodiesTable:
+		return f.eradb.GetRawBody(number)
+	case ChainFreezerReceiptTable:
+		return f.eradb.GetRawReceipts(number)
+	}
+	return nil, nil
+}
+
 // Methods below are just pass-through to the underlying ancient store.
 
 func (f *chainFreezer) Ancients() (uint64, error) {
@@ -71,6 +71,12 @@ func (frdb *freezerdb) Close() error {
 	return nil
 }
 
+// NewSnapshot creates a snapshot of the key-value store, if the backing store
+// supports it.
+func (frdb *freezerdb) NewSnapshot() (ethdb.Snapshot, error) {
+	return newSnapshot(frdb.KeyValueStore)
+}
+
 // Freeze is a helper method used for external testing to trigger and block until
 // a freeze cycle completes, without having to sleep for a minute to trigger the
 // automatic background run.
@@ -162,6 +168,22 @@ func (db *nofreezedb) AncientDatadir() (string, error) {
 	return "", errNotSupported
 }
 
+// NewSnapshot creates a snapshot of the key-value store, if the backing store
+// supports it.
+func (db *nofreezedb) NewSnapshot() (ethdb.Snapshot, error) {
+	return newSnapshot(db.KeyValueStore)
+}
+
+// newSnapshot creates a snapshot of the given key-value store, or returns an
+// error if the store is not capable of creating point-in-time views.
+func newSnapshot(db ethdb.KeyValueStore) (ethdb.Snapshot, error) {
+	snapshotter, ok := db.(ethdb.Snapshotter)
+	if !ok {
+		return nil, errNotSupported
+	}
+	return snapshotter.NewSnapshot()
+}
+
 // NewDatabase creates a high level database on top of a given key-value data
 // store without a freezer moving immutable chain segments into cold storage.
 func NewDatabase(db ethdb.KeyValueStore) ethdb.Database {
@@ -222,6 +244,7 @@ type OpenOptions struct {
 	Era              string // era files directory
 	MetricsNamespace string // prefix added to freezer metric names
 	ReadOnly         bool
+	TrackStats       bool // maintain database statistics in the background
 }
 
 // Open creates a high-level database wrapper for the given key-value store.
@@ -321,6 +344,11 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 			// freezer.
 		}
 	}
+	// Wrap the key-value store with statistics tracking if requested. This needs
+	// to be done before the freezer is started to account for its deletions too.
+	if opts.TrackStats && !opts.ReadOnly {
+		db = newStatsTracker(db, opts.MetricsNamespace)
+	}
 	// Freezer is consistent with the key-value database, permit combining the two
 	if !opts.ReadOnly {
 		frdb.wg.Add(1)
@@ -397,6 +425,175 @@ func (s *stat) countString() string {
 	return counter(atomic.LoadUint64(&s.count)).String()
 }
 
+// inspectCategory is a category of key-value store entries distinguished by
+// the database inspection.
+type inspectCategory int
+
+const (
+	categoryHeaders inspectCategory = iota
+	categoryBodies
+	categoryReceipts
+	categoryTDs
+	categoryNumHashPairings
+	categoryHashNumPairings
+	categoryTxLookups
+	categoryFilterMapRows
+	categoryFilterMapLastBlock
+	categoryFilterMapBlockLV
+	categoryBloomBits
+	categoryCodes
+	categoryLegacyTries
+	categoryStateLookups
+	categoryAccountTries
+	categoryStorageTries
+	categoryVerkleTries
+	categoryVerkleStateLookups
+	categoryPreimages
+	categoryAccountSnaps
+	categoryStorageSnaps
+	categoryStateIndex
+	categoryTrienodeIndex
+	categoryBeaconHeaders
+	categoryCliqueSnaps
+	categoryMetadata
+	categoryUnaccounted
+
+	numInspectCategories
+)
+
+// inspectCategories contains the human readable and the metrics names of the
+// inspection categories, in display order.
+var inspectCategories = [numInspectCategories]struct {
+	name   string // Name displayed in the inspection table
+	metric string // Name used in metrics and stat exports
+}{
+	categoryHeaders:            {"Headers", "headers"},
+	categoryBodies:             {"Bodies", "bodies"},
+	categoryReceipts:           {"Receipt lists", "receipts"},
+	categoryTDs:                {"Difficulties (deprecated)", "difficulties"},
+	categoryNumHashPairings:    {"Block number->hash", "numberhash"},
+	categoryHashNumPairings:    {"Block hash->number", "hashnumber"},
+	categoryTxLookups:          {"Transaction index", "txlookups"},
+	categoryFilterMapRows:      {"Log index filter-map rows", "filtermaprows"},
+	categoryFilterMapLastBlock: {"Log index last-block-of-map", "filtermaplastblock"},
+	categoryFilterMapBlockLV:   {"Log index block-lv", "filtermapblocklv"},
+	categoryBloomBits:          {"Log bloombits (deprecated)", "bloombits"},
+	categoryCodes:              {"Contract codes", "codes"},
+	categoryLegacyTries:        {"Hash trie nodes", "hashtrienodes"},
+	categoryStateLookups:       {"Path trie state lookups", "statelookups"},
+	categoryAccountTries:       {"Path trie account nodes", "accounttrienodes"},
+	categoryStorageTries:       {"Path trie storage nodes", "storagetrienodes"},
+	categoryVerkleTries:        {"Verkle trie nodes", "verkletrienodes"},
+	categoryVerkleStateLookups: {"Verkle trie state lookups", "verklestatelookups"},
+	categoryPreimages:          {"Trie preimages", "preimages"},
+	categoryAccountSnaps:       {"Account snapshot", "accountsnapshot"},
+	categoryStorageSnaps:       {"Storage snapshot", "storagesnapshot"},
+	categoryStateIndex:         {"Historical state index", "stateindex"},
+	categoryTrienodeIndex:      {"Historical trie index", "trienodeindex"},
+	categoryBeaconHeaders:      {"Beacon sync headers", "beaconheaders"},
+	categoryCliqueSnaps:        {"Clique snapshots", "cliquesnapshots"},
+	categoryMetadata:           {"Singleton metadata", "metadata"},
+	categoryUnaccounted:        {"Unaccounted", "unaccounted"},
+}
+
+// classifyKey determines the inspection category of a key-value store entry.
+func classifyKey(key []byte, value []byte) inspectCategory {
+	switch {
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
+		return categoryHeaders
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == (len(blockBodyPrefix)+8+common.HashLength):
+		return categoryBodies
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
+		return categoryReceipts
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix):
+		return categoryTDs
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
+		return categoryNumHashPairings
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
+		return categoryHashNumPairings
+	case IsLegacyTrieNode(key, value):
+		return categoryLegacyTries
+	case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+		return categoryStateLookups
+	case IsAccountTrieNode(key):
+		return categoryAccountTries
+	case IsStorageTrieNode(key):
+		return categoryStorageTries
+	case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
+		return categoryCodes
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
+		return categoryTxLookups
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == (len(SnapshotAccountPrefix)+common.HashLength):
+		return categoryAccountSnaps
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == (len(SnapshotStoragePrefix)+2*common.HashLength):
+		return categoryStorageSnaps
+	case bytes.HasPrefix(key, PreimagePrefix) && len(key) == (len(PreimagePrefix)+common.HashLength):
+		return categoryPreimages
+	case bytes.HasPrefix(key, configPrefix) && len(key) == (len(configPrefix)+common.HashLength):
+		return categoryMetadata
+	case bytes.HasPrefix(key, genesisPrefix) && len(key) == (len(genesisPrefix)+common.HashLength):
+		return categoryMetadata
+	case bytes.HasPrefix(key, skeletonHeaderPrefix) && len(key) == (len(skeletonHeaderPrefix)+8):
+		return categoryBeaconHeaders
+	case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
+		return categoryCliqueSnaps
+
+	// new log index
+	case bytes.HasPrefix(key, filterMapRowPrefix) && len(key) <= len(filterMapRowPrefix)+9:
+		return categoryFilterMapRows
+	case bytes.HasPrefix(key, filterMapLastBlockPrefix) && len(key) == len(filterMapLastBlockPrefix)+4:
+		return categoryFilterMapLastBlock
+	case bytes.HasPrefix(key, filterMapBlockLVPrefix) && len(key) == len(filterMapBlockLVPrefix)+8:
+		return categoryFilterMapBlockLV
+
+	// old log index (deprecated)
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
+		return categoryBloomBits
+	case bytes.HasPrefix(key, bloomBitsMetaPrefix) && len(key) < len(bloomBitsMetaPrefix)+8:
+		return categoryBloomBits
+
+	// Path-based historic state indexes
+	case bytes.HasPrefix(key, StateHistoryAccountMetadataPrefix) && len(key) == len(StateHistoryAccountMetadataPrefix)+common.HashLength:
+		return categoryStateIndex
+	case bytes.HasPrefix(key, StateHistoryStorageMetadataPrefix) && len(key) == len(StateHistoryStorageMetadataPrefix)+2*common.HashLength:
+		return categoryStateIndex
+	case bytes.HasPrefix(key, StateHistoryAccountBlockPrefix) && len(key) == len(StateHistoryAccountBlockPrefix)+common.HashLength+4:
+		return categoryStateIndex
+	case bytes.HasPrefix(key, StateHistoryStorageBlockPrefix) && len(key) == len(StateHistoryStorageBlockPrefix)+2*common.HashLength+4:
+		return categoryStateIndex
+
+	case bytes.HasPrefix(key, TrienodeHistoryMetadataPrefix) && len(key) >= len(TrienodeHistoryMetadataPrefix)+common.HashLength:
+		return categoryTrienodeIndex
+	case bytes.HasPrefix(key, TrienodeHistoryBlockPrefix) && len(key) >= len(TrienodeHistoryBlockPrefix)+common.HashLength+4:
+		return categoryTrienodeIndex
+
+	// Verkle trie data is detected, determine the sub-category
+	case bytes.HasPrefix(key, VerklePrefix):
+		remain := key[len(VerklePrefix):]
+		switch {
+		case IsAccountTrieNode(remain):
+			return categoryVerkleTries
+		case bytes.HasPrefix(remain, stateIDPrefix) && len(remain) == len(stateIDPrefix)+common.HashLength:
+			return categoryVerkleStateLookups
+		case bytes.Equal(remain, persistentStateIDKey):
+			return categoryMetadata
+		case bytes.Equal(remain, trieJournalKey):
+			return categoryMetadata
+		case bytes.Equal(remain, snapSyncStatusFlagKey):
+			return categoryMetadata
+		default:
+			return categoryUnaccounted
+		}
+
+	// Metadata keys
+	case slices.ContainsFunc(knownMetadataKeys, func(x []byte) bool { return bytes.Equal(x, key) }):
+		return categoryMetadata
+
+	default:
+		return categoryUnaccounted
+	}
+}
+
 // InspectDatabase traverses the entire database and checks the size
 // of all different categories of data.
 func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
@@ -405,40 +602,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		count atomic.Int64
 		total atomic.Uint64
 
-		// Key-value store statistics
-		headers            stat
-		bodies             stat
-		receipts           stat
-		tds                stat
-		numHashPairings    stat
-		hashNumPairings    stat
-		legacyTries        stat
-		stateLookups       stat
-		accountTries       stat
-		storageTries       stat
-		codes              stat
-		txLookups          stat
-		accountSnaps       stat
-		storageSnaps       stat
-		preimages          stat
-		beaconHeaders      stat
-		cliqueSnaps        stat
-		bloomBits          stat
-		filterMapRows      stat
-		filterMapLastBlock stat
-		filterMapBlockLV   stat
-
-		// Path-mode archive data
-		stateIndex    stat
-		trienodeIndex stat
-
-		// Verkle statistics
-		verkleTries        stat
-		verkleStateLookups stat
-
-		// Meta- and unaccounted data
-		metadata    stat
-		unaccounted stat
+		// Key-value store statistics, one per inspection category
+		stats [numInspectCategories]stat
 
 		// This map tracks example keys for unaccounted data.
 		// For each unique two-byte prefix, the first unaccounted key encountered
@@ -470,107 +635,16 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			total.Add(uint64(size))
 			count.Add(1)
 
-			switch {
-			case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
-				headers.add(size)
-			case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == (len(blockBodyPrefix)+8+common.HashLength):
-				bodies.add(size)
-			case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
-				receipts.add(size)
-			case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix):
-				tds.add(size)
-			case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
-				numHashPairings.add(size)
-			case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
-				hashNumPairings.add(size)
-			case IsLegacyTrieNode(key, it.Value()):
-				legacyTries.add(size)
-			case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
-				stateLookups.add(size)
-			case IsAccountTrieNode(key):
-				accountTries.add(size)
-			case IsStorageTrieNode(key):
-				storageTries.add(size)
-			case bytes.HasPrefix(key, CodePrefix) && len(key) == len(CodePrefix)+common.HashLength:
-				codes.add(size)
-			case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
-				txLookups.add(size)
-			case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == (len(SnapshotAccountPrefix)+common.HashLength):
-				accountSnaps.add(size)
-			case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == (len(SnapshotStoragePrefix)+2*common.HashLength):
-				storageSnaps.add(size)
-			case bytes.HasPrefix(key, PreimagePrefix) && len(key) == (len(PreimagePrefix)+common.HashLength):
-				preimages.add(size)
-			case bytes.HasPrefix(key, configPrefix) && len(key) == (len(configPrefix)+common.HashLength):
-				metadata.add(size)
-			case bytes.HasPrefix(key, genesisPrefix) && len(key) == (len(genesisPrefix)+common.HashLength):
-				metadata.add(size)
-			case bytes.HasPrefix(key, skeletonHeaderPrefix) && len(key) == (len(skeletonHeaderPrefix)+8):
-				beaconHeaders.add(size)
-			case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
-				cliqueSnaps.add(size)
-
-			// new log index
-			case bytes.HasPrefix(key, filterMapRowPrefix) && len(key) <= len(filterMapRowPrefix)+9:
-				filterMapRows.add(size)
-			case bytes.HasPrefix(key, filterMapLastBlockPrefix) && len(key) == len(filterMapLastBlockPrefix)+4:
-				filterMapLastBlock.add(size)
-			case bytes.HasPrefix(key, filterMapBlockLVPrefix) && len(key) == len(filterMapBlockLVPrefix)+8:
-				filterMapBlockLV.add(size)
-
-			// old log index (deprecated)
-			case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
-				bloomBits.add(size)
-			case bytes.HasPrefix(key, bloomBitsMetaPrefix) && len(key) < len(bloomBitsMetaPrefix)+8:
-				bloomBits.add(size)
-
-			// Path-based historic state indexes
-			case bytes.HasPrefix(key, StateHistoryAccountMetadataPrefix) && len(key) == len(StateHistoryAccountMetadataPrefix)+common.HashLength:
-				stateIndex.add(size)
-			case bytes.HasPrefix(key, StateHistoryStorageMetadataPrefix) && len(key) == len(StateHistoryStorageMetadataPrefix)+2*common.HashLength:
-				stateIndex.add(size)
-			case bytes.HasPrefix(key, StateHistoryAccountBlockPrefix) && len(key) == len(StateHistoryAccountBlockPrefix)+common.HashLength+4:
-				stateIndex.add(size)
-			case bytes.HasPrefix(key, StateHistoryStorageBlockPrefix) && len(key) == len(StateHistoryStorageBlockPrefix)+2*common.HashLength+4:
-				stateIndex.add(size)
-
-			case bytes.HasPrefix(key, TrienodeHistoryMetadataPrefix) && len(key) >= len(TrienodeHistoryMetadataPrefix)+common.HashLength:
-				trienodeIndex.add(size)
-			case bytes.HasPrefix(key, TrienodeHistoryBlockPrefix) && len(key) >= len(TrienodeHistoryBlockPrefix)+common.HashLength+4:
-				trienodeIndex.add(size)
-
-			// Verkle trie data is detected, determine the sub-category
-			case bytes.HasPrefix(key, VerklePrefix):
-				remain := key[len(VerklePrefix):]
-				switch {
-				case IsAccountTrieNode(remain):
-					verkleTries.add(size)
-				case bytes.HasPrefix(remain, stateIDPrefix) && len(remain) == len(stateIDPrefix)+common.HashLength:
-					verkleStateLookups.add(size)
-				case bytes.Equal(remain, persistentStateIDKey):
-					metadata.add(size)
-				case bytes.Equal(remain, trieJournalKey):
-					metadata.add(size)
-				case bytes.Equal(remain, snapSyncStatusFlagKey):
-					metadata.add(size)
-				default:
-					unaccounted.add(size)
-				}
-
-			// Metadata keys
-			case slices.ContainsFunc(knownMetadataKeys, func(x []byte) bool { return bytes.Equal(x, key) }):
-				metadata.add(size)
+			category := classifyKey(key, it.Value())
+			stats[category].add(size)
 
-			default:
-				unaccounted.add(size)
-				if len(key) >= 2 {
-					prefix := [2]byte(key[:2])
-					unaccountedMu.Lock()
-					if _, ok := unaccountedKeys[prefix]; !ok {
-						unaccountedKeys[prefix] = bytes.Clone(key)
-					}
-					unaccountedMu.Unlock()
+			if category == categoryUnaccounted && len(key) >= 2 {
+				prefix := [2]byte(key[:2])
+				unaccountedMu.Lock()
+				if _, ok := unaccountedKeys[prefix]; !ok {
+					unaccountedKeys[prefix] = bytes.Clone(key)
 				}
+				unaccountedMu.Unlock()
 			}
 
 			select {
@@ -617,65 +691,47 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 	close(done)
 
 	// Display the database statistic of key-value store.
-	stats := [][]string{
-		{"Key-Value store", "Headers", headers.sizeString(), headers.countString()},
-		{"Key-Value store", "Bodies", bodies.sizeString(), bodies.countString()},
-		{"Key-Value store", "Receipt lists", receipts.sizeString(), receipts.countString()},
-		{"Key-Value store", "Difficulties (deprecated)", tds.sizeString(), tds.countString()},
-		{"Key-Value store", "Block number->hash", numHashPairings.sizeString(), numHashPairings.countString()},
-		{"Key-Value store", "Block hash->number", hashNumPairings.sizeString(), hashNumPairings.countString()},
-		{"Key-Value store", "Transaction index", txLookups.sizeString(), txLookups.countString()},
-		{"Key-Value store", "Log index filter-map rows", filterMapRows.sizeString(), filterMapRows.countString()},
-		{"Key-Value store", "Log index last-block-of-map", filterMapLastBlock.sizeString(), filterMapLastBlock.countString()},
-		{"Key-Value store", "Log index block-lv", filterMapBlockLV.sizeString(), filterMapBlockLV.countString()},
-		{"Key-Value store", "Log bloombits (deprecated)", bloomBits.sizeString(), bloomBits.countString()},
-		{"Key-Value store", "Contract codes", codes.sizeString(), codes.countString()},
-		{"Key-Value store", "Hash trie nodes", legacyTries.sizeString(), legacyTries.countString()},
-		{"Key-Value store", "Path trie state lookups", stateLookups.sizeString(), stateLookups.countString()},
-		{"Key-Value store", "Path trie account nodes", accountTries.sizeString(), accountTries.countString()},
-		{"Key-Value store", "Path trie storage nodes", storageTries.sizeString(), storageTries.countString()},
-		{"Key-Value store", "Verkle trie nodes", verkleTries.sizeString(), verkleTries.countString()},
-		{"Key-Value store", "Verkle trie state lookups", verkleStateLookups.sizeString(), verkleStateLookups.countString()},
-		{"Key-Value store", "Trie preimages", preimages.sizeString(), preimages.countString()},
-		{"Key-Value store", "Account snapshot", accountSnaps.sizeString(), accountSnaps.countString()},
-		{"Key-Value store", "Storage snapshot", storageSnaps.sizeString(), storageSnaps.countString()},
-		{"Key-Value store", "Historical state index", stateIndex.sizeString(), stateIndex.countString()},
-		{"Key-Value store", "Historical trie index", trienodeIndex.sizeString(), trienodeIndex.countString()},
-		{"Key-Value store", "Beacon sync headers", beaconHeaders.sizeString(), beaconHeaders.countString()},
-		{"Key-Value store", "Clique snapshots", cliqueSnaps.sizeString(), cliqueSnaps.countString()},
-		{"Key-Value store", "Singleton metadata", metadata.sizeString(), metadata.countString()},
+	var rows [][]string
+	for category := range categoryUnaccounted {
+		rows = append(rows, []string{"Key-Value store", inspectCategories[category].name, stats[category].sizeString(), stats[category].countString()})
 	}
-
-	// Inspect all registered append-only file store then.
 	ancients, err := inspectFreezers(db)
 	if err != nil {
 		return err
 	}
+	for _, ancient := range ancients {
+		total.Add(uint64(ancient.size()))
+	}
+	renderStats(rows, ancients, common.StorageSize(total.Load()), uint64(count.Load()))
+
+	if unaccounted := &stats[categoryUnaccounted]; !unaccounted.empty() {
+		log.Error("Database contains unaccounted data", "size", unaccounted.sizeString(), "count", unaccounted.countString())
+		for _, e := range slices.SortedFunc(maps.Values(unaccountedKeys), bytes.Compare) {
+			log.Error(fmt.Sprintf("   example key: %x", e))
+		}
+	}
+	return nil
+}
+
+// renderStats prints the key-value store statistics along with the ancient
+// store statistics as a table to stdout.
+func renderStats(rows [][]string, ancients []freezerInfo, total common.StorageSize, count uint64) {
+	// Inspect all registered append-only file store then.
 	for _, ancient := range ancients {
 		for _, table := range ancient.sizes {
-			stats = append(stats, []string{
+			rows = append(rows, []string{
 				fmt.Sprintf("Ancient store (%s)", strings.Title(ancient.name)),
 				strings.Title(table.name),
 				table.size.String(),
 				fmt.Sprintf("%d", ancient.count),
 			})
 		}
-		total.Add(uint64(ancient.size()))
 	}
-
 	table := NewTableWriter(os.Stdout)
 	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
-	table.SetFooter([]string{"", "Total", common.StorageSize(total.Load()).String(), fmt.Sprintf("%d", count.Load())})
-	table.AppendBulk(stats)
+	table.SetFooter([]string{"", "Total", total.String(), fmt.Sprintf("%d", count)})
+	table.AppendBulk(rows)
 	table.Render()
-
-	if !unaccounted.empty() {
-		log.Error("Database contains unaccounted data", "size", unaccounted.sizeString(), "count", unaccounted.countString())
-		for _, e := range slices.SortedFunc(maps.Values(unaccountedKeys), bytes.Compare) {
-			log.Error(fmt.Sprintf("   example key: %x", e))
-		}
-	}
-	return nil
 }
 
 // This is the list of known 'metadata' keys stored in the databasse.
@@ -686,6 +742,7 @@ var knownMetadataKeys = [][]byte{
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
 	filterMapsRangeKey, headStateHistoryIndexKey, headTrienodeHistoryIndexKey, VerkleTransitionStatePrefix,
+	databaseStatsKey, ancientScrubKey,
 }
 
 // printChainMetadata prints out chain metadata to stderr.
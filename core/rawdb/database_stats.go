@@ -0,0 +1,495 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// statsScanChunk is the number of entries the background inspector visits
+	// before persisting its progress and yielding.
+	statsScanChunk = 10000
+
+	// statsScanThrottle is the pause between two consecutive chunks of the
+	// background inspection, limiting its impact on the live database.
+	statsScanThrottle = 50 * time.Millisecond
+
+	// statsRescanInterval is the time to wait after a full inspection pass
+	// before starting the next one.
+	statsRescanInterval = time.Hour
+)
+
+// CategoryStats is the aggregated size and item count of a single category of
+// key-value store entries.
+type CategoryStats struct {
+	Name  string `json:"name"`
+	Size  uint64 `json:"size"`
+	Count uint64 `json:"count"`
+}
+
+// DatabaseStats contains the per-category statistics of the key-value store, as
+// maintained by the background database inspector.
+type DatabaseStats struct {
+	Categories []CategoryStats `json:"categories"`
+	Updated    uint64          `json:"updated"`  // Unix time of the last completed inspection pass
+	Progress   []byte          `json:"progress"` // Last key visited by the inspection pass in progress
+}
+
+// statsJournal is the persisted state of the background database inspector,
+// allowing it to resume an interrupted inspection pass after a restart.
+type statsJournal struct {
+	Base    []CategoryStats // Statistics of the last completed pass, adjusted by writes since
+	Scan    []CategoryStats // Partial statistics of the pass in progress
+	Cursor  []byte          // Last key visited by the pass in progress
+	Updated uint64          // Unix time of the last completed pass
+}
+
+// ReadDatabaseStats retrieves the database statistics persisted by the background
+// inspector. Nil is returned if the statistics are not available.
+func ReadDatabaseStats(db ethdb.KeyValueReader) *DatabaseStats {
+	journal := readStatsJournal(db)
+	if journal == nil || journal.Updated == 0 {
+		return nil
+	}
+	return &DatabaseStats{
+		Categories: journal.Base,
+		Updated:    journal.Updated,
+		Progress:   journal.Cursor,
+	}
+}
+
+// readStatsJournal retrieves the persisted state of the background inspector.
+func readStatsJournal(db ethdb.KeyValueReader) *statsJournal {
+	blob, err := db.Get(databaseStatsKey)
+	if err != nil || len(blob) == 0 {
+		return nil
+	}
+	var journal statsJournal
+	if err := rlp.DecodeBytes(blob, &journal); err != nil {
+		log.Warn("Failed to decode database statistics", "err", err)
+		return nil
+	}
+	return &journal
+}
+
+// writeStatsJournal stores the state of the background inspector.
+func writeStatsJournal(db ethdb.KeyValueWriter, journal *statsJournal) {
+	blob, err := rlp.EncodeToBytes(journal)
+	if err != nil {
+		log.Crit("Failed to encode database statistics", "err", err)
+	}
+	if err := db.Put(databaseStatsKey, blob); err != nil {
+		log.Crit("Failed to store database statistics", "err", err)
+	}
+}
+
+// LiveDatabaseStats returns the up-to-date statistics of a database opened with
+// statistics tracking enabled. Nil is returned if tracking is not enabled or no
+// inspection pass has been completed yet.
+func LiveDatabaseStats(db ethdb.Database) *DatabaseStats {
+	frdb, ok := db.(*freezerdb)
+	if !ok {
+		return nil
+	}
+	tracker, ok := frdb.KeyValueStore.(*statsTracker)
+	if !ok {
+		return nil
+	}
+	return tracker.stats()
+}
+
+// PrintDatabaseStats displays the database statistics persisted by the background
+// inspector along with the statistics of the ancient stores, without traversing
+// the key-value store.
+func PrintDatabaseStats(db ethdb.Database) error {
+	stats := ReadDatabaseStats(db)
+	if stats == nil {
+		return errors.New("database statistics not available")
+	}
+	var set statsSet
+	set.load(stats.Categories)
+
+	var (
+		rows  [][]string
+		total common.StorageSize
+		count uint64
+	)
+	for category := range numInspectCategories {
+		if category == categoryUnaccounted && set[category].empty() {
+			continue
+		}
+		rows = append(rows, []string{"Key-Value store", inspectCategories[category].name, set[category].sizeString(), set[category].countString()})
+		total += common.StorageSize(set[category].size)
+		count += set[category].count
+	}
+	ancients, err := inspectFreezers(db)
+	if err != nil {
+		return err
+	}
+	for _, ancient := range ancients {
+		total += ancient.size()
+	}
+	renderStats(rows, ancients, total, count)
+	log.Info("Database statistics", "updated", common.PrettyAge(time.Unix(int64(stats.Updated), 0)))
+	return nil
+}
+
+// statsSet is a set of statistics, one per inspection category.
+type statsSet [numInspectCategories]stat
+
+// sub removes an entry of the given size, clamping the statistics at zero.
+func (s *stat) sub(size uint64) {
+	for {
+		old := atomic.LoadUint64(&s.count)
+		if old == 0 || atomic.CompareAndSwapUint64(&s.count, old, old-1) {
+			break
+		}
+	}
+	for {
+		old := atomic.LoadUint64(&s.size)
+		if atomic.CompareAndSwapUint64(&s.size, old, old-min(old, size)) {
+			break
+		}
+	}
+}
+
+// average returns the average entry size of the statistic.
+func (s *stat) average() uint64 {
+	count := atomic.LoadUint64(&s.count)
+	if count == 0 {
+		return 0
+	}
+	return atomic.LoadUint64(&s.size) / count
+}
+
+// reset clears the statistic.
+func (s *stat) reset() {
+	atomic.StoreUint64(&s.size, 0)
+	atomic.StoreUint64(&s.count, 0)
+}
+
+// export converts the set into its serializable form.
+func (set *statsSet) export() []CategoryStats {
+	stats := make([]CategoryStats, numInspectCategories)
+	for i := range set {
+		stats[i] = CategoryStats{
+			Name:  inspectCategories[i].metric,
+			Size:  atomic.LoadUint64(&set[i].size),
+			Count: atomic.LoadUint64(&set[i].count),
+		}
+	}
+	return stats
+}
+
+// load restores the set from its serializable form. Unknown categories are
+// ignored, missing ones are left empty to be filled by the next pass.
+func (set *statsSet) load(stats []CategoryStats) {
+	for i := range set {
+		set[i].reset()
+	}
+	for _, entry := range stats {
+		for i := range inspectCategories {
+			if inspectCategories[i].metric == entry.Name {
+				atomic.StoreUint64(&set[i].size, entry.Size)
+				atomic.StoreUint64(&set[i].count, entry.Count)
+				break
+			}
+		}
+	}
+}
+
+// statsTracker is a key-value store wrapper which maintains per-category
+// statistics of the database content. The statistics are established by a
+// throttled background inspection of the entire key space, which runs in
+// chunks and persists its progress so that it can be resumed across restarts.
+// Between the inspection passes, writes going through the store are applied
+// incrementally to the statistics of the last completed pass.
+//
+// Note, the incremental updates are estimates: overwrites of existing entries
+// are counted as insertions, deletions are accounted with the average entry size
+// of their category and range deletions are not tracked at all. The deviation is
+// reconciled by every completed inspection pass.
+type statsTracker struct {
+	ethdb.KeyValueStore
+
+	base    statsSet // Statistics of the last completed pass, adjusted by writes
+	scan    statsSet // Partial statistics of the pass in progress
+	cursor  atomic.Pointer[[]byte]
+	updated atomic.Uint64
+
+	sizeGauges  [numInspectCategories]*metrics.Gauge
+	countGauges [numInspectCategories]*metrics.Gauge
+
+	closeOnce sync.Once
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newStatsTracker wraps the given key-value store with statistics tracking
+// and starts the background inspection.
+func newStatsTracker(db ethdb.KeyValueStore, namespace string) *statsTracker {
+	t := &statsTracker{
+		KeyValueStore: db,
+		quit:          make(chan struct{}),
+	}
+	for i := range inspectCategories {
+		t.sizeGauges[i] = metrics.GetOrRegisterGauge(fmt.Sprintf("%sstats/%s/size", namespace, inspectCategories[i].metric), nil)
+		t.countGauges[i] = metrics.GetOrRegisterGauge(fmt.Sprintf("%sstats/%s/count", namespace, inspectCategories[i].metric), nil)
+	}
+	if journal := readStatsJournal(db); journal != nil {
+		t.base.load(journal.Base)
+		t.scan.load(journal.Scan)
+		if journal.Cursor != nil {
+			t.cursor.Store(&journal.Cursor)
+		}
+		t.updated.Store(journal.Updated)
+		t.updateMetrics()
+	}
+	t.wg.Add(1)
+	go t.loop()
+	return t
+}
+
+// stats returns the current statistics, or nil if no inspection pass has been
+// completed yet.
+func (t *statsTracker) stats() *DatabaseStats {
+	updated := t.updated.Load()
+	if updated == 0 {
+		return nil
+	}
+	stats := &DatabaseStats{
+		Categories: t.base.export(),
+		Updated:    updated,
+	}
+	if cursor := t.cursor.Load(); cursor != nil {
+		stats.Progress = common.CopyBytes(*cursor)
+	}
+	return stats
+}
+
+// loop runs the background inspection until the tracker is closed.
+func (t *statsTracker) loop() {
+	defer t.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-t.quit:
+			return
+		}
+		done, err := t.inspectChunk()
+		if err != nil {
+			log.Warn("Database inspection failed", "err", err)
+			timer.Reset(statsRescanInterval)
+			continue
+		}
+		if done {
+			timer.Reset(statsRescanInterval)
+		} else {
+			timer.Reset(statsScanThrottle)
+		}
+	}
+}
+
+// inspectChunk visits the next chunk of the key space, returning whether the
+// current inspection pass has been completed.
+func (t *statsTracker) inspectChunk() (bool, error) {
+	var start []byte
+	if cursor := t.cursor.Load(); cursor != nil {
+		start = append(common.CopyBytes(*cursor), 0x00) // Smallest key after the cursor
+	}
+	it := t.KeyValueStore.NewIterator(nil, start)
+	defer it.Release()
+
+	var (
+		visited int
+		last    []byte
+	)
+	for visited < statsScanChunk && it.Next() {
+		key, value := it.Key(), it.Value()
+		t.scan[classifyKey(key, value)].add(common.StorageSize(len(key) + len(value)))
+		last = key
+		visited++
+	}
+	if err := it.Error(); err != nil {
+		return false, err
+	}
+	done := visited < statsScanChunk
+	if done {
+		// Pass completed, promote its statistics and start over
+		for i := range t.scan {
+			atomic.StoreUint64(&t.base[i].size, atomic.LoadUint64(&t.scan[i].size))
+			atomic.StoreUint64(&t.base[i].count, atomic.LoadUint64(&t.scan[i].count))
+			t.scan[i].reset()
+		}
+		t.cursor.Store(nil)
+		t.updated.Store(uint64(time.Now().Unix()))
+	} else {
+		last = common.CopyBytes(last)
+		t.cursor.Store(&last)
+	}
+	t.updateMetrics()
+
+	// Persist the progress directly into the wrapped store, bypassing tracking
+	journal := &statsJournal{
+		Base:    t.base.export(),
+		Scan:    t.scan.export(),
+		Updated: t.updated.Load(),
+	}
+	if !done {
+		journal.Cursor = last
+	}
+	writeStatsJournal(t.KeyValueStore, journal)
+	return done, nil
+}
+
+// updateMetrics publishes the current statistics via the metrics system.
+func (t *statsTracker) updateMetrics() {
+	if t.updated.Load() == 0 {
+		return
+	}
+	for i := range t.base {
+		t.sizeGauges[i].Update(int64(atomic.LoadUint64(&t.base[i].size)))
+		t.countGauges[i].Update(int64(atomic.LoadUint64(&t.base[i].count)))
+	}
+}
+
+// scanned reports whether the given key has already been visited by the
+// inspection pass in progress.
+func (t *statsTracker) scanned(key []byte) bool {
+	cursor := t.cursor.Load()
+	return cursor != nil && bytes.Compare(key, *cursor) <= 0
+}
+
+// onPut accounts for an entry inserted into the database.
+func (t *statsTracker) onPut(key []byte, value []byte) {
+	var (
+		category = classifyKey(key, value)
+		size     = common.StorageSize(len(key) + len(value))
+	)
+	t.base[category].add(size)
+	if t.scanned(key) {
+		t.scan[category].add(size)
+	}
+}
+
+// onDelete accounts for an entry removed from the database.
+func (t *statsTracker) onDelete(key []byte) {
+	category := classifyKey(key, nil)
+	if category == categoryUnaccounted && len(key) == common.HashLength {
+		category = categoryLegacyTries // Values are unknown, assume hash scheme trie node
+	}
+	t.base[category].sub(t.base[category].average())
+	if t.scanned(key) {
+		t.scan[category].sub(t.scan[category].average())
+	}
+}
+
+// Put inserts the given value into the key-value data store.
+func (t *statsTracker) Put(key []byte, value []byte) error {
+	if err := t.KeyValueStore.Put(key, value); err != nil {
+		return err
+	}
+	t.onPut(key, value)
+	return nil
+}
+
+// Delete removes the key from the key-value data store.
+func (t *statsTracker) Delete(key []byte) error {
+	if err := t.KeyValueStore.Delete(key); err != nil {
+		return err
+	}
+	t.onDelete(key)
+	return nil
+}
+
+// NewBatch creates a write-only database that buffers changes to its host db
+// until a final write is called.
+func (t *statsTracker) NewBatch() ethdb.Batch {
+	return t.newBatch(t.KeyValueStore.NewBatch())
+}
+
+// NewBatchWithSize creates a write-only database batch with pre-allocated buffer.
+func (t *statsTracker) NewBatchWithSize(size int) ethdb.Batch {
+	return t.newBatch(t.KeyValueStore.NewBatchWithSize(size))
+}
+
+// newBatch wraps a batch of the underlying store for statistics tracking.
+func (t *statsTracker) newBatch(batch ethdb.Batch) ethdb.Batch {
+	return &statsBatch{Batch: batch, tracker: t}
+}
+
+// NewSnapshot creates a snapshot of the key-value store, if the backing store
+// supports it.
+func (t *statsTracker) NewSnapshot() (ethdb.Snapshot, error) {
+	return newSnapshot(t.KeyValueStore)
+}
+
+// Close stops the background inspection and closes the underlying store.
+func (t *statsTracker) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.quit)
+		t.wg.Wait()
+	})
+	return t.KeyValueStore.Close()
+}
+
+// statsBatch is a batch which applies its writes to the statistics of the
+// tracker once it's written into the database.
+type statsBatch struct {
+	ethdb.Batch
+	tracker *statsTracker
+}
+
+// Write flushes any accumulated data to disk and accounts for it.
+func (b *statsBatch) Write() error {
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	return b.Batch.Replay(ethdb.HookedBatch{
+		Batch:    nopBatch{},
+		OnPut:    b.tracker.onPut,
+		OnDelete: b.tracker.onDelete,
+	})
+}
+
+// nopBatch is a batch dropping all writes, used as the sink when replaying
+// writes into hooks only.
+type nopBatch struct{}
+
+func (nopBatch) Put(key []byte, value []byte) error  { return nil }
+func (nopBatch) Delete(key []byte) error             { return nil }
+func (nopBatch) DeleteRange(start, end []byte) error { return nil }
+func (nopBatch) ValueSize() int                      { return 0 }
+func (nopBatch) Write() error                        { return nil }
+func (nopBatch) Reset()                              {}
+func (nopBatch) Replay(w ethdb.KeyValueWriter) error { return nil }
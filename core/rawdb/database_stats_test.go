@@ -0,0 +1,125 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+// waitStats waits until the background inspection completes a pass.
+func waitStats(t *testing.T, db ethdb.Database) *DatabaseStats {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if stats := LiveDatabaseStats(db); stats != nil {
+			return stats
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("database inspection did not complete")
+	return nil
+}
+
+func findCategory(stats *DatabaseStats, name string) CategoryStats {
+	for _, c := range stats.Categories {
+		if c.Name == name {
+			return c
+		}
+	}
+	return CategoryStats{}
+}
+
+func TestDatabaseStatsTracking(t *testing.T) {
+	kvdb := memorydb.New()
+	for i := uint64(0); i < 10; i++ {
+		WriteHeader(kvdb, &types.Header{Number: new(big.Int).SetUint64(i), Extra: []byte("test")})
+	}
+	db, err := Open(kvdb, OpenOptions{TrackStats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stats := waitStats(t, db)
+	if have := findCategory(stats, "headers").Count; have != 10 {
+		t.Fatalf("wrong header count: have %d, want 10", have)
+	}
+	// Apply writes through batches and direct operations, ensure they are tracked
+	batch := db.NewBatch()
+	for i := uint64(10); i < 15; i++ {
+		WriteHeader(batch, &types.Header{Number: new(big.Int).SetUint64(i), Extra: []byte("test")})
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	WriteCode(db, common.Hash{0x1}, []byte{0x1, 0x2, 0x3})
+	header := &types.Header{Number: big.NewInt(0), Extra: []byte("test")}
+	DeleteHeader(db, header.Hash(), 0)
+
+	stats = LiveDatabaseStats(db)
+	if have := findCategory(stats, "headers").Count; have != 14 {
+		t.Fatalf("wrong header count after writes: have %d, want 14", have)
+	}
+	if have := findCategory(stats, "codes"); have.Count != 1 || have.Size != uint64(len(codeKey(common.Hash{0x1}))+3) {
+		t.Fatalf("wrong code stats after writes: have %+v", have)
+	}
+	// The statistics of the last completed pass should be persisted
+	persisted := ReadDatabaseStats(db)
+	if persisted == nil {
+		t.Fatal("statistics not persisted")
+	}
+	if have := findCategory(persisted, "headers").Count; have != 10 {
+		t.Fatalf("wrong persisted header count: have %d, want 10", have)
+	}
+}
+
+func TestDatabaseStatsResume(t *testing.T) {
+	kvdb := memorydb.New()
+	for i := uint64(0); i < statsScanChunk+10; i++ {
+		WriteCode(kvdb, common.BigToHash(new(big.Int).SetUint64(i)), []byte{0x1})
+	}
+	// Simulate an interrupted pass by persisting a partial journal: the first
+	// half of the codes were visited already (and accounted twice for clarity).
+	cursor := codeKey(common.BigToHash(big.NewInt(statsScanChunk / 2)))
+	var scan statsSet
+	for i := 0; i <= statsScanChunk/2; i++ {
+		scan[categoryCodes].add(common.StorageSize(2 * (len(cursor) + 1)))
+	}
+	writeStatsJournal(kvdb, &statsJournal{Scan: scan.export(), Cursor: cursor})
+
+	db, err := Open(kvdb, OpenOptions{TrackStats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stats := waitStats(t, db)
+	codes := findCategory(stats, "codes")
+	if codes.Count != statsScanChunk+10 {
+		t.Fatalf("wrong code count: have %d, want %d", codes.Count, statsScanChunk+10)
+	}
+	// The resumed pass must have kept the sizes accumulated before the restart
+	if want := uint64((statsScanChunk + 10 + statsScanChunk/2 + 1) * (len(cursor) + 1)); codes.Size != want {
+		t.Fatalf("wrong code size: have %d, want %d", codes.Size, want)
+	}
+}
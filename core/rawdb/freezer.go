@@ -211,6 +211,22 @@ func (f *Freezer) AncientBytes(kind string, id, offset, length uint64) ([]byte,
 	return nil, errUnknownTable
 }
 
+// RewriteAncient overwrites an existing item of the given table in place. It's
+// only meant for restoring corrupted items, the content must have the exact
+// encoded size of the stored item.
+func (f *Freezer) RewriteAncient(kind string, number uint64, blob []byte) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	if table := f.tables[kind]; table != nil {
+		return table.rewriteItem(number, blob)
+	}
+	return errUnknownTable
+}
+
 // Ancients returns the length of the frozen items.
 func (f *Freezer) Ancients() (uint64, error) {
 	return f.frozen.Load(), nil
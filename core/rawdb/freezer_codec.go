@@ -0,0 +1,220 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// freezerCodec identifies the compression algorithm applied to the items of a
+// compressed freezer table. The codec of a table is recorded in its metadata,
+// new tables use snappy until they are migrated to another codec.
+type freezerCodec uint8
+
+const (
+	codecSnappy freezerCodec = 0 // Snappy block format, used by all legacy tables
+	codecZstd   freezerCodec = 1 // Zstd frames, optionally with a trained dictionary
+)
+
+const (
+	// freezerDictSize is the maximum size of a trained zstd dictionary.
+	freezerDictSize = 64 * 1024
+
+	// freezerDictSamples is the maximum number of items sampled from a table
+	// to train a zstd dictionary.
+	freezerDictSamples = 4096
+)
+
+// String implements fmt.Stringer.
+func (c freezerCodec) String() string {
+	switch c {
+	case codecSnappy:
+		return "snappy"
+	case codecZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// parseFreezerCodec resolves a codec from its name.
+func parseFreezerCodec(name string) (freezerCodec, error) {
+	switch name {
+	case "snappy":
+		return codecSnappy, nil
+	case "zstd":
+		return codecZstd, nil
+	default:
+		return 0, fmt.Errorf("unknown freezer codec %q, supported ones: snappy, zstd", name)
+	}
+}
+
+// itemCodec compresses and decompresses the items of a freezer table.
+type itemCodec interface {
+	// encode compresses the given item, reusing the dst buffer if possible.
+	encode(dst, src []byte) []byte
+
+	// decode decompresses the given item.
+	decode(src []byte) ([]byte, error)
+
+	// decodedLen returns the length of the decompressed item.
+	decodedLen(src []byte) (int, error)
+
+	// close releases the resources held by the codec.
+	close() error
+}
+
+// newItemCodec creates the codec for the given compression algorithm and the
+// optional dictionary.
+func newItemCodec(codec freezerCodec, dict []byte) (itemCodec, error) {
+	switch codec {
+	case codecSnappy:
+		if len(dict) != 0 {
+			return nil, errors.New("snappy does not support dictionaries")
+		}
+		return snappyCodec{}, nil
+	case codecZstd:
+		return newZstdCodec(dict)
+	default:
+		return nil, fmt.Errorf("unknown freezer codec %d", codec)
+	}
+}
+
+// snappyCodec compresses items in the snappy block format.
+type snappyCodec struct{}
+
+func (snappyCodec) encode(dst, src []byte) []byte {
+	// The snappy library does not care what the capacity of the buffer is,
+	// but only checks the length. If the length is too small, it will
+	// allocate a brand new buffer.
+	// To avoid that, we check the required size here, and grow the size of the
+	// buffer to utilize the full capacity.
+	if n := snappy.MaxEncodedLen(len(src)); len(dst) < n {
+		if cap(dst) < n {
+			dst = make([]byte, n)
+		}
+		dst = dst[:n]
+	}
+	return snappy.Encode(dst, src)
+}
+
+func (snappyCodec) decode(src []byte) ([]byte, error) {
+	return snappy.Decode(nil, src)
+}
+
+func (snappyCodec) decodedLen(src []byte) (int, error) {
+	return snappy.DecodedLen(src)
+}
+
+func (snappyCodec) close() error { return nil }
+
+// zstdCodec compresses items as zstd frames, optionally using a dictionary
+// trained on the content of the table.
+type zstdCodec struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+func newZstdCodec(dict []byte) (*zstdCodec, error) {
+	encOpts := []zstd.EOption{
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithEncoderLevel(zstd.SpeedBetterCompression),
+		zstd.WithEncoderCRC(false), // Items are verified on a higher level
+	}
+	decOpts := []zstd.DOption{
+		zstd.WithDecoderConcurrency(0),
+	}
+	if len(dict) != 0 {
+		encOpts = append(encOpts, zstd.WithEncoderDict(dict))
+		decOpts = append(decOpts, zstd.WithDecoderDicts(dict))
+	}
+	enc, err := zstd.NewWriter(nil, encOpts...)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil, decOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCodec{enc: enc, dec: dec}, nil
+}
+
+func (c *zstdCodec) encode(dst, src []byte) []byte {
+	return c.enc.EncodeAll(src, dst[:0])
+}
+
+func (c *zstdCodec) decode(src []byte) ([]byte, error) {
+	return c.dec.DecodeAll(src, nil)
+}
+
+func (c *zstdCodec) decodedLen(src []byte) (int, error) {
+	var header zstd.Header
+	if err := header.Decode(src); err != nil {
+		return 0, err
+	}
+	if header.HasFCS {
+		return int(header.FrameContentSize), nil
+	}
+	// Content size is omitted for empty items, fall back to decoding
+	data, err := c.decode(src)
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// close stops the background goroutines of the decoder and releases the
+// buffers of both the encoder and the decoder.
+func (c *zstdCodec) close() error {
+	c.dec.Close()
+	return c.enc.Close()
+}
+
+// trainFreezerDict builds a zstd dictionary from the given item samples. The
+// most recent samples are expected at the end of the list, they are favoured
+// as dictionary content.
+func trainFreezerDict(samples [][]byte) (dict []byte, err error) {
+	// The dictionary builder panics if the samples contain too few matches to
+	// derive the entropy tables from, report it as a regular failure instead.
+	defer func() {
+		if r := recover(); r != nil {
+			dict, err = nil, fmt.Errorf("failed to build dictionary: %v", r)
+		}
+	}()
+	var history []byte
+	for i := len(samples) - 1; i >= 0 && len(history) < freezerDictSize; i-- {
+		history = append(history, samples[i]...)
+	}
+	if len(history) > freezerDictSize {
+		history = history[:freezerDictSize]
+	}
+	if len(history) < 8 {
+		return nil, errors.New("not enough data to train a dictionary")
+	}
+	return zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       crc32.ChecksumIEEE(history) | 1, // Zero is reserved for no dictionary
+		Contents: samples,
+		History:  history,
+		Offsets:  [3]int{1, 4, 8},
+		Level:    zstd.SpeedBetterCompression,
+	})
+}
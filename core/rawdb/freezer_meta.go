@@ -29,7 +29,8 @@ import (
 const (
 	freezerTableV1 = 1              // Initial version of metadata struct
 	freezerTableV2 = 2              // Add field: 'flushOffset'
-	freezerVersion = freezerTableV2 // The current used version
+	freezerTableV3 = 3              // Add fields: 'codec', 'dict'
+	freezerVersion = freezerTableV3 // The current used version
 )
 
 // freezerTableMeta is a collection of additional properties that describe the
@@ -65,6 +66,12 @@ type freezerTableMeta struct {
 	// The offset could be moved forward by applying sync operation, or be moved
 	// backward in cases of head/tail truncation, etc.
 	flushOffset int64
+
+	// codec is the compression algorithm of the items in a compressed table and
+	// dict is the optional dictionary used by it. Tables with the legacy snappy
+	// codec keep using the v2 format to stay readable by older releases.
+	codec freezerCodec
+	dict  []byte
 }
 
 // decodeV1 attempts to decode the metadata structure in v1 format. If fails or
@@ -123,6 +130,41 @@ func decodeV2(file *os.File) *freezerTableMeta {
 	}
 }
 
+// decodeV3 attempts to decode the metadata structure in v3 format. If fails or
+// the result is incompatible, nil is returned.
+func decodeV3(file *os.File) *freezerTableMeta {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil
+	}
+	type obj struct {
+		Version uint16
+		Tail    uint64
+		Offset  uint64
+		Codec   uint8
+		Dict    []byte
+	}
+	var o obj
+	if err := rlp.Decode(file, &o); err != nil {
+		return nil
+	}
+	if o.Version != freezerTableV3 {
+		return nil
+	}
+	if o.Offset > math.MaxInt64 {
+		log.Error("Invalid flushOffset %d in freezer metadata", o.Offset, "file", file.Name())
+		return nil
+	}
+	return &freezerTableMeta{
+		file:        file,
+		version:     freezerTableV3,
+		virtualTail: o.Tail,
+		flushOffset: int64(o.Offset),
+		codec:       freezerCodec(o.Codec),
+		dict:        o.Dict,
+	}
+}
+
 // newMetadata initializes the metadata object, either by loading it from the file
 // or by constructing a new one from scratch.
 func newMetadata(file *os.File) (*freezerTableMeta, error) {
@@ -142,6 +184,9 @@ func newMetadata(file *os.File) (*freezerTableMeta, error) {
 		}
 		return m, nil
 	}
+	if m := decodeV3(file); m != nil {
+		return m, nil
+	}
 	if m := decodeV2(file); m != nil {
 		return m, nil
 	}
@@ -163,24 +208,50 @@ func (m *freezerTableMeta) setFlushOffset(offset int64, sync bool) error {
 	return m.write(sync)
 }
 
+// setCodec sets the codec along with its dictionary and flushes the metadata.
+func (m *freezerTableMeta) setCodec(codec freezerCodec, dict []byte) error {
+	m.codec, m.dict = codec, dict
+	return m.write(true)
+}
+
 // write flushes the content of metadata into file and performs a fsync if required.
 func (m *freezerTableMeta) write(sync bool) error {
-	type obj struct {
-		Version uint16
-		Tail    uint64
-		Offset  uint64
-	}
-	var o obj
-	o.Version = freezerVersion // forcibly use the current version
-	o.Tail = m.virtualTail
-	o.Offset = uint64(m.flushOffset)
-
 	_, err := m.file.Seek(0, io.SeekStart)
 	if err != nil {
 		return err
 	}
-	if err := rlp.Encode(m.file, &o); err != nil {
-		return err
+	// Tables using the legacy codec are stored in the v2 format, allowing them
+	// to be opened by releases without codec support.
+	if m.codec == codecSnappy && len(m.dict) == 0 {
+		type obj struct {
+			Version uint16
+			Tail    uint64
+			Offset  uint64
+		}
+		var o obj
+		o.Version = freezerTableV2
+		o.Tail = m.virtualTail
+		o.Offset = uint64(m.flushOffset)
+		if err := rlp.Encode(m.file, &o); err != nil {
+			return err
+		}
+	} else {
+		type obj struct {
+			Version uint16
+			Tail    uint64
+			Offset  uint64
+			Codec   uint8
+			Dict    []byte
+		}
+		var o obj
+		o.Version = freezerVersion // forcibly use the current version
+		o.Tail = m.virtualTail
+		o.Offset = uint64(m.flushOffset)
+		o.Codec = uint8(m.codec)
+		o.Dict = m.dict
+		if err := rlp.Encode(m.file, &o); err != nil {
+			return err
+		}
 	}
 	if !sync {
 		return nil
@@ -48,6 +48,32 @@ func TestReadWriteFreezerTableMeta(t *testing.T) {
 	}
 }
 
+func TestReadWriteFreezerTableMetaCodec(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "*")
+	if err != nil {
+		t.Fatalf("Failed to create file %v", err)
+	}
+	defer f.Close()
+
+	meta, err := newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to new metadata %v", err)
+	}
+	if err := meta.setCodec(codecZstd, []byte{0x1, 0x2, 0x3}); err != nil {
+		t.Fatalf("Failed to set codec %v", err)
+	}
+	meta, err = newMetadata(f)
+	if err != nil {
+		t.Fatalf("Failed to reload metadata %v", err)
+	}
+	if meta.version != freezerTableV3 {
+		t.Fatalf("Unexpected version field")
+	}
+	if meta.codec != codecZstd || string(meta.dict) != "\x01\x02\x03" {
+		t.Fatalf("Unexpected codec fields: %s %x", meta.codec, meta.dict)
+	}
+}
+
 func TestUpgradeMetadata(t *testing.T) {
 	f, err := os.CreateTemp(t.TempDir(), "*")
 	if err != nil {
@@ -0,0 +1,245 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gofrs/flock"
+)
+
+// migrateBatchBytes is the maximum amount of data retrieved from the source
+// table in one go during the migration.
+const migrateBatchBytes = 16 * 1024 * 1024
+
+// MigrateFreezerTable re-encodes all items of a compressed freezer table with
+// the given codec. For zstd, a dictionary is trained from items sampled across
+// the table. The migrated table is assembled next to the original one and only
+// swapped into place once completely written, the tail of the table is retained.
+//
+// The freezer must not be in use by any other process while migrating.
+func MigrateFreezerTable(ancient string, freezerName string, tableName string, codecName string) error {
+	path, config, err := resolveFreezerTable(ancient, freezerName, tableName)
+	if err != nil {
+		return err
+	}
+	if config.noSnappy {
+		return fmt.Errorf("table %s is not compressed", tableName)
+	}
+	codec, err := parseFreezerCodec(codecName)
+	if err != nil {
+		return err
+	}
+	// Prevent the freezer from being opened during the migration.
+	lock := flock.New(filepath.Join(path, "FLOCK"))
+	if locked, err := lock.TryLock(); err != nil {
+		return err
+	} else if !locked {
+		return errors.New("freezer is in use by another process")
+	}
+	defer lock.Unlock()
+
+	tmp, err := os.MkdirTemp(path, tableName+".migrate-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	start := time.Now()
+	before, after, err := migrateFreezerTable(path, tmp, tableName, config, codec)
+	if err != nil {
+		return err
+	}
+	if err := swapFreezerTable(path, tmp, tableName); err != nil {
+		return err
+	}
+	log.Info("Migrated freezer table", "table", tableName, "codec", codec,
+		"before", common.StorageSize(before), "after", common.StorageSize(after), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// migrateFreezerTable writes all items of the named table in path into a new
+// table in the migration directory, returning the size of both tables.
+func migrateFreezerTable(path string, migrated string, name string, config freezerTableConfig, codec freezerCodec) (uint64, uint64, error) {
+	src, err := newFreezerTable(path, name, config, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	tail, head := src.itemHidden.Load(), src.items.Load()
+	if tail > math.MaxUint32 {
+		return 0, 0, fmt.Errorf("table tail %d out of range", tail)
+	}
+	var dict []byte
+	if codec == codecZstd && head > tail {
+		if dict, err = sampleFreezerDict(src, tail, head); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := initMigratedTable(migrated, name, tail, codec, dict); err != nil {
+		return 0, 0, err
+	}
+	dst, err := newFreezerTable(migrated, name, config, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer dst.Close()
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		batch  = dst.newBatch()
+	)
+	log.Info("Migrating freezer table", "table", name, "codec", codec, "dict", len(dict), "tail", tail, "head", head)
+	for next := tail; next < head; {
+		items, err := src.RetrieveItems(next, head-next, migrateBatchBytes)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, item := range items {
+			if err := batch.AppendRaw(next, item); err != nil {
+				return 0, 0, err
+			}
+			next++
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Migrating freezer table", "table", name, "migrated", next-tail, "remaining", head-next, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := batch.commit(); err != nil {
+		return 0, 0, err
+	}
+	if err := dst.Sync(); err != nil {
+		return 0, 0, err
+	}
+	before, err := src.size()
+	if err != nil {
+		return 0, 0, err
+	}
+	after, err := dst.size()
+	if err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+// sampleFreezerDict trains a zstd dictionary from items sampled evenly across
+// the given range of the table.
+func sampleFreezerDict(t *freezerTable, tail, head uint64) ([]byte, error) {
+	step := (head - tail + freezerDictSamples - 1) / freezerDictSamples
+	var samples [][]byte
+	for i := tail; i < head; i += step {
+		item, err := t.Retrieve(i)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, item)
+	}
+	dict, err := trainFreezerDict(samples)
+	if err != nil {
+		// Tables with too little content are compressed without dictionary
+		log.Warn("Failed to train freezer dictionary", "table", t.name, "err", err)
+		return nil, nil
+	}
+	return dict, nil
+}
+
+// initMigratedTable creates the index and metadata files of an empty table
+// starting at the given tail and using the given codec.
+func initMigratedTable(path string, name string, tail uint64, codec freezerCodec, dict []byte) error {
+	entry := indexEntry{filenum: 0, offset: uint32(tail)}
+	if err := os.WriteFile(filepath.Join(path, name+".cidx"), entry.append(nil), 0644); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(path, name+".meta"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	meta, err := newMetadata(file)
+	if err != nil {
+		return err
+	}
+	meta.virtualTail = tail
+	meta.flushOffset = indexEntrySize
+	return meta.setCodec(codec, dict)
+}
+
+// swapFreezerTable replaces the files of the named table in path with the ones
+// in the migration directory.
+func swapFreezerTable(path string, migrated string, name string) error {
+	old, err := freezerTableFiles(path, name)
+	if err != nil {
+		return err
+	}
+	files, err := freezerTableFiles(migrated, name)
+	if err != nil {
+		return err
+	}
+	// Move the original files aside first, so that they can be recovered
+	// manually if the swap is interrupted.
+	backup, err := os.MkdirTemp(path, name+".backup-")
+	if err != nil {
+		return err
+	}
+	for _, file := range old {
+		if err := os.Rename(filepath.Join(path, file), filepath.Join(backup, file)); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := os.Rename(filepath.Join(migrated, file), filepath.Join(path, file)); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(backup)
+}
+
+// freezerTableFiles returns the names of the index, metadata and data files
+// of the named compressed table in the given directory.
+func freezerTableFiles(path string, name string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		file := entry.Name()
+		switch {
+		case file == name+".cidx", file == name+".meta":
+			files = append(files, file)
+		case strings.HasPrefix(file, name+".") && strings.HasSuffix(file, ".cdat"):
+			num := strings.TrimSuffix(strings.TrimPrefix(file, name+"."), ".cdat")
+			if _, err := strconv.ParseUint(num, 10, 32); err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
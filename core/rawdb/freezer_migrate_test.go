@@ -0,0 +1,106 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// migrationItem returns a compressible test item resembling real chain data.
+func migrationItem(i int) []byte {
+	return []byte(fmt.Sprintf("item %d: {\"from\":\"0x%040x\",\"value\":\"0x%x\",\"gas\":21000}", i, i*7, i*1000))
+}
+
+func TestFreezerMigrate(t *testing.T) {
+	ancient := t.TempDir()
+	path := resolveChainFreezerDir(ancient)
+	config := chainFreezerTableConfigs[ChainFreezerBodiesTable]
+
+	// Create a legacy snappy table spanning multiple files with a pruned tail
+	f, err := newTable(path, ChainFreezerBodiesTable, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 64*1024, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := f.newBatch()
+	for i := 0; i < 5000; i++ {
+		if err := batch.AppendRaw(uint64(i), migrationItem(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.truncateTail(1000); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, codec := range []string{"zstd", "snappy"} {
+		if err := MigrateFreezerTable(ancient, ChainFreezerName, ChainFreezerBodiesTable, codec); err != nil {
+			t.Fatalf("migration to %s failed: %v", codec, err)
+		}
+		f, err := newFreezerTable(path, ChainFreezerBodiesTable, config, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.metadata.codec.String() != codec {
+			t.Fatalf("codec mismatch, want %s, got %s", codec, f.metadata.codec)
+		}
+		if codec == "zstd" && len(f.metadata.dict) == 0 {
+			t.Fatal("dictionary missing")
+		}
+		if tail, head := f.itemHidden.Load(), f.items.Load(); tail != 1000 || head != 5000 {
+			t.Fatalf("range mismatch, want [1000, 5000), got [%d, %d)", tail, head)
+		}
+		for i := 1000; i < 5000; i++ {
+			got, err := f.Retrieve(uint64(i))
+			if err != nil {
+				t.Fatalf("codec %s: reading item %d: %v", codec, i, err)
+			}
+			if !bytes.Equal(got, migrationItem(i)) {
+				t.Fatalf("codec %s: item %d mismatch, got %q", codec, i, got)
+			}
+		}
+		// The table should remain writable after migration
+		batch := f.newBatch()
+		if err := batch.AppendRaw(5000, migrationItem(5000)); err != nil {
+			t.Fatal(err)
+		}
+		if err := batch.commit(); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.truncateHead(5000); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+	// No leftovers of the migration should remain
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			t.Fatalf("leftover directory %s", entry.Name())
+		}
+	}
+}
@@ -32,7 +32,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
-	"github.com/golang/snappy"
 )
 
 var (
@@ -46,6 +45,10 @@ var (
 
 	// errNotSupported is returned if the database doesn't support the required operation.
 	errNotSupported = errors.New("this operation is not supported")
+
+	// errItemSizeMismatch is returned if an item can't be rewritten in place as
+	// its encoded size differs from the stored one.
+	errItemSizeMismatch = errors.New("item size mismatch")
 )
 
 // indexEntry contains the number/id of the file that the data resides in, as well as the
@@ -87,7 +90,7 @@ func (i *indexEntry) bounds(end *indexEntry) (startOffset, endOffset, fileId uin
 }
 
 // freezerTable represents a single chained data table within the freezer (e.g. blocks).
-// It consists of a data file (snappy encoded arbitrary data blobs) and an indexEntry
+// It consists of a data file (raw or compressed arbitrary data blobs) and an indexEntry
 // file (uncompressed 64 bit indices into the data file).
 type freezerTable struct {
 	items      atomic.Uint64 // Number of items stored in the table (including items removed from tail)
@@ -101,6 +104,7 @@ type freezerTable struct {
 	itemHidden atomic.Uint64
 
 	config      freezerTableConfig // table configuration (compression, prunability). Note: compression flag does not apply retroactively to existing files
+	codec       itemCodec          // codec of the compressed items as recorded in the metadata, nil for raw tables
 	readonly    bool
 	maxFileSize uint32 // Max file size for data-files
 	name        string
@@ -191,6 +195,12 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 		readonly:    readonly,
 		maxFileSize: maxFilesize,
 	}
+	if !config.noSnappy {
+		if tab.codec, err = newItemCodec(metadata.codec, metadata.dict); err != nil {
+			tab.Close()
+			return nil, err
+		}
+	}
 	if err := tab.repair(); err != nil {
 		tab.Close()
 		return nil, err
@@ -857,6 +867,12 @@ func (t *freezerTable) Close() error {
 	for _, f := range t.files {
 		doClose(f)
 	}
+	if t.codec != nil {
+		if err := t.codec.close(); err != nil {
+			errs = append(errs, err)
+		}
+		t.codec = nil
+	}
 	t.index = nil
 	t.head = nil
 	t.metadata.file = nil
@@ -988,14 +1004,14 @@ func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, e
 		item := diskData[offset : offset+diskSize]
 		offset += diskSize
 		decompressedSize := diskSize
-		if !t.config.noSnappy {
-			decompressedSize, _ = snappy.DecodedLen(item)
+		if t.codec != nil {
+			decompressedSize, _ = t.codec.decodedLen(item)
 		}
 		if i > 0 && maxBytes != 0 && uint64(outputSize+decompressedSize) > maxBytes {
 			break
 		}
-		if !t.config.noSnappy {
-			data, err := snappy.Decode(nil, item)
+		if t.codec != nil {
+			data, err := t.codec.decode(item)
 			if err != nil {
 				return nil, err
 			}
@@ -1161,7 +1177,7 @@ func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error
 		}
 		t.readMeter.Mark(int64(itemSize))
 
-		data, err := snappy.Decode(nil, buf)
+		data, err := t.codec.decode(buf)
 		if err != nil {
 			return nil, err
 		}
@@ -1172,6 +1188,57 @@ func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error
 	}
 }
 
+// rewriteItem overwrites an existing item in place with the given content. It's
+// meant for restoring corrupted items, therefore the index is left untouched and
+// the operation fails if the encoded content doesn't exactly fit the stored one.
+func (t *freezerTable) rewriteItem(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.head == nil || t.metadata.file == nil {
+		return errClosed
+	}
+	if t.readonly {
+		return errReadOnly
+	}
+	if item < t.itemHidden.Load() || item >= t.items.Load() {
+		return errOutOfBounds
+	}
+	indices, err := t.getIndices(item, 1)
+	if err != nil {
+		return err
+	}
+	startOffset, endOffset, filenum := indices[0].bounds(indices[1])
+	if startOffset > endOffset {
+		return fmt.Errorf("corrupted index entries of item %d: %d > %d", item, startOffset, endOffset)
+	}
+	data := blob
+	if t.codec != nil {
+		data = t.codec.encode(nil, blob)
+	}
+	if size := uint32(len(data)); size != endOffset-startOffset {
+		return fmt.Errorf("%w: item %d has %d bytes, stored %d", errItemSizeMismatch, item, size, endOffset-startOffset)
+	}
+	// Data files other than the head are opened read-only, use a dedicated
+	// writable handle for the rewrite.
+	var name string
+	if t.config.noSnappy {
+		name = fmt.Sprintf("%s.%04d.rdat", t.name, filenum)
+	} else {
+		name = fmt.Sprintf("%s.%04d.cdat", t.name, filenum)
+	}
+	file, err := os.OpenFile(filepath.Join(t.path, name), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteAt(data, int64(startOffset)); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
 // size returns the total data size in the freezer table.
 func (t *freezerTable) size() (uint64, error) {
 	t.lock.RLock()
@@ -19,6 +19,7 @@ package rawdb
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -288,6 +289,117 @@ func TestSnappyDetection(t *testing.T) {
 	}
 }
 
+// newCodecTable creates an empty compressed table using the given codec.
+func newCodecTable(t *testing.T, dir string, name string, codec freezerCodec) *freezerTable {
+	t.Helper()
+
+	if codec != codecSnappy {
+		if err := initMigratedTable(dir, name, 0, codec, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := newTable(dir, name, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, freezerTableConfig{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// TestFreezerCodecs tests that items are retained by tables using the different
+// codecs, and that the codec of an existing table is retained when reopening it.
+func TestFreezerCodecs(t *testing.T) {
+	t.Parallel()
+	rm, wm, sg := metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+	dir := t.TempDir()
+
+	for _, codec := range []freezerCodec{codecSnappy, codecZstd} {
+		fname := fmt.Sprintf("codectest-%s", codec)
+
+		// Create the table with the codec and reopen it
+		f := newCodecTable(t, dir, fname, codec)
+		writeChunks(t, f, 255, 15)
+		f.Close()
+
+		f, err := newTable(dir, fname, rm, wm, sg, 50, freezerTableConfig{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.metadata.codec != codec {
+			t.Fatalf("codec %s: table codec changed to %s", codec, f.metadata.codec)
+		}
+		for y := 0; y < 255; y++ {
+			got, err := f.Retrieve(uint64(y))
+			if err != nil {
+				t.Fatalf("codec %s: reading item %d: %v", codec, y, err)
+			}
+			if !bytes.Equal(got, getChunk(15, y)) {
+				t.Fatalf("codec %s: item %d mismatch, got %x", codec, y, got)
+			}
+		}
+		got, err := f.RetrieveBytes(3, 5, 5)
+		if err != nil {
+			t.Fatalf("codec %s: partial read failed: %v", codec, err)
+		}
+		if !bytes.Equal(got, getChunk(5, 3)) {
+			t.Fatalf("codec %s: partial read mismatch, got %x", codec, got)
+		}
+		f.Close()
+	}
+}
+
+// TestFreezerRewriteItem tests that items can be overwritten in place as long as
+// their encoded size is retained.
+func TestFreezerRewriteItem(t *testing.T) {
+	t.Parallel()
+	for _, config := range []struct {
+		noSnappy bool
+		codec    freezerCodec
+	}{{noSnappy: true}, {codec: codecSnappy}, {codec: codecZstd}} {
+		var f *freezerTable
+		if config.noSnappy {
+			var err error
+			f, err = newTable(t.TempDir(), "rewrite", metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, freezerTableConfig{noSnappy: true}, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			f = newCodecTable(t, t.TempDir(), "rewrite", config.codec)
+		}
+		writeChunks(t, f, 10, 15)
+
+		// Overwrite an item in the middle of a data file with identical content
+		if err := f.rewriteItem(4, getChunk(15, 4)); err != nil {
+			t.Fatalf("rewrite failed: %v", err)
+		}
+		if err := f.rewriteItem(4, []byte("this item does not fit into the original slot")); !errors.Is(err, errItemSizeMismatch) {
+			t.Fatalf("unexpected error for different size: %v", err)
+		}
+		if err := f.rewriteItem(10, getChunk(15, 10)); !errors.Is(err, errOutOfBounds) {
+			t.Fatalf("unexpected error for missing item: %v", err)
+		}
+		if config.noSnappy {
+			// Raw items can be replaced with any content of the same size
+			if err := f.rewriteItem(4, getChunk(15, 0xaa)); err != nil {
+				t.Fatalf("rewrite failed: %v", err)
+			}
+		}
+		for i := 0; i < 10; i++ {
+			want := getChunk(15, i)
+			if i == 4 && config.noSnappy {
+				want = getChunk(15, 0xaa)
+			}
+			got, err := f.Retrieve(uint64(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("item %d mismatch: have %x, want %x", i, got, want)
+			}
+		}
+		f.Close()
+	}
+}
+
 func assertFileSize(f string, size int64) error {
 	stat, err := os.Stat(f)
 	if err != nil {
@@ -104,6 +104,13 @@ var (
 	// snapSyncStatusFlagKey flags that status of snap sync.
 	snapSyncStatusFlagKey = []byte("SnapSyncStatus")
 
+	// databaseStatsKey tracks the persisted statistics and the progress of the
+	// background database inspection.
+	databaseStatsKey = []byte("DatabaseStats")
+
+	// ancientScrubKey tracks the progress of the background ancient scrubber.
+	ancientScrubKey = []byte("AncientScrub")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td (deprecated)
@@ -0,0 +1,346 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package flatstate
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/errgroup"
+)
+
+// checkpointBytes is the amount of data written to a shard between two
+// persisted progress markers.
+const checkpointBytes = 64 * 1024 * 1024
+
+// StateIterator provides ordered iteration over the flat state of a root.
+type StateIterator interface {
+	// AccountIterator creates an account iterator positioned at start.
+	AccountIterator(root common.Hash, start common.Hash) (snapshot.AccountIterator, error)
+
+	// StorageIterator creates a storage iterator of the given account
+	// positioned at start.
+	StorageIterator(root common.Hash, account common.Hash, start common.Hash) (snapshot.StorageIterator, error)
+}
+
+// ExportConfig contains the parameters of a flat state export.
+type ExportConfig struct {
+	Root      common.Hash // State root to export
+	Dir       string      // Directory to place the shard files in
+	Shards    int         // Number of shards to split the account hash range into
+	Parallel  int         // Number of shards exported concurrently
+	Preimages bool        // Whether to export the available hash preimages
+}
+
+// progress is the persisted export progress of a shard, allowing an
+// interrupted export to be resumed.
+type progress struct {
+	Root     common.Hash `json:"root"`
+	Shards   uint64      `json:"shards"`
+	Next     common.Hash `json:"next"`     // Next account hash to export
+	Offset   int64       `json:"offset"`   // File size at the time of the checkpoint
+	Accounts uint64      `json:"accounts"` // Number of accounts exported before Next
+	Slots    uint64      `json:"slots"`    // Number of slots exported before Next
+	Done     bool        `json:"done"`
+}
+
+// ShardFile returns the name of the file the given shard is exported into.
+func ShardFile(shard int) string {
+	return fmt.Sprintf("shard-%03d.flat", shard)
+}
+
+// shardRange returns the account hash range covered by the given shard. The
+// limit of the last shard is the zero hash, denoting the end of the hash space.
+func shardRange(shard, shards int) (common.Hash, common.Hash) {
+	space := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	start := new(big.Int).Mul(space, big.NewInt(int64(shard)))
+	start.Div(start, big.NewInt(int64(shards)))
+
+	if shard == shards-1 {
+		return common.BigToHash(start), common.Hash{}
+	}
+	limit := new(big.Int).Mul(space, big.NewInt(int64(shard+1)))
+	limit.Div(limit, big.NewInt(int64(shards)))
+	return common.BigToHash(start), common.BigToHash(limit)
+}
+
+// incHash returns the hash following h, reporting whether it overflowed.
+func incHash(h common.Hash) (common.Hash, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			return h, false
+		}
+	}
+	return h, true
+}
+
+// Export writes the flat state of the configured root into sharded files. If
+// the target directory contains the progress of a previous, interrupted export
+// of the same root, the export is resumed from the last checkpoint.
+func Export(db ethdb.KeyValueReader, it StateIterator, config ExportConfig) error {
+	if config.Shards <= 0 {
+		return errors.New("invalid number of shards")
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return err
+	}
+	var (
+		start = time.Now()
+		eg    errgroup.Group
+	)
+	eg.SetLimit(max(config.Parallel, 1))
+	for shard := 0; shard < config.Shards; shard++ {
+		eg.Go(func() error {
+			if err := exportShard(db, it, config, shard); err != nil {
+				return fmt.Errorf("shard %d: %w", shard, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	log.Info("Exported flat state", "root", config.Root, "shards", config.Shards, "dir", config.Dir, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// exportShard exports the accounts of a single shard, resuming a previous run
+// if its progress has been persisted.
+func exportShard(db ethdb.KeyValueReader, it StateIterator, config ExportConfig, shard int) error {
+	var (
+		path           = filepath.Join(config.Dir, ShardFile(shard))
+		start, limit   = shardRange(shard, config.Shards)
+		prog, err      = readProgress(path)
+		file           *os.File
+		lastLog        = time.Now()
+		startTime      = time.Now()
+		lastCheckpoint int64
+	)
+	if err != nil {
+		return err
+	}
+	if prog != nil {
+		if prog.Root != config.Root || prog.Shards != uint64(config.Shards) {
+			return fmt.Errorf("existing export of root %x with %d shards", prog.Root, prog.Shards)
+		}
+		if prog.Done {
+			log.Info("Skipping exported shard", "shard", shard, "accounts", prog.Accounts, "slots", prog.Slots)
+			return nil
+		}
+		if file, err = os.OpenFile(path, os.O_RDWR, 0644); err != nil {
+			return err
+		}
+		if err := file.Truncate(prog.Offset); err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Seek(prog.Offset, io.SeekStart); err != nil {
+			file.Close()
+			return err
+		}
+		log.Info("Resuming shard export", "shard", shard, "next", prog.Next, "accounts", prog.Accounts, "slots", prog.Slots)
+	} else {
+		if file, err = os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0644); err != nil {
+			return err
+		}
+		prog = &progress{Root: config.Root, Shards: uint64(config.Shards), Next: start}
+	}
+	defer file.Close()
+
+	var (
+		buf    = bufio.NewWriter(file)
+		writer = NewWriter(buf)
+		base   = prog.Offset
+	)
+	// checkpoint flushes the written data to disk and persists the progress
+	// marker pointing past it.
+	checkpoint := func() error {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			return err
+		}
+		prog.Offset = base + writer.Written()
+		lastCheckpoint = writer.Written()
+		return writeProgress(path, prog)
+	}
+	if base == 0 {
+		header := &Header{
+			Version: Version,
+			Root:    config.Root,
+			Shard:   uint64(shard),
+			Shards:  uint64(config.Shards),
+			Start:   start,
+			Limit:   limit,
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		if err := checkpoint(); err != nil {
+			return err
+		}
+	}
+	accIt, err := it.AccountIterator(config.Root, prog.Next)
+	if err != nil {
+		return err
+	}
+	defer accIt.Release()
+
+	var (
+		codes    = make(map[common.Hash]struct{})
+		accounts = prog.Accounts
+		slots    = prog.Slots
+	)
+	for accIt.Next() {
+		hash := accIt.Hash()
+		if limit != (common.Hash{}) && hash.Cmp(limit) >= 0 {
+			break
+		}
+		account, err := types.FullAccount(accIt.Account())
+		if err != nil {
+			return fmt.Errorf("invalid account %x: %w", hash, err)
+		}
+		if config.Preimages {
+			if err := writePreimage(db, writer, hash); err != nil {
+				return err
+			}
+		}
+		codeHash := common.BytesToHash(account.CodeHash)
+		if codeHash != types.EmptyCodeHash {
+			if _, ok := codes[codeHash]; !ok {
+				code := rawdb.ReadCode(db, codeHash)
+				if len(code) == 0 {
+					return fmt.Errorf("missing code %x of account %x", codeHash, hash)
+				}
+				if err := writer.WriteRecord(KindCode, codeHash, code); err != nil {
+					return err
+				}
+				codes[codeHash] = struct{}{}
+			}
+		}
+		if err := writer.WriteRecord(KindAccount, hash, accIt.Account()); err != nil {
+			return err
+		}
+		accounts++
+
+		if account.Root != types.EmptyRootHash {
+			stIt, err := it.StorageIterator(config.Root, hash, common.Hash{})
+			if err != nil {
+				return err
+			}
+			for stIt.Next() {
+				if config.Preimages {
+					if err := writePreimage(db, writer, stIt.Hash()); err != nil {
+						stIt.Release()
+						return err
+					}
+				}
+				if err := writer.WriteRecord(KindStorage, stIt.Hash(), stIt.Slot()); err != nil {
+					stIt.Release()
+					return err
+				}
+				slots++
+			}
+			err = stIt.Error()
+			stIt.Release()
+			if err != nil {
+				return fmt.Errorf("storage iteration of account %x failed: %w", hash, err)
+			}
+		}
+		// Persist the progress at account boundaries once enough data has been
+		// written since the last checkpoint.
+		if writer.Written()-lastCheckpoint >= checkpointBytes {
+			if next, overflow := incHash(hash); !overflow {
+				prog.Next, prog.Accounts, prog.Slots = next, accounts, slots
+				if err := checkpoint(); err != nil {
+					return err
+				}
+			}
+		}
+		if time.Since(lastLog) > 8*time.Second {
+			log.Info("Exporting flat state", "shard", shard, "at", hash, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(startTime)))
+			lastLog = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return fmt.Errorf("account iteration failed: %w", err)
+	}
+	if err := writer.WriteEnd(accounts, slots); err != nil {
+		return err
+	}
+	prog.Accounts, prog.Slots, prog.Done = accounts, slots, true
+	if err := checkpoint(); err != nil {
+		return err
+	}
+	log.Info("Exported shard", "shard", shard, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(startTime)))
+	return nil
+}
+
+// writePreimage exports the preimage of the given hash if it's available.
+func writePreimage(db ethdb.KeyValueReader, writer *Writer, hash common.Hash) error {
+	preimage := rawdb.ReadPreimage(db, hash)
+	if len(preimage) == 0 {
+		return nil
+	}
+	return writer.WriteRecord(KindPreimage, hash, preimage)
+}
+
+// readProgress loads the persisted progress of the given shard file, returning
+// nil if the shard has not been started yet.
+func readProgress(path string) (*progress, error) {
+	blob, err := os.ReadFile(path + ".progress")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var prog progress
+	if err := json.Unmarshal(blob, &prog); err != nil {
+		return nil, fmt.Errorf("invalid progress file: %w", err)
+	}
+	return &prog, nil
+}
+
+// writeProgress atomically replaces the persisted progress of the given shard.
+func writeProgress(path string, prog *progress) error {
+	blob, err := json.Marshal(prog)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".progress.tmp"
+	if err := os.WriteFile(tmp, blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+".progress")
+}
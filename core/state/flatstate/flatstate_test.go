@@ -0,0 +1,216 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package flatstate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// testIterator exposes the flat state iterators of the path database.
+type testIterator struct {
+	db *triedb.Database
+}
+
+func (it *testIterator) AccountIterator(root common.Hash, start common.Hash) (snapshot.AccountIterator, error) {
+	return it.db.AccountIterator(root, start)
+}
+
+func (it *testIterator) StorageIterator(root common.Hash, account common.Hash, start common.Hash) (snapshot.StorageIterator, error) {
+	return it.db.StorageIterator(root, account, start)
+}
+
+func testAddress(i int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte{byte(i >> 8), byte(i)}))
+}
+
+func testSlot(i, j int) (common.Hash, common.Hash) {
+	return common.BigToHash(uint256.NewInt(uint64(j)).ToBig()), common.BigToHash(uint256.NewInt(uint64(i*1000 + j + 1)).ToBig())
+}
+
+// makeTestState creates a state with a mix of plain accounts, contracts and
+// storage, returning the database and the state root.
+func makeTestState(t *testing.T) (*triedb.Database, common.Hash) {
+	t.Helper()
+
+	tdb := triedb.NewDatabase(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true, PathDB: pathdb.Defaults})
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	for i := 0; i < 256; i++ {
+		addr := testAddress(i)
+		statedb.AddBalance(addr, uint256.NewInt(uint64(i+1)), tracing.BalanceChangeUnspecified)
+		statedb.SetNonce(addr, uint64(i), tracing.NonceChangeUnspecified)
+		if i%4 == 0 {
+			statedb.SetCode(addr, []byte{0x60, byte(i % 16), 0x00}, tracing.CodeChangeUnspecified)
+		}
+		for j := 0; j < i%7; j++ {
+			key, value := testSlot(i, j)
+			statedb.SetState(addr, key, value)
+		}
+	}
+	root, err := statedb.Commit(1, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return tdb, root
+}
+
+func shardFiles(dir string, shards int) []string {
+	var files []string
+	for i := 0; i < shards; i++ {
+		files = append(files, filepath.Join(dir, ShardFile(i)))
+	}
+	return files
+}
+
+// checkImport imports the shards into a fresh database and verifies the state.
+func checkImport(t *testing.T, files []string, root common.Hash) {
+	t.Helper()
+
+	db := rawdb.NewMemoryDatabase()
+	have, err := Import(db, rawdb.PathScheme, files)
+	if err != nil {
+		t.Fatalf("failed to import state: %v", err)
+	}
+	if have != root {
+		t.Fatalf("root mismatch: have %x, want %x", have, root)
+	}
+	tdb := triedb.NewDatabase(db, &triedb.Config{PathDB: pathdb.Defaults})
+	defer tdb.Close()
+
+	statedb, err := state.New(root, state.NewDatabase(tdb, nil))
+	if err != nil {
+		t.Fatalf("failed to open imported state: %v", err)
+	}
+	for i := 0; i < 256; i++ {
+		addr := testAddress(i)
+		if balance := statedb.GetBalance(addr); balance.Uint64() != uint64(i+1) {
+			t.Fatalf("account %d: balance mismatch: have %v, want %d", i, balance, i+1)
+		}
+		if i%4 == 0 && len(statedb.GetCode(addr)) != 3 {
+			t.Fatalf("account %d: code missing", i)
+		}
+		for j := 0; j < i%7; j++ {
+			key, value := testSlot(i, j)
+			if have := statedb.GetState(addr, key); have != value {
+				t.Fatalf("account %d slot %d: have %x, want %x", i, j, have, value)
+			}
+		}
+	}
+	if preimage := rawdb.ReadPreimage(db, crypto.Keccak256Hash(testAddress(1).Bytes())); !bytes.Equal(preimage, testAddress(1).Bytes()) {
+		t.Fatalf("preimage not imported: %x", preimage)
+	}
+	// The flat state should be usable by the iterators right away
+	accIt, err := tdb.AccountIterator(root, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer accIt.Release()
+
+	var accounts int
+	for accIt.Next() {
+		accounts++
+	}
+	if accounts != 256 {
+		t.Fatalf("flat state account count mismatch: have %d, want %d", accounts, 256)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	tdb, root := makeTestState(t)
+	defer tdb.Close()
+
+	dir := t.TempDir()
+	config := ExportConfig{Root: root, Dir: dir, Shards: 4, Parallel: 2, Preimages: true}
+	if err := Export(tdb.Disk(), &testIterator{tdb}, config); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	checkImport(t, shardFiles(dir, 4), root)
+
+	// An incomplete export must be rejected
+	if _, err := Import(rawdb.NewMemoryDatabase(), rawdb.PathScheme, shardFiles(dir, 3)); err == nil {
+		t.Fatal("import of incomplete export succeeded")
+	}
+	// A truncated shard must be rejected
+	path := filepath.Join(dir, ShardFile(2))
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, blob[:len(blob)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Import(rawdb.NewMemoryDatabase(), rawdb.PathScheme, shardFiles(dir, 4)); err == nil {
+		t.Fatal("import of truncated shard succeeded")
+	}
+}
+
+func TestExportResume(t *testing.T) {
+	tdb, root := makeTestState(t)
+	defer tdb.Close()
+
+	dir := t.TempDir()
+	config := ExportConfig{Root: root, Dir: dir, Shards: 4, Parallel: 1, Preimages: true}
+	if err := Export(tdb.Disk(), &testIterator{tdb}, config); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	// Rewind the progress of a shard to right after its header, leaving some
+	// garbage behind as if the export was interrupted.
+	var (
+		header       bytes.Buffer
+		start, limit = shardRange(1, 4)
+		path         = filepath.Join(dir, ShardFile(1))
+	)
+	if err := NewWriter(&header).WriteHeader(&Header{Version: Version, Root: root, Shard: 1, Shards: 4, Start: start, Limit: limit}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeProgress(path, &progress{Root: root, Shards: 4, Next: start, Offset: int64(header.Len())}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{byte(KindAccount), 0xde, 0xad})
+	f.Close()
+
+	if err := Export(tdb.Disk(), &testIterator{tdb}, config); err != nil {
+		t.Fatalf("failed to resume export: %v", err)
+	}
+	checkImport(t, shardFiles(dir, 4), root)
+
+	// Resuming with a different configuration must be rejected
+	config.Root = common.Hash{0x01}
+	if err := Export(tdb.Disk(), &testIterator{tdb}, config); err == nil {
+		t.Fatal("export with mismatching root resumed")
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package flatstate implements the export and import of the flat state at a
+// given state root in a simple, length-prefixed binary format.
+//
+// An export consists of one or more shard files, each covering a disjoint range
+// of account hashes. A shard file starts with the 8 byte magic "gethflat",
+// followed by the uvarint length-prefixed RLP encoding of the file header. The
+// header is followed by a sequence of records, each consisting of a one byte
+// kind, a 32 byte key and the uvarint length-prefixed value:
+//
+//   - account:  key is the account hash, value is the slim RLP encoding of the account
+//   - storage:  key is the slot hash, value is the RLP encoding of the slot value
+//   - code:     key is the code hash, value is the contract code
+//   - preimage: key is the hash, value is the preimage (address or slot key)
+//
+// Storage records directly follow the account they belong to, while code and
+// preimage records precede the account or slot they belong to. Accounts and the
+// storage slots of an account are sorted by hash. The file is terminated by an
+// end record, whose key is empty and its value holds the uvarint encoded number
+// of accounts and storage slots in the shard, which allows detecting incomplete
+// files.
+package flatstate
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Version is the current version of the export format.
+const Version = 1
+
+// magic is the file signature of a shard file.
+var magic = []byte("gethflat")
+
+// maxValueSize is the maximum accepted size of a record value, guarding
+// against allocating huge buffers when reading corrupted files.
+const maxValueSize = 64 * 1024 * 1024
+
+// Kind is the type of a record in a shard file.
+type Kind byte
+
+const (
+	KindEnd      Kind = 0x00 // End of the shard, value holds the item counts
+	KindAccount  Kind = 0x01 // Account in slim RLP encoding
+	KindStorage  Kind = 0x02 // Storage slot of the last account
+	KindCode     Kind = 0x03 // Contract code
+	KindPreimage Kind = 0x04 // Preimage of an account or slot hash
+)
+
+// String implements fmt.Stringer.
+func (k Kind) String() string {
+	switch k {
+	case KindEnd:
+		return "end"
+	case KindAccount:
+		return "account"
+	case KindStorage:
+		return "storage"
+	case KindCode:
+		return "code"
+	case KindPreimage:
+		return "preimage"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(k))
+	}
+}
+
+// Header describes the content of a shard file.
+type Header struct {
+	Version uint64
+	Root    common.Hash // State root the shard was exported from
+	Shard   uint64      // Index of the shard
+	Shards  uint64      // Total number of shards of the export
+	Start   common.Hash // First account hash covered by the shard
+	Limit   common.Hash // First account hash not covered, zero for the last shard
+}
+
+// Record is a single item of a shard file.
+type Record struct {
+	Kind  Kind
+	Key   common.Hash
+	Value []byte
+}
+
+// Writer encodes records into a shard file.
+type Writer struct {
+	w       io.Writer
+	written int64
+	buf     [binary.MaxVarintLen64]byte
+}
+
+// NewWriter creates a writer encoding the shard header and the records into w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Written returns the number of bytes written so far.
+func (w *Writer) Written() int64 {
+	return w.written
+}
+
+func (w *Writer) write(data []byte) error {
+	n, err := w.w.Write(data)
+	w.written += int64(n)
+	return err
+}
+
+func (w *Writer) writeValue(value []byte) error {
+	n := binary.PutUvarint(w.buf[:], uint64(len(value)))
+	if err := w.write(w.buf[:n]); err != nil {
+		return err
+	}
+	return w.write(value)
+}
+
+// WriteHeader writes the file signature and the header.
+func (w *Writer) WriteHeader(header *Header) error {
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return err
+	}
+	if err := w.write(magic); err != nil {
+		return err
+	}
+	return w.writeValue(enc)
+}
+
+// WriteRecord writes a single record.
+func (w *Writer) WriteRecord(kind Kind, key common.Hash, value []byte) error {
+	if err := w.write([]byte{byte(kind)}); err != nil {
+		return err
+	}
+	if err := w.write(key[:]); err != nil {
+		return err
+	}
+	return w.writeValue(value)
+}
+
+// WriteEnd terminates the shard with the number of exported items.
+func (w *Writer) WriteEnd(accounts, slots uint64) error {
+	value := binary.AppendUvarint(nil, accounts)
+	value = binary.AppendUvarint(value, slots)
+	return w.WriteRecord(KindEnd, common.Hash{}, value)
+}
+
+// Reader decodes the records of a shard file.
+type Reader struct {
+	r        *bufio.Reader
+	header   Header
+	accounts uint64
+	slots    uint64
+	done     bool
+}
+
+// NewReader creates a reader for the shard file, decoding the header.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+
+	sig := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.r, sig); err != nil {
+		return nil, err
+	}
+	if string(sig) != string(magic) {
+		return nil, errors.New("invalid file signature")
+	}
+	enc, err := reader.readValue()
+	if err != nil {
+		return nil, err
+	}
+	if err := rlp.DecodeBytes(enc, &reader.header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	if reader.header.Version != Version {
+		return nil, fmt.Errorf("unsupported version %d", reader.header.Version)
+	}
+	return reader, nil
+}
+
+// Header returns the header of the shard file.
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+func (r *Reader) readValue() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxValueSize {
+		return nil, fmt.Errorf("oversized value: %d bytes", size)
+	}
+	value := make([]byte, size)
+	if _, err := io.ReadFull(r.r, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Next decodes the next record. It returns io.EOF once the end record has been
+// reached and checked against the number of decoded items, and
+// io.ErrUnexpectedEOF if the file is truncated.
+func (r *Reader) Next() (*Record, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	kind, err := r.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	rec := &Record{Kind: Kind(kind)}
+	if _, err := io.ReadFull(r.r, rec.Key[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if rec.Value, err = r.readValue(); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	switch rec.Kind {
+	case KindAccount:
+		r.accounts++
+	case KindStorage:
+		r.slots++
+	case KindCode, KindPreimage:
+	case KindEnd:
+		accounts, n := binary.Uvarint(rec.Value)
+		if n <= 0 {
+			return nil, errors.New("invalid end record")
+		}
+		slots, m := binary.Uvarint(rec.Value[n:])
+		if m <= 0 {
+			return nil, errors.New("invalid end record")
+		}
+		if accounts != r.accounts || slots != r.slots {
+			return nil, fmt.Errorf("item count mismatch: have %d accounts, %d slots, want %d accounts, %d slots", r.accounts, r.slots, accounts, slots)
+		}
+		r.done = true
+		return nil, io.EOF
+	default:
+		return nil, fmt.Errorf("unknown record kind %d", kind)
+	}
+	return rec, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
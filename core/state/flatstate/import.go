@@ -0,0 +1,305 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package flatstate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// generator mirrors the snapshot generation marker shared by the hash-based
+// snapshot and the path database, which is persisted as completed once the
+// import finishes.
+type generator struct {
+	Wiping   bool
+	Done     bool
+	Marker   []byte
+	Accounts uint64
+	Slots    uint64
+	Storage  uint64
+}
+
+// shardReader is an opened shard file.
+type shardReader struct {
+	path   string
+	file   *os.File
+	reader *Reader
+}
+
+// importer rebuilds the state tries and the flat state from the records of
+// the shard files.
+type importer struct {
+	db     ethdb.Database
+	batch  ethdb.Batch
+	scheme string
+
+	accTrie  *trie.StackTrie
+	lastAcc  *common.Hash
+	storage  *trie.StackTrie // Storage trie of the last account, nil if empty
+	stRoot   common.Hash     // Storage root of the last account
+	lastSlot *common.Hash
+
+	codes   map[common.Hash]bool // Referenced codes, true if imported
+	pending int                  // Number of referenced but not yet imported codes
+
+	accounts uint64
+	slots    uint64
+	size     uint64
+}
+
+// Import rebuilds the state from the given shard files into the database,
+// using the provided state scheme for the trie nodes. The shards must cover the
+// entire account hash range of a single export. The state root is returned
+// after it has been verified against the exported one.
+func Import(db ethdb.Database, scheme string, files []string) (common.Hash, error) {
+	shards, err := openShards(files)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer func() {
+		for _, shard := range shards {
+			shard.file.Close()
+		}
+	}()
+	root := shards[0].reader.Header().Root
+
+	imp := &importer{
+		db:     db,
+		batch:  db.NewBatch(),
+		scheme: scheme,
+		codes:  make(map[common.Hash]bool),
+	}
+	imp.accTrie = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(imp.batch, common.Hash{}, path, hash, blob, scheme)
+	})
+	var (
+		start   = time.Now()
+		lastLog = time.Now()
+	)
+	for _, shard := range shards {
+		for {
+			rec, err := shard.reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return common.Hash{}, fmt.Errorf("%s: %w", shard.path, err)
+			}
+			if err := imp.process(rec); err != nil {
+				return common.Hash{}, fmt.Errorf("%s: %w", shard.path, err)
+			}
+			if imp.batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := imp.flush(); err != nil {
+					return common.Hash{}, err
+				}
+			}
+			if time.Since(lastLog) > 8*time.Second {
+				log.Info("Importing flat state", "accounts", imp.accounts, "slots", imp.slots, "elapsed", common.PrettyDuration(time.Since(start)))
+				lastLog = time.Now()
+			}
+		}
+	}
+	if err := imp.finishStorage(); err != nil {
+		return common.Hash{}, err
+	}
+	if imp.pending != 0 {
+		return common.Hash{}, fmt.Errorf("missing %d contract codes", imp.pending)
+	}
+	if have := imp.accTrie.Hash(); have != root {
+		return common.Hash{}, fmt.Errorf("state root mismatch: have %x, want %x", have, root)
+	}
+	// Mark the flat state as completely generated for the imported root
+	gen, err := rlp.EncodeToBytes(&generator{Done: true, Accounts: imp.accounts, Slots: imp.slots, Storage: imp.size})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	rawdb.WriteSnapshotRoot(imp.batch, root)
+	rawdb.WriteSnapshotGenerator(imp.batch, gen)
+	if err := imp.flush(); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Imported flat state", "root", root, "accounts", imp.accounts, "slots", imp.slots, "elapsed", common.PrettyDuration(time.Since(start)))
+	return root, nil
+}
+
+// openShards opens the given shard files, returning them ordered by the hash
+// range they cover after checking that they form a complete export.
+func openShards(files []string) ([]*shardReader, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no shard files")
+	}
+	var shards []*shardReader
+	fail := func(err error) ([]*shardReader, error) {
+		for _, shard := range shards {
+			shard.file.Close()
+		}
+		return nil, err
+	}
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return fail(err)
+		}
+		reader, err := NewReader(file)
+		if err != nil {
+			file.Close()
+			return fail(fmt.Errorf("%s: %w", path, err))
+		}
+		shards = append(shards, &shardReader{path: path, file: file, reader: reader})
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].reader.Header().Shard < shards[j].reader.Header().Shard
+	})
+	first := shards[0].reader.Header()
+	if first.Shards != uint64(len(shards)) {
+		return fail(fmt.Errorf("incomplete export: have %d shards, want %d", len(shards), first.Shards))
+	}
+	for i, shard := range shards {
+		header := shard.reader.Header()
+		if header.Root != first.Root || header.Shards != first.Shards {
+			return fail(fmt.Errorf("%s: shard of a different export", shard.path))
+		}
+		if header.Shard != uint64(i) {
+			return fail(fmt.Errorf("%s: duplicate shard %d", shard.path, header.Shard))
+		}
+		if i == 0 && header.Start != (common.Hash{}) {
+			return fail(fmt.Errorf("%s: first shard starts at %x", shard.path, header.Start))
+		}
+		if i > 0 && header.Start != shards[i-1].reader.Header().Limit {
+			return fail(fmt.Errorf("%s: gap before shard %d", shard.path, i))
+		}
+		if i == len(shards)-1 && header.Limit != (common.Hash{}) {
+			return fail(fmt.Errorf("%s: last shard ends at %x", shard.path, header.Limit))
+		}
+	}
+	return shards, nil
+}
+
+// process imports a single record.
+func (imp *importer) process(rec *Record) error {
+	switch rec.Kind {
+	case KindAccount:
+		if imp.lastAcc != nil && rec.Key.Cmp(*imp.lastAcc) <= 0 {
+			return fmt.Errorf("unordered account %x", rec.Key)
+		}
+		if err := imp.finishStorage(); err != nil {
+			return err
+		}
+		account, err := types.FullAccount(rec.Value)
+		if err != nil {
+			return fmt.Errorf("invalid account %x: %w", rec.Key, err)
+		}
+		full, err := types.FullAccountRLP(rec.Value)
+		if err != nil {
+			return fmt.Errorf("invalid account %x: %w", rec.Key, err)
+		}
+		if err := imp.accTrie.Update(rec.Key[:], full); err != nil {
+			return err
+		}
+		rawdb.WriteAccountSnapshot(imp.batch, rec.Key, rec.Value)
+
+		codeHash := common.BytesToHash(account.CodeHash)
+		if codeHash != types.EmptyCodeHash {
+			if _, ok := imp.codes[codeHash]; !ok {
+				imp.codes[codeHash] = false
+				imp.pending++
+			}
+		}
+		if account.Root != types.EmptyRootHash {
+			owner := rec.Key
+			imp.storage = trie.NewStackTrie(func(path []byte, hash common.Hash, blob []byte) {
+				rawdb.WriteTrieNode(imp.batch, owner, path, hash, blob, imp.scheme)
+			})
+			imp.stRoot = account.Root
+		}
+		key := rec.Key
+		imp.lastAcc, imp.lastSlot = &key, nil
+		imp.accounts++
+		imp.size += uint64(common.HashLength + len(rec.Value))
+
+	case KindStorage:
+		if imp.storage == nil {
+			return fmt.Errorf("storage slot %x without account storage", rec.Key)
+		}
+		if imp.lastSlot != nil && rec.Key.Cmp(*imp.lastSlot) <= 0 {
+			return fmt.Errorf("unordered storage slot %x of account %x", rec.Key, *imp.lastAcc)
+		}
+		if err := imp.storage.Update(rec.Key[:], rec.Value); err != nil {
+			return err
+		}
+		rawdb.WriteStorageSnapshot(imp.batch, *imp.lastAcc, rec.Key, rec.Value)
+
+		key := rec.Key
+		imp.lastSlot = &key
+		imp.slots++
+		imp.size += uint64(2*common.HashLength + len(rec.Value))
+
+	case KindCode:
+		if crypto.Keccak256Hash(rec.Value) != rec.Key {
+			return fmt.Errorf("code hash mismatch %x", rec.Key)
+		}
+		if imported, ok := imp.codes[rec.Key]; !ok || !imported {
+			if ok {
+				imp.pending--
+			}
+			imp.codes[rec.Key] = true
+			rawdb.WriteCode(imp.batch, rec.Key, rec.Value)
+		}
+
+	case KindPreimage:
+		if crypto.Keccak256Hash(rec.Value) != rec.Key {
+			return fmt.Errorf("preimage hash mismatch %x", rec.Key)
+		}
+		rawdb.WritePreimages(imp.batch, map[common.Hash][]byte{rec.Key: rec.Value})
+	}
+	return nil
+}
+
+// finishStorage completes the storage trie of the last account, verifying it
+// against the storage root of the account.
+func (imp *importer) finishStorage() error {
+	if imp.storage == nil {
+		return nil
+	}
+	if have := imp.storage.Hash(); have != imp.stRoot {
+		return fmt.Errorf("storage root mismatch of account %x: have %x, want %x", *imp.lastAcc, have, imp.stRoot)
+	}
+	imp.storage = nil
+	return nil
+}
+
+// flush writes the accumulated batch into the database.
+func (imp *importer) flush() error {
+	if err := imp.batch.Write(); err != nil {
+		return err
+	}
+	imp.batch.Reset()
+	return nil
+}
@@ -17,6 +17,7 @@
 package stateless
 
 import (
+	"errors"
 	"io"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -55,6 +56,18 @@ func (w *Witness) fromExtWitness(ext *ExtWitness) error {
 	return nil
 }
 
+// ToWitness converts the consensus witness format into our internal one.
+func (ext *ExtWitness) ToWitness() (*Witness, error) {
+	if len(ext.Headers) == 0 {
+		return nil, errors.New("witness without parent header")
+	}
+	w := new(Witness)
+	if err := w.fromExtWitness(ext); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 // EncodeRLP serializes a witness as RLP.
 func (w *Witness) EncodeRLP(wr io.Writer) error {
 	return rlp.Encode(wr, w.ToExtWitness())
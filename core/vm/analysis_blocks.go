@@ -0,0 +1,292 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// A basic block is a sequence of instructions which, once entered at its start,
+// is executed up to its end unless an error occurs. Blocks start at the beginning
+// of the code, at every JUMPDEST and after every block end. They end at jumps, at
+// instructions halting the execution, and at instructions whose gas cost depends
+// on, or which observe, the remaining gas (i.e. all instructions with a dynamic
+// gas cost as well as GAS).
+//
+// Since no instruction but the last can observe the gas, the constant gas of the
+// whole block can be charged on entry. Similarly, the stack bounds of all its
+// instructions are validated on entry. If these checks fail, the block is executed
+// instruction by instruction instead, so that the failure surfaces at the same
+// instruction with the same error.
+
+// basicBlock is a block of instructions with pre-computed gas and stack bounds.
+type basicBlock struct {
+	instrs   []instruction
+	last     *operation // last operation of the block, which may have a dynamic gas cost
+	gas      uint64     // total constant gas of the instructions
+	minStack int        // minimum stack height on entry
+	maxStack int        // maximum stack height on entry
+}
+
+// instruction is an operation within a basic block. Common sequences of
+// operations are fused into one superinstruction.
+type instruction struct {
+	execute executionFunc
+	op      OpCode // the first opcode of a fused sequence
+	size    int    // the number of opcodes fused
+}
+
+// blockAnalysisCacheSize is the number of analyzed contracts retained across
+// EVM instances.
+const blockAnalysisCacheSize = 1024
+
+// blockAnalysisKey identifies an analysis by the code and the jump table its
+// instructions were resolved with.
+type blockAnalysisKey struct {
+	table *JumpTable
+	hash  common.Hash
+}
+
+// blockAnalyses caches the basic block analyses of the most recently executed
+// contracts. The analyses are never modified once created, so they are shared
+// by all EVM instances, including concurrently executing ones.
+var blockAnalyses = lru.NewCache[blockAnalysisKey, *blockAnalysis](blockAnalysisCacheSize)
+
+// blockAnalysis is the basic block partitioning of a piece of code.
+type blockAnalysis struct {
+	index  []uint32 // one-based index of the block starting at each pc, zero if none
+	blocks []basicBlock
+}
+
+// block returns the basic block starting at pc, if any.
+func (a *blockAnalysis) block(pc uint64) *basicBlock {
+	if pc >= uint64(len(a.index)) {
+		return nil
+	}
+	if i := a.index[pc]; i != 0 {
+		return &a.blocks[i-1]
+	}
+	return nil
+}
+
+// runnable reports whether the block can be entered with the given stack height
+// and gas, without any instruction failing due to a lack of either.
+func (b *basicBlock) runnable(stackLen int, gas uint64) bool {
+	return stackLen >= b.minStack && stackLen <= b.maxStack && gas >= b.gas
+}
+
+// analyzeBlocks splits code into basic blocks, using the operations of the given
+// jump table.
+func analyzeBlocks(code []byte, table *JumpTable) *blockAnalysis {
+	var (
+		a      = &blockAnalysis{index: make([]uint32, len(code))}
+		cur    *basicBlock
+		start  uint64
+		height int    // stack height relative to the block entry
+		open   = true // whether the next instruction may start a block
+	)
+	closeBlock := func(next bool) {
+		if cur != nil {
+			a.blocks = append(a.blocks, *cur)
+			a.index[start] = uint32(len(a.blocks))
+		}
+		cur, open = nil, next
+	}
+	for pc := uint64(0); pc < uint64(len(code)); {
+		var (
+			op        = OpCode(code[pc])
+			operation = table[op]
+			size      = uint64(1)
+		)
+		if op >= PUSH1 && op <= PUSH32 {
+			size += uint64(op - PUSH1 + 1)
+		}
+		if op == JUMPDEST {
+			closeBlock(true)
+		}
+		// Operations with immediates other than PUSH may alter the pc in ways this
+		// analysis doesn't know about. Leave them out, and don't start a block until
+		// the next JUMPDEST.
+		if !operation.undefined && op > LOG4 && op < CREATE {
+			closeBlock(false)
+			pc++
+			continue
+		}
+		if cur == nil {
+			if !open {
+				pc += size
+				continue
+			}
+			cur = &basicBlock{maxStack: int(params.StackLimit)}
+			start, height = pc, 0
+		}
+		cur.gas += operation.constantGas
+		cur.minStack = max(cur.minStack, operation.minStack-height)
+		cur.maxStack = min(cur.maxStack, operation.maxStack-height)
+		height += int(params.StackLimit) - operation.maxStack
+		cur.last = operation
+		cur.add(op, operation)
+
+		pc += size
+		switch {
+		case operation.undefined, operation.dynamicGas != nil:
+			closeBlock(true)
+		case op == STOP, op == JUMP, op == JUMPI, op == GAS, op == RETURN, op == REVERT, op == SELFDESTRUCT:
+			closeBlock(true)
+		}
+	}
+	closeBlock(false)
+	return a
+}
+
+// add appends an operation to the block, fusing it with the previous instruction
+// if possible.
+func (b *basicBlock) add(op OpCode, operation *operation) {
+	if n := len(b.instrs); n > 0 {
+		prev := &b.instrs[n-1]
+		if prev.size == 1 {
+			switch {
+			case prev.op >= PUSH1 && prev.op <= PUSH32 && op == JUMP:
+				prev.execute, prev.size = makePushJump(uint64(prev.op-PUSH1+1)), 2
+				return
+			case prev.op >= PUSH1 && prev.op <= PUSH32 && op == JUMPI:
+				prev.execute, prev.size = makePushJumpi(uint64(prev.op-PUSH1+1)), 2
+				return
+			case prev.op >= DUP1 && prev.op <= DUP16 && op >= SWAP1 && op <= SWAP16:
+				prev.execute, prev.size = fuse(prev.execute, operation.execute), 2
+				return
+			}
+		}
+	}
+	b.instrs = append(b.instrs, instruction{execute: operation.execute, op: op, size: 1})
+}
+
+// makePushJump creates a superinstruction for PUSHn followed by JUMP.
+func makePushJump(size uint64) executionFunc {
+	return func(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+		if evm.abort.Load() {
+			return nil, errStopToken
+		}
+		var (
+			start = *pc + 1
+			dest  = new(uint256.Int).SetBytes(scope.Contract.Code[start : start+size])
+		)
+		if !scope.Contract.validJumpdest(dest) {
+			return nil, ErrInvalidJump
+		}
+		*pc = dest.Uint64() - 1 // pc will be increased by the interpreter loop
+		return nil, nil
+	}
+}
+
+// makePushJumpi creates a superinstruction for PUSHn followed by JUMPI.
+func makePushJumpi(size uint64) executionFunc {
+	return func(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+		if evm.abort.Load() {
+			return nil, errStopToken
+		}
+		var (
+			start = *pc + 1
+			cond  = scope.Stack.pop()
+		)
+		if cond.IsZero() {
+			*pc = start + size // the JUMPI, pc will be increased by the interpreter loop
+			return nil, nil
+		}
+		dest := new(uint256.Int).SetBytes(scope.Contract.Code[start : start+size])
+		if !scope.Contract.validJumpdest(dest) {
+			return nil, ErrInvalidJump
+		}
+		*pc = dest.Uint64() - 1 // pc will be increased by the interpreter loop
+		return nil, nil
+	}
+}
+
+// fuse creates a superinstruction executing two operations which don't fail and
+// don't alter the pc.
+func fuse(first, second executionFunc) executionFunc {
+	return func(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+		first(pc, evm, scope)
+		*pc++
+		return second(pc, evm, scope)
+	}
+}
+
+// blockAnalysis returns the basic block analysis of the contract code, caching
+// it per code hash and jump table. Code without a hash, i.e. initcode, is not
+// analyzed.
+func (evm *EVM) blockAnalysis(contract *Contract) *blockAnalysis {
+	if contract.CodeHash == (common.Hash{}) {
+		return nil
+	}
+	key := blockAnalysisKey{table: evm.table, hash: contract.CodeHash}
+	if a, ok := blockAnalyses.Get(key); ok {
+		return a
+	}
+	a := analyzeBlocks(contract.Code, evm.table)
+	blockAnalyses.Add(key, a)
+	return a
+}
+
+// runBlock executes a basic block starting at pc, which must be runnable.
+func (evm *EVM) runBlock(block *basicBlock, pc *uint64, scope *ScopeContext) ([]byte, error) {
+	scope.Contract.Gas -= block.gas
+
+	last := len(block.instrs) - 1
+	for _, ins := range block.instrs[:last] {
+		if _, err := ins.execute(pc, evm, scope); err != nil {
+			return nil, err
+		}
+		*pc++
+	}
+	// The last operation may have a dynamic gas cost and memory expansion.
+	if operation := block.last; operation.dynamicGas != nil {
+		var memorySize uint64
+		if operation.memorySize != nil {
+			memSize, overflow := operation.memorySize(scope.Stack)
+			if overflow {
+				return nil, ErrGasUintOverflow
+			}
+			if memorySize, overflow = math.SafeMul(toWordSize(memSize), 32); overflow {
+				return nil, ErrGasUintOverflow
+			}
+		}
+		dynamicCost, err := operation.dynamicGas(evm, scope.Contract, scope.Stack, scope.Memory, memorySize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOutOfGas, err)
+		}
+		if scope.Contract.Gas < dynamicCost {
+			return nil, ErrOutOfGas
+		}
+		scope.Contract.Gas -= dynamicCost
+		if memorySize > 0 {
+			scope.Memory.Resize(memorySize)
+		}
+	}
+	res, err := block.instrs[last].execute(pc, evm, scope)
+	if err != nil {
+		return res, err
+	}
+	*pc++
+	return res, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+func TestBlockAnalysis(t *testing.T) {
+	table := copyJumpTable(&cancunInstructionSet)
+	if err := EnableEIP(8024, table); err != nil {
+		t.Fatal(err)
+	}
+	code := []byte{
+		byte(PUSH1), 0x01, byte(DUP1), byte(SWAP1), byte(PUSH1), 0x08, byte(JUMPI), // 0: fused DUP1+SWAP1 and PUSH1+JUMPI
+		byte(STOP),                                      // 7: block after JUMPI
+		byte(JUMPDEST), byte(PUSH1), 0x00, byte(SSTORE), // 8: ends at SSTORE with dynamic gas
+		byte(DUPN), 0x00, byte(PUSH1), 0x00, // 12: no blocks until the next JUMPDEST
+		byte(JUMPDEST), byte(ADD), // 16: stack underflow if entered empty
+	}
+	a := analyzeBlocks(code, table)
+
+	type want struct {
+		pc                 uint64
+		instrs             int
+		gas                uint64
+		minStack, maxStack int
+	}
+	wants := []want{
+		{0, 3, 4*GasFastestStep + GasSlowStep, 0, 1021},
+		{7, 1, 0, 0, 1024},
+		{8, 3, params.JumpdestGas + GasFastestStep + table[SSTORE].constantGas, 1, 1023},
+		{16, 2, params.JumpdestGas + GasFastestStep, 2, 1024},
+	}
+	if len(a.blocks) != len(wants) {
+		t.Fatalf("wrong number of blocks: have %d, want %d", len(a.blocks), len(wants))
+	}
+	for _, w := range wants {
+		b := a.block(w.pc)
+		if b == nil {
+			t.Fatalf("no block at pc %d", w.pc)
+		}
+		if len(b.instrs) != w.instrs || b.gas != w.gas || b.minStack != w.minStack || b.maxStack != w.maxStack {
+			t.Errorf("pc %d: have %d instructions, gas %d, stack %d..%d, want %d instructions, gas %d, stack %d..%d",
+				w.pc, len(b.instrs), b.gas, b.minStack, b.maxStack, w.instrs, w.gas, w.minStack, w.maxStack)
+		}
+	}
+	for _, pc := range []uint64{1, 2, 12, 13, 14, 17, 100} {
+		if a.block(pc) != nil {
+			t.Errorf("unexpected block at pc %d", pc)
+		}
+	}
+}
+
+// TestBlockAnalysisCache checks that analyses are shared across EVM instances
+// using the same jump table, but not across different ones.
+func TestBlockAnalysisCache(t *testing.T) {
+	var (
+		code     = []byte{byte(PUSH1), 0x01, byte(JUMPDEST), byte(STOP)}
+		contract = NewContract(common.Address{}, common.Address{}, new(uint256.Int), 0, nil)
+	)
+	contract.SetCallCode(common.Hash{0xaa}, code)
+
+	first := NewEVM(BlockContext{BlockNumber: big.NewInt(0)}, nil, params.MergedTestChainConfig, Config{})
+	second := NewEVM(BlockContext{BlockNumber: big.NewInt(0)}, nil, params.MergedTestChainConfig, Config{})
+	if first.blockAnalysis(contract) != second.blockAnalysis(contract) {
+		t.Error("analysis not shared across EVM instances")
+	}
+	other := NewEVM(BlockContext{BlockNumber: big.NewInt(0)}, nil, params.MergedTestChainConfig, Config{ExtraEips: []int{8024}})
+	if first.blockAnalysis(contract) == other.blockAnalysis(contract) {
+		t.Error("analysis shared across jump tables")
+	}
+	// Initcode is not analyzed
+	contract.SetCallCode(common.Hash{}, code)
+	if first.blockAnalysis(contract) != nil {
+		t.Error("initcode analyzed")
+	}
+}
+
+// blockAnalysisOps are the opcodes used to generate random programs, favouring
+// control flow and stack operations.
+var blockAnalysisOps = []OpCode{
+	STOP, ADD, MUL, SUB, DIV, SDIV, MOD, EXP, LT, GT, EQ, ISZERO, AND, OR, XOR, NOT, BYTE, SHL, SHR, SAR,
+	KECCAK256, ADDRESS, CALLER, CALLVALUE, CALLDATALOAD, CALLDATASIZE, CALLDATACOPY, CODESIZE, CODECOPY,
+	RETURNDATASIZE, RETURNDATACOPY, NUMBER, SELFBALANCE, POP, MLOAD, MSTORE, MSTORE8, SLOAD, SSTORE,
+	TLOAD, TSTORE, MCOPY, PC, MSIZE, GAS, LOG0, LOG1, CALL, STATICCALL, RETURN, REVERT, INVALID, 0x0c, 0xef,
+	PUSH0, DUP1, DUP2, DUP3, DUP16, SWAP1, SWAP2, SWAP16, DUPN, SWAPN, EXCHANGE,
+	JUMPDEST, JUMPDEST, JUMPDEST, JUMP, JUMP, JUMPI, JUMPI, JUMPI,
+}
+
+// randomProgram generates a program of random operations, where pushes are mostly
+// small values likely to be jump destinations within the program, and fusable
+// sequences are common.
+func randomProgram(rng *rand.Rand, size int) []byte {
+	var code []byte
+	for len(code) < size {
+		switch n := rng.Intn(11); {
+		case n < 3:
+			code = append(code, byte(PUSH1), byte(rng.Intn(size)))
+		case n < 4:
+			push := PUSH1 + OpCode(rng.Intn(32))
+			code = append(code, byte(push))
+			for i := 0; i <= int(push-PUSH1); i++ {
+				code = append(code, byte(rng.Intn(256)))
+			}
+		case n < 5:
+			code = append(code, byte(DUP1+OpCode(rng.Intn(3))), byte(SWAP1+OpCode(rng.Intn(3))))
+		default:
+			code = append(code, byte(blockAnalysisOps[rng.Intn(len(blockAnalysisOps))]))
+		}
+	}
+	return code
+}
+
+type blockAnalysisResult struct {
+	ret  []byte
+	gas  uint64
+	err  string
+	root common.Hash
+	logs int
+}
+
+func runBlockAnalysisProgram(config *params.ChainConfig, vmConfig Config, code []byte, gas uint64) blockAnalysisResult {
+	var (
+		address = common.BytesToAddress([]byte("contract"))
+		caller  = common.BytesToAddress([]byte("caller"))
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.CreateAccount(address)
+	statedb.SetCode(address, code, tracing.CodeChangeUnspecified)
+	statedb.SetState(address, common.Hash{}, common.Hash{1})
+	statedb.Finalise(true)
+
+	vmctx := BlockContext{
+		CanTransfer: func(db StateDB, addr common.Address, amount *uint256.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		GetHash:     func(n uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(n)) },
+		BlockNumber: big.NewInt(1),
+		Time:        1,
+		Random:      &common.Hash{},
+		BaseFee:     big.NewInt(1),
+		BlobBaseFee: big.NewInt(1),
+	}
+	evm := NewEVM(vmctx, statedb, config, vmConfig)
+	evm.SetTxContext(TxContext{Origin: caller, GasPrice: big.NewInt(1)})
+	statedb.Prepare(evm.chainRules, caller, common.Address{}, &address, nil, nil)
+
+	ret, left, err := evm.Call(caller, address, []byte{0x01, 0x02}, gas, new(uint256.Int))
+	res := blockAnalysisResult{ret: ret, gas: left, root: statedb.IntermediateRoot(true), logs: len(statedb.Logs())}
+	if err != nil {
+		res.err = err.Error()
+	}
+	return res
+}
+
+// TestBlockAnalysisEquivalence checks that executing random programs with and
+// without basic block analysis produces the same results.
+func TestBlockAnalysisEquivalence(t *testing.T) {
+	configs := []struct {
+		name   string
+		config *params.ChainConfig
+		eips   []int
+	}{
+		{"london", params.AllEthashProtocolChanges, nil},
+		{"osaka", params.MergedTestChainConfig, nil},
+		{"osaka+8024", params.MergedTestChainConfig, []int{8024}},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		code := randomProgram(rng, 8+rng.Intn(120))
+		gas := uint64(rng.Intn(200000))
+		for _, c := range configs {
+			want := runBlockAnalysisProgram(c.config, Config{ExtraEips: c.eips}, code, gas)
+			have := runBlockAnalysisProgram(c.config, Config{ExtraEips: c.eips, EnableBlockAnalysis: true}, code, gas)
+			if !bytes.Equal(have.ret, want.ret) || have.gas != want.gas || have.err != want.err || have.root != want.root || have.logs != want.logs {
+				t.Fatalf("%s: program %x with gas %d: have %+v, want %+v", c.name, code, gas, have, want)
+			}
+		}
+	}
+}
+
+// TestBlockAnalysisGasBoundaries checks a loop for every gas limit around the
+// point where it runs out of gas within a basic block.
+func TestBlockAnalysisGasBoundaries(t *testing.T) {
+	// jumpdest, push1 1, push1 0, sload, add, push1 0, sstore, push1 0, jump
+	code := common.FromHex("5b600160005401600055600056")
+	for gas := uint64(0); gas < 50000; gas += 7 {
+		want := runBlockAnalysisProgram(params.MergedTestChainConfig, Config{}, code, gas)
+		have := runBlockAnalysisProgram(params.MergedTestChainConfig, Config{EnableBlockAnalysis: true}, code, gas)
+		if fmt.Sprint(have) != fmt.Sprint(want) {
+			t.Fatalf("gas %d: have %+v, want %+v", gas, have, want)
+		}
+	}
+}
@@ -38,6 +38,10 @@ type Contract struct {
 	CodeHash common.Hash
 	Input    []byte
 
+	// Container is the parsed EOF container of the code, nil for legacy code.
+	// During execution, Code is the code section being executed.
+	Container *Container
+
 	// is the execution frame represented by this object a contract deployment
 	IsDeployment bool
 	IsSystemCall bool
@@ -212,6 +212,14 @@ func init() {
 }
 
 func activePrecompiledContracts(rules params.Rules) PrecompiledContracts {
+	contracts := builtinPrecompiledContracts(rules)
+	if len(rules.Precompiles) > 0 {
+		return withCustomPrecompiles(contracts, rules)
+	}
+	return contracts
+}
+
+func builtinPrecompiledContracts(rules params.Rules) PrecompiledContracts {
 	switch {
 	case rules.IsVerkle:
 		return PrecompiledContractsVerkle
@@ -239,6 +247,14 @@ func ActivePrecompiledContracts(rules params.Rules) PrecompiledContracts {
 
 // ActivePrecompiles returns the precompile addresses enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	addrs := builtinPrecompiles(rules)
+	if len(rules.Precompiles) > 0 {
+		return withCustomPrecompileAddresses(addrs, rules)
+	}
+	return addrs
+}
+
+func builtinPrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsOsaka:
 		return PrecompiledAddressesOsaka
@@ -0,0 +1,186 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// StatefulPrecompiledContract is a precompiled contract with access to the state
+// and the context of the call. The gas returned by RequiredGas is charged before
+// the contract is run, additional gas can be charged through the environment.
+//
+// The EVM runs stateful contracts through RunStateful, Run is only invoked when
+// the contract is run outside of the EVM, e.g. via RunPrecompiledContract.
+//
+// Note that accounts without code, nonce and balance are deleted as empty at the
+// end of a transaction (EIP-161). A contract keeping storage in its own account
+// has to make sure it's not empty, e.g. by setting its nonce.
+type StatefulPrecompiledContract interface {
+	PrecompiledContract
+	RunStateful(env *PrecompileEnvironment, input []byte) ([]byte, error)
+}
+
+// PrecompileEnvironment is the context a stateful precompiled contract is run in.
+// It is only valid for the duration of the call.
+type PrecompileEnvironment struct {
+	EVM *EVM
+
+	Caller   common.Address // Address of the caller (msg.sender)
+	Address  common.Address // Address whose storage is in scope, the precompile unless delegated
+	Value    *uint256.Int   // Value sent with the call
+	ReadOnly bool           // Whether state modifications are disallowed
+
+	gas uint64
+}
+
+// StateDB returns the state the contract is run on. Modifications are reverted
+// if the contract returns an error.
+func (env *PrecompileEnvironment) StateDB() StateDB {
+	return env.EVM.StateDB
+}
+
+// Gas returns the gas left for the call.
+func (env *PrecompileEnvironment) Gas() uint64 {
+	return env.gas
+}
+
+// UseGas charges the given amount of gas, returning false if not enough is left.
+// The contract should return ErrOutOfGas in that case.
+func (env *PrecompileEnvironment) UseGas(amount uint64) bool {
+	if env.gas < amount {
+		return false
+	}
+	if tracer := env.EVM.Config.Tracer; tracer != nil && tracer.OnGasChange != nil {
+		tracer.OnGasChange(env.gas, env.gas-amount, tracing.GasChangeCallPrecompiledContract)
+	}
+	env.gas -= amount
+	return true
+}
+
+var (
+	customPrecompilesLock sync.RWMutex
+	customPrecompiles     = make(map[string]PrecompiledContract)
+)
+
+// RegisterPrecompile registers a custom precompiled contract under the given name.
+// It is activated at the address and fork given by the Precompiles section of the
+// chain config. Contracts should be registered before any chain is set up; it
+// panics if a contract with the same name is already registered.
+func RegisterPrecompile(name string, contract PrecompiledContract) {
+	customPrecompilesLock.Lock()
+	defer customPrecompilesLock.Unlock()
+
+	if _, ok := customPrecompiles[name]; ok {
+		panic(fmt.Sprintf("precompile %q already registered", name))
+	}
+	customPrecompiles[name] = contract
+}
+
+// registeredPrecompile returns the custom precompiled contract registered under
+// the given name.
+func registeredPrecompile(name string) (PrecompiledContract, bool) {
+	customPrecompilesLock.RLock()
+	defer customPrecompilesLock.RUnlock()
+
+	contract, ok := customPrecompiles[name]
+	return contract, ok
+}
+
+// CheckPrecompiles verifies that all custom precompiles scheduled in the chain
+// config are registered and don't replace the built-in ones.
+func CheckPrecompiles(config *params.ChainConfig) error {
+	for addr, p := range config.Precompiles {
+		if p == nil || p.Name == "" {
+			return fmt.Errorf("precompile %v has no name", addr)
+		}
+		if p.Block != nil && p.Time != nil {
+			return fmt.Errorf("precompile %v has both an activation block and time", addr)
+		}
+		if _, ok := registeredPrecompile(p.Name); !ok {
+			return fmt.Errorf("precompile %q at %v is not registered", p.Name, addr)
+		}
+		if _, ok := PrecompiledContractsOsaka[addr]; ok {
+			return fmt.Errorf("precompile %q at %v replaces a built-in precompile", p.Name, addr)
+		}
+		if _, ok := PrecompiledContractsVerkle[addr]; ok {
+			return fmt.Errorf("precompile %q at %v replaces a built-in precompile", p.Name, addr)
+		}
+	}
+	return nil
+}
+
+// withCustomPrecompiles adds the active custom precompiles to a set of built-in
+// precompiled contracts.
+func withCustomPrecompiles(contracts PrecompiledContracts, rules params.Rules) PrecompiledContracts {
+	active := make(PrecompiledContracts, len(contracts)+len(rules.Precompiles))
+	for addr, contract := range contracts {
+		active[addr] = contract
+	}
+	for addr, name := range rules.Precompiles {
+		if contract, ok := registeredPrecompile(name); ok {
+			active[addr] = contract
+		}
+	}
+	return active
+}
+
+// withCustomPrecompileAddresses adds the addresses of the active custom
+// precompiles to a list of built-in precompile addresses.
+func withCustomPrecompileAddresses(addrs []common.Address, rules params.Rules) []common.Address {
+	custom := make([]common.Address, 0, len(rules.Precompiles))
+	for addr, name := range rules.Precompiles {
+		if _, ok := registeredPrecompile(name); ok {
+			custom = append(custom, addr)
+		}
+	}
+	slices.SortFunc(custom, common.Address.Cmp)
+	return append(slices.Clone(addrs), custom...)
+}
+
+// runPrecompiledContract runs a precompiled contract called in the given context,
+// providing stateful contracts with their environment.
+func (evm *EVM) runPrecompiledContract(p PrecompiledContract, caller common.Address, addr common.Address, input []byte, gas uint64, value *uint256.Int, readOnly bool) ([]byte, uint64, error) {
+	stateful, ok := p.(StatefulPrecompiledContract)
+	if !ok {
+		return RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+	}
+	gasCost := p.RequiredGas(input)
+	if gas < gasCost {
+		return nil, 0, ErrOutOfGas
+	}
+	if evm.Config.Tracer != nil && evm.Config.Tracer.OnGasChange != nil {
+		evm.Config.Tracer.OnGasChange(gas, gas-gasCost, tracing.GasChangeCallPrecompiledContract)
+	}
+	env := &PrecompileEnvironment{
+		EVM:      evm,
+		Caller:   caller,
+		Address:  addr,
+		Value:    value,
+		ReadOnly: readOnly,
+		gas:      gas - gasCost,
+	}
+	ret, err := stateful.RunStateful(env, input)
+	return ret, env.gas, err
+}
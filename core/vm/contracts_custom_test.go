@@ -0,0 +1,161 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// counterPrecompile is a stateful precompile incrementing a counter in the
+// storage of the address in scope.
+type counterPrecompile struct{}
+
+func (c *counterPrecompile) RequiredGas(input []byte) uint64 { return 100 }
+func (c *counterPrecompile) Run(input []byte) ([]byte, error) {
+	return nil, errors.New("stateless execution not supported")
+}
+func (c *counterPrecompile) Name() string { return "COUNTER" }
+
+func (c *counterPrecompile) RunStateful(env *PrecompileEnvironment, input []byte) ([]byte, error) {
+	if env.ReadOnly {
+		return nil, ErrWriteProtection
+	}
+	if !env.UseGas(params.SstoreSetGasEIP2200) {
+		return nil, ErrOutOfGas
+	}
+	// Keep the account from being deleted as empty
+	if env.StateDB().GetNonce(env.Address) == 0 {
+		env.StateDB().SetNonce(env.Address, 1, tracing.NonceChangeUnspecified)
+	}
+	count := env.StateDB().GetState(env.Address, common.Hash{}).Big()
+	count.Add(count, big.NewInt(1))
+	env.StateDB().SetState(env.Address, common.Hash{}, common.BigToHash(count))
+	return common.BigToHash(count).Bytes(), nil
+}
+
+func init() {
+	RegisterPrecompile("counter", new(counterPrecompile))
+}
+
+func TestStatefulPrecompile(t *testing.T) {
+	var (
+		counter  = common.Address{0x01, 0x00}
+		caller   = common.Address{0xca}
+		delegate = common.Address{0xde}
+		config   = *params.MergedTestChainConfig
+	)
+	config.Precompiles = map[common.Address]*params.PrecompileConfig{
+		counter: {Name: "counter", Time: newUint64(10)},
+	}
+	if err := CheckPrecompiles(&config); err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+
+	// delegatecall(gas, counter, 0, 0, 0, 0)
+	code := append([]byte{byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH20)}, counter.Bytes()...)
+	code = append(code, byte(GAS), byte(DELEGATECALL), byte(STOP))
+	statedb.SetCode(delegate, code, tracing.CodeChangeUnspecified)
+
+	newEVM := func(time uint64) *EVM {
+		vmctx := BlockContext{
+			CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+			BlockNumber: big.NewInt(1),
+			Time:        time,
+			Random:      &common.Hash{},
+		}
+		return NewEVM(vmctx, statedb, &config, Config{})
+	}
+	// Before the activation, the address is an empty account.
+	evm := newEVM(9)
+	if slices.Contains(ActivePrecompiles(evm.chainRules), counter) {
+		t.Fatal("precompile active before activation")
+	}
+	if ret, gas, err := evm.Call(caller, counter, nil, 10000, new(uint256.Int)); err != nil || len(ret) != 0 || gas != 10000 {
+		t.Fatalf("call before activation: ret %x, gas %d, err %v", ret, gas, err)
+	}
+	// After the activation, calls increment the counter.
+	evm = newEVM(10)
+	if !slices.Contains(ActivePrecompiles(evm.chainRules), counter) {
+		t.Fatal("precompile not active after activation")
+	}
+	for i := byte(1); i <= 2; i++ {
+		ret, gas, err := evm.Call(caller, counter, nil, 30000, new(uint256.Int))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := common.BytesToHash([]byte{i}).Bytes(); common.BytesToHash(ret) != common.BytesToHash(want) {
+			t.Fatalf("call %d: have %x, want %x", i, ret, want)
+		}
+		if used := 30000 - gas; used != 100+params.SstoreSetGasEIP2200 {
+			t.Fatalf("call %d: wrong gas used %d", i, used)
+		}
+	}
+	// Running out of gas reverts and consumes all gas.
+	if _, gas, err := evm.Call(caller, counter, nil, 1000, new(uint256.Int)); !errors.Is(err, ErrOutOfGas) || gas != 0 {
+		t.Fatalf("call without enough gas: gas %d, err %v", gas, err)
+	}
+	// Static calls are read-only.
+	if _, _, err := evm.StaticCall(caller, counter, nil, 30000); !errors.Is(err, ErrWriteProtection) {
+		t.Fatalf("static call: err %v", err)
+	}
+	if count := statedb.GetState(counter, common.Hash{}); count != common.BytesToHash([]byte{2}) {
+		t.Fatalf("wrong counter: %x", count)
+	}
+	// Delegate calls operate on the storage of the caller.
+	if _, _, err := evm.Call(caller, delegate, nil, 100000, new(uint256.Int)); err != nil {
+		t.Fatal(err)
+	}
+	if count := statedb.GetState(delegate, common.Hash{}); count != common.BytesToHash([]byte{1}) {
+		t.Fatalf("wrong delegated counter: %x", count)
+	}
+	if count := statedb.GetState(counter, common.Hash{}); count != common.BytesToHash([]byte{2}) {
+		t.Fatalf("counter changed by delegate call: %x", count)
+	}
+}
+
+func TestCheckPrecompiles(t *testing.T) {
+	tests := []struct {
+		precompiles map[common.Address]*params.PrecompileConfig
+		ok          bool
+	}{
+		{map[common.Address]*params.PrecompileConfig{{0x01, 0x00}: {Name: "counter", Block: big.NewInt(0)}}, true},
+		{map[common.Address]*params.PrecompileConfig{{0x01, 0x00}: {Name: "unknown", Block: big.NewInt(0)}}, false},
+		{map[common.Address]*params.PrecompileConfig{{0x01, 0x00}: {Name: "counter", Block: big.NewInt(0), Time: newUint64(0)}}, false},
+		{map[common.Address]*params.PrecompileConfig{common.BytesToAddress([]byte{0x01}): {Name: "counter", Block: big.NewInt(0)}}, false},
+		{map[common.Address]*params.PrecompileConfig{common.BytesToAddress([]byte{0x11}): {Name: "counter", Block: big.NewInt(0)}}, false},
+	}
+	for i, test := range tests {
+		config := &params.ChainConfig{Precompiles: test.precompiles}
+		if err := CheckPrecompiles(config); (err == nil) != test.ok {
+			t.Errorf("test %d: have error %v, want ok %v", i, err, test.ok)
+		}
+	}
+}
+
+func newUint64(val uint64) *uint64 { return &val }
@@ -0,0 +1,506 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// EVM Object Format (EIP-3540) constants.
+const (
+	eof1Version = 1
+
+	kindTypes     = 1
+	kindCode      = 2
+	kindContainer = 3
+	kindData      = 0xff
+
+	eofTypeSize = 4 // inputs, outputs and max stack increase of a code section
+
+	maxCodeSections       = 1024
+	maxContainerSections  = 256
+	maxInputItems         = 127
+	maxOutputItems        = 127
+	maxStackHeight        = 1023
+	maxReturnStackHeight  = 1024
+	nonReturningFunction  = 0x80
+	maxDataSize           = 0xffff
+	maxContainerSizeBytes = params.MaxInitCodeSize
+)
+
+var (
+	eofMagic = []byte{0xef, 0x00}
+
+	// eofMagicHash is the code hash legacy code observes for EOF contracts.
+	eofMagicHash = crypto.Keccak256Hash(eofMagic)
+)
+
+var (
+	errInvalidMagic           = errors.New("invalid magic")
+	errUnsupportedVersion     = errors.New("unsupported eof version")
+	errMissingTypeHeader      = errors.New("missing type header")
+	errInvalidTypeSize        = errors.New("invalid type section size")
+	errMissingCodeHeader      = errors.New("missing code header")
+	errInvalidCodeSize        = errors.New("invalid code size")
+	errInvalidContainerSize   = errors.New("invalid container section size")
+	errMissingDataHeader      = errors.New("missing data header")
+	errMissingTerminator      = errors.New("missing header terminator")
+	errTooManySections        = errors.New("too many sections")
+	errInvalidSectionCount    = errors.New("invalid section count")
+	errInvalidContainerLength = errors.New("invalid container length")
+	errTruncatedContainer     = errors.New("truncated container")
+	errContainerTooLarge      = errors.New("container too large")
+	errInvalidSection0Type    = errors.New("invalid section 0 type")
+	errTooManyInputs          = errors.New("invalid section inputs")
+	errTooManyOutputs         = errors.New("invalid section outputs")
+	errTooLargeMaxStackHeight = errors.New("invalid section max stack increase")
+)
+
+// HasEOFMagic returns whether the code starts with the EOF magic bytes.
+func HasEOFMagic(code []byte) bool {
+	return bytes.HasPrefix(code, eofMagic)
+}
+
+// functionMetadata is the type of a code section, as stored in the types section.
+type functionMetadata struct {
+	inputs           uint8
+	outputs          uint8
+	maxStackIncrease uint16
+}
+
+// returning returns whether the code section returns to its caller.
+func (m *functionMetadata) returning() bool {
+	return m.outputs != nonReturningFunction
+}
+
+// checkStackMax returns an error if calling the section could overflow the stack.
+func (m *functionMetadata) checkStackMax(stackMax int) error {
+	if newMax := stackMax + int(m.maxStackIncrease); newMax > int(params.StackLimit) {
+		return fmt.Errorf("%w: stack could reach %d", errStackOverflow, newMax)
+	}
+	return nil
+}
+
+// Container is an EOF container object.
+type Container struct {
+	types             []*functionMetadata
+	codeSections      [][]byte
+	subContainers     []*Container
+	subContainerCodes [][]byte
+	data              []byte
+	dataSize          int // declared size of the data section, may exceed len(data) if truncated
+}
+
+// CodeSections returns the number of code sections in the container.
+func (c *Container) CodeSections() int {
+	return len(c.codeSections)
+}
+
+// Data returns the data section of the container.
+func (c *Container) Data() []byte {
+	return c.data
+}
+
+// truncated returns whether the data section is shorter than declared.
+func (c *Container) truncated() bool {
+	return len(c.data) < c.dataSize
+}
+
+// MarshalBinary encodes an EOF container into binary format.
+func (c *Container) MarshalBinary() []byte {
+	// Build header.
+	b := make([]byte, 2)
+	copy(b, eofMagic)
+	b = append(b, eof1Version)
+	b = append(b, kindTypes)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.types)*eofTypeSize))
+	b = append(b, kindCode)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(c.codeSections)))
+	for _, code := range c.codeSections {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(code)))
+	}
+	if len(c.subContainers) > 0 {
+		b = append(b, kindContainer)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(c.subContainers)))
+		for _, section := range c.subContainerCodes {
+			b = binary.BigEndian.AppendUint32(b, uint32(len(section)))
+		}
+	}
+	b = append(b, kindData)
+	b = binary.BigEndian.AppendUint16(b, uint16(c.dataSize))
+	b = append(b, 0) // terminator
+
+	// Write section contents.
+	for _, ty := range c.types {
+		b = append(b, []byte{ty.inputs, ty.outputs, byte(ty.maxStackIncrease >> 8), byte(ty.maxStackIncrease)}...)
+	}
+	for _, code := range c.codeSections {
+		b = append(b, code...)
+	}
+	for _, section := range c.subContainerCodes {
+		b = append(b, section...)
+	}
+	b = append(b, c.data...)
+
+	return b
+}
+
+// UnmarshalBinary decodes an EOF container. The container must span the entire
+// input and its data section must not be truncated. Use ValidateCode to check
+// the contents of the code sections.
+func (c *Container) UnmarshalBinary(b []byte) error {
+	size, err := c.unmarshal(b, true)
+	if err != nil {
+		return err
+	}
+	if size != len(b) {
+		return fmt.Errorf("%w: have %d bytes, container has %d", errInvalidContainerLength, len(b), size)
+	}
+	return nil
+}
+
+// UnmarshalPrefix decodes an EOF container at the beginning of the input and
+// returns the number of bytes it occupies. Trailing bytes are not part of the
+// container, e.g. the calldata of a creation transaction.
+func (c *Container) UnmarshalPrefix(b []byte) (int, error) {
+	return c.unmarshal(b, true)
+}
+
+// unmarshal decodes the container at the beginning of b and returns its size.
+// Nested containers may have a truncated data section, the top-level ones not.
+func (c *Container) unmarshal(b []byte, topLevel bool) (int, error) {
+	if !HasEOFMagic(b) {
+		return 0, fmt.Errorf("%w: want %x", errInvalidMagic, eofMagic)
+	}
+	if len(b) < 3 || b[2] != eof1Version {
+		return 0, errUnsupportedVersion
+	}
+	var (
+		pos      = 3
+		readKind = func(kind byte, err error) error {
+			if pos >= len(b) || b[pos] != kind {
+				return fmt.Errorf("%w at offset %d", err, pos)
+			}
+			pos++
+			return nil
+		}
+		readUint16 = func() (int, error) {
+			if pos+2 > len(b) {
+				return 0, fmt.Errorf("%w: header truncated at offset %d", errTruncatedContainer, pos)
+			}
+			v := int(binary.BigEndian.Uint16(b[pos:]))
+			pos += 2
+			return v, nil
+		}
+		readUint32 = func() (int, error) {
+			if pos+4 > len(b) {
+				return 0, fmt.Errorf("%w: header truncated at offset %d", errTruncatedContainer, pos)
+			}
+			v := int(binary.BigEndian.Uint32(b[pos:]))
+			pos += 4
+			return v, nil
+		}
+	)
+	// Parse the type section header.
+	if err := readKind(kindTypes, errMissingTypeHeader); err != nil {
+		return 0, err
+	}
+	typesSize, err := readUint16()
+	if err != nil {
+		return 0, err
+	}
+	if typesSize < eofTypeSize || typesSize%eofTypeSize != 0 {
+		return 0, fmt.Errorf("%w: %d", errInvalidTypeSize, typesSize)
+	}
+	// Parse the code section header.
+	if err := readKind(kindCode, errMissingCodeHeader); err != nil {
+		return 0, err
+	}
+	codeCount, err := readUint16()
+	if err != nil {
+		return 0, err
+	}
+	if codeCount == 0 {
+		return 0, fmt.Errorf("%w: no code sections", errInvalidSectionCount)
+	}
+	if codeCount > maxCodeSections {
+		return 0, fmt.Errorf("%w: %d code sections, limit %d", errTooManySections, codeCount, maxCodeSections)
+	}
+	if codeCount != typesSize/eofTypeSize {
+		return 0, fmt.Errorf("%w: %d code sections, %d types", errInvalidTypeSize, codeCount, typesSize/eofTypeSize)
+	}
+	codeSizes := make([]int, codeCount)
+	for i := range codeSizes {
+		if codeSizes[i], err = readUint16(); err != nil {
+			return 0, err
+		}
+		if codeSizes[i] == 0 {
+			return 0, fmt.Errorf("%w: section %d is empty", errInvalidCodeSize, i)
+		}
+	}
+	// Parse the optional container section header.
+	var containerSizes []int
+	if pos < len(b) && b[pos] == kindContainer {
+		pos++
+		containerCount, err := readUint16()
+		if err != nil {
+			return 0, err
+		}
+		if containerCount == 0 {
+			return 0, fmt.Errorf("%w: no container sections", errInvalidSectionCount)
+		}
+		if containerCount > maxContainerSections {
+			return 0, fmt.Errorf("%w: %d container sections, limit %d", errTooManySections, containerCount, maxContainerSections)
+		}
+		containerSizes = make([]int, containerCount)
+		for i := range containerSizes {
+			if containerSizes[i], err = readUint32(); err != nil {
+				return 0, err
+			}
+			if containerSizes[i] == 0 {
+				return 0, fmt.Errorf("%w: section %d is empty", errInvalidContainerSize, i)
+			}
+		}
+	}
+	// Parse the data section header and the terminator.
+	if err := readKind(kindData, errMissingDataHeader); err != nil {
+		return 0, err
+	}
+	dataSize, err := readUint16()
+	if err != nil {
+		return 0, err
+	}
+	if err := readKind(0, errMissingTerminator); err != nil {
+		return 0, err
+	}
+	// Check the body is complete, up to the data section.
+	bodySize := typesSize
+	for _, size := range codeSizes {
+		bodySize += size
+	}
+	for _, size := range containerSizes {
+		bodySize += size
+	}
+	if pos+bodySize > maxContainerSizeBytes || pos+bodySize+dataSize > maxContainerSizeBytes {
+		return 0, fmt.Errorf("%w: %d bytes, limit %d", errContainerTooLarge, pos+bodySize+dataSize, maxContainerSizeBytes)
+	}
+	if pos+bodySize > len(b) {
+		return 0, fmt.Errorf("%w: have %d bytes, want at least %d", errTruncatedContainer, len(b), pos+bodySize)
+	}
+	// Parse the types section.
+	types := make([]*functionMetadata, codeCount)
+	for i := range types {
+		sig := &functionMetadata{
+			inputs:           b[pos],
+			outputs:          b[pos+1],
+			maxStackIncrease: binary.BigEndian.Uint16(b[pos+2:]),
+		}
+		if sig.inputs > maxInputItems {
+			return 0, fmt.Errorf("%w: section %d has %d inputs, limit %d", errTooManyInputs, i, sig.inputs, maxInputItems)
+		}
+		if sig.outputs > maxOutputItems && sig.outputs != nonReturningFunction {
+			return 0, fmt.Errorf("%w: section %d has %d outputs, limit %d", errTooManyOutputs, i, sig.outputs, maxOutputItems)
+		}
+		if sig.maxStackIncrease > maxStackHeight {
+			return 0, fmt.Errorf("%w: section %d has %d, limit %d", errTooLargeMaxStackHeight, i, sig.maxStackIncrease, maxStackHeight)
+		}
+		types[i] = sig
+		pos += eofTypeSize
+	}
+	if types[0].inputs != 0 || types[0].returning() {
+		return 0, fmt.Errorf("%w: have %d inputs, %d outputs, want 0 inputs, %d outputs", errInvalidSection0Type, types[0].inputs, types[0].outputs, nonReturningFunction)
+	}
+	// Parse the code sections.
+	codeSections := make([][]byte, codeCount)
+	for i, size := range codeSizes {
+		codeSections[i] = b[pos : pos+size]
+		pos += size
+	}
+	// Parse the nested containers, which must be complete apart from their
+	// data sections.
+	var (
+		subContainers     []*Container
+		subContainerCodes [][]byte
+	)
+	for i, size := range containerSizes {
+		sub := new(Container)
+		end := pos + size
+		n, err := sub.unmarshal(b[pos:end], false)
+		if err != nil {
+			return 0, fmt.Errorf("subcontainer %d: %w", i, err)
+		}
+		if n != size {
+			return 0, fmt.Errorf("subcontainer %d: %w: section has %d bytes, container %d", i, errInvalidContainerLength, size, n)
+		}
+		subContainers = append(subContainers, sub)
+		subContainerCodes = append(subContainerCodes, b[pos:end])
+		pos = end
+	}
+	// Parse the data section, which may only be truncated in nested containers.
+	end := pos + dataSize
+	if end > len(b) {
+		if topLevel {
+			return 0, fmt.Errorf("%w: have %d bytes, want %d", errTruncatedContainer, len(b), end)
+		}
+		end = len(b)
+	}
+	c.types = types
+	c.codeSections = codeSections
+	c.subContainers = subContainers
+	c.subContainerCodes = subContainerCodes
+	c.data = b[pos:end]
+	c.dataSize = dataSize
+	return end, nil
+}
+
+// String returns a human readable overview of the container.
+func (c *Container) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Header\n")
+	fmt.Fprintf(&b, "  - EOFMagic: %02x\n", eofMagic)
+	fmt.Fprintf(&b, "  - EOFVersion: %02x\n", eof1Version)
+	fmt.Fprintf(&b, "  - KindType: %02x\n", kindTypes)
+	fmt.Fprintf(&b, "  - TypesSize: %04x\n", len(c.types)*eofTypeSize)
+	fmt.Fprintf(&b, "  - KindCode: %02x\n", kindCode)
+	fmt.Fprintf(&b, "  - NumCodeSections: %04x\n", len(c.codeSections))
+	for i, code := range c.codeSections {
+		fmt.Fprintf(&b, "  - Code section %d length: %04x\n", i, len(code))
+	}
+	if len(c.subContainers) > 0 {
+		fmt.Fprintf(&b, "  - KindContainer: %02x\n", kindContainer)
+		fmt.Fprintf(&b, "  - NumContainerSections: %04x\n", len(c.subContainers))
+		for i, section := range c.subContainerCodes {
+			fmt.Fprintf(&b, "  - Container section %d length: %08x\n", i, len(section))
+		}
+	}
+	fmt.Fprintf(&b, "  - KindData: %02x\n", kindData)
+	fmt.Fprintf(&b, "  - DataSize: %04x\n", c.dataSize)
+	fmt.Fprintf(&b, "  - Terminator: 0x00\n")
+	fmt.Fprintf(&b, "Body\n")
+	for i, ty := range c.types {
+		fmt.Fprintf(&b, "  - Type %d: inputs %d, outputs %#x, max stack increase %d\n", i, ty.inputs, ty.outputs, ty.maxStackIncrease)
+	}
+	for i, code := range c.codeSections {
+		fmt.Fprintf(&b, "  - Code section %d: %#x\n", i, code)
+	}
+	for i, section := range c.subContainerCodes {
+		fmt.Fprintf(&b, "  - Container section %d: %#x\n", i, section)
+	}
+	fmt.Fprintf(&b, "  - Data: %#x\n", c.data)
+	return b.String()
+}
+
+// eofContainer returns the validated EOF container of deployed code, which is
+// cached by code hash.
+func (evm *EVM) eofContainer(codeHash common.Hash, code []byte) (*Container, error) {
+	if c, ok := evm.eofContainers[codeHash]; ok {
+		return c, nil
+	}
+	c := new(Container)
+	if err := c.UnmarshalBinary(code); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEOF, err)
+	}
+	if err := c.ValidateCode(evm.eofTable, false); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEOF, err)
+	}
+	if codeHash != (common.Hash{}) {
+		if evm.eofContainers == nil {
+			evm.eofContainers = make(map[common.Hash]*Container)
+		}
+		evm.eofContainers[codeHash] = c
+	}
+	return c, nil
+}
+
+// ValidateEOF parses and validates an EOF container against the latest EOF
+// instruction set. It is meant for tooling, the EVM validates containers with
+// the instruction set of the active fork.
+func ValidateEOF(code []byte, isInitCode bool) (*Container, error) {
+	c := new(Container)
+	if err := c.UnmarshalBinary(code); err != nil {
+		return nil, err
+	}
+	if err := c.ValidateCode(&osakaEOFInstructionSet, isInitCode); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// eofExceptions maps the EOF validation errors to the names of the matching
+// exceptions in the execution spec tests. Some errors cover several checks,
+// they match all of the related exceptions.
+var eofExceptions = []struct {
+	err   error
+	names []string
+}{
+	{errInvalidMagic, []string{"INVALID_MAGIC", "INCOMPLETE_MAGIC"}},
+	{errUnsupportedVersion, []string{"INVALID_VERSION", "UNKNOWN_VERSION"}},
+	{errMissingTypeHeader, []string{"MISSING_TYPE_HEADER"}},
+	{errInvalidTypeSize, []string{"INVALID_TYPE_SECTION_SIZE", "ZERO_SECTION_SIZE"}},
+	{errMissingCodeHeader, []string{"MISSING_CODE_HEADER"}},
+	{errInvalidCodeSize, []string{"ZERO_SECTION_SIZE"}},
+	{errInvalidContainerSize, []string{"ZERO_SECTION_SIZE"}},
+	{errMissingDataHeader, []string{"MISSING_DATA_SECTION", "MISSING_HEADERS_TERMINATOR"}},
+	{errMissingTerminator, []string{"MISSING_TERMINATOR", "MISSING_HEADERS_TERMINATOR"}},
+	{errTooManySections, []string{"TOO_MANY_CODE_SECTIONS", "TOO_MANY_CONTAINERS"}},
+	{errInvalidSectionCount, []string{"ZERO_SECTION_SIZE", "INCOMPLETE_SECTION_NUMBER"}},
+	{errInvalidContainerLength, []string{"INVALID_SECTION_BODIES_SIZE"}},
+	{errTruncatedContainer, []string{"INCOMPLETE_SECTION_NUMBER", "INCOMPLETE_SECTION_SIZE", "MISSING_HEADERS_TERMINATOR", "INVALID_SECTION_BODIES_SIZE", "TOPLEVEL_CONTAINER_TRUNCATED"}},
+	{errContainerTooLarge, []string{"CONTAINER_SIZE_ABOVE_LIMIT"}},
+	{errInvalidSection0Type, []string{"INVALID_FIRST_SECTION_TYPE"}},
+	{errTooManyInputs, []string{"INPUTS_OUTPUTS_NUM_ABOVE_LIMIT"}},
+	{errTooManyOutputs, []string{"INPUTS_OUTPUTS_NUM_ABOVE_LIMIT"}},
+	{errTooLargeMaxStackHeight, []string{"MAX_STACK_INCREASE_ABOVE_LIMIT"}},
+	{errUndefinedInstruction, []string{"UNDEFINED_INSTRUCTION"}},
+	{errTruncatedImmediate, []string{"TRUNCATED_INSTRUCTION"}},
+	{errInvalidSectionArgument, []string{"INVALID_CODE_SECTION_INDEX"}},
+	{errInvalidCallArgument, []string{"CALLF_TO_NON_RETURNING"}},
+	{errInvalidDataloadNArgument, []string{"INVALID_DATALOADN_INDEX"}},
+	{errInvalidJumpDest, []string{"INVALID_RJUMP_DESTINATION"}},
+	{errInvalidBackwardJump, []string{"STACK_HEIGHT_MISMATCH"}},
+	{errInvalidOutputs, []string{"JUMPF_DESTINATION_INCOMPATIBLE_OUTPUTS", "STACK_HIGHER_THAN_OUTPUTS", "STACK_UNDERFLOW"}},
+	{errInvalidMaxStackHeight, []string{"INVALID_MAX_STACK_INCREASE"}},
+	{errInvalidCodeTermination, []string{"MISSING_STOP_OPCODE"}},
+	{errInvalidNonReturning, []string{"INVALID_NON_RETURNING_FLAG"}},
+	{errInvalidContainerArgument, []string{"INVALID_CONTAINER_SECTION_INDEX"}},
+	{errEOFCreateWithTruncated, []string{"EOFCREATE_WITH_TRUNCATED_CONTAINER"}},
+	{errIncompatibleContainerKind, []string{"INCOMPATIBLE_CONTAINER_KIND"}},
+	{errOrphanedSubcontainer, []string{"ORPHAN_SUBCONTAINER"}},
+	{errUnreachableCode, []string{"UNREACHABLE_INSTRUCTIONS"}},
+	{errUnreachableSection, []string{"UNREACHABLE_CODE_SECTIONS"}},
+	{errStackUnderflow, []string{"STACK_UNDERFLOW"}},
+	{errStackOverflow, []string{"STACK_OVERFLOW", "MAX_STACK_INCREASE_ABOVE_LIMIT"}},
+}
+
+// EOFExceptions returns the names of the execution spec test exceptions (without
+// the "EOFException." prefix) matching an error returned by ValidateEOF.
+func EOFExceptions(err error) []string {
+	for _, e := range eofExceptions {
+		if errors.Is(err, e.err) {
+			return e.names
+		}
+	}
+	return nil
+}
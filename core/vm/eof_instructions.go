@@ -0,0 +1,597 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+const (
+	minRetainedGas = 5000 // Gas kept by the caller of EXTCALL and friends (EIP-7069)
+	minCalleeGas   = 2300 // Minimum gas passed to the callee of EXTCALL and friends (EIP-7069)
+)
+
+// Status codes pushed by EXTCALL and friends.
+const (
+	extCallSuccess = 0
+	extCallRevert  = 1
+	extCallFailure = 2
+)
+
+// eofReturnFrame is an entry of the return stack of an EOF execution.
+type eofReturnFrame struct {
+	section int
+	pc      uint64
+}
+
+// newEOFInstructionSet returns the instruction set for EOF code, derived from
+// the legacy instruction set of the fork it is enabled on.
+func newEOFInstructionSet(legacy *JumpTable) JumpTable {
+	instructionSet := *copyJumpTable(legacy)
+	enableEOF(&instructionSet)
+	return validate(instructionSet)
+}
+
+// enableEOF applies the EVM Object Format (EIP-7692) changes to an instruction
+// set, which is only used to execute EOF containers.
+func enableEOF(jt *JumpTable) {
+	// Instructions observing code or gas, and legacy control flow, are rejected
+	// by code validation.
+	for _, op := range []OpCode{
+		CALL, CALLCODE, DELEGATECALL, STATICCALL, SELFDESTRUCT, JUMP, JUMPI, PC,
+		CREATE, CREATE2, CODESIZE, CODECOPY, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH, GAS,
+	} {
+		jt[op] = &operation{execute: opUndefined, maxStack: maxStack(0, 0), undefined: true}
+	}
+	jt[RJUMP] = &operation{
+		execute:     opRjump,
+		constantGas: GasQuickStep,
+		minStack:    minStack(0, 0),
+		maxStack:    maxStack(0, 0),
+	}
+	jt[RJUMPI] = &operation{
+		execute:     opRjumpi,
+		constantGas: GasFastishStep,
+		minStack:    minStack(1, 0),
+		maxStack:    maxStack(1, 0),
+	}
+	jt[RJUMPV] = &operation{
+		execute:     opRjumpv,
+		constantGas: GasFastishStep,
+		minStack:    minStack(1, 0),
+		maxStack:    maxStack(1, 0),
+	}
+	jt[CALLF] = &operation{
+		execute:     opCallf,
+		constantGas: GasFastStep,
+		minStack:    minStack(0, 0),
+		maxStack:    maxStack(0, 0),
+	}
+	jt[RETF] = &operation{
+		execute:     opRetf,
+		constantGas: GasFastestStep,
+		minStack:    minStack(0, 0),
+		maxStack:    maxStack(0, 0),
+	}
+	jt[JUMPF] = &operation{
+		execute:     opJumpf,
+		constantGas: GasFastStep,
+		minStack:    minStack(0, 0),
+		maxStack:    maxStack(0, 0),
+	}
+	jt[DATALOAD] = &operation{
+		execute:     opDataLoad,
+		constantGas: GasFastishStep,
+		minStack:    minStack(1, 1),
+		maxStack:    maxStack(1, 1),
+	}
+	jt[DATALOADN] = &operation{
+		execute:     opDataLoadN,
+		constantGas: GasFastestStep,
+		minStack:    minStack(0, 1),
+		maxStack:    maxStack(0, 1),
+	}
+	jt[DATASIZE] = &operation{
+		execute:     opDataSize,
+		constantGas: GasQuickStep,
+		minStack:    minStack(0, 1),
+		maxStack:    maxStack(0, 1),
+	}
+	jt[DATACOPY] = &operation{
+		execute:     opDataCopy,
+		constantGas: GasFastestStep,
+		dynamicGas:  gasDataCopy,
+		minStack:    minStack(3, 0),
+		maxStack:    maxStack(3, 0),
+		memorySize:  memoryDataCopy,
+	}
+	jt[DUPN] = &operation{
+		execute:     opDupNEOF,
+		constantGas: GasFastestStep,
+		minStack:    minStack(1, 2),
+		maxStack:    maxStack(1, 2),
+	}
+	jt[SWAPN] = &operation{
+		execute:     opSwapNEOF,
+		constantGas: GasFastestStep,
+		minStack:    minStack(2, 2),
+		maxStack:    maxStack(2, 2),
+	}
+	jt[EXCHANGE] = &operation{
+		execute:     opExchangeEOF,
+		constantGas: GasFastestStep,
+		minStack:    minStack(3, 3),
+		maxStack:    maxStack(3, 3),
+	}
+	jt[RETURNDATALOAD] = &operation{
+		execute:     opReturnDataLoad,
+		constantGas: GasFastestStep,
+		minStack:    minStack(1, 1),
+		maxStack:    maxStack(1, 1),
+	}
+	jt[RETURNDATACOPY] = &operation{
+		execute:     opReturnDataCopyEOF,
+		constantGas: GasFastestStep,
+		dynamicGas:  gasReturnDataCopy,
+		minStack:    minStack(3, 0),
+		maxStack:    maxStack(3, 0),
+		memorySize:  memoryReturnDataCopy,
+	}
+	jt[EOFCREATE] = &operation{
+		execute:     opEOFCreate,
+		constantGas: params.Create2Gas,
+		dynamicGas:  pureMemoryGascost,
+		minStack:    minStack(4, 1),
+		maxStack:    maxStack(4, 1),
+		memorySize:  memoryEOFCreate,
+	}
+	jt[RETURNCONTRACT] = &operation{
+		execute:     opReturnContract,
+		constantGas: 0,
+		dynamicGas:  pureMemoryGascost,
+		minStack:    minStack(2, 0),
+		maxStack:    maxStack(2, 0),
+		memorySize:  memoryReturnContract,
+	}
+	jt[EXTCALL] = &operation{
+		execute:     opExtCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtCall,
+		minStack:    minStack(4, 1),
+		maxStack:    maxStack(4, 1),
+		memorySize:  memoryExtCall,
+	}
+	jt[EXTDELEGATECALL] = &operation{
+		execute:     opExtDelegateCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtDelegateCall,
+		minStack:    minStack(3, 1),
+		maxStack:    maxStack(3, 1),
+		memorySize:  memoryExtCall,
+	}
+	jt[EXTSTATICCALL] = &operation{
+		execute:     opExtStaticCall,
+		constantGas: params.WarmStorageReadCostEIP2929,
+		dynamicGas:  gasExtStaticCall,
+		minStack:    minStack(3, 1),
+		maxStack:    maxStack(3, 1),
+		memorySize:  memoryExtCall,
+	}
+}
+
+// jumpTo moves the pc to the given position, the interpreter loop increments
+// the pc after every instruction.
+func jumpTo(pc *uint64, pos uint64) {
+	*pc = pos - 1
+}
+
+func opRjump(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	offset := parseInt16(scope.Contract.Code[*pc+1:])
+	jumpTo(pc, uint64(int64(*pc)+3+int64(offset)))
+	return nil, nil
+}
+
+func opRjumpi(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	condition := scope.Stack.pop()
+	if condition.IsZero() {
+		*pc += 2
+		return nil, nil
+	}
+	return opRjump(pc, evm, scope)
+}
+
+func opRjumpv(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		code  = scope.Contract.Code
+		count = uint64(code[*pc+1]) + 1
+		index = scope.Stack.pop()
+		next  = *pc + 2 + 2*count
+	)
+	if !index.IsUint64() || index.Uint64() >= count {
+		// Out of bounds, fall through to the next instruction.
+		jumpTo(pc, next)
+		return nil, nil
+	}
+	offset := parseInt16(code[*pc+2+2*index.Uint64():])
+	jumpTo(pc, uint64(int64(next)+int64(offset)))
+	return nil, nil
+}
+
+func opCallf(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		container = scope.Contract.Container
+		section   = parseUint16(scope.Contract.Code[*pc+1:])
+		typ       = container.types[section]
+	)
+	if len(scope.returnStack) >= maxReturnStackHeight {
+		return nil, ErrReturnStackExceeded
+	}
+	if limit := int(params.StackLimit) - int(typ.maxStackIncrease); scope.Stack.len() > limit {
+		return nil, &ErrStackOverflow{stackLen: scope.Stack.len(), limit: limit}
+	}
+	scope.returnStack = append(scope.returnStack, eofReturnFrame{section: scope.codeSection, pc: *pc + 3})
+	scope.codeSection = section
+	scope.Contract.Code = container.codeSections[section]
+	jumpTo(pc, 0)
+	return nil, nil
+}
+
+func opRetf(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	frame := scope.returnStack[len(scope.returnStack)-1]
+	scope.returnStack = scope.returnStack[:len(scope.returnStack)-1]
+	scope.codeSection = frame.section
+	scope.Contract.Code = scope.Contract.Container.codeSections[frame.section]
+	jumpTo(pc, frame.pc)
+	return nil, nil
+}
+
+func opJumpf(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		container = scope.Contract.Container
+		section   = parseUint16(scope.Contract.Code[*pc+1:])
+		typ       = container.types[section]
+	)
+	if limit := int(params.StackLimit) - int(typ.maxStackIncrease); scope.Stack.len() > limit {
+		return nil, &ErrStackOverflow{stackLen: scope.Stack.len(), limit: limit}
+	}
+	scope.codeSection = section
+	scope.Contract.Code = container.codeSections[section]
+	jumpTo(pc, 0)
+	return nil, nil
+}
+
+func opDataLoad(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	x := scope.Stack.peek()
+	if offset, overflow := x.Uint64WithOverflow(); !overflow {
+		x.SetBytes(getData(scope.Contract.Container.data, offset, 32))
+	} else {
+		x.Clear()
+	}
+	return nil, nil
+}
+
+func opDataLoadN(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	offset := uint64(parseUint16(scope.Contract.Code[*pc+1:]))
+	scope.Stack.push(new(uint256.Int).SetBytes(getData(scope.Contract.Container.data, offset, 32)))
+	*pc += 2
+	return nil, nil
+}
+
+func opDataSize(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	scope.Stack.push(new(uint256.Int).SetUint64(uint64(len(scope.Contract.Container.data))))
+	return nil, nil
+}
+
+func opDataCopy(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		memOffset  = scope.Stack.pop()
+		dataOffset = scope.Stack.pop()
+		length     = scope.Stack.pop()
+	)
+	offset64, overflow := dataOffset.Uint64WithOverflow()
+	if overflow {
+		offset64 = math.MaxUint64
+	}
+	scope.Memory.Set(memOffset.Uint64(), length.Uint64(), getData(scope.Contract.Container.data, offset64, length.Uint64()))
+	return nil, nil
+}
+
+func opDupNEOF(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	n := int(scope.Contract.Code[*pc+1]) + 1
+	scope.Stack.dup(n)
+	*pc += 1
+	return nil, nil
+}
+
+func opSwapNEOF(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		n    = int(scope.Contract.Code[*pc+1]) + 1
+		data = scope.Stack.data
+		top  = len(data) - 1
+	)
+	data[top], data[top-n] = data[top-n], data[top]
+	*pc += 1
+	return nil, nil
+}
+
+func opExchangeEOF(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		imm  = scope.Contract.Code[*pc+1]
+		n    = int(imm>>4) + 1
+		m    = int(imm&0x0f) + 1
+		data = scope.Stack.data
+		top  = len(data) - 1
+	)
+	data[top-n], data[top-n-m] = data[top-n-m], data[top-n]
+	*pc += 1
+	return nil, nil
+}
+
+func opReturnDataLoad(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	x := scope.Stack.peek()
+	if offset, overflow := x.Uint64WithOverflow(); !overflow {
+		x.SetBytes(getData(evm.returnData, offset, 32))
+	} else {
+		x.Clear()
+	}
+	return nil, nil
+}
+
+// opReturnDataCopyEOF is RETURNDATACOPY in EOF code, which pads out of bounds
+// reads with zeroes instead of failing.
+func opReturnDataCopyEOF(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		memOffset  = scope.Stack.pop()
+		dataOffset = scope.Stack.pop()
+		length     = scope.Stack.pop()
+	)
+	offset64, overflow := dataOffset.Uint64WithOverflow()
+	if overflow {
+		offset64 = math.MaxUint64
+	}
+	scope.Memory.Set(memOffset.Uint64(), length.Uint64(), getData(evm.returnData, offset64, length.Uint64()))
+	return nil, nil
+}
+
+func opEOFCreate(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	if evm.readOnly {
+		return nil, ErrWriteProtection
+	}
+	var (
+		index        = int(scope.Contract.Code[*pc+1])
+		value        = scope.Stack.pop()
+		salt         = scope.Stack.pop()
+		offset, size = scope.Stack.pop(), scope.Stack.pop()
+		input        = scope.Memory.GetCopy(offset.Uint64(), size.Uint64())
+		gas          = scope.Contract.Gas
+	)
+	*pc += 1
+
+	gas -= gas / 64
+	scope.Contract.UseGas(gas, evm.Config.Tracer, tracing.GasChangeCallContractCreation2)
+
+	res, addr, returnGas, suberr := evm.EOFCreate(scope.Contract.Address(), scope.Contract.Container, index, input, gas, &value, &salt)
+	stackvalue := size
+	if suberr != nil {
+		stackvalue.Clear()
+	} else {
+		stackvalue.SetBytes(addr.Bytes())
+	}
+	scope.Stack.push(&stackvalue)
+	scope.Contract.RefundGas(returnGas, evm.Config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+
+	if suberr == ErrExecutionReverted {
+		evm.returnData = res // set REVERT data to return data buffer
+		return res, nil
+	}
+	evm.returnData = nil // clear dirty return data buffer
+	return nil, nil
+}
+
+func opReturnContract(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		index        = int(scope.Contract.Code[*pc+1])
+		offset, size = scope.Stack.pop(), scope.Stack.pop()
+		aux          = scope.Memory.GetPtr(offset.Uint64(), size.Uint64())
+		deploy       = *scope.Contract.Container.subContainers[index]
+	)
+	// Append the aux data to the data section of the deployed container, which
+	// has to be complete afterwards.
+	dataSize := len(deploy.data) + len(aux)
+	if dataSize < deploy.dataSize {
+		return nil, ErrInvalidEOFDeployment
+	}
+	if dataSize > maxDataSize {
+		return nil, ErrInvalidEOFDeployment
+	}
+	deploy.data = append(common.CopyBytes(deploy.data), aux...)
+	deploy.dataSize = dataSize
+	return deploy.MarshalBinary(), errStopToken
+}
+
+// extCallGas returns the gas passed to the callee of EXTCALL and friends, or
+// zero if it is below the minimum.
+func extCallGas(available uint64) uint64 {
+	retained := max(available/64, minRetainedGas)
+	if available < retained+minCalleeGas {
+		return 0
+	}
+	return available - retained
+}
+
+// makeGasExtCall creates the gas function of EXTCALL and friends, charging for
+// the account access, value transfer, memory expansion and the callee gas.
+func makeGasExtCall(transfersValue bool) gasFunc {
+	return func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		// The target address must not have any of the high 12 bytes set.
+		target := stack.Back(0)
+		if target.BitLen() > 160 {
+			return 0, ErrInvalidEOFAddress
+		}
+		gas, err := memoryGasCost(mem, memorySize)
+		if err != nil {
+			return 0, err
+		}
+		addr := common.Address(target.Bytes20())
+		if !evm.StateDB.AddressInAccessList(addr) {
+			evm.StateDB.AddAddressToAccessList(addr)
+			gas += params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+		}
+		if delegate, ok := types.ParseDelegation(evm.StateDB.GetCode(addr)); ok {
+			if evm.StateDB.AddressInAccessList(delegate) {
+				gas += params.WarmStorageReadCostEIP2929
+			} else {
+				evm.StateDB.AddAddressToAccessList(delegate)
+				gas += params.ColdAccountAccessCostEIP2929
+			}
+		}
+		if transfersValue && !stack.Back(3).IsZero() {
+			if evm.readOnly {
+				return 0, ErrWriteProtection
+			}
+			gas += params.CallValueTransferGas
+			if evm.StateDB.Empty(addr) {
+				gas += params.CallNewAccountGas
+			}
+		}
+		if contract.Gas < gas {
+			return gas, nil // the interpreter fails with out of gas
+		}
+		// The callee gas is charged upfront and refunded after the call, it can't
+		// overflow as it's limited to the gas left.
+		evm.callGasTemp = extCallGas(contract.Gas - gas)
+		return gas + evm.callGasTemp, nil
+	}
+}
+
+var (
+	gasExtCall         = makeGasExtCall(true)
+	gasExtDelegateCall = makeGasExtCall(false)
+	gasExtStaticCall   = makeGasExtCall(false)
+)
+
+// extCallStatus returns the status pushed by EXTCALL and friends for the error
+// returned by the call.
+func extCallStatus(err error) uint64 {
+	switch err {
+	case nil:
+		return extCallSuccess
+	case ErrExecutionReverted, ErrDepth, ErrInsufficientBalance:
+		return extCallRevert
+	}
+	return extCallFailure
+}
+
+// extCallLightFailure pushes the status of a call which wasn't made, returning
+// the callee gas to the caller.
+func extCallLightFailure(evm *EVM, scope *ScopeContext) ([]byte, error) {
+	scope.Contract.RefundGas(evm.callGasTemp, evm.Config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+	scope.Stack.push(new(uint256.Int).SetUint64(extCallRevert))
+	evm.returnData = nil
+	return nil, nil
+}
+
+// extCallResult pushes the status of a call and sets the return data buffer.
+func extCallResult(evm *EVM, scope *ScopeContext, ret []byte, returnGas uint64, err error) ([]byte, error) {
+	scope.Stack.push(new(uint256.Int).SetUint64(extCallStatus(err)))
+	scope.Contract.RefundGas(returnGas, evm.Config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+	evm.returnData = ret
+	return ret, nil
+}
+
+func opExtCall(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		stack                         = scope.Stack
+		addr, inOffset, inSize, value = stack.pop(), stack.pop(), stack.pop(), stack.pop()
+		toAddr                        = common.Address(addr.Bytes20())
+		args                          = scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
+	)
+	if evm.callGasTemp == 0 {
+		return extCallLightFailure(evm, scope)
+	}
+	ret, returnGas, err := evm.Call(scope.Contract.Address(), toAddr, args, evm.callGasTemp, &value)
+	return extCallResult(evm, scope, ret, returnGas, err)
+}
+
+func opExtDelegateCall(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		stack                  = scope.Stack
+		addr, inOffset, inSize = stack.pop(), stack.pop(), stack.pop()
+		toAddr                 = common.Address(addr.Bytes20())
+		args                   = scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
+	)
+	// Only EOF contracts can be delegated to.
+	if evm.callGasTemp == 0 || !HasEOFMagic(evm.StateDB.GetCode(toAddr)) {
+		return extCallLightFailure(evm, scope)
+	}
+	ret, returnGas, err := evm.DelegateCall(scope.Contract.Caller(), scope.Contract.Address(), toAddr, args, evm.callGasTemp, scope.Contract.value)
+	return extCallResult(evm, scope, ret, returnGas, err)
+}
+
+func opExtStaticCall(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
+	var (
+		stack                  = scope.Stack
+		addr, inOffset, inSize = stack.pop(), stack.pop(), stack.pop()
+		toAddr                 = common.Address(addr.Bytes20())
+		args                   = scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
+	)
+	if evm.callGasTemp == 0 {
+		return extCallLightFailure(evm, scope)
+	}
+	ret, returnGas, err := evm.StaticCall(scope.Contract.Address(), toAddr, args, evm.callGasTemp)
+	return extCallResult(evm, scope, ret, returnGas, err)
+}
+
+// delegatesToEOF returns whether legacy code delegating to the address would
+// run EOF code, which is not allowed.
+func (evm *EVM) delegatesToEOF(addr common.Address) bool {
+	return evm.eofTable != nil && HasEOFMagic(evm.resolveCode(addr))
+}
+
+// delegateToEOFFailure pushes the failure of a legacy DELEGATECALL or CALLCODE
+// to EOF code, returning the call gas to the caller.
+func delegateToEOFFailure(evm *EVM, scope *ScopeContext, status *uint256.Int, gas uint64) ([]byte, error) {
+	status.Clear()
+	scope.Stack.push(status)
+	scope.Contract.RefundGas(gas, evm.Config.Tracer, tracing.GasChangeCallLeftOverRefunded)
+	evm.returnData = nil
+	return nil, nil
+}
+
+var gasDataCopy = memoryCopierGas(2)
+
+func memoryDataCopy(stack *Stack) (uint64, bool) {
+	return calcMemSize64(stack.Back(0), stack.Back(2))
+}
+
+func memoryEOFCreate(stack *Stack) (uint64, bool) {
+	return calcMemSize64(stack.Back(2), stack.Back(3))
+}
+
+func memoryReturnContract(stack *Stack) (uint64, bool) {
+	return calcMemSize64(stack.Back(0), stack.Back(1))
+}
+
+func memoryExtCall(stack *Stack) (uint64, bool) {
+	return calcMemSize64(stack.Back(1), stack.Back(2))
+}
@@ -0,0 +1,345 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// section is a code section of a test container.
+type section struct {
+	inputs, outputs uint8
+	maxStack        uint16
+	code            []byte
+}
+
+// newTestContainer assembles an EOF container from its sections.
+func newTestContainer(sections []section, subContainers [][]byte, data []byte) []byte {
+	c := &Container{data: data, dataSize: len(data)}
+	for _, s := range sections {
+		c.types = append(c.types, &functionMetadata{inputs: s.inputs, outputs: s.outputs, maxStackIncrease: s.maxStack})
+		c.codeSections = append(c.codeSections, s.code)
+	}
+	for _, sub := range subContainers {
+		c.subContainers = append(c.subContainers, new(Container))
+		c.subContainerCodes = append(c.subContainerCodes, sub)
+	}
+	return c.MarshalBinary()
+}
+
+func TestEOFMarshaling(t *testing.T) {
+	sub := newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, []byte{1, 2})
+	for i, code := range [][]byte{
+		newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, nil),
+		newTestContainer([]section{{0, 0x80, 1, []byte{byte(PUSH0), byte(POP), byte(STOP)}}, {1, 1, 0, []byte{byte(RETF)}}}, nil, []byte("data")),
+		newTestContainer([]section{{0, 0x80, 0, []byte{byte(INVALID)}}}, [][]byte{sub}, nil),
+	} {
+		var c Container
+		if err := c.UnmarshalBinary(code); err != nil {
+			t.Fatalf("test %d: failed to unmarshal: %v", i, err)
+		}
+		if have := c.MarshalBinary(); !bytes.Equal(have, code) {
+			t.Errorf("test %d: roundtrip mismatch\nhave %x\nwant %x", i, have, code)
+		}
+	}
+}
+
+func TestEOFUnmarshalErrors(t *testing.T) {
+	valid := newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, []byte{0xaa})
+	for i, tt := range []struct {
+		code []byte
+		err  error
+	}{
+		{common.FromHex("ef"), errInvalidMagic},
+		{common.FromHex("ef0002"), errUnsupportedVersion},
+		{common.FromHex("ef000102"), errMissingTypeHeader},
+		{common.FromHex("ef0001010003"), errInvalidTypeSize},
+		{common.FromHex("ef000101000403"), errMissingCodeHeader},
+		{common.FromHex("ef0001010004020000"), errInvalidSectionCount},
+		{common.FromHex("ef000101000402000100000400000000800000"), errInvalidCodeSize},
+		{common.FromHex("ef000101000402000100010500000000800000"), errMissingDataHeader},
+		{common.FromHex("ef00010100040200010001ff00000100800000"), errMissingTerminator},
+		{common.FromHex("ef00010100040200010001ff0000000000000000"), errInvalidSection0Type},
+		{common.FromHex("ef00010100040200010001ff0000000080"), errTruncatedContainer},
+		{valid[:len(valid)-1], errTruncatedContainer},
+		{append(valid, 0x00), errInvalidContainerLength},
+	} {
+		var c Container
+		err := c.UnmarshalBinary(tt.code)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if len(EOFExceptions(err)) == 0 {
+			t.Errorf("test %d: no exception known for %v", i, err)
+		}
+	}
+}
+
+func TestEOFValidation(t *testing.T) {
+	var (
+		stop       = section{0, 0x80, 0, []byte{byte(STOP)}}
+		initcode   = newTestContainer([]section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURNCONTRACT), 0}}}, [][]byte{newTestContainer([]section{stop}, nil, nil)}, nil)
+		returnSize = newTestContainer([]section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURN)}}}, nil, nil)
+	)
+	for i, tt := range []struct {
+		sections []section
+		subs     [][]byte
+		data     []byte
+		initcode bool
+		err      error
+	}{
+		{sections: []section{stop}},
+		{sections: []section{{0, 0x80, 1, []byte{byte(PUSH1), 1, byte(POP), byte(STOP)}}}},
+		// Legacy opcodes and truncated immediates.
+		{sections: []section{{0, 0x80, 1, []byte{byte(PUSH1), 0, byte(JUMP)}}}, err: errUndefinedInstruction},
+		{sections: []section{{0, 0x80, 0, []byte{byte(GAS), byte(STOP)}}}, err: errUndefinedInstruction},
+		{sections: []section{{0, 0x80, 0, []byte{byte(PUSH2), 0}}}, err: errTruncatedImmediate},
+		{sections: []section{{0, 0x80, 1, []byte{byte(PUSH0), byte(POP)}}}, err: errInvalidCodeTermination},
+		// Relative jumps.
+		{sections: []section{{0, 0x80, 0, []byte{byte(RJUMP), 0xff, 0xfd}}}},
+		{sections: []section{{0, 0x80, 0, []byte{byte(RJUMP), 0x00, 0x01, byte(PUSH1), 0x00}}}, err: errInvalidJumpDest},
+		{sections: []section{{0, 0x80, 0, []byte{byte(RJUMP), 0x00, 0x05, byte(STOP)}}}, err: errInvalidJumpDest},
+		{sections: []section{{0, 0x80, 1, []byte{byte(PUSH0), byte(RJUMP), 0xff, 0xfc}}}, err: errInvalidBackwardJump},
+		{sections: []section{{0, 0x80, 1, []byte{byte(PUSH0), byte(RJUMPV), 1, 0x00, 0x00, 0x00, 0x01, byte(STOP), byte(STOP)}}}},
+		// Code sections and functions.
+		{sections: []section{{0, 0x80, 0, []byte{byte(CALLF), 0, 1, byte(STOP)}}, {0, 0, 0, []byte{byte(RETF)}}}},
+		{sections: []section{{0, 0x80, 0, []byte{byte(CALLF), 0, 2, byte(STOP)}}, {0, 0, 0, []byte{byte(RETF)}}}, err: errInvalidSectionArgument},
+		{sections: []section{{0, 0x80, 0, []byte{byte(CALLF), 0, 1, byte(STOP)}}, {0, 0x80, 0, []byte{byte(STOP)}}}, err: errInvalidCallArgument},
+		{sections: []section{stop, stop}, err: errUnreachableSection},
+		{sections: []section{{0, 0x80, 0, []byte{byte(CALLF), 0, 1, byte(STOP)}}, {0, 0, 0, []byte{byte(STOP)}}}, err: errInvalidNonReturning},
+		{sections: []section{{0, 0x80, 0, []byte{byte(JUMPF), 0, 1}}, stop}},
+		{sections: []section{{0, 0x80, 1, []byte{byte(CALLF), 0, 1, byte(STOP)}}, {0, 1, 0, []byte{byte(RETF)}}}, err: errInvalidOutputs},
+		{sections: []section{{0, 0x80, 0, []byte{byte(CALLF), 0, 1, byte(STOP)}}, {2, 1, 0, []byte{byte(ADD), byte(RETF)}}}, err: errStackUnderflow},
+		{sections: []section{{0, 0x80, 2, []byte{byte(PUSH0), byte(STOP)}}}, err: errInvalidMaxStackHeight},
+		// Data section.
+		{sections: []section{{0, 0x80, 1, []byte{byte(DATALOADN), 0, 0, byte(POP), byte(STOP)}}}, data: make([]byte, 32)},
+		{sections: []section{{0, 0x80, 1, []byte{byte(DATALOADN), 0, 1, byte(POP), byte(STOP)}}}, data: make([]byte, 32), err: errInvalidDataloadNArgument},
+		// Subcontainers.
+		{sections: []section{{0, 0x80, 4, []byte{byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(EOFCREATE), 0, byte(POP), byte(STOP)}}}, subs: [][]byte{initcode}},
+		{sections: []section{{0, 0x80, 4, []byte{byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(EOFCREATE), 1, byte(POP), byte(STOP)}}}, subs: [][]byte{initcode}, err: errInvalidContainerArgument},
+		{sections: []section{{0, 0x80, 4, []byte{byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(PUSH0), byte(EOFCREATE), 0, byte(POP), byte(STOP)}}}, subs: [][]byte{returnSize}, err: errIncompatibleContainerKind},
+		{sections: []section{stop}, subs: [][]byte{initcode}, err: errOrphanedSubcontainer},
+		{sections: []section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURNCONTRACT), 0}}}, subs: [][]byte{initcode}, err: errIncompatibleContainerKind},
+		{sections: []section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURNCONTRACT), 0}}}, subs: [][]byte{newTestContainer([]section{stop}, nil, nil)}, initcode: true},
+		{sections: []section{stop}, initcode: true, err: errIncompatibleContainerKind},
+	} {
+		var c Container
+		if err := c.UnmarshalBinary(newTestContainer(tt.sections, tt.subs, tt.data)); err != nil {
+			t.Fatalf("test %d: failed to unmarshal: %v", i, err)
+		}
+		err := c.ValidateCode(&pragueEOFInstructionSet, tt.initcode)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+		if err != nil && len(EOFExceptions(err)) == 0 {
+			t.Errorf("test %d: no exception known for %v", i, err)
+		}
+	}
+}
+
+// newEOFTestEVM creates an EVM with EOF activated.
+func newEOFTestEVM(t *testing.T) (*EVM, *state.StateDB) {
+	t.Helper()
+
+	config := *params.MergedTestChainConfig
+	config.EOFTime = new(uint64)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	vmctx := BlockContext{
+		CanTransfer: func(db StateDB, addr common.Address, amount *uint256.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		BlockNumber: big.NewInt(0),
+		Random:      &common.Hash{},
+	}
+	evm := NewEVM(vmctx, statedb, &config, Config{})
+	if !evm.chainRules.IsEOF {
+		t.Fatal("eof not activated")
+	}
+	return evm, statedb
+}
+
+func TestEOFExecution(t *testing.T) {
+	var (
+		// Stores the top of the stack to memory and returns it.
+		returnTop = []byte{byte(PUSH0), byte(MSTORE), byte(PUSH1), 0x20, byte(PUSH0), byte(RETURN)}
+		word      = func(v uint64) []byte { return common.LeftPadBytes(new(big.Int).SetUint64(v).Bytes(), 32) }
+	)
+	for i, tt := range []struct {
+		sections []section
+		data     []byte
+		want     []byte
+	}{
+		// Add two numbers in a function.
+		{
+			sections: []section{
+				{0, 0x80, 2, append([]byte{byte(PUSH1), 3, byte(PUSH1), 4, byte(CALLF), 0, 1}, returnTop...)},
+				{2, 1, 0, []byte{byte(ADD), byte(RETF)}},
+			},
+			want: word(7),
+		},
+		// Take the RJUMPI branch skipping over an RJUMP.
+		{
+			sections: []section{
+				{0, 0x80, 2, append([]byte{byte(PUSH1), 1, byte(RJUMPI), 0, 5, byte(PUSH1), 0xbb, byte(RJUMP), 0, 2, byte(PUSH1), 0xaa}, returnTop...)},
+			},
+			want: word(0xaa),
+		},
+		// Select the second case of RJUMPV.
+		{
+			sections: []section{
+				{0, 0x80, 2, append([]byte{byte(PUSH1), 1, byte(RJUMPV), 1, 0, 5, 0, 10, byte(PUSH1), 0xaa, byte(RJUMP), 0, 7, byte(PUSH1), 0xbb, byte(RJUMP), 0, 2, byte(PUSH1), 0xcc}, returnTop...)},
+			},
+			want: word(0xcc),
+		},
+		// Load from the data section.
+		{
+			sections: []section{
+				{0, 0x80, 2, append([]byte{byte(DATALOADN), 0, 1}, returnTop...)},
+			},
+			data: append(make([]byte, 32), 0x42),
+			want: word(0x42),
+		},
+		// Swap with SWAPN and return the swapped item.
+		{
+			sections: []section{
+				{0, 0x80, 3, append([]byte{byte(PUSH1), 1, byte(PUSH1), 2, byte(PUSH1), 3, byte(SWAPN), 1, byte(POP), byte(POP)}, returnTop...)},
+			},
+			want: word(3),
+		},
+		// Tail call into a non-returning function.
+		{
+			sections: []section{
+				{0, 0x80, 1, []byte{byte(PUSH1), 9, byte(JUMPF), 0, 1}},
+				{1, 0x80, 1, returnTop},
+			},
+			want: word(9),
+		},
+	} {
+		evm, statedb := newEOFTestEVM(t)
+		address := common.Address{0xee}
+		statedb.SetCode(address, newTestContainer(tt.sections, nil, tt.data), tracing.CodeChangeUnspecified)
+
+		ret, _, err := evm.Call(common.Address{}, address, nil, 100000, new(uint256.Int))
+		if err != nil {
+			t.Fatalf("test %d: execution failed: %v", i, err)
+		}
+		if !bytes.Equal(ret, tt.want) {
+			t.Errorf("test %d: return mismatch: have %x, want %x", i, ret, tt.want)
+		}
+	}
+}
+
+func TestEOFInvalidContainerExecution(t *testing.T) {
+	evm, statedb := newEOFTestEVM(t)
+	address := common.Address{0xee}
+	statedb.SetCode(address, newTestContainer([]section{{0, 0x80, 0, []byte{byte(PUSH0)}}}, nil, nil), tracing.CodeChangeUnspecified)
+
+	if _, _, err := evm.Call(common.Address{}, address, nil, 100000, new(uint256.Int)); !errors.Is(err, ErrInvalidEOF) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInvalidEOF)
+	}
+}
+
+func TestEOFCreate(t *testing.T) {
+	var (
+		runtime  = newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, []byte{0xaa})
+		initcode = newTestContainer([]section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURNCONTRACT), 0}}}, [][]byte{runtime}, nil)
+		factory  = newTestContainer([]section{{0, 0x80, 4, []byte{byte(PUSH0), byte(PUSH0), byte(PUSH1), 0x01, byte(PUSH0), byte(EOFCREATE), 0, byte(PUSH0), byte(MSTORE), byte(PUSH1), 0x20, byte(PUSH0), byte(RETURN)}}}, [][]byte{initcode}, nil)
+
+		evm, statedb = newEOFTestEVM(t)
+		address      = common.Address{0xee}
+	)
+	statedb.SetCode(address, factory, tracing.CodeChangeUnspecified)
+
+	ret, _, err := evm.Call(common.Address{}, address, nil, 1000000, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	salt := common.Hash{31: 0x01}
+	want := common.BytesToAddress(crypto.Keccak256([]byte{0xff}, common.LeftPadBytes(address.Bytes(), 32), salt[:]))
+	if have := common.BytesToAddress(ret); have != want {
+		t.Fatalf("address mismatch: have %x, want %x", have, want)
+	}
+	if code := statedb.GetCode(want); !bytes.Equal(code, runtime) {
+		t.Errorf("deployed code mismatch: have %x, want %x", code, runtime)
+	}
+	// Legacy code observes EOF accounts as the magic only.
+	legacy := common.Address{0x1e}
+	statedb.SetCode(legacy, append(append([]byte{byte(PUSH20)}, want.Bytes()...), byte(EXTCODESIZE), byte(PUSH0), byte(MSTORE), byte(PUSH1), 0x20, byte(PUSH0), byte(RETURN)), tracing.CodeChangeUnspecified)
+	ret, _, err = evm.Call(common.Address{}, legacy, nil, 100000, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if size := new(big.Int).SetBytes(ret); size.Uint64() != 2 {
+		t.Errorf("extcodesize mismatch: have %d, want 2", size)
+	}
+}
+
+func TestEOFCreateTransaction(t *testing.T) {
+	var (
+		runtime  = newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, nil)
+		initcode = newTestContainer([]section{{0, 0x80, 2, []byte{byte(PUSH0), byte(PUSH0), byte(RETURNCONTRACT), 0}}}, [][]byte{runtime}, nil)
+		sender   = common.Address{0x5e}
+	)
+	evm, statedb := newEOFTestEVM(t)
+	_, addr, _, err := evm.Create(sender, append(initcode, 0xca, 0x11), 1000000, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("creation failed: %v", err)
+	}
+	if code := statedb.GetCode(addr); !bytes.Equal(code, runtime) {
+		t.Errorf("deployed code mismatch: have %x, want %x", code, runtime)
+	}
+	// Invalid initcode consumes all gas but still bumps the nonce.
+	nonce := statedb.GetNonce(sender)
+	_, _, leftOver, err := evm.Create(sender, newTestContainer([]section{{0, 0x80, 0, []byte{byte(STOP)}}}, nil, nil), 1000000, new(uint256.Int))
+	if !errors.Is(err, ErrInvalidEOFInitcode) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInvalidEOFInitcode)
+	}
+	if leftOver != 0 {
+		t.Errorf("left over gas mismatch: have %d, want 0", leftOver)
+	}
+	if have := statedb.GetNonce(sender); have != nonce+1 {
+		t.Errorf("nonce mismatch: have %d, want %d", have, nonce+1)
+	}
+}
+
+func TestEOFInstructionSet(t *testing.T) {
+	for _, op := range []OpCode{JUMP, JUMPI, PC, GAS, CODESIZE, CODECOPY, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH, CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, CREATE2, SELFDESTRUCT} {
+		if !pragueEOFInstructionSet[op].undefined {
+			t.Errorf("opcode %v defined in eof", op)
+		}
+	}
+	for _, op := range []OpCode{RJUMP, RJUMPI, RJUMPV, CALLF, RETF, JUMPF, DATALOAD, DATALOADN, DATASIZE, DATACOPY, EOFCREATE, RETURNCONTRACT, EXTCALL, EXTDELEGATECALL, EXTSTATICCALL} {
+		if pragueEOFInstructionSet[op].undefined {
+			t.Errorf("opcode %v undefined in eof", op)
+		}
+		if !pragueInstructionSet[op].undefined {
+			t.Errorf("opcode %v defined in legacy", op)
+		}
+	}
+}
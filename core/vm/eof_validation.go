@@ -0,0 +1,377 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	errUndefinedInstruction      = errors.New("undefined instruction")
+	errTruncatedImmediate        = errors.New("truncated immediate")
+	errInvalidSectionArgument    = errors.New("invalid section argument")
+	errInvalidCallArgument       = errors.New("callf into non-returning section")
+	errInvalidDataloadNArgument  = errors.New("invalid dataloadN argument")
+	errInvalidJumpDest           = errors.New("invalid jump destination")
+	errInvalidBackwardJump       = errors.New("invalid backward jump")
+	errInvalidOutputs            = errors.New("invalid number of outputs")
+	errInvalidMaxStackHeight     = errors.New("invalid max stack height")
+	errInvalidCodeTermination    = errors.New("invalid code termination")
+	errInvalidNonReturning       = errors.New("invalid non-returning flag")
+	errInvalidContainerArgument  = errors.New("invalid container argument")
+	errEOFCreateWithTruncated    = errors.New("eofcreate with truncated container")
+	errIncompatibleContainerKind = errors.New("incompatible container kind")
+	errOrphanedSubcontainer      = errors.New("subcontainer not referenced")
+	errUnreachableCode           = errors.New("unreachable code")
+	errUnreachableSection        = errors.New("unreachable code section")
+	errStackUnderflow            = errors.New("stack underflow")
+	errStackOverflow             = errors.New("stack overflow")
+)
+
+// Subcontainer references, a subcontainer is deployed by RETURNCONTRACT or
+// executed as initcode by EOFCREATE, but not both.
+const (
+	notReferenced = iota
+	refByReturnContract
+	refByEOFCreate
+)
+
+// ValidateCode validates the code sections of the container and all nested
+// containers against the given EOF instruction set. Initcode containers may
+// not contain RETURN or STOP, runtime containers may not contain RETURNCONTRACT.
+func (c *Container) ValidateCode(jt *JumpTable, isInitCode bool) error {
+	if isInitCode && c.truncated() {
+		return errTruncatedContainer
+	}
+	var (
+		visited = make([]bool, len(c.codeSections))
+		subRefs = make([]int, len(c.subContainers))
+		queue   = []int{0}
+	)
+	for len(queue) > 0 {
+		section := queue[0]
+		queue = queue[1:]
+		if visited[section] {
+			continue
+		}
+		visited[section] = true
+
+		refs, err := c.validateSection(section, jt, isInitCode, subRefs)
+		if err != nil {
+			return fmt.Errorf("section %d: %w", section, err)
+		}
+		queue = append(queue, refs...)
+	}
+	for i, ok := range visited {
+		if !ok {
+			return fmt.Errorf("%w: %d", errUnreachableSection, i)
+		}
+	}
+	for i, sub := range c.subContainers {
+		var err error
+		switch subRefs[i] {
+		case notReferenced:
+			err = errOrphanedSubcontainer
+		case refByReturnContract:
+			err = sub.ValidateCode(jt, false)
+		case refByEOFCreate:
+			err = sub.ValidateCode(jt, true)
+		}
+		if err != nil {
+			return fmt.Errorf("subcontainer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// immediateSize returns the size of the immediate argument of the instruction
+// at the given position, the code is assumed to contain the opcode.
+func immediateSize(code []byte, pos int) int {
+	switch op := OpCode(code[pos]); {
+	case op >= PUSH1 && op <= PUSH32:
+		return int(op - PUSH0)
+	case op == RJUMP, op == RJUMPI, op == CALLF, op == JUMPF, op == DATALOADN:
+		return 2
+	case op == RJUMPV:
+		if pos+1 < len(code) {
+			return 1 + 2*(int(code[pos+1])+1)
+		}
+		return 1
+	case op == DUPN, op == SWAPN, op == EXCHANGE, op == EOFCREATE, op == RETURNCONTRACT:
+		return 1
+	}
+	return 0
+}
+
+// parseInt16 returns the signed 16-bit integer at the given position.
+func parseInt16(b []byte) int {
+	return int(int16(binary.BigEndian.Uint16(b)))
+}
+
+// parseUint16 returns the unsigned 16-bit integer at the given position.
+func parseUint16(b []byte) int {
+	return int(binary.BigEndian.Uint16(b))
+}
+
+// relativeJumpTargets returns the destinations of the relative jump at the
+// given position.
+func relativeJumpTargets(code []byte, pos int) []int {
+	switch OpCode(code[pos]) {
+	case RJUMP, RJUMPI:
+		return []int{pos + 3 + parseInt16(code[pos+1:])}
+	case RJUMPV:
+		var (
+			count   = int(code[pos+1]) + 1
+			next    = pos + 2 + 2*count
+			targets = make([]int, count)
+		)
+		for i := range targets {
+			targets[i] = next + parseInt16(code[pos+2+2*i:])
+		}
+		return targets
+	}
+	return nil
+}
+
+// validateSection validates the instructions and stack usage of a code section,
+// returning the code sections it references.
+func (c *Container) validateSection(section int, jt *JumpTable, isInitCode bool, subRefs []int) ([]int, error) {
+	var (
+		code       = c.codeSections[section]
+		meta       = c.types[section]
+		boundaries = make([]bool, len(code))
+		refs       []int
+		jumps      []int
+		returns    bool
+	)
+	for pos := 0; pos < len(code); {
+		op := OpCode(code[pos])
+		if jt[op].undefined {
+			return nil, fmt.Errorf("%w: %v at %d", errUndefinedInstruction, op, pos)
+		}
+		boundaries[pos] = true
+
+		size := immediateSize(code, pos)
+		if pos+1+size > len(code) {
+			return nil, fmt.Errorf("%w: %v at %d", errTruncatedImmediate, op, pos)
+		}
+		switch op {
+		case RJUMP, RJUMPI, RJUMPV:
+			jumps = append(jumps, pos)
+		case CALLF:
+			arg := parseUint16(code[pos+1:])
+			if arg >= len(c.types) {
+				return nil, fmt.Errorf("%w: CALLF to section %d at %d", errInvalidSectionArgument, arg, pos)
+			}
+			if !c.types[arg].returning() {
+				return nil, fmt.Errorf("%w: section %d at %d", errInvalidCallArgument, arg, pos)
+			}
+			refs = append(refs, arg)
+		case RETF:
+			if !meta.returning() {
+				return nil, fmt.Errorf("%w: RETF in non-returning section at %d", errInvalidNonReturning, pos)
+			}
+			returns = true
+		case JUMPF:
+			arg := parseUint16(code[pos+1:])
+			if arg >= len(c.types) {
+				return nil, fmt.Errorf("%w: JUMPF to section %d at %d", errInvalidSectionArgument, arg, pos)
+			}
+			if target := c.types[arg]; target.returning() {
+				if !meta.returning() || target.outputs > meta.outputs {
+					return nil, fmt.Errorf("%w: JUMPF to section %d with %d outputs at %d", errInvalidOutputs, arg, target.outputs, pos)
+				}
+				returns = true
+			}
+			refs = append(refs, arg)
+		case DATALOADN:
+			if arg := parseUint16(code[pos+1:]); arg+32 > c.dataSize {
+				return nil, fmt.Errorf("%w: offset %d, data size %d at %d", errInvalidDataloadNArgument, arg, c.dataSize, pos)
+			}
+		case EOFCREATE:
+			arg := int(code[pos+1])
+			if arg >= len(c.subContainers) {
+				return nil, fmt.Errorf("%w: EOFCREATE of container %d at %d", errInvalidContainerArgument, arg, pos)
+			}
+			if c.subContainers[arg].truncated() {
+				return nil, fmt.Errorf("%w: container %d at %d", errEOFCreateWithTruncated, arg, pos)
+			}
+			if subRefs[arg] == refByReturnContract {
+				return nil, fmt.Errorf("%w: container %d is deployed and created at %d", errIncompatibleContainerKind, arg, pos)
+			}
+			subRefs[arg] = refByEOFCreate
+		case RETURNCONTRACT:
+			if !isInitCode {
+				return nil, fmt.Errorf("%w: RETURNCONTRACT in runtime code at %d", errIncompatibleContainerKind, pos)
+			}
+			arg := int(code[pos+1])
+			if arg >= len(c.subContainers) {
+				return nil, fmt.Errorf("%w: RETURNCONTRACT of container %d at %d", errInvalidContainerArgument, arg, pos)
+			}
+			if subRefs[arg] == refByEOFCreate {
+				return nil, fmt.Errorf("%w: container %d is deployed and created at %d", errIncompatibleContainerKind, arg, pos)
+			}
+			subRefs[arg] = refByReturnContract
+		case RETURN, STOP:
+			if isInitCode {
+				return nil, fmt.Errorf("%w: %v in initcode at %d", errIncompatibleContainerKind, op, pos)
+			}
+		}
+		pos += 1 + size
+	}
+	// Relative jumps must land on instructions.
+	for _, pos := range jumps {
+		for _, target := range relativeJumpTargets(code, pos) {
+			if target < 0 || target >= len(code) || !boundaries[target] {
+				return nil, fmt.Errorf("%w: %v to %d at %d", errInvalidJumpDest, OpCode(code[pos]), target, pos)
+			}
+		}
+	}
+	if meta.returning() != returns {
+		return nil, fmt.Errorf("%w: section declared with %#x outputs", errInvalidNonReturning, meta.outputs)
+	}
+	maxIncrease, err := c.validateStack(section, jt)
+	if err != nil {
+		return nil, err
+	}
+	if maxIncrease != int(meta.maxStackIncrease) {
+		return nil, fmt.Errorf("%w: have %d, declared %d", errInvalidMaxStackHeight, maxIncrease, meta.maxStackIncrease)
+	}
+	return refs, nil
+}
+
+// stackBounds is the range of stack heights an instruction can be reached with.
+type stackBounds struct {
+	min, max int
+}
+
+// validateStack runs the stack validation of EIP-5450 on a code section with
+// valid instructions, returning the maximum stack height increase.
+func (c *Container) validateStack(section int, jt *JumpTable) (int, error) {
+	var (
+		code      = c.codeSections[section]
+		meta      = c.types[section]
+		inputs    = int(meta.inputs)
+		heights   = make([]stackBounds, len(code))
+		maxHeight = inputs
+	)
+	for i := range heights {
+		heights[i] = stackBounds{-1, -1}
+	}
+	heights[0] = stackBounds{inputs, inputs}
+
+	for pos := 0; pos < len(code); {
+		var (
+			op            = OpCode(code[pos])
+			cur           = heights[pos]
+			size          = immediateSize(code, pos)
+			pops, pushes  int
+			terminating   bool
+			nextPositions []int
+		)
+		if cur.min < 0 {
+			return 0, fmt.Errorf("%w at %d", errUnreachableCode, pos)
+		}
+		switch op {
+		case CALLF:
+			target := c.types[parseUint16(code[pos+1:])]
+			if err := target.checkStackMax(cur.max); err != nil {
+				return 0, fmt.Errorf("CALLF at %d: %w", pos, err)
+			}
+			pops, pushes = int(target.inputs), int(target.outputs)
+		case JUMPF:
+			target := c.types[parseUint16(code[pos+1:])]
+			if err := target.checkStackMax(cur.max); err != nil {
+				return 0, fmt.Errorf("JUMPF at %d: %w", pos, err)
+			}
+			if target.returning() {
+				want := int(meta.outputs) + int(target.inputs) - int(target.outputs)
+				if cur.min != want || cur.max != want {
+					return 0, fmt.Errorf("%w: JUMPF at %d with stack %d..%d, want %d", errInvalidOutputs, pos, cur.min, cur.max, want)
+				}
+			}
+			pops, terminating = int(target.inputs), true
+		case RETF:
+			if cur.min != int(meta.outputs) || cur.max != int(meta.outputs) {
+				return 0, fmt.Errorf("%w: RETF at %d with stack %d..%d, want %d", errInvalidOutputs, pos, cur.min, cur.max, meta.outputs)
+			}
+			pops, terminating = int(meta.outputs), true
+		case DUPN:
+			n := int(code[pos+1]) + 1
+			pops, pushes = n, n+1
+		case SWAPN:
+			n := int(code[pos+1]) + 2
+			pops, pushes = n, n
+		case EXCHANGE:
+			n := int(code[pos+1]>>4) + int(code[pos+1]&0x0f) + 3
+			pops, pushes = n, n
+		default:
+			pops = jt[op].minStack
+			pushes = int(params.StackLimit) + pops - jt[op].maxStack
+			switch op {
+			case STOP, RETURN, REVERT, INVALID, RETURNCONTRACT:
+				terminating = true
+			}
+		}
+		if cur.min < pops {
+			return 0, fmt.Errorf("%w: %v at %d needs %d items, stack has %d", errStackUnderflow, op, pos, pops, cur.min)
+		}
+		next := stackBounds{cur.min - pops + pushes, cur.max - pops + pushes}
+		if next.max > maxHeight {
+			maxHeight = next.max
+		}
+		if maxHeight > maxStackHeight {
+			return 0, fmt.Errorf("%w: %v at %d reaches %d items", errStackOverflow, op, pos, maxHeight)
+		}
+		// Collect the successors of the instruction.
+		switch op {
+		case RJUMP:
+			nextPositions = relativeJumpTargets(code, pos)
+		case RJUMPI, RJUMPV:
+			nextPositions = append([]int{pos + 1 + size}, relativeJumpTargets(code, pos)...)
+		default:
+			if !terminating {
+				nextPositions = []int{pos + 1 + size}
+			}
+		}
+		for _, succ := range nextPositions {
+			if succ >= len(code) {
+				return 0, fmt.Errorf("%w: %v at %d", errInvalidCodeTermination, op, pos)
+			}
+			if succ <= pos {
+				// Backward jumps must keep the stack height of the target.
+				if heights[succ] != next {
+					return 0, fmt.Errorf("%w: %v at %d with stack %d..%d, target has %d..%d",
+						errInvalidBackwardJump, op, pos, next.min, next.max, heights[succ].min, heights[succ].max)
+				}
+				continue
+			}
+			if heights[succ].min < 0 {
+				heights[succ] = next
+			} else {
+				heights[succ].min = min(heights[succ].min, next.min)
+				heights[succ].max = max(heights[succ].max, next.max)
+			}
+		}
+		pos += 1 + size
+	}
+	return maxHeight - inputs, nil
+}
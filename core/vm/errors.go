@@ -38,6 +38,11 @@ var (
 	ErrGasUintOverflow          = errors.New("gas uint64 overflow")
 	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
 	ErrNonceUintOverflow        = errors.New("nonce uint64 overflow")
+	ErrInvalidEOF               = errors.New("invalid eof container")
+	ErrInvalidEOFInitcode       = errors.New("invalid eof initcode")
+	ErrInvalidEOFDeployment     = errors.New("invalid eof deployment: data section size mismatch")
+	ErrInvalidEOFAddress        = errors.New("invalid eof call target: address has high bytes set")
+	ErrReturnStackExceeded      = errors.New("return stack limit reached")
 
 	// errStopToken is an internal token indicating interpreter loop termination,
 	// never returned to outside callers.
@@ -18,6 +18,7 @@ package vm
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync/atomic"
 
@@ -99,6 +100,9 @@ type EVM struct {
 	// table holds the opcode specific handlers
 	table *JumpTable
 
+	// eofTable holds the opcode specific handlers for EOF code, if enabled
+	eofTable *JumpTable
+
 	// depth is the current call stack
 	depth int
 
@@ -125,6 +129,9 @@ type EVM struct {
 	// jumpDests stores results of JUMPDEST analysis.
 	jumpDests JumpDestCache
 
+	// eofContainers stores validated EOF containers by code hash.
+	eofContainers map[common.Hash]*Container
+
 	hasher    crypto.KeccakState // Keccak256 hasher instance shared across opcodes
 	hasherBuf common.Hash        // Keccak256 hasher result array shared across opcodes
 
@@ -195,6 +202,14 @@ func NewEVM(blockCtx BlockContext, statedb StateDB, chainConfig *params.ChainCon
 		}
 	}
 	evm.Config.ExtraEips = extraEips
+
+	if evm.chainRules.IsEOF {
+		if evm.chainRules.IsOsaka {
+			evm.eofTable = &osakaEOFInstructionSet
+		} else {
+			evm.eofTable = &pragueEOFInstructionSet
+		}
+	}
 	return evm
 }
 
@@ -283,7 +298,7 @@ func (evm *EVM) Call(caller common.Address, addr common.Address, input []byte, g
 	evm.Context.Transfer(evm.StateDB, caller, addr, value)
 
 	if isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, caller, addr, input, gas, value, evm.readOnly)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		code := evm.resolveCode(addr)
@@ -347,7 +362,7 @@ func (evm *EVM) CallCode(caller common.Address, addr common.Address, input []byt
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, caller, caller, input, gas, value, evm.readOnly)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
@@ -390,7 +405,7 @@ func (evm *EVM) DelegateCall(originCaller common.Address, caller common.Address,
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, originCaller, caller, input, gas, value, evm.readOnly)
 	} else {
 		// Initialise a new contract and make initialise the delegate values
 		//
@@ -442,7 +457,7 @@ func (evm *EVM) StaticCall(caller common.Address, addr common.Address, input []b
 	evm.StateDB.AddBalance(addr, new(uint256.Int), tracing.BalanceChangeTouchAccount)
 
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, caller, addr, input, gas, new(uint256.Int), true)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
@@ -468,8 +483,9 @@ func (evm *EVM) StaticCall(caller common.Address, addr common.Address, input []b
 	return ret, gas, err
 }
 
-// create creates a new contract using code as deployment code.
-func (evm *EVM) create(caller common.Address, code []byte, gas uint64, value *uint256.Int, address common.Address, typ OpCode) (ret []byte, createAddress common.Address, leftOverGas uint64, err error) {
+// create creates a new contract using code as deployment code. EOF initcode is
+// passed as a validated container along with its input.
+func (evm *EVM) create(caller common.Address, code []byte, container *Container, input []byte, gas uint64, value *uint256.Int, address common.Address, typ OpCode) (ret []byte, createAddress common.Address, leftOverGas uint64, err error) {
 	if evm.Config.Tracer != nil {
 		evm.captureBegin(evm.depth, typ, caller, address, code, gas, value.ToBig())
 		defer func(startGas uint64) {
@@ -558,9 +574,10 @@ func (evm *EVM) create(caller common.Address, code []byte, gas uint64, value *ui
 	// Explicitly set the code to a null hash to prevent caching of jump analysis
 	// for the initialization code.
 	contract.SetCallCode(common.Hash{}, code)
+	contract.Container = container
 	contract.IsDeployment = true
 
-	ret, err = evm.initNewContract(contract, address)
+	ret, err = evm.initNewContract(contract, address, input)
 	if err != nil && (evm.chainRules.IsHomestead || err != ErrCodeStoreOutOfGas) {
 		evm.StateDB.RevertToSnapshot(snapshot)
 		if err != ErrExecutionReverted {
@@ -572,8 +589,9 @@ func (evm *EVM) create(caller common.Address, code []byte, gas uint64, value *ui
 
 // initNewContract runs a new contract's creation code, performs checks on the
 // resulting code that is to be deployed, and consumes necessary gas.
-func (evm *EVM) initNewContract(contract *Contract, address common.Address) ([]byte, error) {
-	ret, err := evm.Run(contract, nil, false)
+func (evm *EVM) initNewContract(contract *Contract, address common.Address, input []byte) ([]byte, error) {
+	isEOF := contract.Container != nil
+	ret, err := evm.Run(contract, input, false)
 	if err != nil {
 		return ret, err
 	}
@@ -583,8 +601,9 @@ func (evm *EVM) initNewContract(contract *Contract, address common.Address) ([]b
 		return ret, ErrMaxCodeSizeExceeded
 	}
 
-	// Reject code starting with 0xEF if EIP-3541 is enabled.
-	if len(ret) >= 1 && ret[0] == 0xEF && evm.chainRules.IsLondon {
+	// Reject code starting with 0xEF if EIP-3541 is enabled. EOF initcode can
+	// only return EOF containers by RETURNCONTRACT.
+	if len(ret) >= 1 && ret[0] == 0xEF && evm.chainRules.IsLondon && !isEOF {
 		return ret, ErrInvalidCode
 	}
 
@@ -610,7 +629,40 @@ func (evm *EVM) initNewContract(contract *Contract, address common.Address) ([]b
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller common.Address, code []byte, gas uint64, value *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	contractAddr = crypto.CreateAddress(caller, evm.StateDB.GetNonce(caller))
-	return evm.create(caller, code, gas, value, contractAddr, CREATE)
+	if evm.eofTable != nil && evm.depth == 0 && HasEOFMagic(code) {
+		return evm.createEOF(caller, code, gas, value, contractAddr)
+	}
+	return evm.create(caller, code, nil, nil, gas, value, contractAddr, CREATE)
+}
+
+// createEOF runs a creation transaction with EOF initcode. The transaction data
+// consists of the initcode container followed by the input of the initcode.
+// Invalid initcode consumes all gas.
+func (evm *EVM) createEOF(caller common.Address, code []byte, gas uint64, value *uint256.Int, contractAddr common.Address) (ret []byte, createAddress common.Address, leftOverGas uint64, err error) {
+	container := new(Container)
+	size, err := container.UnmarshalPrefix(code)
+	if err == nil {
+		err = container.ValidateCode(evm.eofTable, true)
+	}
+	if err == nil {
+		return evm.create(caller, code[:size], container, code[size:], gas, value, contractAddr, CREATE)
+	}
+	err = fmt.Errorf("%w: %v", ErrInvalidEOFInitcode, err)
+	if evm.Config.Tracer != nil {
+		evm.captureBegin(evm.depth, CREATE, caller, contractAddr, code, gas, value.ToBig())
+		defer func(startGas uint64) {
+			evm.captureEnd(evm.depth, startGas, 0, nil, err)
+		}(gas)
+		if evm.Config.Tracer.OnGasChange != nil {
+			evm.Config.Tracer.OnGasChange(gas, 0, tracing.GasChangeCallFailedExecution)
+		}
+	}
+	nonce := evm.StateDB.GetNonce(caller)
+	if nonce+1 < nonce {
+		return nil, common.Address{}, gas, ErrNonceUintOverflow
+	}
+	evm.StateDB.SetNonce(caller, nonce+1, tracing.NonceChangeContractCreator)
+	return nil, contractAddr, 0, err
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -620,7 +672,18 @@ func (evm *EVM) Create(caller common.Address, code []byte, gas uint64, value *ui
 func (evm *EVM) Create2(caller common.Address, code []byte, gas uint64, endowment *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	inithash := crypto.HashData(evm.hasher, code)
 	contractAddr = crypto.CreateAddress2(caller, salt.Bytes32(), inithash[:])
-	return evm.create(caller, code, gas, endowment, contractAddr, CREATE2)
+	return evm.create(caller, code, nil, nil, gas, endowment, contractAddr, CREATE2)
+}
+
+// EOFCreate creates a new contract running the initcode container with the given
+// index in the container of the caller.
+//
+// The address is keccak256(0xff ++ msg.sender ++ salt)[12:], where the sender is
+// padded to 32 bytes.
+func (evm *EVM) EOFCreate(caller common.Address, container *Container, index int, input []byte, gas uint64, value *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
+	salt32 := salt.Bytes32()
+	contractAddr = common.BytesToAddress(crypto.Keccak256([]byte{0xff}, common.LeftPadBytes(caller.Bytes(), 32), salt32[:]))
+	return evm.create(caller, container.subContainerCodes[index], container.subContainers[index], input, gas, value, contractAddr, EOFCREATE)
 }
 
 // resolveCode returns the code associated with the provided account. After
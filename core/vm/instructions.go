@@ -334,7 +334,11 @@ func opReturnDataCopy(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error)
 
 func opExtCodeSize(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
 	slot := scope.Stack.peek()
-	slot.SetUint64(uint64(evm.StateDB.GetCodeSize(slot.Bytes20())))
+	if evm.chainRules.IsEOF && HasEOFMagic(evm.StateDB.GetCode(slot.Bytes20())) {
+		slot.SetUint64(uint64(len(eofMagic)))
+	} else {
+		slot.SetUint64(uint64(evm.StateDB.GetCodeSize(slot.Bytes20())))
+	}
 	return nil, nil
 }
 
@@ -373,6 +377,9 @@ func opExtCodeCopy(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
 	}
 	addr := common.Address(a.Bytes20())
 	code := evm.StateDB.GetCode(addr)
+	if evm.chainRules.IsEOF && HasEOFMagic(code) {
+		code = eofMagic
+	}
 	codeCopy := getData(code, uint64CodeOffset, length.Uint64())
 	scope.Memory.Set(memOffset.Uint64(), length.Uint64(), codeCopy)
 
@@ -410,6 +417,8 @@ func opExtCodeHash(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
 	address := common.Address(slot.Bytes20())
 	if evm.StateDB.Empty(address) {
 		slot.Clear()
+	} else if evm.chainRules.IsEOF && HasEOFMagic(evm.StateDB.GetCode(address)) {
+		slot.SetBytes(eofMagicHash.Bytes())
 	} else {
 		slot.SetBytes(evm.StateDB.GetCodeHash(address).Bytes())
 	}
@@ -779,6 +788,9 @@ func opCallCode(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
 	// Get arguments from the memory.
 	args := scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
 
+	if evm.delegatesToEOF(toAddr) {
+		return delegateToEOFFailure(evm, scope, &temp, gas)
+	}
 	if !value.IsZero() {
 		gas += params.CallStipend
 	}
@@ -812,6 +824,9 @@ func opDelegateCall(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error) {
 	// Get arguments from the memory.
 	args := scope.Memory.GetPtr(inOffset.Uint64(), inSize.Uint64())
 
+	if evm.delegatesToEOF(toAddr) {
+		return delegateToEOFFailure(evm, scope, &temp, gas)
+	}
 	ret, returnGas, err := evm.DelegateCall(scope.Contract.Caller(), scope.Contract.Address(), toAddr, args, gas, scope.Contract.value)
 	if err != nil {
 		temp.Clear()
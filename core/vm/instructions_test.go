@@ -107,7 +107,7 @@ func testTwoOperandOp(t *testing.T, tests []TwoOperandTestcase, opFn executionFu
 		expected := new(uint256.Int).SetBytes(common.Hex2Bytes(test.Expected))
 		stack.push(x)
 		stack.push(y)
-		opFn(&pc, evm, &ScopeContext{nil, stack, nil})
+		opFn(&pc, evm, &ScopeContext{Stack: stack})
 		if len(stack.data) != 1 {
 			t.Errorf("Expected one item on stack after %v, got %d: ", name, len(stack.data))
 		}
@@ -221,7 +221,7 @@ func TestAddMod(t *testing.T) {
 		stack.push(z)
 		stack.push(y)
 		stack.push(x)
-		opAddmod(&pc, evm, &ScopeContext{nil, stack, nil})
+		opAddmod(&pc, evm, &ScopeContext{Stack: stack})
 		actual := stack.pop()
 		if actual.Cmp(expected) != 0 {
 			t.Errorf("Testcase %d, expected  %x, got %x", i, expected, actual)
@@ -247,7 +247,7 @@ func TestWriteExpectedValues(t *testing.T) {
 			y := new(uint256.Int).SetBytes(common.Hex2Bytes(param.y))
 			stack.push(x)
 			stack.push(y)
-			opFn(&pc, evm, &ScopeContext{nil, stack, nil})
+			opFn(&pc, evm, &ScopeContext{Stack: stack})
 			actual := stack.pop()
 			result[i] = TwoOperandTestcase{param.x, param.y, fmt.Sprintf("%064x", actual)}
 		}
@@ -283,7 +283,7 @@ func opBenchmark(bench *testing.B, op executionFunc, args ...string) {
 	var (
 		evm   = NewEVM(BlockContext{}, nil, params.TestChainConfig, Config{})
 		stack = newstack()
-		scope = &ScopeContext{nil, stack, nil}
+		scope = &ScopeContext{Stack: stack}
 	)
 	// convert args
 	intArgs := make([]*uint256.Int, len(args))
@@ -526,13 +526,13 @@ func TestOpMstore(t *testing.T) {
 	v := "abcdef00000000000000abba000000000deaf000000c0de00100000000133700"
 	stack.push(new(uint256.Int).SetBytes(common.Hex2Bytes(v)))
 	stack.push(new(uint256.Int))
-	opMstore(&pc, evm, &ScopeContext{mem, stack, nil})
+	opMstore(&pc, evm, &ScopeContext{Memory: mem, Stack: stack})
 	if got := common.Bytes2Hex(mem.GetCopy(0, 32)); got != v {
 		t.Fatalf("Mstore fail, got %v, expected %v", got, v)
 	}
 	stack.push(new(uint256.Int).SetUint64(0x1))
 	stack.push(new(uint256.Int))
-	opMstore(&pc, evm, &ScopeContext{mem, stack, nil})
+	opMstore(&pc, evm, &ScopeContext{Memory: mem, Stack: stack})
 	if common.Bytes2Hex(mem.GetCopy(0, 32)) != "0000000000000000000000000000000000000000000000000000000000000001" {
 		t.Fatalf("Mstore failed to overwrite previous value")
 	}
@@ -552,7 +552,7 @@ func BenchmarkOpMstore(bench *testing.B) {
 	for bench.Loop() {
 		stack.push(value)
 		stack.push(memStart)
-		opMstore(&pc, evm, &ScopeContext{mem, stack, nil})
+		opMstore(&pc, evm, &ScopeContext{Memory: mem, Stack: stack})
 	}
 }
 
@@ -565,7 +565,7 @@ func TestOpTstore(t *testing.T) {
 		caller       = common.Address{}
 		to           = common.Address{1}
 		contract     = NewContract(caller, to, new(uint256.Int), 0, nil)
-		scopeContext = ScopeContext{mem, stack, contract}
+		scopeContext = ScopeContext{Memory: mem, Stack: stack, Contract: contract}
 		value        = common.Hex2Bytes("abcdef00000000000000abba000000000deaf000000c0de00100000000133700")
 	)
 
@@ -609,7 +609,7 @@ func BenchmarkOpKeccak256(bench *testing.B) {
 	for bench.Loop() {
 		stack.push(uint256.NewInt(32))
 		stack.push(start)
-		opKeccak256(&pc, evm, &ScopeContext{mem, stack, nil})
+		opKeccak256(&pc, evm, &ScopeContext{Memory: mem, Stack: stack})
 	}
 }
 
@@ -703,7 +703,7 @@ func TestRandom(t *testing.T) {
 			stack = newstack()
 			pc    = uint64(0)
 		)
-		opRandom(&pc, evm, &ScopeContext{nil, stack, nil})
+		opRandom(&pc, evm, &ScopeContext{Stack: stack})
 		if len(stack.data) != 1 {
 			t.Errorf("Expected one item on stack after %v, got %d: ", tt.name, len(stack.data))
 		}
@@ -745,7 +745,7 @@ func TestBlobHash(t *testing.T) {
 		)
 		evm.SetTxContext(TxContext{BlobHashes: tt.hashes})
 		stack.push(uint256.NewInt(tt.idx))
-		opBlobHash(&pc, evm, &ScopeContext{nil, stack, nil})
+		opBlobHash(&pc, evm, &ScopeContext{Stack: stack})
 		if len(stack.data) != 1 {
 			t.Errorf("Expected one item on stack after %v, got %d: ", tt.name, len(stack.data))
 		}
@@ -885,7 +885,7 @@ func TestOpMCopy(t *testing.T) {
 			mem.Resize(memorySize)
 		}
 		// Do the copy
-		opMcopy(&pc, evm, &ScopeContext{mem, stack, nil})
+		opMcopy(&pc, evm, &ScopeContext{Memory: mem, Stack: stack})
 		want := common.FromHex(strings.ReplaceAll(tc.want, " ", ""))
 		if have := mem.store; !bytes.Equal(want, have) {
 			t.Errorf("case %d: \nwant: %#x\nhave: %#x\n", i, want, have)
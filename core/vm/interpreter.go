@@ -34,6 +34,7 @@ type Config struct {
 
 	StatelessSelfValidation bool // Generate execution witnesses and self-check against them (testing purpose)
 	EnableWitnessStats      bool // Whether trie access statistics collection is enabled
+	EnableBlockAnalysis     bool // Executes code in pre-analyzed basic blocks with superinstructions (experimental)
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -42,6 +43,9 @@ type ScopeContext struct {
 	Memory   *Memory
 	Stack    *Stack
 	Contract *Contract
+
+	codeSection int              // EOF code section being executed
+	returnStack []eofReturnFrame // EOF return stack of CALLF
 }
 
 // MemoryData returns the underlying memory slice. Callers must not modify the contents
@@ -147,6 +151,26 @@ func (evm *EVM) Run(contract *Contract, input []byte, readOnly bool) (ret []byte
 	}()
 	contract.Input = input
 
+	// EOF containers are executed with their own instruction set, starting at
+	// the first code section. Initcode containers are set up by the creation,
+	// deployed code starting with the EOF magic is an EOF container.
+	if evm.eofTable != nil {
+		if contract.Container == nil && !contract.IsDeployment && HasEOFMagic(contract.Code) {
+			if contract.Container, err = evm.eofContainer(contract.CodeHash, contract.Code); err != nil {
+				return nil, err
+			}
+		}
+		if contract.Container != nil {
+			jumpTable = evm.eofTable
+			contract.Code = contract.Container.codeSections[0]
+		}
+	}
+	// Execute basic blocks at once where possible, unless every step needs to be
+	// traced or charged individually.
+	var blocks *blockAnalysis
+	if evm.Config.EnableBlockAnalysis && !debug && !isEIP4762 && contract.Container == nil {
+		blocks = evm.blockAnalysis(contract)
+	}
 	if debug {
 		defer func() { // this deferred method handles exit-with-error
 			if err == nil {
@@ -166,6 +190,14 @@ func (evm *EVM) Run(contract *Contract, input []byte, readOnly bool) (ret []byte
 	// parent context.
 	_ = jumpTable[0] // nil-check the jumpTable out of the loop
 	for {
+		if blocks != nil {
+			if block := blocks.block(pc); block != nil && block.runnable(stack.len(), contract.Gas) {
+				if res, err = evm.runBlock(block, &pc, callContext); err != nil {
+					break
+				}
+				continue
+			}
+		}
 		if debug {
 			// Capture pre-execution values for tracing.
 			logged, pcCopy, gasCopy = false, pc, contract.Gas
@@ -43,35 +43,37 @@ func TestLoopInterrupt(t *testing.T) {
 		Transfer: func(StateDB, common.Address, common.Address, *uint256.Int) {},
 	}
 
-	for i, tt := range loopInterruptTests {
-		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
-		statedb.CreateAccount(address)
-		statedb.SetCode(address, common.Hex2Bytes(tt), tracing.CodeChangeUnspecified)
-		statedb.Finalise(true)
+	for _, config := range []Config{{}, {EnableBlockAnalysis: true}} {
+		for i, tt := range loopInterruptTests {
+			statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+			statedb.CreateAccount(address)
+			statedb.SetCode(address, common.Hex2Bytes(tt), tracing.CodeChangeUnspecified)
+			statedb.Finalise(true)
 
-		evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{})
+			evm := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, config)
 
-		errChannel := make(chan error)
-		timeout := make(chan bool)
+			errChannel := make(chan error)
+			timeout := make(chan bool)
 
-		go func(evm *EVM) {
-			_, _, err := evm.Call(common.Address{}, address, nil, math.MaxUint64, new(uint256.Int))
-			errChannel <- err
-		}(evm)
+			go func(evm *EVM) {
+				_, _, err := evm.Call(common.Address{}, address, nil, math.MaxUint64, new(uint256.Int))
+				errChannel <- err
+			}(evm)
 
-		go func() {
-			<-time.After(time.Second)
-			timeout <- true
-		}()
+			go func() {
+				<-time.After(time.Second)
+				timeout <- true
+			}()
 
-		evm.Cancel()
+			evm.Cancel()
 
-		select {
-		case <-timeout:
-			t.Errorf("test %d timed out", i)
-		case err := <-errChannel:
-			if err != nil {
-				t.Errorf("test %d failure: %v", i, err)
+			select {
+			case <-timeout:
+				t.Errorf("test %d (block analysis %t) timed out", i, config.EnableBlockAnalysis)
+			case err := <-errChannel:
+				if err != nil {
+					t.Errorf("test %d (block analysis %t) failure: %v", i, config.EnableBlockAnalysis, err)
+				}
 			}
 		}
 	}
@@ -63,6 +63,9 @@ var (
 	verkleInstructionSet           = newVerkleInstructionSet()
 	pragueInstructionSet           = newPragueInstructionSet()
 	osakaInstructionSet            = newOsakaInstructionSet()
+
+	pragueEOFInstructionSet = newEOFInstructionSet(&pragueInstructionSet)
+	osakaEOFInstructionSet  = newEOFInstructionSet(&osakaInstructionSet)
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
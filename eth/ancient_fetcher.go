@@ -0,0 +1,129 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// ancientFetchPeers is the maximum number of peers asked for an item.
+	ancientFetchPeers = 3
+
+	// ancientFetchTimeout is the time allowance for a peer to deliver an item.
+	ancientFetchTimeout = 5 * time.Second
+)
+
+var errAncientFetchTimeout = errors.New("ancient item request timed out")
+
+// ancientFetcher retrieves chain data from the connected peers, allowing the
+// ancient scrubber to restore corrupted items. The delivered items are not
+// trusted, they are verified by the scrubber before being written.
+type ancientFetcher struct {
+	peers *peerSet
+}
+
+func newAncientFetcher(peers *peerSet) *ancientFetcher {
+	return &ancientFetcher{peers: peers}
+}
+
+// Name implements core.AncientSource.
+func (f *ancientFetcher) Name() string { return "peers" }
+
+// FetchAncient implements core.AncientSource, asking a few random peers for
+// the requested item.
+func (f *ancientFetcher) FetchAncient(ctx context.Context, kind string, number uint64, hash common.Hash) ([]byte, error) {
+	peers := f.peers.all()
+	rand.Shuffle(len(peers), func(i, j int) { peers[i], peers[j] = peers[j], peers[i] })
+
+	var err error
+	for _, peer := range peers[:min(len(peers), ancientFetchPeers)] {
+		var blob []byte
+		if blob, err = f.fetch(ctx, peer.Peer, kind, number, hash); err == nil && blob != nil {
+			return blob, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+// fetch requests a single item from the given peer and converts it into the
+// encoding of the ancient store.
+func (f *ancientFetcher) fetch(ctx context.Context, peer *eth.Peer, kind string, number uint64, hash common.Hash) ([]byte, error) {
+	var (
+		sink = make(chan *eth.Response)
+		req  *eth.Request
+		err  error
+	)
+	switch kind {
+	case rawdb.ChainFreezerHeaderTable:
+		req, err = peer.RequestHeadersByNumber(number, 1, 0, false, sink)
+	case rawdb.ChainFreezerBodiesTable:
+		req, err = peer.RequestBodies([]common.Hash{hash}, sink)
+	case rawdb.ChainFreezerReceiptTable:
+		req, err = peer.RequestReceipts([]common.Hash{hash}, sink)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer req.Close()
+
+	timeout := time.NewTimer(ancientFetchTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-timeout.C:
+		return nil, errAncientFetchTimeout
+
+	case res := <-sink:
+		res.Done <- nil
+
+		switch packet := res.Res.(type) {
+		case *eth.BlockHeadersRequest:
+			if len(*packet) == 0 {
+				return nil, nil
+			}
+			return rlp.EncodeToBytes((*packet)[0])
+		case *eth.BlockBodiesResponse:
+			if len(*packet) == 0 {
+				return nil, nil
+			}
+			return rlp.EncodeToBytes((*packet)[0])
+		case *eth.ReceiptsRLPResponse:
+			if len(*packet) == 0 {
+				return nil, nil
+			}
+			return (*packet)[0], nil
+		}
+		return nil, nil
+	}
+}
@@ -64,6 +64,7 @@ func (b *EthAPIBackend) CurrentBlock() *types.Header {
 func (b *EthAPIBackend) SetHead(number uint64) {
 	b.eth.handler.downloader.Cancel()
 	b.eth.blockchain.SetHead(number)
+	b.eth.rpcCache.Purge()
 }
 
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
@@ -141,6 +141,8 @@ type Config struct {
 	DatabaseCache      int
 	DatabaseFreezer    string
 	DatabaseEra        string
+	DatabaseStats      bool // Maintain database statistics in the background
+	AncientScrub       bool // Verify and repair the ancient store in the background
 
 	TrieCleanCache int
 	TrieDirtyCache int
@@ -174,6 +176,9 @@ type Config struct {
 	// Generate execution witnesses and self-check against them (testing purpose)
 	StatelessSelfValidation bool
 
+	// Executes contract code in pre-analyzed basic blocks (experimental)
+	EnableBlockAnalysis bool
+
 	// Enables tracking of state size
 	EnableStateSizeTracking bool
 
@@ -45,6 +45,7 @@ var (
 	errExceedMaxTopics        = errors.New("exceed max topics")
 	errExceedLogQueryLimit    = errors.New("exceed max addresses or topics per search position")
 	errExceedMaxTxHashes      = errors.New("exceed max number of transaction hashes allowed per transactionReceipts subscription")
+	errBackfillOverflow       = errors.New("too many live logs pending during backfill")
 )
 
 type invalidParamsError struct {
@@ -286,16 +287,42 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
-
+	// A fromBlock without toBlock resumes the subscription at the given block,
+	// backfilling the historical logs before switching to live ones. Criteria
+	// with a toBlock are only matched against live logs, as they always were.
+	var (
+		resume bool
+		from   uint64
+	)
+	if crit.FromBlock != nil && crit.ToBlock == nil && crit.FromBlock.Int64() != rpc.LatestBlockNumber.Int64() {
+		var err error
+		if from, err = api.resolveBackfillStart(ctx, rpc.BlockNumber(crit.FromBlock.Int64())); err != nil {
+			return nil, err
+		}
+		if err = api.checkBackfillRange(from); err != nil {
+			return nil, err
+		}
+		resume = true
+	}
 	var (
 		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
+		query       = ethereum.FilterQuery(crit)
 	)
-
-	logsSub, err := api.events.SubscribeLogs(ethereum.FilterQuery(crit), matchedLogs)
+	if resume {
+		query.FromBlock = nil
+	}
+	logsSub, err := api.events.SubscribeLogs(query, matchedLogs)
 	if err != nil {
 		return nil, err
 	}
+	if resume {
+		go func() {
+			defer logsSub.Unsubscribe()
+			api.streamResumedLogs(notifier, rpcSub, crit, from, matchedLogs)
+		}()
+		return rpcSub, nil
+	}
 
 	go func() {
 		defer logsSub.Unsubscribe()
@@ -489,6 +516,10 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 	if err != nil {
 		return nil, err
 	}
+	// Results over explicit ranges of finalized blocks never change
+	if crit.BlockHash == nil && crit.FromBlock != nil && crit.FromBlock.Sign() >= 0 && crit.ToBlock != nil && crit.ToBlock.Sign() >= 0 {
+		ethapi.MarkFinalizedResult(ctx, api.sys.backend, crit.ToBlock.Uint64(), common.Hash{})
+	}
 	return returnLogs(logs), err
 }
 
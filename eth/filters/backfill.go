@@ -0,0 +1,201 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// backfillChunk is the number of blocks queried at once when backfilling the
+	// historical logs of a resumed log subscription.
+	backfillChunk = 2048
+
+	// backfillReorgWindow is the number of blocks below the backfill head whose
+	// hashes are remembered to reconcile reorgs with the live log stream.
+	backfillReorgWindow = 128
+
+	// maxBackfillPending is the maximum number of live logs held back while the
+	// historical logs are backfilled. The subscription is terminated if exceeded.
+	maxBackfillPending = 16384
+)
+
+// logCursor tracks the historical logs delivered by a resumed log subscription,
+// so live logs overlapping the backfilled range are not delivered twice.
+type logCursor struct {
+	from   uint64                   // first block of the subscription
+	head   uint64                   // last block covered by the backfill
+	blocks map[common.Hash]struct{} // blocks near the head with delivered logs
+}
+
+// track records a delivered historical log.
+func (c *logCursor) track(log *types.Log) {
+	if log.BlockNumber+backfillReorgWindow > c.head {
+		c.blocks[log.BlockHash] = struct{}{}
+	}
+}
+
+// admit reports whether a live log should be delivered. Logs of blocks covered
+// by the backfill are only delivered if they belong to a block replacing one
+// that was backfilled, or if they revoke a log delivered by the backfill.
+func (c *logCursor) admit(log *types.Log) bool {
+	if log.BlockNumber < c.from {
+		return false
+	}
+	if log.BlockNumber > c.head {
+		return true
+	}
+	_, delivered := c.blocks[log.BlockHash]
+	return delivered == log.Removed
+}
+
+// resolveBackfillStart converts the fromBlock of a log subscription into the
+// first block to backfill.
+func (api *FilterAPI) resolveBackfillStart(ctx context.Context, from rpc.BlockNumber) (uint64, error) {
+	cutoff := api.sys.backend.HistoryPruningCutoff()
+	switch from {
+	case rpc.PendingBlockNumber:
+		return 0, errPendingLogsUnsupported
+	case rpc.EarliestBlockNumber:
+		return cutoff, nil
+	case rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		header, err := api.sys.backend.HeaderByNumber(ctx, from)
+		if err != nil {
+			return 0, err
+		}
+		if header == nil {
+			return 0, errUnknownBlock
+		}
+		return header.Number.Uint64(), nil
+	}
+	if from < 0 {
+		return 0, errInvalidBlockRange
+	}
+	if uint64(from) < cutoff {
+		return 0, &history.PrunedHistoryError{}
+	}
+	return uint64(from), nil
+}
+
+// checkBackfillRange returns an error if backfilling the logs from the given
+// block to the current head exceeds the configured block range limit.
+func (api *FilterAPI) checkBackfillRange(from uint64) error {
+	if api.rangeLimit == 0 {
+		return nil
+	}
+	head := api.sys.backend.CurrentHeader().Number.Uint64()
+	if head > from && head-from > api.rangeLimit {
+		return invalidParamsErr("backfill range exceeds maximum block range: %d", api.rangeLimit)
+	}
+	return nil
+}
+
+// backfillLogs retrieves the historical logs matching the criteria between the
+// given blocks in chunks, sending each chunk on the results channel. The channel
+// is closed when all logs were sent, failures are reported on errc instead.
+func (api *FilterAPI) backfillLogs(ctx context.Context, crit FilterCriteria, from, to uint64, results chan<- []*types.Log, errc chan<- error) {
+	chunk := uint64(backfillChunk)
+	if api.rangeLimit != 0 && api.rangeLimit < chunk {
+		chunk = api.rangeLimit
+	}
+	for begin := from; begin <= to; begin += chunk {
+		end := min(begin+chunk-1, to)
+		filter := api.sys.NewRangeFilter(int64(begin), int64(end), crit.Addresses, crit.Topics, api.rangeLimit)
+		logs, err := filter.Logs(ctx)
+		if err != nil {
+			errc <- err
+			return
+		}
+		if len(logs) == 0 {
+			continue
+		}
+		select {
+		case results <- logs:
+		case <-ctx.Done():
+			return
+		}
+	}
+	close(results)
+}
+
+// streamResumedLogs delivers the historical logs of a subscription starting at
+// the given block, followed by the live logs received from the event system.
+func (api *FilterAPI) streamResumedLogs(notifier *rpc.Notifier, rpcSub *rpc.Subscription, crit FilterCriteria, from uint64, matchedLogs chan []*types.Log) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		cursor  = &logCursor{from: from, head: api.sys.backend.CurrentHeader().Number.Uint64(), blocks: make(map[common.Hash]struct{})}
+		history = make(chan []*types.Log)
+		errc    = make(chan error, 1)
+		pending []*types.Log // live logs received while backfilling
+	)
+	if from <= cursor.head {
+		go api.backfillLogs(ctx, crit, from, cursor.head, history, errc)
+	} else {
+		close(history)
+	}
+	for history != nil {
+		select {
+		case logs, ok := <-history:
+			if !ok {
+				history = nil
+				continue
+			}
+			for _, l := range logs {
+				cursor.track(l)
+				notifier.Notify(rpcSub.ID, l)
+			}
+		case err := <-errc:
+			log.Debug("Failed to backfill logs", "id", rpcSub.ID, "from", from, "err", err)
+			notifier.Close(rpcSub.ID, err)
+			return
+		case logs := <-matchedLogs:
+			if len(pending)+len(logs) > maxBackfillPending {
+				log.Debug("Dropping log subscription with too many pending logs", "id", rpcSub.ID)
+				notifier.Close(rpcSub.ID, errBackfillOverflow)
+				return
+			}
+			pending = append(pending, logs...)
+		case <-rpcSub.Err():
+			return
+		}
+	}
+	for _, l := range pending {
+		if cursor.admit(l) {
+			notifier.Notify(rpcSub.ID, l)
+		}
+	}
+	for {
+		select {
+		case logs := <-matchedLogs:
+			for _, l := range logs {
+				if cursor.admit(l) {
+					notifier.Notify(rpcSub.ID, l)
+				}
+			}
+		case <-rpcSub.Err():
+			return
+		}
+	}
+}
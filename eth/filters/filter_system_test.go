@@ -922,3 +922,204 @@ func TestTransactionReceiptsSubscription(t *testing.T) {
 		})
 	}
 }
+
+// TestResumedLogSubscription tests that a log subscription with a fromBlock
+// backfills the historical logs and reconciles them with the live ones.
+func TestResumedLogSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		contract     = common.Address{0xfe}
+		gspec        = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	defer db.Close()
+
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, func(i int, gen *core.BlockGen) {
+		if i == 2 || i == 7 || i == 14 {
+			receipt := makeReceipt(contract)
+			receipt.Logs[0].Topics = []common.Hash{}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log, 10)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x5", "address": contract})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	next := func() types.Log {
+		t.Helper()
+		select {
+		case log := <-logs:
+			return log
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for log")
+		}
+		return types.Log{}
+	}
+	// The logs of blocks 8 and 15 are backfilled, block 3 is before fromBlock
+	for _, number := range []uint64{8, 15} {
+		if log := next(); log.BlockNumber != number || log.BlockHash != chain[number-1].Hash() || log.Removed {
+			t.Fatalf("wrong backfilled log: %+v", log)
+		}
+	}
+	var (
+		newHash = common.Hash{0x01}
+		live    = []*types.Log{
+			{Address: contract, BlockNumber: 15, BlockHash: chain[14].Hash()},  // duplicate of backfilled log
+			{Address: contract, BlockNumber: 15, BlockHash: newHash},           // log of a replacing block
+			{Address: contract, BlockNumber: 21, BlockHash: common.Hash{0x02}}, // log of a new block
+		}
+		removed = []*types.Log{
+			{Address: contract, BlockNumber: 14, BlockHash: common.Hash{0x03}, Removed: true}, // never delivered
+			{Address: contract, BlockNumber: 15, BlockHash: chain[14].Hash(), Removed: true},  // revokes backfilled log
+		}
+	)
+	for _, log := range append(live, removed...) {
+		log.Topics = []common.Hash{}
+	}
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: removed})
+	backend.logsFeed.Send(live)
+
+	if log := next(); log.BlockHash != chain[14].Hash() || !log.Removed {
+		t.Fatalf("expected removal of backfilled log, got %+v", log)
+	}
+	if log := next(); log.BlockHash != newHash || log.Removed {
+		t.Fatalf("expected log of replacing block, got %+v", log)
+	}
+	if log := next(); log.BlockNumber != 21 || log.Removed {
+		t.Fatalf("expected log of new block, got %+v", log)
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log: %+v", log)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestResumedLogSubscriptionRangeLimit tests that resuming a log subscription
+// is rejected if the backfill exceeds the block range limit.
+func TestResumedLogSubscriptionRangeLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{RangeLimit: 10})
+		api          = NewFilterAPI(sys)
+		gspec        = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	defer db.Close()
+
+	_, chain, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, func(i int, gen *core.BlockGen) {})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	if _, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x5"}); err == nil {
+		t.Fatal("backfill exceeding the range limit accepted")
+	}
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0xa"})
+	if err != nil {
+		t.Fatalf("backfill within the range limit rejected: %v", err)
+	}
+	sub.Unsubscribe()
+}
+
+// TestResumedLogSubscriptionFailure tests that a log subscription is terminated
+// with an error if the backfill fails.
+func TestResumedLogSubscriptionFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys)
+		gspec        = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+	)
+	defer db.Close()
+
+	_, chain, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 20, func(i int, gen *core.BlockGen) {})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	backend.startFilterMaps(0, true, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	// Drop a block from the middle of the backfilled range
+	rawdb.DeleteCanonicalHash(db, 10)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	logs := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]any{"fromBlock": "0x5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-sub.Err():
+		if err == nil {
+			t.Fatal("subscription ended without error")
+		}
+	case log := <-logs:
+		t.Fatalf("unexpected log: %+v", log)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not terminated")
+	}
+}
@@ -34,12 +34,14 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/internal/sessions"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -96,11 +98,16 @@ type Backend interface {
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
 type API struct {
 	backend Backend
+
+	debugSessions *sessions.Store[rpc.ID, *debugSession] // interactive debugging sessions by subscription
 }
 
 // NewAPI creates a new API definition for the tracing methods of the Ethereum service.
 func NewAPI(backend Backend) *API {
-	return &API{backend: backend}
+	return &API{
+		backend:       backend,
+		debugSessions: newDebugSessions(debugSessionIdleTimeout),
+	}
 }
 
 // chainContext constructs the context reader which is used by the evm for reading
@@ -864,36 +871,52 @@ func containsTx(block *types.Block, hash common.Hash) bool {
 // TraceTransaction returns the structured logs created during the execution of EVM
 // and returns them as a JSON object.
 func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	tx, msg, txctx, vmctx, statedb, release, err := api.stateAtTransactionHash(ctx, hash, reexec)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := api.traceTx(ctx, tx, msg, txctx, vmctx, statedb, config, nil)
+	if err != nil {
+		return nil, err
+	}
+	ethapi.MarkFinalizedResult(ctx, api.backend, txctx.BlockNumber.Uint64(), txctx.BlockHash)
+	return result, nil
+}
+
+// stateAtTransactionHash returns the canonical transaction with the given hash, along
+// with the execution environment and the state it is executed in.
+func (api *API) stateAtTransactionHash(ctx context.Context, hash common.Hash, reexec uint64) (*types.Transaction, *core.Message, *Context, vm.BlockContext, *state.StateDB, StateReleaseFunc, error) {
 	found, _, blockHash, blockNumber, index := api.backend.GetCanonicalTransaction(hash)
 	if !found {
 		// Warn in case tx indexer is not done.
 		if !api.backend.TxIndexDone() {
-			return nil, ethapi.NewTxIndexingError()
+			return nil, nil, nil, vm.BlockContext{}, nil, nil, ethapi.NewTxIndexingError()
 		}
 		// Only mined txes are supported
-		return nil, errTxNotFound
+		return nil, nil, nil, vm.BlockContext{}, nil, nil, errTxNotFound
 	}
 	// It shouldn't happen in practice.
 	if blockNumber == 0 {
-		return nil, errors.New("genesis is not traceable")
-	}
-	reexec := defaultTraceReexec
-	if config != nil && config.Reexec != nil {
-		reexec = *config.Reexec
+		return nil, nil, nil, vm.BlockContext{}, nil, nil, errors.New("genesis is not traceable")
 	}
 	block, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(blockNumber), blockHash)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, vm.BlockContext{}, nil, nil, err
 	}
 	tx, vmctx, statedb, release, err := api.backend.StateAtTransaction(ctx, block, int(index), reexec)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, vm.BlockContext{}, nil, nil, err
 	}
-	defer release()
-
 	msg, err := core.TransactionToMessage(tx, types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time()), block.BaseFee())
 	if err != nil {
-		return nil, err
+		release()
+		return nil, nil, nil, vm.BlockContext{}, nil, nil, err
 	}
 	txctx := &Context{
 		BlockHash:   blockHash,
@@ -901,7 +924,7 @@ func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *
 		TxIndex:     int(index),
 		TxHash:      hash,
 	}
-	return api.traceTx(ctx, tx, msg, txctx, vmctx, statedb, config, nil)
+	return tx, msg, txctx, vmctx, statedb, release, nil
 }
 
 // TraceCall lets you trace a given eth_call. It collects the structured logs
@@ -1003,6 +1026,79 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	return api.traceTx(ctx, tx, msg, new(Context), blockContext, statedb, traceConfig, precompiles)
 }
 
+// SimulateV1 executes a series of blocks like eth_simulateV1 and traces every
+// simulated call. The result of the tracer is returned in the trace field of
+// the call results. Blocks are simulated on top of the given block, the state
+// of the simulated blocks is carried over from one to the next. The whole
+// simulation is bounded by the trace timeout.
+func (api *API) SimulateV1(ctx context.Context, opts ethapi.SimOpts, blockNrOrHash *rpc.BlockNumberOrHash, config *TraceConfig) (interface{}, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	var (
+		err   error
+		block *types.Block
+	)
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err = api.blockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		if number == rpc.PendingBlockNumber {
+			return nil, errors.New("tracing on top of pending is not supported")
+		}
+		block, err = api.blockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	reexec := defaultTraceReexec
+	if config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, release, err := api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	timeout, err := simulateTimeout(config)
+	if err != nil {
+		return nil, err
+	}
+	newTracer := func(header *types.Header, txHash common.Hash, txIndex int) (*tracing.Hooks, func() (json.RawMessage, error), error) {
+		// Default tracer is the struct logger
+		if config.Tracer == nil {
+			logger := logger.NewStructLogger(config.Config)
+			return logger.Hooks(), logger.GetResult, nil
+		}
+		txctx := &Context{
+			BlockNumber: header.Number,
+			TxIndex:     txIndex,
+			TxHash:      txHash,
+		}
+		tracer, err := DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig, api.backend.ChainConfig())
+		if err != nil {
+			return nil, nil, err
+		}
+		return tracer.Hooks, tracer.GetResult, nil
+	}
+	return ethapi.Simulate(ctx, api.backend, statedb, block.Header(), opts, api.backend.RPCGasCap(), timeout, newTracer)
+}
+
+// simulateTimeout returns the time bounding a whole simulation, which is the
+// trace timeout if given, or the default trace timeout otherwise.
+func simulateTimeout(config *TraceConfig) (time.Duration, error) {
+	if config.Timeout == nil {
+		return defaultTraceTimeout, nil
+	}
+	return time.ParseDuration(*config.Timeout)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
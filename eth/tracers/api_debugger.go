@@ -0,0 +1,247 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/debugger"
+	"github.com/ethereum/go-ethereum/internal/sessions"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxDebugSessions is the maximum number of concurrent interactive debugging
+	// sessions, each of which holds on to the state of a transaction.
+	maxDebugSessions = 8
+
+	// debugSessionIdleTimeout is the time after which a debugging session that
+	// neither received commands nor sent events is dropped.
+	debugSessionIdleTimeout = 5 * time.Minute
+)
+
+var (
+	errDebugSessionNotFound = errors.New("debugging session not found")
+	errDebugSessionExpired  = errors.New("debugging session expired")
+)
+
+// debugSession is an active interactive debugging session.
+type debugSession struct {
+	*debugger.Debugger
+	cancel context.CancelCauseFunc // stops forwarding the events
+}
+
+// newDebugSessions creates the store of debugging sessions, which stops the
+// sessions left idle for the given timeout.
+func newDebugSessions(idle time.Duration) *sessions.Store[rpc.ID, *debugSession] {
+	return sessions.New(maxDebugSessions, idle, func(_ rpc.ID, session *debugSession) {
+		session.cancel(errDebugSessionExpired)
+	})
+}
+
+// DebugConfig holds extra parameters to debugging functions.
+type DebugConfig struct {
+	Breakpoints []debugger.Breakpoint `json:"breakpoints"`
+	Reexec      *uint64               `json:"reexec"`
+}
+
+// DebugTransaction starts an interactive debugging session of the given transaction.
+// The execution pauses before the first opcode and at every breakpoint, sending an
+// event describing its state to the subscriber. While paused, the session can be
+// inspected and resumed via the debugger methods, identified by the subscription id.
+// The last event is sent when the execution finished.
+func (api *API) DebugTransaction(ctx context.Context, hash common.Hash, config *DebugConfig) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if config == nil {
+		config = &DebugConfig{}
+	}
+	d := debugger.New()
+	for _, bp := range config.Breakpoints {
+		if _, err := d.AddBreakpoint(bp); err != nil {
+			return nil, err
+		}
+	}
+	reexec := defaultTraceReexec
+	if config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	// Reserve the session before preparing the state, so concurrent requests
+	// can't exceed the session limit.
+	if !api.debugSessions.Reserve() {
+		return nil, fmt.Errorf("too many debugging sessions (max %d)", maxDebugSessions)
+	}
+	tx, msg, txctx, vmctx, statedb, release, err := api.stateAtTransactionHash(ctx, hash, reexec)
+	if err != nil {
+		api.debugSessions.Cancel()
+		return nil, err
+	}
+	var (
+		sub               = notifier.CreateSubscription()
+		fwdCtx, fwdCancel = context.WithCancelCause(context.Background())
+	)
+	api.debugSessions.Add(sub.ID, &debugSession{Debugger: d, cancel: fwdCancel})
+
+	// Execute the transaction in the background, driven by the debugger.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer d.Close()
+
+		hooks := d.Hooks()
+		evm := vm.NewEVM(vmctx, state.NewHookedState(statedb, hooks), api.backend.ChainConfig(), vm.Config{Tracer: hooks, NoBaseFee: true})
+		statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
+		var usedGas uint64
+		core.ApplyTransactionWithEVM(msg, new(core.GasPool).AddGas(msg.GasLimit), statedb, vmctx.BlockNumber, txctx.BlockHash, vmctx.Time, tx, &usedGas, evm)
+	}()
+
+	// Forward the events until the execution finished, the subscriber left or
+	// the session expired.
+	go func() {
+		defer func() {
+			api.debugSessions.Remove(sub.ID)
+			fwdCancel(nil)
+			d.Close()
+			<-done
+			release()
+		}()
+		go func() {
+			select {
+			case <-sub.Err():
+				fwdCancel(nil)
+			case <-fwdCtx.Done():
+			}
+		}()
+		for {
+			ev, err := d.Wait(fwdCtx)
+			if err != nil {
+				if context.Cause(fwdCtx) == errDebugSessionExpired {
+					log.Debug("Debugging session expired", "id", sub.ID)
+					notifier.Close(sub.ID, errDebugSessionExpired)
+				}
+				return
+			}
+			api.debugSessions.Get(sub.ID) // extend the lifetime of the session
+			if err := notifier.Notify(sub.ID, ev); err != nil {
+				return
+			}
+			if ev.Reason == debugger.ReasonFinished {
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// debugSession returns the session with the given subscription id, extending
+// its lifetime.
+func (api *API) debugSession(id rpc.ID) (*debugSession, error) {
+	session, ok := api.debugSessions.Get(id)
+	if !ok {
+		return nil, errDebugSessionNotFound
+	}
+	return session, nil
+}
+
+// DebuggerContinue resumes the paused execution of a debugging session until the
+// next breakpoint.
+func (api *API) DebuggerContinue(id rpc.ID) error {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return err
+	}
+	return d.Continue()
+}
+
+// DebuggerStep resumes the paused execution of a debugging session until the next
+// opcode.
+func (api *API) DebuggerStep(id rpc.ID) error {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return err
+	}
+	return d.Step()
+}
+
+// DebuggerStepOver resumes the paused execution of a debugging session until the
+// next opcode in the current call.
+func (api *API) DebuggerStepOver(id rpc.ID) error {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return err
+	}
+	return d.StepOver()
+}
+
+// DebuggerStepOut resumes the paused execution of a debugging session until the
+// current call returns.
+func (api *API) DebuggerStepOut(id rpc.ID) error {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return err
+	}
+	return d.StepOut()
+}
+
+// DebuggerAddBreakpoint adds a breakpoint to a debugging session, returning its id.
+func (api *API) DebuggerAddBreakpoint(id rpc.ID, bp debugger.Breakpoint) (int, error) {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return 0, err
+	}
+	return d.AddBreakpoint(bp)
+}
+
+// DebuggerRemoveBreakpoint removes a breakpoint from a debugging session, reporting
+// whether it existed.
+func (api *API) DebuggerRemoveBreakpoint(id rpc.ID, breakpoint int) (bool, error) {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return false, err
+	}
+	return d.RemoveBreakpoint(breakpoint), nil
+}
+
+// DebuggerMemory returns the memory of the current call of a paused debugging
+// session in the given range.
+func (api *API) DebuggerMemory(id rpc.ID, offset, size hexutil.Uint64) (hexutil.Bytes, error) {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return nil, err
+	}
+	return d.Memory(uint64(offset), uint64(size))
+}
+
+// DebuggerStorage returns the value of a storage slot in a paused debugging session.
+func (api *API) DebuggerStorage(id rpc.ID, address common.Address, slot common.Hash) (common.Hash, error) {
+	d, err := api.debugSession(id)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return d.Storage(address, slot)
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/eth/tracers/debugger"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newDebugTestBackend creates a backend with a transaction writing a storage
+// slot of a contract, returning the transaction hash and the contract address.
+func newDebugTestBackend(t *testing.T) (*testBackend, common.Hash, common.Address) {
+	accounts := newAccounts(1)
+	contract := common.HexToAddress("0xc0de")
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Code: program.New().Sstore(1, 0x2a).Op(vm.STOP).Bytes()},
+		},
+	}
+	var target common.Hash
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &contract,
+			Gas:      100000,
+			GasPrice: b.BaseFee(),
+		}), types.HomesteadSigner{}, accounts[0].key)
+		b.AddTx(tx)
+		target = tx.Hash()
+	})
+	return backend, target, contract
+}
+
+func TestDebugTransaction(t *testing.T) {
+	t.Parallel()
+
+	backend, target, contract := newDebugTestBackend(t)
+	defer backend.teardown()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", NewAPI(backend)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events := make(chan *debugger.Event)
+	sub, err := client.Subscribe(ctx, "debug", events, "debugTransaction", target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	next := func() *debugger.Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for event")
+		}
+		return nil
+	}
+	id := rpc.ID(sub.ID())
+
+	// The execution pauses before the first opcode of the contract.
+	if ev := next(); ev.Reason != debugger.ReasonStep || ev.PC != 0 || ev.Address != contract {
+		t.Fatalf("unexpected first event: %v", ev)
+	}
+	var bp int
+	if err := client.CallContext(ctx, &bp, "debug_debuggerAddBreakpoint", id, debugger.Breakpoint{Op: "SSTORE"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CallContext(ctx, nil, "debug_debuggerContinue", id); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev.Reason != debugger.ReasonBreakpoint || ev.Breakpoint != bp || ev.Op != "SSTORE" {
+		t.Fatalf("breakpoint not hit: %v", ev)
+	}
+	if err := client.CallContext(ctx, nil, "debug_debuggerStep", id); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev.Op != "STOP" {
+		t.Fatalf("unexpected event after step: %v", ev)
+	}
+	var value common.Hash
+	if err := client.CallContext(ctx, &value, "debug_debuggerStorage", id, contract, common.BigToHash(common.Big1)); err != nil {
+		t.Fatal(err)
+	}
+	if value != common.HexToHash("0x2a") {
+		t.Fatalf("wrong storage value %x", value)
+	}
+	if err := client.CallContext(ctx, nil, "debug_debuggerContinue", id); err != nil {
+		t.Fatal(err)
+	}
+	if ev := next(); ev.Reason != debugger.ReasonFinished || ev.Error != "" || ev.GasUsed == 0 {
+		t.Fatalf("execution not finished: %v", ev)
+	}
+	// The session is gone once the execution finished.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := client.CallContext(ctx, nil, "debug_debuggerStep", id)
+		if err != nil && err.Error() == errDebugSessionNotFound.Error() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("session not removed after finishing: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDebugTransactionIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	backend, target, _ := newDebugTestBackend(t)
+	defer backend.teardown()
+
+	api := NewAPI(backend)
+	api.debugSessions = newDebugSessions(100 * time.Millisecond)
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events := make(chan *debugger.Event, 1)
+	sub, err := client.Subscribe(ctx, "debug", events, "debugTransaction", target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	// The session paused before the first opcode is dropped if left alone.
+	select {
+	case err := <-sub.Err():
+		if err == nil || err.Error() != errDebugSessionExpired.Error() {
+			t.Fatalf("wrong subscription error: %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("session did not expire")
+	}
+	if n := api.debugSessions.Len(); n != 0 {
+		t.Fatalf("session not released: %d sessions", n)
+	}
+}
@@ -1373,3 +1373,143 @@ func TestStandardTraceBlockToFile(t *testing.T) {
 		}
 	}
 }
+
+// callerPrecompile is a stateful precompile returning its caller.
+type callerPrecompile struct{}
+
+func (c *callerPrecompile) RequiredGas(input []byte) uint64  { return 50 }
+func (c *callerPrecompile) Run(input []byte) ([]byte, error) { return nil, vm.ErrExecutionReverted }
+func (c *callerPrecompile) Name() string                     { return "CALLER" }
+
+func (c *callerPrecompile) RunStateful(env *vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+	return common.LeftPadBytes(env.Caller.Bytes(), 32), nil
+}
+
+func init() {
+	vm.RegisterPrecompile("tracers-caller", new(callerPrecompile))
+}
+
+func TestTraceCallCustomPrecompile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts   = newAccounts(1)
+		precompile = common.HexToAddress("0x0100000000000000000000000000000000000000")
+		config     = *params.TestChainConfig
+	)
+	config.Precompiles = map[common.Address]*params.PrecompileConfig{
+		precompile: {Name: "tracers-caller", Block: big.NewInt(1)},
+	}
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	tests := []struct {
+		block rpc.BlockNumber
+		want  string
+	}{
+		{0, `{"gas":21000,"failed":false,"returnValue":"0x"}`},
+		{1, fmt.Sprintf(`{"gas":21050,"failed":false,"returnValue":"0x%x"}`, common.LeftPadBytes(accounts[0].addr.Bytes(), 32))},
+	}
+	for _, tc := range tests {
+		args := ethapi.TransactionArgs{From: &accounts[0].addr, To: &precompile}
+		result, err := api.TraceCall(context.Background(), args, rpc.BlockNumberOrHash{BlockNumber: &tc.block}, nil)
+		if err != nil {
+			t.Fatalf("block %d: failed to trace call: %v", tc.block, err)
+		}
+		var have, want struct {
+			Gas         int
+			Failed      bool
+			ReturnValue string
+		}
+		blob, _ := json.Marshal(result)
+		json.Unmarshal(blob, &have)
+		json.Unmarshal([]byte(tc.want), &want)
+		if have != want {
+			t.Errorf("block %d: have %s, want %s", tc.block, blob, tc.want)
+		}
+	}
+}
+
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0xc0de")
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+	)
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := NewAPI(backend)
+
+	// The contract stores 1 in slot 0, which is only set by the call of the
+	// first simulated block.
+	var opts ethapi.SimOpts
+	input := fmt.Sprintf(`{"blockStateCalls": [
+		{"stateOverrides": {"%v": {"code": "0x600160005500"}}, "calls": [{"from": "%v", "to": "%v"}]},
+		{"calls": [{"from": "%v", "to": "%v"}]}
+	]}`, contract, accounts[0].addr, contract, accounts[0].addr, contract)
+	if err := json.Unmarshal([]byte(input), &opts); err != nil {
+		t.Fatalf("failed to decode options: %v", err)
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	result, err := api.SimulateV1(context.Background(), opts, &latest, nil)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	blob, _ := json.Marshal(result)
+	var blocks []struct {
+		Calls []struct {
+			Trace *logger.ExecutionResult `json:"trace"`
+		} `json:"calls"`
+	}
+	if err := json.Unmarshal(blob, &blocks); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("block count mismatch: have %d, want 2", len(blocks))
+	}
+	var gas []uint64
+	for i, block := range blocks {
+		if len(block.Calls) != 1 || block.Calls[0].Trace == nil {
+			t.Fatalf("block %d: missing call trace: %s", i, blob)
+		}
+		if have := len(block.Calls[0].Trace.StructLogs); have != 4 {
+			t.Errorf("block %d: struct log count mismatch: have %d, want 4", i, have)
+		}
+		gas = append(gas, block.Calls[0].Trace.Gas)
+	}
+	// Overwriting the slot in the second block is cheaper than setting it.
+	if want := []uint64{43106, 23206}; !reflect.DeepEqual(gas, want) {
+		t.Errorf("gas mismatch: have %v, want %v", gas, want)
+	}
+}
+
+func TestSimulateTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Simulations without a timeout are bounded by the default trace timeout.
+	if timeout, err := simulateTimeout(&TraceConfig{}); err != nil || timeout != defaultTraceTimeout {
+		t.Errorf("default timeout mismatch: have %v, %v, want %v", timeout, err, defaultTraceTimeout)
+	}
+	custom := "1m"
+	if timeout, err := simulateTimeout(&TraceConfig{Timeout: &custom}); err != nil || timeout != time.Minute {
+		t.Errorf("custom timeout mismatch: have %v, %v, want %v", timeout, err, time.Minute)
+	}
+	invalid := "soon"
+	if _, err := simulateTimeout(&TraceConfig{Timeout: &invalid}); err == nil {
+		t.Error("invalid timeout accepted")
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package debugger implements an interactive EVM debugger. The debugger suspends the
+// execution of a transaction at breakpoints or after single steps, and lets a
+// controller inspect the stack, memory and storage before resuming it.
+//
+// The execution runs in its own goroutine using the hooks of the debugger, while the
+// controller waits for it to pause via Wait and resumes it via Continue, Step,
+// StepOver or StepOut. A debugger can only be used for a single transaction.
+package debugger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	// ErrNotPaused is returned by commands if the execution is not paused.
+	ErrNotPaused = errors.New("execution not paused")
+
+	// ErrDetached is returned by commands if the debugger was closed.
+	ErrDetached = errors.New("debugger detached")
+)
+
+// Reasons for the execution to pause.
+const (
+	ReasonStep       = "step"
+	ReasonBreakpoint = "breakpoint"
+	ReasonFinished   = "finished"
+)
+
+// Breakpoint pauses the execution before an opcode matching all of its conditions.
+type Breakpoint struct {
+	PC      *uint64         `json:"pc,omitempty"`      // program counter
+	Op      string          `json:"op,omitempty"`      // opcode name
+	Address *common.Address `json:"address,omitempty"` // address of the executing contract
+	Slot    *common.Hash    `json:"slot,omitempty"`    // storage slot accessed by SLOAD or SSTORE
+	Depth   *int            `json:"depth,omitempty"`   // call depth, starting at 1
+
+	op vm.OpCode
+}
+
+// String implements fmt.Stringer.
+func (b *Breakpoint) String() string {
+	var s string
+	if b.PC != nil {
+		s += fmt.Sprintf(" pc=%d", *b.PC)
+	}
+	if b.Op != "" {
+		s += " op=" + b.Op
+	}
+	if b.Address != nil {
+		s += " address=" + b.Address.Hex()
+	}
+	if b.Slot != nil {
+		s += " slot=" + b.Slot.Hex()
+	}
+	if b.Depth != nil {
+		s += fmt.Sprintf(" depth=%d", *b.Depth)
+	}
+	if s == "" {
+		return ""
+	}
+	return s[1:]
+}
+
+// matches reports whether the breakpoint applies to the opcode about to be executed.
+func (b *Breakpoint) matches(pc uint64, op vm.OpCode, scope tracing.OpContext, depth int) bool {
+	if b.PC != nil && *b.PC != pc {
+		return false
+	}
+	if b.Op != "" && b.op != op {
+		return false
+	}
+	if b.Address != nil && *b.Address != scope.Address() {
+		return false
+	}
+	if b.Depth != nil && *b.Depth != depth {
+		return false
+	}
+	if b.Slot != nil {
+		if op != vm.SLOAD && op != vm.SSTORE {
+			return false
+		}
+		stack := scope.StackData()
+		if len(stack) == 0 || common.Hash(stack[len(stack)-1].Bytes32()) != *b.Slot {
+			return false
+		}
+	}
+	return true
+}
+
+// Event describes the state of the execution when it pauses or finishes.
+type Event struct {
+	Reason     string         `json:"reason"`
+	Breakpoint int            `json:"breakpoint,omitempty"` // id of the breakpoint hit
+	PC         uint64         `json:"pc"`
+	Op         string         `json:"op"`
+	Gas        uint64         `json:"gas"`
+	GasCost    uint64         `json:"gasCost"`
+	Depth      int            `json:"depth"`
+	Address    common.Address `json:"address"`
+	Stack      []hexutil.U256 `json:"stack"`
+	MemorySize int            `json:"memorySize"`
+
+	// Set when the execution finished.
+	GasUsed uint64 `json:"gasUsed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// String implements fmt.Stringer.
+func (e *Event) String() string {
+	switch e.Reason {
+	case ReasonFinished:
+		if e.Error != "" {
+			return fmt.Sprintf("finished: gasUsed=%d error=%q", e.GasUsed, e.Error)
+		}
+		return fmt.Sprintf("finished: gasUsed=%d", e.GasUsed)
+	case ReasonBreakpoint:
+		return fmt.Sprintf("breakpoint %d: depth=%d pc=%d op=%s gas=%d cost=%d address=%s", e.Breakpoint, e.Depth, e.PC, e.Op, e.Gas, e.GasCost, e.Address.Hex())
+	default:
+		return fmt.Sprintf("%s: depth=%d pc=%d op=%s gas=%d cost=%d address=%s", e.Reason, e.Depth, e.PC, e.Op, e.Gas, e.GasCost, e.Address.Hex())
+	}
+}
+
+type stepMode int
+
+const (
+	modeContinue stepMode = iota // run until a breakpoint is hit
+	modeStep                     // pause before the next opcode
+	modeStepOver                 // pause before the next opcode at the same or a lower depth
+	modeStepOut                  // pause before the next opcode at a lower depth
+)
+
+// command is a request of the controller, served by the paused execution.
+type command struct {
+	mode  stepMode // mode to resume the execution in
+	query func(scope tracing.OpContext) any
+	reply chan any
+}
+
+// Debugger is a tracer pausing the execution at breakpoints.
+type Debugger struct {
+	mu          sync.Mutex
+	breakpoints map[int]*Breakpoint
+	nextID      int
+	mode        stepMode
+	modeDepth   int // depth at which step over/out was requested
+	paused      bool
+	statedb     tracing.StateDB
+
+	events   chan *Event
+	commands chan *command
+	finished chan struct{}
+	result   *Event
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// New creates a debugger, which pauses before the first opcode.
+func New() *Debugger {
+	return &Debugger{
+		breakpoints: make(map[int]*Breakpoint),
+		nextID:      1,
+		mode:        modeStep,
+		events:      make(chan *Event),
+		commands:    make(chan *command),
+		finished:    make(chan struct{}),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Hooks returns the tracing hooks to execute the debugged transaction with.
+func (d *Debugger) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart: d.onTxStart,
+		OnTxEnd:   d.onTxEnd,
+		OnOpcode:  d.onOpcode,
+	}
+}
+
+// AddBreakpoint adds a breakpoint, returning its id.
+func (d *Debugger) AddBreakpoint(bp Breakpoint) (int, error) {
+	if bp.Op != "" {
+		bp.op = vm.StringToOp(bp.Op)
+		if bp.op.String() != bp.Op {
+			return 0, fmt.Errorf("invalid opcode %q", bp.Op)
+		}
+	}
+	if bp.String() == "" {
+		return 0, errors.New("breakpoint without conditions")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := d.nextID
+	d.nextID++
+	d.breakpoints[id] = &bp
+	return id, nil
+}
+
+// RemoveBreakpoint removes the breakpoint with the given id, reporting whether it
+// existed.
+func (d *Debugger) RemoveBreakpoint(id int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.breakpoints[id]
+	delete(d.breakpoints, id)
+	return ok
+}
+
+// Breakpoints returns the breakpoints by id.
+func (d *Debugger) Breakpoints() map[int]Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	bps := make(map[int]Breakpoint, len(d.breakpoints))
+	for id, bp := range d.breakpoints {
+		bps[id] = *bp
+	}
+	return bps
+}
+
+// Wait blocks until the execution pauses or finishes, returning the event describing
+// its state. Once the execution finished, the final event is returned by all calls.
+func (d *Debugger) Wait(ctx context.Context) (*Event, error) {
+	// Report the end of the execution even if the debugger was detached.
+	select {
+	case <-d.finished:
+		return d.result, nil
+	default:
+	}
+	select {
+	case ev := <-d.events:
+		return ev, nil
+	case <-d.finished:
+		return d.result, nil
+	case <-d.quit:
+		return nil, ErrDetached
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Continue resumes the execution until a breakpoint is hit.
+func (d *Debugger) Continue() error {
+	return d.resume(modeContinue)
+}
+
+// Step resumes the execution until the next opcode.
+func (d *Debugger) Step() error {
+	return d.resume(modeStep)
+}
+
+// StepOver resumes the execution until the next opcode in the current call, stepping
+// over any calls and contract creations. Breakpoints within them are still hit.
+func (d *Debugger) StepOver() error {
+	return d.resume(modeStepOver)
+}
+
+// StepOut resumes the execution until the current call returns.
+func (d *Debugger) StepOut() error {
+	return d.resume(modeStepOut)
+}
+
+// Memory returns the memory of the current call in the given range, truncated to the
+// size of the memory.
+func (d *Debugger) Memory(offset, size uint64) ([]byte, error) {
+	res, err := d.query(func(scope tracing.OpContext) any {
+		mem := scope.MemoryData()
+		if offset >= uint64(len(mem)) {
+			return []byte{}
+		}
+		end := min(offset+size, uint64(len(mem)))
+		if end < offset { // overflow
+			end = uint64(len(mem))
+		}
+		return common.CopyBytes(mem[offset:end])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]byte), nil
+}
+
+// Storage returns the value of the storage slot of the given account.
+func (d *Debugger) Storage(addr common.Address, slot common.Hash) (common.Hash, error) {
+	res, err := d.query(func(scope tracing.OpContext) any {
+		if d.statedb == nil {
+			return common.Hash{}
+		}
+		return d.statedb.GetState(addr, slot)
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return res.(common.Hash), nil
+}
+
+// Close detaches the debugger, letting the execution run to completion.
+func (d *Debugger) Close() {
+	d.quitOnce.Do(func() { close(d.quit) })
+}
+
+// resume sends a command resuming the paused execution.
+func (d *Debugger) resume(mode stepMode) error {
+	_, err := d.send(&command{mode: mode})
+	return err
+}
+
+// query runs fn on the paused execution.
+func (d *Debugger) query(fn func(scope tracing.OpContext) any) (any, error) {
+	return d.send(&command{query: fn})
+}
+
+func (d *Debugger) send(cmd *command) (any, error) {
+	d.mu.Lock()
+	paused := d.paused
+	d.mu.Unlock()
+	if !paused {
+		return nil, ErrNotPaused
+	}
+	cmd.reply = make(chan any, 1)
+	select {
+	case d.commands <- cmd:
+		return <-cmd.reply, nil
+	case <-d.quit:
+		return nil, ErrDetached
+	}
+}
+
+func (d *Debugger) onTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	d.statedb = env.StateDB
+}
+
+func (d *Debugger) onTxEnd(receipt *types.Receipt, err error) {
+	d.result = &Event{Reason: ReasonFinished}
+	if receipt != nil {
+		d.result.GasUsed = receipt.GasUsed
+	}
+	if err != nil {
+		d.result.Error = err.Error()
+	}
+	close(d.finished)
+}
+
+func (d *Debugger) onOpcode(pc uint64, opcode byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	select {
+	case <-d.quit:
+		return // detached
+	default:
+	}
+	op := vm.OpCode(opcode)
+
+	// Check whether to pause before the opcode.
+	d.mu.Lock()
+	var (
+		reason string
+		bpID   int
+	)
+	switch {
+	case d.mode == modeStep,
+		d.mode == modeStepOver && depth <= d.modeDepth,
+		d.mode == modeStepOut && depth < d.modeDepth:
+		reason = ReasonStep
+	}
+	for id, bp := range d.breakpoints {
+		if bp.matches(pc, op, scope, depth) && (bpID == 0 || id < bpID) {
+			reason, bpID = ReasonBreakpoint, id
+		}
+	}
+	if reason == "" {
+		d.mu.Unlock()
+		return
+	}
+	d.paused = true
+	d.mu.Unlock()
+
+	ev := &Event{
+		Reason:     reason,
+		Breakpoint: bpID,
+		PC:         pc,
+		Op:         op.String(),
+		Gas:        gas,
+		GasCost:    cost,
+		Depth:      depth,
+		Address:    scope.Address(),
+		Stack:      make([]hexutil.U256, 0, len(scope.StackData())),
+		MemorySize: len(scope.MemoryData()),
+	}
+	for _, item := range scope.StackData() {
+		ev.Stack = append(ev.Stack, hexutil.U256(item))
+	}
+	defer func() {
+		d.mu.Lock()
+		d.paused = false
+		d.mu.Unlock()
+	}()
+	select {
+	case d.events <- ev:
+	case <-d.quit:
+		return
+	}
+	// Serve the controller until it resumes the execution.
+	for {
+		select {
+		case cmd := <-d.commands:
+			if cmd.query != nil {
+				cmd.reply <- cmd.query(scope)
+				continue
+			}
+			d.mu.Lock()
+			d.mode, d.modeDepth = cmd.mode, depth
+			d.paused = false
+			d.mu.Unlock()
+			cmd.reply <- nil
+			return
+		case <-d.quit:
+			return
+		}
+	}
+}
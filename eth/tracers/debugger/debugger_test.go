@@ -0,0 +1,188 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/holiman/uint256"
+)
+
+var (
+	mainAddr   = common.HexToAddress("0xaaaa")
+	calleeAddr = common.HexToAddress("0xbbbb")
+)
+
+// startExecution runs a call of the main contract, which calls the callee storing 0x2a
+// in slot 1 and then stores 7 in slot 2 itself. It returns a channel closed when the
+// execution is done.
+func startExecution(t *testing.T, d *Debugger) chan struct{} {
+	t.Helper()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(calleeAddr, program.New().Sstore(1, 0x2a).Op(vm.STOP).Bytes(), tracing.CodeChangeUnspecified)
+	statedb.SetCode(mainAddr, program.New().Call(nil, calleeAddr, 0, 0, 0, 0, 0).Sstore(2, 7).Op(vm.STOP).Bytes(), tracing.CodeChangeUnspecified)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runtime.Call(mainAddr, nil, &runtime.Config{State: statedb, EVMConfig: vm.Config{Tracer: d.Hooks()}})
+	}()
+	t.Cleanup(func() {
+		d.Close()
+		<-done
+	})
+	return done
+}
+
+func wait(t *testing.T, d *Debugger) *Event {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ev, err := d.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	d := New()
+	startExecution(t, d)
+
+	// The execution pauses before the first opcode.
+	if ev := wait(t, d); ev.Reason != ReasonStep || ev.PC != 0 || ev.Depth != 1 || ev.Address != mainAddr {
+		t.Fatalf("unexpected first event: %v", ev)
+	}
+	slotBP, err := d.AddBreakpoint(Breakpoint{Slot: ptr(common.BigToHash(common.Big1))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opBP, err := d.AddBreakpoint(Breakpoint{Op: "SSTORE", Depth: ptr(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddBreakpoint(Breakpoint{Op: "FOO"}); err == nil {
+		t.Fatal("breakpoint with invalid opcode added")
+	}
+
+	// The slot breakpoint is hit in the callee.
+	if err := d.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	ev := wait(t, d)
+	if ev.Reason != ReasonBreakpoint || ev.Breakpoint != slotBP || ev.Op != "SSTORE" || ev.Depth != 2 || ev.Address != calleeAddr {
+		t.Fatalf("slot breakpoint not hit: %v", ev)
+	}
+	if top := ev.Stack[len(ev.Stack)-1]; (*uint256.Int)(&top).Uint64() != 1 {
+		t.Fatalf("wrong stack top %v", top)
+	}
+	if value, err := d.Storage(calleeAddr, common.BigToHash(common.Big1)); err != nil || value != (common.Hash{}) {
+		t.Fatalf("wrong storage value before store: %x, %v", value, err)
+	}
+
+	// Stepping out of the callee pauses after the call.
+	if err := d.StepOut(); err != nil {
+		t.Fatal(err)
+	}
+	if ev := wait(t, d); ev.Reason != ReasonStep || ev.Depth != 1 || ev.Address != mainAddr {
+		t.Fatalf("unexpected event after stepping out: %v", ev)
+	}
+	if value, err := d.Storage(calleeAddr, common.BigToHash(common.Big1)); err != nil || value != common.HexToHash("0x2a") {
+		t.Fatalf("wrong storage value after store: %x, %v", value, err)
+	}
+
+	// The opcode breakpoint is hit in the main contract.
+	if err := d.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	if ev := wait(t, d); ev.Reason != ReasonBreakpoint || ev.Breakpoint != opBP || ev.Depth != 1 {
+		t.Fatalf("opcode breakpoint not hit: %v", ev)
+	}
+	if err := d.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	if ev := wait(t, d); ev.Reason != ReasonFinished || ev.Error != "" || ev.GasUsed == 0 {
+		t.Fatalf("execution not finished: %v", ev)
+	}
+	if err := d.Step(); !errors.Is(err, ErrNotPaused) {
+		t.Fatalf("wrong error stepping finished execution: %v", err)
+	}
+}
+
+func TestDebuggerStepOver(t *testing.T) {
+	d := New()
+	startExecution(t, d)
+
+	wait(t, d)
+	if _, err := d.AddBreakpoint(Breakpoint{Op: "CALL"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Continue(); err != nil {
+		t.Fatal(err)
+	}
+	call := wait(t, d)
+	if call.Op != "CALL" {
+		t.Fatalf("call breakpoint not hit: %v", call)
+	}
+	// Stepping over the call pauses at the next opcode of the main contract.
+	if err := d.StepOver(); err != nil {
+		t.Fatal(err)
+	}
+	if ev := wait(t, d); ev.Depth != 1 || ev.PC != call.PC+1 {
+		t.Fatalf("call not stepped over: %v", ev)
+	}
+	// Single steps pause at every opcode.
+	if err := d.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if ev := wait(t, d); ev.PC <= call.PC+1 {
+		t.Fatalf("unexpected event after step: %v", ev)
+	}
+	if mem, err := d.Memory(0, 32); err != nil || len(mem) != 0 {
+		t.Fatalf("unexpected memory %x, %v", mem, err)
+	}
+}
+
+func TestDebuggerDetach(t *testing.T) {
+	d := New()
+	done := startExecution(t, d)
+
+	wait(t, d)
+	d.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution not finished after detaching")
+	}
+	if err := d.Continue(); !errors.Is(err, ErrNotPaused) && !errors.Is(err, ErrDetached) {
+		t.Fatalf("wrong error after detaching: %v", err)
+	}
+}
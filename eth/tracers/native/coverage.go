@@ -0,0 +1,194 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/internal"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("coverageTracer", newCoverageTracer, false)
+}
+
+// Coverage is the execution coverage of a set of transactions, keyed by the
+// hash of the executed code. Init code is keyed by its own hash, so runtime
+// and creation code can be mapped onto their respective solc source maps.
+type Coverage map[common.Hash]*CodeCoverage
+
+// CodeCoverage is the execution coverage of a single piece of code.
+type CodeCoverage struct {
+	Addresses []common.Address         `json:"addresses"`          // Sorted accounts the code was executed in
+	PCs       map[uint64]uint64        `json:"pcs"`                // Execution count of each executed program counter
+	Branches  map[uint64]*BranchCounts `json:"branches,omitempty"` // Outcomes of each executed JUMPI
+}
+
+// BranchCounts counts the directions a conditional jump went to.
+type BranchCounts struct {
+	Taken    uint64 `json:"taken"`
+	NotTaken uint64 `json:"notTaken"`
+}
+
+// code returns the coverage of the code with the given hash, creating it if
+// it doesn't exist yet.
+func (c Coverage) code(hash common.Hash) *CodeCoverage {
+	cov := c[hash]
+	if cov == nil {
+		cov = &CodeCoverage{PCs: make(map[uint64]uint64)}
+		c[hash] = cov
+	}
+	return cov
+}
+
+// Merge adds the coverage of other to c.
+func (c Coverage) Merge(other Coverage) {
+	for hash, src := range other {
+		dst := c.code(hash)
+		for _, addr := range src.Addresses {
+			dst.addAddress(addr)
+		}
+		for pc, n := range src.PCs {
+			dst.PCs[pc] += n
+		}
+		for pc, src := range src.Branches {
+			dst := dst.branch(pc)
+			dst.Taken += src.Taken
+			dst.NotTaken += src.NotTaken
+		}
+	}
+}
+
+// addAddress records an account the code was executed in.
+func (c *CodeCoverage) addAddress(addr common.Address) {
+	if i, found := slices.BinarySearchFunc(c.Addresses, addr, common.Address.Cmp); !found {
+		c.Addresses = slices.Insert(c.Addresses, i, addr)
+	}
+}
+
+// branch returns the branch counters of the JUMPI at the given pc.
+func (c *CodeCoverage) branch(pc uint64) *BranchCounts {
+	if c.Branches == nil {
+		c.Branches = make(map[uint64]*BranchCounts)
+	}
+	b := c.Branches[pc]
+	if b == nil {
+		b = new(BranchCounts)
+		c.Branches[pc] = b
+	}
+	return b
+}
+
+// coverageTracer records the program counters executed and the directions
+// taken by conditional jumps, per code hash. The result of multiple tracer
+// runs can be combined with Coverage.Merge.
+//
+// Example:
+//
+//	> debug.traceTransaction("0x...", {tracer: "coverageTracer"})
+//	{
+//	  "0x3c2d...f1a9": {
+//	    "addresses": ["0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"],
+//	    "pcs": {"0": 1, "2": 1, "4": 1, ...},
+//	    "branches": {"11": {"taken": 1, "notTaken": 0}, ...}
+//	  }
+//	}
+type coverageTracer struct {
+	coverage  Coverage
+	frames    []*CodeCoverage // Coverage of the code of each active call frame, resolved lazily
+	interrupt atomic.Bool     // Atomic flag to signal execution interruption
+	reason    error           // Textual reason for the interruption
+}
+
+// newCoverageTracer returns a native go tracer which collects the execution
+// coverage of transactions.
+func newCoverageTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	t := &coverageTracer{coverage: make(Coverage)}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnEnter:  t.OnEnter,
+			OnExit:   t.OnExit,
+			OnOpcode: t.OnOpcode,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *coverageTracer) OnEnter(depth int, opcode byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// The code is only known once it starts executing.
+	t.frames = append(t.frames, nil)
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *coverageTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if len(t.frames) > 0 {
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+}
+
+func (t *coverageTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	cov := t.frames[len(t.frames)-1]
+	if cov == nil {
+		cov = t.coverage.code(crypto.Keccak256Hash(scope.ContractCode()))
+		cov.addAddress(scope.Address())
+		t.frames[len(t.frames)-1] = cov
+	}
+	cov.PCs[pc]++
+
+	if vm.OpCode(op) == vm.JUMPI && err == nil {
+		stack := scope.StackData()
+		if len(stack) < 2 {
+			return
+		}
+		if internal.StackBack(stack, 1).IsZero() {
+			cov.branch(pc).NotTaken++
+		} else {
+			cov.branch(pc).Taken++
+		}
+	}
+}
+
+// GetResult returns the json-encoded coverage, and any error arising from the
+// encoding or forceful termination (via `Stop`).
+func (t *coverageTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.coverage)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *coverageTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
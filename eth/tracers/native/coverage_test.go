@@ -0,0 +1,77 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/native"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageTracer(t *testing.T) {
+	var (
+		addr = common.HexToAddress("0xaa")
+		// jumpi(7, calldataload(0)); stop; jumpdest; stop
+		code = common.FromHex("600035600757005b00")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(addr, code, tracing.CodeChangeUnspecified)
+
+	tracer, err := tracers.DefaultDirectory.New("coverageTracer", &tracers.Context{}, nil, params.MergedTestChainConfig)
+	require.NoError(t, err)
+
+	// Run both directions of the branch with the same tracer.
+	for _, input := range []string{"0x01", "0x00"} {
+		_, _, err := runtime.Call(addr, common.LeftPadBytes(common.FromHex(input), 32), &runtime.Config{
+			ChainConfig: params.MergedTestChainConfig,
+			State:       statedb,
+			EVMConfig:   vm.Config{Tracer: tracer.Hooks},
+		})
+		require.NoError(t, err)
+	}
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+
+	var coverage native.Coverage
+	require.NoError(t, json.Unmarshal(res, &coverage))
+	require.Len(t, coverage, 1)
+
+	cov := coverage[crypto.Keccak256Hash(code)]
+	require.NotNil(t, cov)
+	require.Equal(t, []common.Address{addr}, cov.Addresses)
+	require.Equal(t, map[uint64]uint64{0: 2, 2: 2, 3: 2, 5: 2, 6: 1, 7: 1, 8: 1}, cov.PCs)
+	require.Equal(t, map[uint64]*native.BranchCounts{5: {Taken: 1, NotTaken: 1}}, cov.Branches)
+
+	// Merging must add up the counters.
+	merged := make(native.Coverage)
+	merged.Merge(coverage)
+	merged.Merge(coverage)
+	require.Equal(t, []common.Address{addr}, merged[crypto.Keccak256Hash(code)].Addresses)
+	require.Equal(t, uint64(4), merged[crypto.Keccak256Hash(code)].PCs[0])
+	require.Equal(t, &native.BranchCounts{Taken: 2, NotTaken: 2}, merged[crypto.Keccak256Hash(code)].Branches[5])
+}
@@ -0,0 +1,442 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/internal"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("gasProfilerTracer", newGasProfilerTracer, false)
+}
+
+const (
+	// gasProfileIntrinsicFrame is the name of the folded stack holding the
+	// intrinsic gas of the transaction.
+	gasProfileIntrinsicFrame = "intrinsic"
+
+	// gasProfileDataFloorFrame is the name of the folded stack holding the
+	// gas charged to reach the EIP-7623 calldata floor.
+	gasProfileDataFloorFrame = "datafloor"
+)
+
+// gasProfileKey identifies a function of a contract.
+type gasProfileKey struct {
+	address  common.Address
+	selector string
+}
+
+// gasProfileFunction is the gas spent in a function of a contract.
+type gasProfileFunction struct {
+	Address       common.Address `json:"address"`
+	Selector      string         `json:"selector,omitempty"`
+	Calls         uint64         `json:"calls"`
+	GasUsed       uint64         `json:"gasUsed"`       // Including the gas used by subcalls
+	SelfGas       uint64         `json:"selfGas"`       // Excluding the gas used by subcalls
+	ColdAccessGas uint64         `json:"coldAccessGas"` // Surcharge of cold account and slot accesses of the function itself
+}
+
+// gasProfileFrame is an active call frame.
+type gasProfileFrame struct {
+	key      gasProfileKey
+	path     string // Folded stack of the frame, including itself
+	childGas uint64 // Gas used by finished subcalls
+	coldGas  uint64 // Cold access surcharges of the frame itself
+	accessed int    // Length of the access journal when the frame was entered
+}
+
+// gasProfileSlot is a storage slot of an account.
+type gasProfileSlot struct {
+	address common.Address
+	slot    common.Hash
+}
+
+// gasProfileAccess is an addition to the access list, either of an account or,
+// if isSlot is set, of a storage slot.
+type gasProfileAccess struct {
+	gasProfileSlot
+	isSlot bool
+}
+
+// gasProfileAccessList mirrors the EIP-2929 access list of the transaction,
+// which is not exposed to tracers. Additions are journaled, so that the ones
+// made by reverted call frames can be undone like in the state.
+type gasProfileAccessList struct {
+	addresses map[common.Address]struct{}
+	slots     map[gasProfileSlot]struct{}
+	journal   []gasProfileAccess
+}
+
+func newGasProfileAccessList() *gasProfileAccessList {
+	return &gasProfileAccessList{
+		addresses: make(map[common.Address]struct{}),
+		slots:     make(map[gasProfileSlot]struct{}),
+	}
+}
+
+// addAddress marks the account as accessed, returning whether it was cold.
+func (al *gasProfileAccessList) addAddress(addr common.Address) bool {
+	if _, ok := al.addresses[addr]; ok {
+		return false
+	}
+	al.addresses[addr] = struct{}{}
+	al.journal = append(al.journal, gasProfileAccess{gasProfileSlot: gasProfileSlot{address: addr}})
+	return true
+}
+
+// addSlot marks the storage slot as accessed, returning whether it was cold.
+func (al *gasProfileAccessList) addSlot(addr common.Address, slot common.Hash) bool {
+	key := gasProfileSlot{address: addr, slot: slot}
+	if _, ok := al.slots[key]; ok {
+		return false
+	}
+	al.slots[key] = struct{}{}
+	al.journal = append(al.journal, gasProfileAccess{gasProfileSlot: key, isSlot: true})
+	return true
+}
+
+// revert undoes all additions after the given journal length.
+func (al *gasProfileAccessList) revert(length int) {
+	for _, entry := range al.journal[length:] {
+		if entry.isSlot {
+			delete(al.slots, entry.gasProfileSlot)
+		} else {
+			delete(al.addresses, entry.address)
+		}
+	}
+	al.journal = al.journal[:length]
+}
+
+type gasProfilerTracerConfig struct {
+	Format string `json:"format"` // Result format, either "json" (default) or "folded"
+}
+
+// gasProfilerTracer attributes the gas of a transaction to the contract
+// functions, identified by address and 4byte selector, and to the call
+// stacks executing them. Besides a JSON summary, it can emit the call stacks
+// in the folded format consumed by flamegraph tools:
+//
+//	> debug.traceTransaction("0x...", {tracer: "gasProfilerTracer", tracerConfig: {format: "folded"}})
+//	"intrinsic 21000\n0xa0b8...eb48:0xa9059cbb 31225\n..."
+type gasProfilerTracer struct {
+	config            gasProfilerTracerConfig
+	stack             []*gasProfileFrame
+	functions         map[gasProfileKey]*gasProfileFunction
+	folded            map[string]uint64
+	gasUsed           uint64
+	intrinsicGas      uint64
+	refund            uint64
+	interrupt         atomic.Bool // Atomic flag to signal execution interruption
+	reason            error       // Textual reason for the interruption
+	chainConfig       *params.ChainConfig
+	rules             params.Rules
+	activePrecompiles []common.Address // Updated on tx start based on given rules
+	env               *tracing.VMContext
+	accessList        *gasProfileAccessList // Accessed accounts and slots of the current transaction
+}
+
+// newGasProfilerTracer returns a native go tracer which aggregates the gas
+// usage of a transaction per function and call stack.
+func newGasProfilerTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*tracers.Tracer, error) {
+	var config gasProfilerTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	switch config.Format {
+	case "", "json", "folded":
+	default:
+		return nil, fmt.Errorf("unknown gas profile format %q", config.Format)
+	}
+	t := &gasProfilerTracer{
+		config:      config,
+		functions:   make(map[gasProfileKey]*gasProfileFunction),
+		folded:      make(map[string]uint64),
+		chainConfig: chainConfig,
+	}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart:   t.OnTxStart,
+			OnTxEnd:     t.OnTxEnd,
+			OnEnter:     t.OnEnter,
+			OnExit:      t.OnExit,
+			OnOpcode:    t.OnOpcode,
+			OnGasChange: t.OnGasChange,
+		},
+		GetResult: t.GetResult,
+		Stop:      t.Stop,
+	}, nil
+}
+
+func (t *gasProfilerTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.env = env
+	t.rules = t.chainConfig.Rules(env.BlockNumber, env.Random != nil, env.Time)
+	t.activePrecompiles = vm.ActivePrecompiles(t.rules)
+
+	// Warm the same accounts and slots as the state does before execution.
+	t.accessList = newGasProfileAccessList()
+	t.accessList.addAddress(from)
+	if to := tx.To(); to != nil {
+		t.accessList.addAddress(*to)
+	}
+	for _, addr := range t.activePrecompiles {
+		t.accessList.addAddress(addr)
+	}
+	for _, el := range tx.AccessList() {
+		t.accessList.addAddress(el.Address)
+		for _, key := range el.StorageKeys {
+			t.accessList.addSlot(el.Address, key)
+		}
+	}
+	if t.rules.IsShanghai {
+		t.accessList.addAddress(env.Coinbase)
+	}
+	// Authorities are warmed even if the authorization turns out to be invalid
+	// later on, as long as the chain ID and signature are valid.
+	for _, auth := range tx.SetCodeAuthorizations() {
+		if !auth.ChainID.IsZero() && auth.ChainID.CmpBig(t.chainConfig.ChainID) != 0 {
+			continue
+		}
+		if authority, err := auth.Authority(); err == nil {
+			t.accessList.addAddress(authority)
+		}
+	}
+}
+
+func (t *gasProfilerTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	if err != nil || receipt == nil {
+		return
+	}
+	t.gasUsed += receipt.GasUsed
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *gasProfilerTracer) OnEnter(depth int, opcode byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	key := gasProfileKey{address: to}
+	switch op := vm.OpCode(opcode); {
+	case op == vm.CREATE || op == vm.CREATE2 || op == vm.EOFCREATE:
+		key.selector = "create"
+	case len(input) >= 4 && !slices.Contains(t.activePrecompiles, to):
+		key.selector = bytesToHex(input[:4])
+	}
+	// The callee and its delegation target are warmed by the caller, so they
+	// stay warm even if the call reverts.
+	if t.accessList != nil {
+		t.accessList.addAddress(to)
+		if target, ok := types.ParseDelegation(t.env.StateDB.GetCode(to)); ok {
+			t.accessList.addAddress(target)
+		}
+	}
+	frame := &gasProfileFrame{key: key, path: key.String()}
+	if len(t.stack) > 0 {
+		frame.path = t.stack[len(t.stack)-1].path + ";" + frame.path
+	}
+	if t.accessList != nil {
+		frame.accessed = len(t.accessList.journal)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *gasProfilerTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() || len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	if reverted && t.accessList != nil {
+		t.accessList.revert(frame.accessed)
+	}
+
+	// The call stipend is not charged to the caller, so the subcalls may use
+	// more gas than the frame itself.
+	var self uint64
+	if gasUsed > frame.childGas {
+		self = gasUsed - frame.childGas
+	}
+	t.folded[frame.path] += self
+
+	fn := t.functions[frame.key]
+	if fn == nil {
+		fn = &gasProfileFunction{Address: frame.key.address, Selector: frame.key.selector}
+		t.functions[frame.key] = fn
+	}
+	fn.Calls++
+	fn.GasUsed += gasUsed
+	fn.SelfGas += self
+	fn.ColdAccessGas += frame.coldGas
+
+	if len(t.stack) > 0 {
+		t.stack[len(t.stack)-1].childGas += gasUsed
+	}
+}
+
+// OnOpcode picks up the cold access surcharges of state accesses, which are
+// charged as part of the dynamic opcode gas instead of a separate gas change.
+func (t *gasProfilerTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() || err != nil || len(t.stack) == 0 || !t.rules.IsBerlin || t.accessList == nil {
+		return
+	}
+	t.stack[len(t.stack)-1].coldGas += t.coldAccessSurcharge(vm.OpCode(op), scope)
+}
+
+// coldAccessSurcharge returns the EIP-2929 cold access surcharge of a state
+// accessing opcode and marks the accessed account or slot as warm.
+func (t *gasProfilerTracer) coldAccessSurcharge(op vm.OpCode, scope tracing.OpContext) uint64 {
+	stack := scope.StackData()
+	switch op {
+	case vm.SLOAD:
+		if len(stack) > 0 && t.accessList.addSlot(scope.Address(), internal.StackBack(stack, 0).Bytes32()) {
+			return params.ColdSloadCostEIP2929 - params.WarmStorageReadCostEIP2929
+		}
+	case vm.SSTORE:
+		if len(stack) > 0 && t.accessList.addSlot(scope.Address(), internal.StackBack(stack, 0).Bytes32()) {
+			return params.ColdSloadCostEIP2929
+		}
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODEHASH, vm.EXTCODECOPY:
+		if len(stack) > 0 && t.accessList.addAddress(internal.StackBack(stack, 0).Bytes20()) {
+			return params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+		}
+	case vm.SELFDESTRUCT:
+		// There is no warm access cost for the beneficiary.
+		if len(stack) > 0 && t.accessList.addAddress(internal.StackBack(stack, 0).Bytes20()) {
+			return params.ColdAccountAccessCostEIP2929
+		}
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// The surcharge is reported as a separate gas change, but the callee
+		// is warmed even if the call doesn't enter it.
+		if len(stack) > 1 {
+			t.accessList.addAddress(internal.StackBack(stack, 1).Bytes20())
+		}
+	}
+	return 0
+}
+
+// OnGasChange is called when gas is either consumed or refunded.
+func (t *gasProfilerTracer) OnGasChange(old, new uint64, reason tracing.GasChangeReason) {
+	if t.interrupt.Load() {
+		return
+	}
+	switch reason {
+	case tracing.GasChangeTxIntrinsicGas:
+		if old > new {
+			t.intrinsicGas += old - new
+			t.folded[gasProfileIntrinsicFrame] += old - new
+		}
+	case tracing.GasChangeTxDataFloor:
+		if old > new {
+			t.folded[gasProfileDataFloorFrame] += old - new
+		}
+	case tracing.GasChangeTxRefunds:
+		if new > old {
+			t.refund += new - old
+		}
+	case tracing.GasChangeCallStorageColdAccess:
+		// Cold accesses of call targets are charged separately.
+		if len(t.stack) > 0 && old > new {
+			t.stack[len(t.stack)-1].coldGas += old - new
+		}
+	}
+}
+
+// GetResult returns the gas profile either as a JSON summary, or as a string
+// of folded stacks, and any error arising from the encoding or forceful
+// termination (via `Stop`).
+func (t *gasProfilerTracer) GetResult() (json.RawMessage, error) {
+	var (
+		res []byte
+		err error
+	)
+	if t.config.Format == "folded" {
+		var folded string
+		if lines := t.foldedLines(); len(lines) > 0 {
+			folded = strings.Join(lines, "\n") + "\n"
+		}
+		res, err = json.Marshal(folded)
+	} else {
+		functions := make([]*gasProfileFunction, 0, len(t.functions))
+		for _, fn := range t.functions {
+			functions = append(functions, fn)
+		}
+		slices.SortFunc(functions, func(a, b *gasProfileFunction) int {
+			if c := cmp.Compare(b.SelfGas, a.SelfGas); c != 0 {
+				return c
+			}
+			if c := a.Address.Cmp(b.Address); c != 0 {
+				return c
+			}
+			return strings.Compare(a.Selector, b.Selector)
+		})
+		res, err = json.Marshal(struct {
+			GasUsed      uint64                `json:"gasUsed"`
+			IntrinsicGas uint64                `json:"intrinsicGas"`
+			Refund       uint64                `json:"refund"`
+			Functions    []*gasProfileFunction `json:"functions"`
+			Folded       []string              `json:"folded"`
+		}{t.gasUsed, t.intrinsicGas, t.refund, functions, t.foldedLines()})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// foldedLines returns the profile in the folded stack format, one stack per
+// line followed by the gas spent in its innermost frame.
+func (t *gasProfilerTracer) foldedLines() []string {
+	lines := make([]string, 0, len(t.folded))
+	for path, gas := range t.folded {
+		if gas > 0 {
+			lines = append(lines, fmt.Sprintf("%s %d", path, gas))
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *gasProfilerTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// String returns the name of the function in folded stacks.
+func (k gasProfileKey) String() string {
+	if k.selector == "" {
+		return k.address.Hex()
+	}
+	return k.address.Hex() + ":" + k.selector
+}
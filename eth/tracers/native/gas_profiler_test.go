@@ -0,0 +1,175 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type gasProfile struct {
+	GasUsed   uint64 `json:"gasUsed"`
+	Functions []struct {
+		Address       common.Address `json:"address"`
+		Selector      string         `json:"selector"`
+		Calls         uint64         `json:"calls"`
+		GasUsed       uint64         `json:"gasUsed"`
+		SelfGas       uint64         `json:"selfGas"`
+		ColdAccessGas uint64         `json:"coldAccessGas"`
+	} `json:"functions"`
+	Folded []string `json:"folded"`
+}
+
+// runGasProfiler executes a call from a contract into another one, which
+// writes a cold storage slot, and returns the gas profile of the given format.
+func runGasProfiler(t *testing.T, format string) json.RawMessage {
+	t.Helper()
+
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	// mstore(0, shl(0xe0, 0x12345678)); call(gas(), 0xbb, 0, 0, 4, 0, 0)
+	statedb.SetCode(caller, common.FromHex("6312345678"+"60e01b600052"+"60006000600460006000"+"60bb5af15000"), tracing.CodeChangeUnspecified)
+	// sstore(0, 1)
+	statedb.SetCode(callee, common.FromHex("600160005500"), tracing.CodeChangeUnspecified)
+
+	cfg, _ := json.Marshal(map[string]string{"format": format})
+	tracer, err := tracers.DefaultDirectory.New("gasProfilerTracer", &tracers.Context{}, cfg, params.MergedTestChainConfig)
+	require.NoError(t, err)
+
+	_, _, err = runtime.Call(caller, common.FromHex("0xdeadbeef"), &runtime.Config{
+		ChainConfig: params.MergedTestChainConfig,
+		State:       statedb,
+		GasLimit:    1000000,
+		EVMConfig:   vm.Config{Tracer: tracer.Hooks},
+	})
+	require.NoError(t, err)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	return res
+}
+
+func TestGasProfilerTracer(t *testing.T) {
+	var profile gasProfile
+	require.NoError(t, json.Unmarshal(runGasProfiler(t, ""), &profile))
+	require.Len(t, profile.Functions, 2)
+
+	// The callee is the most expensive function, it writes a cold slot.
+	callee := profile.Functions[0]
+	require.Equal(t, common.HexToAddress("0xbb"), callee.Address)
+	require.Equal(t, "0x12345678", callee.Selector)
+	require.Equal(t, uint64(1), callee.Calls)
+	require.Equal(t, params.SstoreSetGasEIP2200+params.ColdSloadCostEIP2929+6, callee.GasUsed)
+	require.Equal(t, callee.GasUsed, callee.SelfGas)
+	require.Equal(t, params.ColdSloadCostEIP2929, callee.ColdAccessGas)
+
+	// The caller includes the callee, but it's not part of its own gas.
+	caller := profile.Functions[1]
+	require.Equal(t, common.HexToAddress("0xaa"), caller.Address)
+	require.Equal(t, "0xdeadbeef", caller.Selector)
+	require.Equal(t, profile.GasUsed, caller.GasUsed)
+	require.Equal(t, caller.GasUsed-callee.GasUsed, caller.SelfGas)
+	require.Equal(t, params.ColdAccountAccessCostEIP2929-params.WarmStorageReadCostEIP2929, caller.ColdAccessGas)
+
+	// The folded stacks must add up to the total gas.
+	var total uint64
+	for _, line := range profile.Folded {
+		idx := strings.LastIndexByte(line, ' ')
+		gas, err := strconv.ParseUint(line[idx+1:], 10, 64)
+		require.NoError(t, err)
+		total += gas
+	}
+	require.Equal(t, profile.GasUsed, total)
+}
+
+func TestGasProfilerTracerFolded(t *testing.T) {
+	var folded string
+	require.NoError(t, json.Unmarshal(runGasProfiler(t, "folded"), &folded))
+
+	var (
+		caller = common.HexToAddress("0xaa").Hex() + ":0xdeadbeef"
+		callee = common.HexToAddress("0xbb").Hex() + ":0x12345678"
+		want   = caller + " "
+	)
+	lines := strings.Split(strings.TrimSuffix(folded, "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[0], want), "unexpected caller stack %q", lines[0])
+	require.Equal(t, caller+";"+callee+" 22106", lines[1])
+}
+
+func TestGasProfilerTracerInvalidFormat(t *testing.T) {
+	_, err := tracers.DefaultDirectory.New("gasProfilerTracer", &tracers.Context{}, json.RawMessage(`{"format":"pprof"}`), params.MainnetChainConfig)
+	require.Error(t, err)
+}
+
+// Tests that the cold access surcharges follow the access list, including the
+// accesses of reverted calls being undone.
+func TestGasProfilerTracerColdAccess(t *testing.T) {
+	var (
+		caller = common.HexToAddress("0xaa")
+		callee = common.HexToAddress("0xbb")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.SetCode(caller, common.FromHex(
+		"600060006000"+"60cc3c"+ // extcodecopy(0xcc, 0, 0, 0), cold
+			"600060006000"+"60cc3c"+ // extcodecopy(0xcc, 0, 0, 0), warm
+			"6000600060006000600060bb5af150"+ // call(gas(), 0xbb, 0, 0, 0, 0, 0), cold
+			"60ee3b50"+ // extcodesize(0xee), cold again after the revert
+			"60ddff"), // selfdestruct(0xdd), cold
+		tracing.CodeChangeUnspecified)
+	// extcodesize(0xee); revert(0, 0)
+	statedb.SetCode(callee, common.FromHex("60ee3b50"+"60006000fd"), tracing.CodeChangeUnspecified)
+
+	tracer, err := tracers.DefaultDirectory.New("gasProfilerTracer", &tracers.Context{}, nil, params.MergedTestChainConfig)
+	require.NoError(t, err)
+
+	_, _, err = runtime.Call(caller, nil, &runtime.Config{
+		ChainConfig: params.MergedTestChainConfig,
+		State:       statedb,
+		GasLimit:    1000000,
+		EVMConfig:   vm.Config{Tracer: tracer.Hooks},
+	})
+	require.NoError(t, err)
+
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	var profile gasProfile
+	require.NoError(t, json.Unmarshal(res, &profile))
+
+	cold := make(map[common.Address]uint64)
+	for _, fn := range profile.Functions {
+		cold[fn.Address] = fn.ColdAccessGas
+	}
+	account := params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+	require.Equal(t, 3*account+params.ColdAccountAccessCostEIP2929, cold[caller])
+	require.Equal(t, account, cold[callee])
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package engineclient provides an RPC client for the engine API, which is used by
+// consensus clients to drive an execution client.
+//
+// The engine API is only served on the authenticated endpoint of a node. To access it,
+// dial the endpoint with the shared JWT secret:
+//
+//	c, err := rpc.DialOptions(ctx, url, rpc.WithHTTPAuth(node.NewJWTAuth(secret)))
+package engineclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is a wrapper around rpc.Client that implements the engine API.
+type Client struct {
+	c *rpc.Client
+}
+
+// New creates a client that uses the given RPC client.
+func New(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+// ExchangeCapabilities sends the engine API methods supported by the caller to the
+// node and returns the methods supported by the node.
+func (ec *Client) ExchangeCapabilities(ctx context.Context, methods []string) ([]string, error) {
+	var result []string
+	err := ec.c.CallContext(ctx, &result, "engine_exchangeCapabilities", methods)
+	return result, err
+}
+
+// GetClientVersionV1 sends the version of the caller to the node and returns the
+// versions of the node.
+func (ec *Client) GetClientVersionV1(ctx context.Context, info engine.ClientVersionV1) ([]engine.ClientVersionV1, error) {
+	var result []engine.ClientVersionV1
+	err := ec.c.CallContext(ctx, &result, "engine_getClientVersionV1", info)
+	return result, err
+}
+
+// ForkchoiceUpdatedV1 updates the fork choice of the node and, if attributes are
+// given, starts building a paris payload on top of the new head.
+func (ec *Client) ForkchoiceUpdatedV1(ctx context.Context, update engine.ForkchoiceStateV1, attributes *engine.PayloadAttributes) (*engine.ForkChoiceResponse, error) {
+	return ec.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV1", update, attributes)
+}
+
+// ForkchoiceUpdatedV2 updates the fork choice of the node and, if attributes are
+// given, starts building a paris or shanghai payload on top of the new head.
+func (ec *Client) ForkchoiceUpdatedV2(ctx context.Context, update engine.ForkchoiceStateV1, attributes *engine.PayloadAttributes) (*engine.ForkChoiceResponse, error) {
+	return ec.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV2", update, attributes)
+}
+
+// ForkchoiceUpdatedV3 updates the fork choice of the node and, if attributes are
+// given, starts building a cancun or later payload on top of the new head.
+func (ec *Client) ForkchoiceUpdatedV3(ctx context.Context, update engine.ForkchoiceStateV1, attributes *engine.PayloadAttributes) (*engine.ForkChoiceResponse, error) {
+	return ec.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV3", update, attributes)
+}
+
+func (ec *Client) forkchoiceUpdated(ctx context.Context, method string, update engine.ForkchoiceStateV1, attributes *engine.PayloadAttributes) (*engine.ForkChoiceResponse, error) {
+	var result engine.ForkChoiceResponse
+	if err := ec.c.CallContext(ctx, &result, method, update, attributes); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// NewPayloadV1 sends a paris payload to the node for validation and insertion.
+func (ec *Client) NewPayloadV1(ctx context.Context, payload *engine.ExecutableData) (*engine.PayloadStatusV1, error) {
+	return ec.newPayload(ctx, "engine_newPayloadV1", payload)
+}
+
+// NewPayloadV2 sends a paris or shanghai payload to the node for validation and
+// insertion.
+func (ec *Client) NewPayloadV2(ctx context.Context, payload *engine.ExecutableData) (*engine.PayloadStatusV1, error) {
+	return ec.newPayload(ctx, "engine_newPayloadV2", payload)
+}
+
+// NewPayloadV3 sends a cancun payload to the node for validation and insertion,
+// along with the versioned hashes of its blobs and the parent beacon block root.
+func (ec *Client) NewPayloadV3(ctx context.Context, payload *engine.ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (*engine.PayloadStatusV1, error) {
+	return ec.newPayload(ctx, "engine_newPayloadV3", payload, versionedHashes, beaconRoot)
+}
+
+// NewPayloadV4 sends a prague or later payload to the node for validation and
+// insertion, along with the versioned hashes of its blobs, the parent beacon block
+// root and the execution layer requests.
+func (ec *Client) NewPayloadV4(ctx context.Context, payload *engine.ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash, requests []hexutil.Bytes) (*engine.PayloadStatusV1, error) {
+	return ec.newPayload(ctx, "engine_newPayloadV4", payload, versionedHashes, beaconRoot, requests)
+}
+
+func (ec *Client) newPayload(ctx context.Context, method string, args ...any) (*engine.PayloadStatusV1, error) {
+	var result engine.PayloadStatusV1
+	if err := ec.c.CallContext(ctx, &result, method, args...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPayloadV1 returns the paris payload being built by the node under the given id.
+func (ec *Client) GetPayloadV1(ctx context.Context, id engine.PayloadID) (*engine.ExecutableData, error) {
+	var result engine.ExecutableData
+	if err := ec.c.CallContext(ctx, &result, "engine_getPayloadV1", id); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPayloadV2 returns the paris or shanghai payload being built by the node under
+// the given id, along with its block value.
+func (ec *Client) GetPayloadV2(ctx context.Context, id engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	return ec.getPayload(ctx, "engine_getPayloadV2", id)
+}
+
+// GetPayloadV3 returns the cancun payload being built by the node under the given
+// id, along with its block value and blobs.
+func (ec *Client) GetPayloadV3(ctx context.Context, id engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	return ec.getPayload(ctx, "engine_getPayloadV3", id)
+}
+
+// GetPayloadV4 returns the prague payload being built by the node under the given
+// id, along with its block value, blobs and execution layer requests.
+func (ec *Client) GetPayloadV4(ctx context.Context, id engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	return ec.getPayload(ctx, "engine_getPayloadV4", id)
+}
+
+// GetPayloadV5 returns the osaka payload being built by the node under the given
+// id, along with its block value, blobs with cell proofs and execution layer requests.
+func (ec *Client) GetPayloadV5(ctx context.Context, id engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	return ec.getPayload(ctx, "engine_getPayloadV5", id)
+}
+
+func (ec *Client) getPayload(ctx context.Context, method string, id engine.PayloadID) (*engine.ExecutionPayloadEnvelope, error) {
+	var result engine.ExecutionPayloadEnvelope
+	if err := ec.c.CallContext(ctx, &result, method, id); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlobsV1 returns the blobs and proofs of the given versioned hashes from the
+// transaction pool of the node. Unknown blobs are returned as nil.
+func (ec *Client) GetBlobsV1(ctx context.Context, hashes []common.Hash) ([]*engine.BlobAndProofV1, error) {
+	var result []*engine.BlobAndProofV1
+	err := ec.c.CallContext(ctx, &result, "engine_getBlobsV1", hashes)
+	return result, err
+}
+
+// GetBlobsV2 returns the blobs and cell proofs of the given versioned hashes from
+// the transaction pool of the node. The result is nil unless all blobs are known.
+func (ec *Client) GetBlobsV2(ctx context.Context, hashes []common.Hash) ([]*engine.BlobAndProofV2, error) {
+	var result []*engine.BlobAndProofV2
+	err := ec.c.CallContext(ctx, &result, "engine_getBlobsV2", hashes)
+	return result, err
+}
+
+// GetBlobsV3 returns the blobs and cell proofs of the given versioned hashes from
+// the transaction pool of the node. Unknown blobs are returned as nil.
+func (ec *Client) GetBlobsV3(ctx context.Context, hashes []common.Hash) ([]*engine.BlobAndProofV2, error) {
+	var result []*engine.BlobAndProofV2
+	err := ec.c.CallContext(ctx, &result, "engine_getBlobsV3", hashes)
+	return result, err
+}
+
+// GetPayloadBodiesByHashV1 returns the bodies of the blocks with the given hashes.
+// Unknown blocks are returned as nil.
+func (ec *Client) GetPayloadBodiesByHashV1(ctx context.Context, hashes []common.Hash) ([]*engine.ExecutionPayloadBody, error) {
+	var result []*engine.ExecutionPayloadBody
+	err := ec.c.CallContext(ctx, &result, "engine_getPayloadBodiesByHashV1", hashes)
+	return result, err
+}
+
+// GetPayloadBodiesByHashV2 returns the bodies of the blocks with the given hashes,
+// including their execution layer requests. Unknown blocks are returned as nil.
+func (ec *Client) GetPayloadBodiesByHashV2(ctx context.Context, hashes []common.Hash) ([]*engine.ExecutionPayloadBody, error) {
+	var result []*engine.ExecutionPayloadBody
+	err := ec.c.CallContext(ctx, &result, "engine_getPayloadBodiesByHashV2", hashes)
+	return result, err
+}
+
+// GetPayloadBodiesByRangeV1 returns the bodies of count canonical blocks, starting
+// at the given number.
+func (ec *Client) GetPayloadBodiesByRangeV1(ctx context.Context, start, count uint64) ([]*engine.ExecutionPayloadBody, error) {
+	var result []*engine.ExecutionPayloadBody
+	err := ec.c.CallContext(ctx, &result, "engine_getPayloadBodiesByRangeV1", hexutil.Uint64(start), hexutil.Uint64(count))
+	return result, err
+}
+
+// GetPayloadBodiesByRangeV2 returns the bodies of count canonical blocks, starting
+// at the given number, including their execution layer requests.
+func (ec *Client) GetPayloadBodiesByRangeV2(ctx context.Context, start, count uint64) ([]*engine.ExecutionPayloadBody, error) {
+	var result []*engine.ExecutionPayloadBody
+	err := ec.c.CallContext(ctx, &result, "engine_getPayloadBodiesByRangeV2", hexutil.Uint64(start), hexutil.Uint64(count))
+	return result, err
+}
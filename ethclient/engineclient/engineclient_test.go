@@ -0,0 +1,152 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package engineclient
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr    = crypto.PubkeyToAddress(testKey.PublicKey)
+	testBalance = big.NewInt(2e15)
+)
+
+// newTestBackend starts a node on a chain merged and upgraded to shanghai at genesis,
+// serving the engine API.
+func newTestBackend(t *testing.T) (*node.Node, *types.Block) {
+	config := *params.AllEthashProtocolChanges
+	config.TerminalTotalDifficulty = common.Big0
+	config.MergeNetsplitBlock = common.Big0
+	config.ShanghaiTime = new(uint64)
+
+	genesis := &core.Genesis{
+		Config:     &config,
+		Alloc:      types.GenesisAlloc{testAddr: {Balance: testBalance}},
+		ExtraData:  []byte("test genesis"),
+		Timestamp:  9000,
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+		Difficulty: common.Big0,
+	}
+	n, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("can't create new node: %v", err)
+	}
+	ethservice, err := eth.New(n, &ethconfig.Config{Genesis: genesis})
+	if err != nil {
+		t.Fatalf("can't create new ethereum service: %v", err)
+	}
+	if err := catalyst.Register(n, ethservice); err != nil {
+		t.Fatalf("can't register engine API: %v", err)
+	}
+	if err := n.Start(); err != nil {
+		t.Fatalf("can't start test node: %v", err)
+	}
+	ethservice.SetSynced()
+	return n, ethservice.BlockChain().Genesis()
+}
+
+func TestEngineClient(t *testing.T) {
+	backend, genesis := newTestBackend(t)
+	client := backend.Attach()
+	defer backend.Close()
+	defer client.Close()
+
+	var (
+		ec  = New(client)
+		ctx = context.Background()
+	)
+	caps, err := ec.ExchangeCapabilities(ctx, []string{"engine_newPayloadV2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(caps, "engine_forkchoiceUpdatedV2") || !slices.Contains(caps, "engine_getPayloadV2") {
+		t.Fatalf("missing capabilities: %v", caps)
+	}
+	versions, err := ec.GetClientVersionV1(ctx, engine.ClientVersionV1{Code: "TT", Name: "test", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Code != engine.ClientCode {
+		t.Fatalf("unexpected client versions: %v", versions)
+	}
+
+	// Build a block on top of genesis, insert it and make it the head.
+	fcState := engine.ForkchoiceStateV1{HeadBlockHash: genesis.Hash()}
+	resp, err := ec.ForkchoiceUpdatedV2(ctx, fcState, &engine.PayloadAttributes{
+		Timestamp:             genesis.Time() + 5,
+		SuggestedFeeRecipient: common.Address{1},
+		Withdrawals:           []*types.Withdrawal{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.PayloadStatus.Status != engine.VALID || resp.PayloadID == nil {
+		t.Fatalf("payload building not started: %v", resp.PayloadStatus)
+	}
+	envelope, err := ec.GetPayloadV2(ctx, *resp.PayloadID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := envelope.ExecutionPayload
+	if payload.ParentHash != genesis.Hash() || payload.FeeRecipient != (common.Address{1}) {
+		t.Fatalf("unexpected payload: parent %x, fee recipient %x", payload.ParentHash, payload.FeeRecipient)
+	}
+	status, err := ec.NewPayloadV2(ctx, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != engine.VALID {
+		t.Fatalf("payload not valid: %v", status.Status)
+	}
+	fcState.HeadBlockHash = payload.BlockHash
+	if resp, err = ec.ForkchoiceUpdatedV2(ctx, fcState, nil); err != nil {
+		t.Fatal(err)
+	}
+	if resp.PayloadStatus.Status != engine.VALID || resp.PayloadID != nil {
+		t.Fatalf("head not updated: %v", resp.PayloadStatus)
+	}
+
+	// Retrieve the body of the new block.
+	bodies, err := ec.GetPayloadBodiesByHashV1(ctx, []common.Hash{payload.BlockHash, {1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || bodies[0] == nil || bodies[1] != nil {
+		t.Fatalf("unexpected bodies by hash: %v", bodies)
+	}
+	if bodies, err = ec.GetPayloadBodiesByRangeV1(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 1 || bodies[0] == nil || len(bodies[0].Withdrawals) != 0 {
+		t.Fatalf("unexpected bodies by range: %v", bodies)
+	}
+}
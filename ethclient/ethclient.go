@@ -481,6 +481,9 @@ func (ec *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]typ
 }
 
 // SubscribeFilterLogs subscribes to the results of a streaming filter query.
+//
+// If the query has a FromBlock, the matching logs since that block are delivered
+// before the live ones.
 func (ec *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
 	arg, err := toFilterArg(q)
 	if err != nil {
@@ -496,6 +499,27 @@ func (ec *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuer
 	return sub, nil
 }
 
+// SubscribeFilterLogsResumable subscribes to the results of a streaming filter
+// query like SubscribeFilterLogs, but re-establishes the subscription after
+// connection failures, resuming at the last delivered log. Logs of blocks that
+// were reorged while disconnected are delivered again with the Removed flag set,
+// as long as the reorg is within 128 blocks of the last delivered log.
+//
+// The subscription only ends when it is unsubscribed, the client is closed, or
+// the server returns an error, which is reported on the error channel. Queries
+// with a BlockHash or ToBlock can't be resumed.
+func (ec *Client) SubscribeFilterLogsResumable(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if q.BlockHash != nil || q.ToBlock != nil {
+		return nil, errors.New("can't resume log subscriptions with a block hash or end block")
+	}
+	s := &logSubscription{ec: ec, query: q, ch: ch}
+	sub, logs, err := s.subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(sub, logs), nil
+}
+
 func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
 	arg := map[string]interface{}{}
 	if q.Addresses != nil {
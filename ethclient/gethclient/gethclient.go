@@ -19,6 +19,7 @@ package gethclient
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"runtime"
@@ -27,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -81,6 +83,16 @@ type StorageResult struct {
 // GetProof returns the account and storage values of the specified account including the Merkle-proof.
 // The block number can be nil, in which case the value is taken from the latest known block.
 func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []string, blockNumber *big.Int) (*AccountResult, error) {
+	return ec.getProof(ctx, account, keys, toBlockNumArg(blockNumber))
+}
+
+// GetProofAtHash returns the account and storage values of the specified account including
+// the Merkle-proof, taken from the state of the block with the given hash.
+func (ec *Client) GetProofAtHash(ctx context.Context, account common.Address, keys []string, blockHash common.Hash) (*AccountResult, error) {
+	return ec.getProof(ctx, account, keys, rpc.BlockNumberOrHashWithHash(blockHash, false))
+}
+
+func (ec *Client) getProof(ctx context.Context, account common.Address, keys []string, block any) (*AccountResult, error) {
 	type storageResult struct {
 		Key   string       `json:"key"`
 		Value *hexutil.Big `json:"value"`
@@ -103,7 +115,7 @@ func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []s
 	}
 
 	var res accountResult
-	err := ec.c.CallContext(ctx, &res, "eth_getProof", account, keys, toBlockNumArg(blockNumber))
+	err := ec.c.CallContext(ctx, &res, "eth_getProof", account, keys, block)
 	if err != nil {
 		return nil, err
 	}
@@ -229,6 +241,150 @@ func (ec *Client) TraceBlock(ctx context.Context, hash common.Hash, config *trac
 	return result, nil
 }
 
+// TraceCall lets you trace a given eth_call. It collects the structured logs created
+// during the execution of EVM if the given transaction was added on top of the provided
+// block and returns them as a JSON object.
+func (ec *Client) TraceCall(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, config *tracers.TraceCallConfig) (any, error) {
+	var result any
+	err := ec.c.CallContext(ctx, &result, "debug_traceCall", toCallArg(msg), toBlockNumArg(blockNumber), config)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExecutionWitness returns the witness required to statelessly execute the block with
+// the given number. The block number can be nil, in which case the latest block is used.
+func (ec *Client) ExecutionWitness(ctx context.Context, blockNumber *big.Int) (*stateless.ExtWitness, error) {
+	var result stateless.ExtWitness
+	if err := ec.c.CallContext(ctx, &result, "debug_executionWitness", toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExecutionWitnessByHash returns the witness required to statelessly execute the block
+// with the given hash.
+func (ec *Client) ExecutionWitnessByHash(ctx context.Context, hash common.Hash) (*stateless.ExtWitness, error) {
+	var result stateless.ExtWitness
+	if err := ec.c.CallContext(ctx, &result, "debug_executionWitnessByHash", hash); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Peers retrieves information about the peers connected to a geth node.
+func (ec *Client) Peers(ctx context.Context) ([]*p2p.PeerInfo, error) {
+	var result []*p2p.PeerInfo
+	err := ec.c.CallContext(ctx, &result, "admin_peers")
+	return result, err
+}
+
+// AddPeer requests a geth node to connect to the peer with the given enode URL and
+// to maintain the connection at all times, reconnecting if it is lost.
+func (ec *Client) AddPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_addPeer", url)
+}
+
+// RemovePeer requests a geth node to disconnect from the peer with the given enode
+// URL, if the connection exists.
+func (ec *Client) RemovePeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_removePeer", url)
+}
+
+// AddTrustedPeer allows the peer with the given enode URL to always connect to a geth
+// node, even if its peer slots are full.
+func (ec *Client) AddTrustedPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_addTrustedPeer", url)
+}
+
+// RemoveTrustedPeer removes the peer with the given enode URL from the trusted peer
+// set of a geth node, but does not disconnect it.
+func (ec *Client) RemoveTrustedPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_removeTrustedPeer", url)
+}
+
+// PoolTransaction is a transaction contained within the transaction pool.
+type PoolTransaction struct {
+	Tx   *types.Transaction
+	From common.Address // Sender of the transaction, as reported by the node
+}
+
+func (tx *PoolTransaction) UnmarshalJSON(msg []byte) error {
+	if err := json.Unmarshal(msg, &tx.Tx); err != nil {
+		return err
+	}
+	var extra struct {
+		From common.Address `json:"from"`
+	}
+	if err := json.Unmarshal(msg, &extra); err != nil {
+		return err
+	}
+	tx.From = extra.From
+	return nil
+}
+
+// TxPoolContent is the content of the transaction pool, grouped by sender and nonce.
+type TxPoolContent struct {
+	Pending map[common.Address]map[uint64]*PoolTransaction `json:"pending"`
+	Queued  map[common.Address]map[uint64]*PoolTransaction `json:"queued"`
+}
+
+// TxPoolAccountContent is the content of the transaction pool for a single sender,
+// grouped by nonce.
+type TxPoolAccountContent struct {
+	Pending map[uint64]*PoolTransaction `json:"pending"`
+	Queued  map[uint64]*PoolTransaction `json:"queued"`
+}
+
+// TxPoolInspection is a textual summary of the transaction pool content, grouped by
+// sender and nonce.
+type TxPoolInspection struct {
+	Pending map[common.Address]map[uint64]string `json:"pending"`
+	Queued  map[common.Address]map[uint64]string `json:"queued"`
+}
+
+// TxPoolStatus returns the number of pending and queued transactions in the pool.
+func (ec *Client) TxPoolStatus(ctx context.Context) (pending uint, queued uint, err error) {
+	var result struct {
+		Pending hexutil.Uint `json:"pending"`
+		Queued  hexutil.Uint `json:"queued"`
+	}
+	if err := ec.c.CallContext(ctx, &result, "txpool_status"); err != nil {
+		return 0, 0, err
+	}
+	return uint(result.Pending), uint(result.Queued), nil
+}
+
+// TxPoolContent returns the transactions contained within the transaction pool.
+func (ec *Client) TxPoolContent(ctx context.Context) (*TxPoolContent, error) {
+	var result TxPoolContent
+	if err := ec.c.CallContext(ctx, &result, "txpool_content"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TxPoolContentFrom returns the transactions contained within the transaction pool
+// that were sent by the given address.
+func (ec *Client) TxPoolContentFrom(ctx context.Context, addr common.Address) (*TxPoolAccountContent, error) {
+	var result TxPoolAccountContent
+	if err := ec.c.CallContext(ctx, &result, "txpool_contentFrom", addr); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TxPoolInspect returns a textual summary of the transactions contained within the
+// transaction pool, listing their recipient, value and gas cost.
+func (ec *Client) TxPoolInspect(ctx context.Context) (*TxPoolInspection, error) {
+	var result TxPoolInspection
+	if err := ec.c.CallContext(ctx, &result, "txpool_inspect"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -21,6 +21,8 @@ import (
 	"context"
 	"encoding/json"
 	"math/big"
+	"net"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -34,8 +36,10 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/tracers"
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -119,7 +123,7 @@ func generateTestChain() (*core.Genesis, []*types.Block, []common.Hash) {
 }
 
 func TestGethClient(t *testing.T) {
-	backend, _, txHashes := newTestBackend(t)
+	backend, blocks, txHashes := newTestBackend(t)
 	client := backend.Attach()
 	defer backend.Close()
 	defer client.Close()
@@ -137,6 +141,9 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestGetProofEmpty",
 			func(t *testing.T) { testGetProof(t, client, testEmpty) },
+		}, {
+			"TestGetProofAtHash",
+			func(t *testing.T) { testGetProofAtHash(t, client, blocks[1].Hash()) },
 		}, {
 			"TestGetProofNonExistent",
 			func(t *testing.T) { testGetProofNonExistent(t, client) },
@@ -155,6 +162,18 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestSubscribePendingTxHashes",
 			func(t *testing.T) { testSubscribePendingTransactions(t, client) },
+		}, {
+			"TestTxPool",
+			func(t *testing.T) { testTxPool(t, client) },
+		}, {
+			"TestPeers",
+			func(t *testing.T) { testPeers(t, client) },
+		}, {
+			"TestTraceCall",
+			func(t *testing.T) { testTraceCall(t, client) },
+		}, {
+			"TestExecutionWitness",
+			func(t *testing.T) { testExecutionWitness(t, client, blocks[1]) },
 		}, {
 			"TestCallContract",
 			func(t *testing.T) { testCallContract(t, client) },
@@ -620,3 +639,119 @@ func testCallContractWithBlockOverrides(t *testing.T, client *rpc.Client) {
 		t.Fatalf("unexpected result: %x", res)
 	}
 }
+
+func testGetProofAtHash(t *testing.T, client *rpc.Client, hash common.Hash) {
+	ec := New(client)
+	result, err := ec.GetProofAtHash(context.Background(), testAddr, []string{testSlot.String()}, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := ec.GetProof(context.Background(), testAddr, []string{testSlot.String()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, latest) {
+		t.Fatalf("proof at head hash differs from latest proof: have %v, want %v", result, latest)
+	}
+	if _, err := ec.GetProofAtHash(context.Background(), testAddr, nil, common.Hash{1}); err == nil {
+		t.Fatal("proof of unknown block returned")
+	}
+}
+
+func testTxPool(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	// The pending transaction test leaves a single transaction in the pool.
+	pending, queued, err := ec.TxPoolStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 1 || queued != 0 {
+		t.Fatalf("wrong pool status: pending %d, queued %d", pending, queued)
+	}
+	content, err := ec.TxPoolContent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := content.Pending[testAddr][1]
+	if tx == nil || tx.From != testAddr || tx.Tx.Nonce() != 1 {
+		t.Fatalf("pending transaction missing from pool content: %v", content.Pending)
+	}
+	from, err := ec.TxPoolContentFrom(context.Background(), testAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(from.Pending) != 1 || from.Pending[1] == nil || from.Pending[1].Tx.Hash() != tx.Tx.Hash() {
+		t.Fatalf("wrong pool content of sender: %v", from.Pending)
+	}
+	inspect, err := ec.TxPoolInspect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := inspect.Pending[testAddr][1]; !strings.HasPrefix(summary, common.Address{1}.Hex()) {
+		t.Fatalf("wrong pool summary: %q", summary)
+	}
+}
+
+func testPeers(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	peers, err := ec.Peers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 0 {
+		t.Fatalf("unexpected peers: %v", peers)
+	}
+	key, _ := crypto.GenerateKey()
+	url := enode.NewV4(&key.PublicKey, net.IP{127, 0, 0, 1}, 30303, 30303).URLv4()
+	for _, fn := range []func(context.Context, string) error{ec.AddPeer, ec.AddTrustedPeer, ec.RemoveTrustedPeer, ec.RemovePeer} {
+		if err := fn(context.Background(), url); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ec.AddPeer(context.Background(), "enode://invalid"); err == nil {
+		t.Fatal("invalid enode URL accepted")
+	}
+}
+
+func testTraceCall(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	msg := ethereum.CallMsg{
+		From:  testAddr,
+		To:    &common.Address{},
+		Gas:   21000,
+		Value: big.NewInt(1),
+	}
+	tracer := "callTracer"
+	result, err := ec.TraceCall(context.Background(), msg, nil, &tracers.TraceCallConfig{
+		TraceConfig: tracers.TraceConfig{Tracer: &tracer},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame, ok := result.(map[string]any)
+	if !ok || frame["type"] != "CALL" || frame["from"] != strings.ToLower(testAddr.Hex()) {
+		t.Fatalf("unexpected call trace: %v", result)
+	}
+}
+
+func testExecutionWitness(t *testing.T, client *rpc.Client, block *types.Block) {
+	ec := New(client)
+	byNumber, err := ec.ExecutionWitness(context.Background(), block.Number())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byHash, err := ec.ExecutionWitnessByHash(context.Background(), block.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The trie nodes are collected in a set, so their order is random.
+	if len(byNumber.Headers) != len(byHash.Headers) || len(byNumber.State) != len(byHash.State) {
+		t.Fatal("witnesses by number and hash differ")
+	}
+	if len(byHash.Headers) == 0 || byHash.Headers[0].Hash() != block.ParentHash() {
+		t.Fatalf("witness missing parent header")
+	}
+	if len(byHash.State) == 0 {
+		t.Fatal("witness missing state")
+	}
+}
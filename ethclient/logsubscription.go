@@ -0,0 +1,252 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// logResubscribeBackoff is the maximum time between two attempts to
+	// re-establish a failed log subscription.
+	logResubscribeBackoff = 30 * time.Second
+
+	// logReorgWindow is the number of blocks below the last delivered log whose
+	// delivered logs are kept to revoke them if the blocks are reorged while
+	// disconnected. It matches the reorg window of backfilled subscriptions on
+	// the server.
+	logReorgWindow = 128
+)
+
+// logBlock is a block of delivered logs.
+type logBlock struct {
+	number uint64
+	hash   common.Hash
+	logs   []types.Log
+}
+
+// logSubscription is a log subscription that resumes after connection failures
+// at the last delivered log.
+type logSubscription struct {
+	ec    *Client
+	query ethereum.FilterQuery
+	ch    chan<- types.Log
+
+	// Position of the subscription. The delivered logs of the blocks within the
+	// reorg window are kept to revoke them if the blocks are reorged while
+	// disconnected.
+	started bool
+	from    uint64      // first block of a resumed subscription
+	index   uint        // index of the last delivered log in the last block
+	blocks  []logBlock  // blocks of the delivered logs, in ascending order
+	removed []types.Log // revoked logs pending delivery
+
+	err error // error returned by the server, ending the subscription
+}
+
+// arg returns the subscription criteria, starting at the resume position when
+// resuming.
+func (s *logSubscription) arg() map[string]interface{} {
+	arg := map[string]interface{}{}
+	if s.query.Addresses != nil {
+		arg["address"] = s.query.Addresses
+	}
+	if s.query.Topics != nil {
+		arg["topics"] = s.query.Topics
+	}
+	switch {
+	case s.started:
+		arg["fromBlock"] = hexutil.EncodeUint64(s.from)
+	case s.query.FromBlock != nil:
+		arg["fromBlock"] = toBlockNumArg(s.query.FromBlock)
+	}
+	return arg
+}
+
+// subscribe establishes a subscription on the server.
+func (s *logSubscription) subscribe(ctx context.Context) (*rpc.ClientSubscription, chan types.Log, error) {
+	logs := make(chan types.Log)
+	sub, err := s.ec.c.EthSubscribe(ctx, logs, "logs", s.arg())
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, logs, nil
+}
+
+// revoke finds the last block of delivered logs that is still canonical, and
+// queues the delivered logs of the blocks above it with the removed flag set,
+// starting at the newest block. The subscription is moved back to resume after
+// the canonical block, or at the oldest known block if none of them are
+// canonical anymore.
+func (s *logSubscription) revoke(ctx context.Context) error {
+	i := len(s.blocks) - 1
+	for ; i >= 0; i-- {
+		header, err := s.ec.HeaderByNumber(ctx, new(big.Int).SetUint64(s.blocks[i].number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if header != nil && header.Hash() == s.blocks[i].hash {
+			break
+		}
+	}
+	if i == len(s.blocks)-1 {
+		return nil
+	}
+	if i >= 0 {
+		s.from = s.blocks[i].number + 1
+	} else {
+		s.from = s.blocks[0].number
+	}
+	for j := len(s.blocks) - 1; j > i; j-- {
+		for _, log := range s.blocks[j].logs {
+			log.Removed = true
+			s.removed = append(s.removed, log)
+		}
+	}
+	s.blocks = s.blocks[:i+1]
+	return nil
+}
+
+// delivered reports whether the log was delivered before the subscription was
+// resumed.
+func (s *logSubscription) delivered(log types.Log) bool {
+	if !s.started || log.Removed || len(s.blocks) == 0 {
+		return false
+	}
+	last := s.blocks[len(s.blocks)-1]
+	return log.BlockHash == last.hash && log.Index <= s.index
+}
+
+// advance moves the position of the subscription past the given log.
+func (s *logSubscription) advance(log types.Log) {
+	if log.Removed {
+		s.blocks = slices.DeleteFunc(s.blocks, func(b logBlock) bool {
+			return b.hash == log.BlockHash
+		})
+		// Resume after the remaining blocks if the last one was removed.
+		if n := len(s.blocks); n > 0 && s.blocks[n-1].number < s.from {
+			s.from = s.blocks[n-1].number + 1
+		}
+		return
+	}
+	if n := len(s.blocks); n == 0 || s.blocks[n-1].hash != log.BlockHash {
+		s.blocks = append(s.blocks, logBlock{number: log.BlockNumber, hash: log.BlockHash})
+		for len(s.blocks) > 0 && s.blocks[0].number+logReorgWindow <= log.BlockNumber {
+			s.blocks = s.blocks[1:]
+		}
+	}
+	last := &s.blocks[len(s.blocks)-1]
+	last.logs = append(last.logs, log)
+	s.started, s.from, s.index = true, log.BlockNumber, log.Index
+}
+
+// fail records errors returned by the server, which end the subscription
+// instead of re-establishing it.
+func (s *logSubscription) fail(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		s.err = err
+		return true
+	}
+	return false
+}
+
+// resubscribeFunc returns the function establishing the subscription for
+// event.ResubscribeErr, starting with the already established one.
+func (s *logSubscription) resubscribeFunc(sub *rpc.ClientSubscription, logs chan types.Log) event.ResubscribeErrFunc {
+	ended := func() event.Subscription {
+		return event.NewSubscription(func(<-chan struct{}) error { return nil })
+	}
+	return func(ctx context.Context, _ error) (event.Subscription, error) {
+		if sub == nil {
+			err := s.revoke(ctx)
+			if err == nil {
+				sub, logs, err = s.subscribe(ctx)
+			}
+			if errors.Is(err, rpc.ErrClientQuit) || s.fail(err) {
+				// The client was closed or the server rejected the
+				// subscription, end it.
+				return ended(), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		current, currentLogs := sub, logs
+		sub, logs = nil, nil
+
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			defer current.Unsubscribe()
+			for len(s.removed) > 0 {
+				select {
+				case s.ch <- s.removed[0]:
+					s.removed = s.removed[1:]
+				case <-quit:
+					return nil
+				}
+			}
+			for {
+				select {
+				case log := <-currentLogs:
+					if s.delivered(log) {
+						continue
+					}
+					select {
+					case s.ch <- log:
+						s.advance(log)
+					case <-quit:
+						return nil
+					}
+				case err := <-current.Err():
+					if s.fail(err) {
+						return nil
+					}
+					return err
+				case <-quit:
+					return nil
+				}
+			}
+		}), nil
+	}
+}
+
+// run keeps the subscription established until it is unsubscribed, or ended by
+// the client or the server. Errors returned by the server are reported.
+func (s *logSubscription) run(sub *rpc.ClientSubscription, logs chan types.Log) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		resub := event.ResubscribeErr(logResubscribeBackoff, s.resubscribeFunc(sub, logs))
+		defer resub.Unsubscribe()
+
+		select {
+		case <-resub.Err():
+			return s.err
+		case <-quit:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// logService is a fake log subscription service serving a mutable chain.
+type logService struct {
+	mu      sync.Mutex
+	headers map[uint64]*types.Header
+	logs    []types.Log
+	from    chan string // fromBlock of each subscription
+	reject  error       // error returned for new subscriptions
+}
+
+func (s *logService) setBlock(number uint64, extra string, logs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: common.Big0, Extra: []byte(extra)}
+	s.headers[number] = header
+
+	kept := s.logs[:0]
+	for _, log := range s.logs {
+		if log.BlockNumber != number {
+			kept = append(kept, log)
+		}
+	}
+	s.logs = kept
+	for i := 0; i < logs; i++ {
+		s.logs = append(s.logs, types.Log{BlockNumber: number, BlockHash: header.Hash(), Index: uint(i), Topics: []common.Hash{}})
+	}
+}
+
+func (s *logService) Logs(ctx context.Context, crit map[string]any) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	from, _ := crit["fromBlock"].(string)
+	s.from <- from
+
+	s.mu.Lock()
+	reject := s.reject
+	s.mu.Unlock()
+	if reject != nil {
+		return nil, reject
+	}
+
+	var start rpc.BlockNumber
+	if from != "" {
+		if err := start.UnmarshalJSON([]byte(`"` + from + `"`)); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	var logs []types.Log
+	for _, log := range s.logs {
+		if log.BlockNumber >= uint64(start) {
+			logs = append(logs, log)
+		}
+	}
+	s.mu.Unlock()
+
+	sub := notifier.CreateSubscription()
+	for _, log := range logs {
+		notifier.Notify(sub.ID, log)
+	}
+	return sub, nil
+}
+
+func (s *logService) GetBlockByNumber(number rpc.BlockNumber, full bool) *types.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers[uint64(number)]
+}
+
+// trackingListener records the accepted connections, so they can be dropped.
+type trackingListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.mu.Lock()
+		l.conns = append(l.conns, conn)
+		l.mu.Unlock()
+	}
+	return conn, err
+}
+
+func (l *trackingListener) drop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+	l.conns = nil
+}
+
+// newLogServer starts a websocket server for the log service, returning a client
+// connected to it and the listener tracking its connections.
+func newLogServer(t *testing.T, service *logService) (*ethclient.Client, *trackingListener) {
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &trackingListener{Listener: listener}
+	httpsrv := &http.Server{Handler: server.WebsocketHandler([]string{"*"})}
+	go httpsrv.Serve(tracker)
+	t.Cleanup(func() { httpsrv.Close() })
+
+	client, err := ethclient.Dial("ws://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client, tracker
+}
+
+func TestSubscribeFilterLogsResume(t *testing.T) {
+	t.Parallel()
+
+	service := &logService{headers: make(map[uint64]*types.Header), from: make(chan string, 10)}
+	service.setBlock(1, "", 1)
+	service.setBlock(2, "", 2)
+	client, tracker := newLogServer(t, service)
+
+	logs := make(chan types.Log)
+	sub, err := client.SubscribeFilterLogsResumable(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(1)}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	expect := func(from string, want ...[3]uint64) {
+		t.Helper()
+		select {
+		case have := <-service.from:
+			if have != from {
+				t.Fatalf("wrong fromBlock: have %q, want %q", have, from)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for subscription")
+		}
+		for _, w := range want {
+			select {
+			case log := <-logs:
+				removed := uint64(0)
+				if log.Removed {
+					removed = 1
+				}
+				if log.BlockNumber != w[0] || uint64(log.Index) != w[1] || removed != w[2] {
+					t.Fatalf("wrong log: have block %d index %d removed %v, want %v", log.BlockNumber, log.Index, log.Removed, w)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timeout waiting for log %v", w)
+			}
+		}
+	}
+	expect("0x1", [3]uint64{1, 0, 0}, [3]uint64{2, 0, 0}, [3]uint64{2, 1, 0})
+
+	// After reconnecting, the subscription resumes at the last delivered log.
+	service.setBlock(3, "", 1)
+	tracker.drop()
+	expect("0x2", [3]uint64{3, 0, 0})
+
+	// A block reorged while disconnected is revoked before resuming.
+	service.setBlock(3, "reorg", 2)
+	tracker.drop()
+	expect("0x3", [3]uint64{3, 0, 1}, [3]uint64{3, 0, 0}, [3]uint64{3, 1, 0})
+
+	// Deeper reorgs are revoked down to the common ancestor, newest first.
+	service.setBlock(2, "deep", 1)
+	service.setBlock(3, "deep", 1)
+	tracker.drop()
+	expect("0x2",
+		[3]uint64{3, 0, 1}, [3]uint64{3, 1, 1}, [3]uint64{2, 0, 1}, [3]uint64{2, 1, 1},
+		[3]uint64{2, 0, 0}, [3]uint64{3, 0, 0},
+	)
+
+	// Errors returned by the server end the subscription.
+	service.mu.Lock()
+	service.reject = errors.New("rejected")
+	service.mu.Unlock()
+	tracker.drop()
+	expect("0x3")
+	select {
+	case err := <-sub.Err():
+		if err == nil || err.Error() != "rejected" {
+			t.Fatalf("wrong subscription error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended")
+	}
+}
+
+func TestSubscribeFilterLogsNoResume(t *testing.T) {
+	t.Parallel()
+
+	service := &logService{headers: make(map[uint64]*types.Header), from: make(chan string, 10)}
+	service.setBlock(1, "", 1)
+	client, tracker := newLogServer(t, service)
+
+	logs := make(chan types.Log, 1)
+	sub, err := client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(1)}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	<-service.from
+
+	// Plain subscriptions report connection failures instead of resuming.
+	tracker.drop()
+	select {
+	case err := <-sub.Err():
+		if err == nil {
+			t.Fatal("subscription ended without error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended")
+	}
+	select {
+	case from := <-service.from:
+		t.Fatalf("subscription resumed from %q", from)
+	default:
+	}
+}
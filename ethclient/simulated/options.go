@@ -17,10 +17,13 @@
 package simulated
 
 import (
+	"maps"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // WithBlockGasLimit configures the simulated backend to target a specific gas limit
@@ -53,3 +56,18 @@ func WithMinerMinTip(tip *big.Int) func(nodeConf *node.Config, ethConf *ethconfi
 		ethConf.Miner.GasPrice = tip
 	}
 }
+
+// WithPrecompile configures the simulated backend to activate the custom precompiled
+// contract registered with the EVM under the given name at an address from genesis.
+func WithPrecompile(addr common.Address, name string) func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+	return func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		// Copy the chain config and its precompiles, the default config is shared
+		config := *ethConf.Genesis.Config
+		config.Precompiles = maps.Clone(config.Precompiles)
+		if config.Precompiles == nil {
+			config.Precompiles = make(map[common.Address]*params.PrecompileConfig)
+		}
+		config.Precompiles[addr] = &params.PrecompileConfig{Name: name, Block: new(big.Int)}
+		ethConf.Genesis.Config = &config
+	}
+}
@@ -23,8 +23,12 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -72,3 +76,103 @@ func TestWithCallGasLimitOption(t *testing.T) {
 		t.Fatalf("error mismatch: have %v, want %v", err, core.ErrIntrinsicGas)
 	}
 }
+
+// counterPrecompile is a stateful precompile incrementing a counter in its storage.
+type counterPrecompile struct{}
+
+func (c *counterPrecompile) RequiredGas(input []byte) uint64  { return 1000 }
+func (c *counterPrecompile) Run(input []byte) ([]byte, error) { return nil, vm.ErrExecutionReverted }
+func (c *counterPrecompile) Name() string                     { return "COUNTER" }
+
+func (c *counterPrecompile) RunStateful(env *vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+	if env.ReadOnly {
+		return nil, vm.ErrWriteProtection
+	}
+	// Keep the account from being deleted as empty
+	if env.StateDB().GetNonce(env.Address) == 0 {
+		env.StateDB().SetNonce(env.Address, 1, tracing.NonceChangeUnspecified)
+	}
+	count := env.StateDB().GetState(env.Address, common.Hash{}).Big()
+	count.Add(count, common.Big1)
+	env.StateDB().SetState(env.Address, common.Hash{}, common.BigToHash(count))
+	return common.BigToHash(count).Bytes(), nil
+}
+
+func init() {
+	vm.RegisterPrecompile("simulated-counter", new(counterPrecompile))
+}
+
+// Tests that custom precompiles are honored by block processing, eth_call and
+// eth_simulateV1.
+func TestWithPrecompileOption(t *testing.T) {
+	counter := common.HexToAddress("0x0100000000000000000000000000000000000000")
+	sim := NewBackend(types.GenesisAlloc{
+		testAddr: {Balance: big.NewInt(params.Ether)},
+	}, WithPrecompile(counter, "simulated-counter"))
+	defer sim.Close()
+
+	var (
+		ctx    = context.Background()
+		client = sim.Client()
+	)
+	ret, err := client.CallContract(ctx, ethereum.CallMsg{From: testAddr, To: &counter}, nil)
+	if err != nil {
+		t.Fatalf("failed to call precompile: %v", err)
+	}
+	if have := new(big.Int).SetBytes(ret); have.Uint64() != 1 {
+		t.Fatalf("wrong call result: have %v, want 1", have)
+	}
+	// Increment the counter in a transaction.
+	head, _ := client.HeaderByNumber(ctx, nil)
+	chainid, _ := client.ChainID(ctx)
+	tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainid,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(head.BaseFee, big.NewInt(params.GWei)),
+		Gas:       100000,
+		To:        &counter,
+	}), types.LatestSignerForChainID(chainid), testKey)
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	sim.Commit()
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("transaction calling precompile failed")
+	}
+	count, err := client.StorageAt(ctx, counter, common.Hash{}, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve storage: %v", err)
+	}
+	if have := new(big.Int).SetBytes(count); have.Uint64() != 1 {
+		t.Fatalf("wrong counter after transaction: have %v, want 1", have)
+	}
+	// Simulate two more calls on top of the chain.
+	var results []struct {
+		Calls []struct {
+			ReturnData hexutil.Bytes  `json:"returnData"`
+			Status     hexutil.Uint64 `json:"status"`
+		} `json:"calls"`
+	}
+	call := map[string]any{"from": testAddr, "to": counter}
+	opts := map[string]any{
+		"blockStateCalls": []any{map[string]any{"calls": []any{call, call}}},
+	}
+	rpcClient := sim.node.Attach()
+	defer rpcClient.Close()
+	if err := rpcClient.CallContext(ctx, &results, "eth_simulateV1", opts, "latest"); err != nil {
+		t.Fatalf("failed to simulate calls: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Calls) != 2 {
+		t.Fatalf("wrong simulation results: %+v", results)
+	}
+	for i, call := range results[0].Calls {
+		if have := new(big.Int).SetBytes(call.ReturnData); call.Status != 1 || have.Uint64() != uint64(i+2) {
+			t.Errorf("simulated call %d: have status %d, result %v, want result %d", i, call.Status, have, i+2)
+		}
+	}
+}
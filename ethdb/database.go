@@ -82,6 +82,26 @@ type KeyValueSyncer interface {
 	SyncKeyValue() error
 }
 
+// Snapshot is a point-in-time, read-only view of a key-value data store. Writes
+// issued to the database after the snapshot was created are not visible through
+// it.
+type Snapshot interface {
+	KeyValueReader
+
+	// Release releases associated resources. Release should always succeed and
+	// can be called multiple times without causing error.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store. It is an
+// optional extension that not every key-value store supports.
+type Snapshotter interface {
+	// NewSnapshot creates a database snapshot based on the current state.
+	// The created snapshot will not be affected by all following mutations
+	// happened on the database.
+	NewSnapshot() (Snapshot, error)
+}
+
 // Compacter wraps the Compact method of a backing data store.
 type Compacter interface {
 	// Compact flattens the underlying data store for the given key range. In essence,
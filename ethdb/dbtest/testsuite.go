@@ -319,6 +319,58 @@ func TestDatabaseSuite(t *testing.T, New func() ethdb.KeyValueStore) {
 		}
 	})
 
+	t.Run("Snapshot", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		snapshotter, ok := db.(ethdb.Snapshotter)
+		if !ok {
+			t.Skip("database does not support snapshots")
+		}
+		initial := map[string]string{
+			"k1": "v1", "k2": "v2", "k3": "", "k4": "",
+		}
+		for k, v := range initial {
+			db.Put([]byte(k), []byte(v))
+		}
+		snapshot, err := snapshotter.NewSnapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range initial {
+			got, err := snapshot.Get([]byte(k))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, []byte(v)) {
+				t.Fatalf("Unexpected value want: %v, got %v", v, got)
+			}
+		}
+		// Flush more modifications into the database, ensure the snapshot
+		// isn't affected.
+		update := map[string]string{"k1": "v1-b", "k3": "v3-b"}
+		for k, v := range update {
+			db.Put([]byte(k), []byte(v))
+		}
+		db.Delete([]byte("k2"))
+		db.Put([]byte("k5"), []byte("v5"))
+
+		for k, v := range initial {
+			got, err := snapshot.Get([]byte(k))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, []byte(v)) {
+				t.Fatalf("Unexpected value want: %v, got %v", v, got)
+			}
+		}
+		if ok, _ := snapshot.Has([]byte("k5")); ok {
+			t.Fatal("Unexpected key in snapshot")
+		}
+		snapshot.Release()
+		snapshot.Release() // must be idempotent
+	})
+
 	t.Run("OperationsAfterClose", func(t *testing.T) {
 		db := New()
 		db.Put([]byte("key"), []byte("value"))
@@ -0,0 +1,104 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// writeOp is a single mutation sent to the remote node via debug_dbWrite.
+type writeOp struct {
+	Type  string        `json:"type"`
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value,omitempty"`
+	End   hexutil.Bytes `json:"end,omitempty"`
+}
+
+// batch is a write-only remote database that commits changes to the remote
+// node in a single atomic debug_dbWrite call when Write is invoked.
+type batch struct {
+	db   *Database
+	ops  []writeOp
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, writeOp{Type: "put", Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, writeOp{Type: "delete", Key: common.CopyBytes(key)})
+	b.size += len(key)
+	return nil
+}
+
+// DeleteRange removes all keys in the range [start, end) from the batch for
+// later committing.
+func (b *batch) DeleteRange(start, end []byte) error {
+	b.ops = append(b.ops, writeOp{Type: "deleteRange", Key: common.CopyBytes(start), End: common.CopyBytes(end)})
+	b.size += len(start) + len(end)
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to the remote node.
+func (b *batch) Write() error {
+	if len(b.ops) == 0 {
+		return nil
+	}
+	return b.db.write(b.ops)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, op := range b.ops {
+		var err error
+		switch op.Type {
+		case "put":
+			err = w.Put(op.Key, op.Value)
+		case "delete":
+			err = w.Delete(op.Key)
+		case "deleteRange":
+			// Check if the writer also supports range deletion
+			if rangeDeleter, ok := w.(ethdb.KeyValueRangeDeleter); ok {
+				err = rangeDeleter.DeleteRange(op.Key, op.End)
+			} else {
+				return errNotSupported
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// iteratorPageSize is the number of entries requested from the remote node in
+// a single debug_dbIterate call.
+const iteratorPageSize = 1024
+
+// entry is a key-value pair as returned by debug_dbIterate.
+type entry struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// page is a batch of entries as returned by debug_dbIterate.
+type page struct {
+	Entries []entry        `json:"entries"`
+	Next    *hexutil.Bytes `json:"next"`
+}
+
+// iterator pages through the key-value store of the remote node. Each page is
+// fetched on demand, so the iteration does not observe a consistent view of a
+// remote database which is concurrently written to.
+type iterator struct {
+	db     *Database
+	prefix []byte
+	next   []byte // Start key of the next page to fetch, nil if exhausted
+	items  []entry
+	index  int
+	err    error
+}
+
+func newIterator(db *Database, prefix []byte, start []byte) *iterator {
+	if start == nil {
+		start = []byte{}
+	}
+	return &iterator{
+		db:     db,
+		prefix: prefix,
+		next:   start,
+		index:  -1,
+	}
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.items) {
+		it.index++
+		return true
+	}
+	// Current page exhausted, fetch the next one if there's any
+	for it.next != nil {
+		var res page
+		if err := it.db.remote.Call(&res, "debug_dbIterate", hexutil.Bytes(it.prefix), hexutil.Bytes(it.next), iteratorPageSize); err != nil {
+			it.err = err
+			it.items, it.index = nil, -1
+			return false
+		}
+		it.items, it.index, it.next = res.Entries, -1, nil
+		if res.Next != nil {
+			it.next = *res.Next
+		}
+		if len(it.items) > 0 {
+			it.index = 0
+			return true
+		}
+	}
+	it.items, it.index = nil, -1
+	return false
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].Key
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index].Value
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	it.items, it.index, it.next = nil, -1, nil
+}
+
+// snapshot is a point-in-time view of the remote key-value store, held open on
+// the remote node until released.
+type snapshot struct {
+	db       *Database
+	id       rpc.ID
+	released bool
+}
+
+// Has retrieves if a key is present in the remote snapshot.
+func (snap *snapshot) Has(key []byte) (bool, error) {
+	var resp bool
+	err := snap.db.remote.Call(&resp, "debug_dbSnapshotHas", snap.id, hexutil.Bytes(key))
+	return resp, err
+}
+
+// Get retrieves the given key if it's present in the remote snapshot.
+func (snap *snapshot) Get(key []byte) ([]byte, error) {
+	var resp hexutil.Bytes
+	if err := snap.db.remote.Call(&resp, "debug_dbSnapshotGet", snap.id, hexutil.Bytes(key)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Release releases the snapshot on the remote node. Release should always
+// succeed and can be called multiple times without causing error.
+func (snap *snapshot) Release() {
+	if snap.released {
+		return
+	}
+	snap.released = true
+	snap.db.remote.Call(nil, "debug_dbReleaseSnapshot", snap.id)
+}
@@ -15,28 +15,35 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 // Package remotedb implements the key-value database layer based on a remote geth
-// node. Under the hood, it utilises the `debug_dbGet` method to implement a
-// read-only database.
+// node. Under the hood, it utilises the `debug_db*` family of methods to read,
+// iterate, snapshot and batch-write the key-value store of the remote node, and
+// to read its ancient store.
 // There really are no guarantees in this database, since the local geth does not
-// exclusive access, but it can be used for basic diagnostics of a remote node.
+// exclusive access, but it can be used for diagnostics and maintenance of a
+// live remote node.
 package remotedb
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// Database is a key-value lookup for a remote database via debug_dbGet.
+// errNotSupported is returned for operations which cannot be proxied to the
+// remote node.
+var errNotSupported = errors.New("not supported by remote database")
+
+// Database is a key-value store for a remote database via the debug_db* methods.
 type Database struct {
 	remote *rpc.Client
 }
 
 func (db *Database) Has(key []byte) (bool, error) {
-	if _, err := db.Get(key); err != nil {
-		return false, err
-	}
-	return true, nil
+	var resp bool
+	err := db.remote.Call(&resp, "debug_dbHas", hexutil.Bytes(key))
+	return resp, err
 }
 
 func (db *Database) Get(key []byte) ([]byte, error) {
@@ -58,7 +65,27 @@ func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
 }
 
 func (db *Database) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	panic("not supported")
+	var (
+		items [][]byte
+		size  uint64
+	)
+	for i := uint64(0); i < count; i++ {
+		item, err := db.Ancient(kind, start+i)
+		if err != nil {
+			// Mirror the freezer: return what has been gathered so far
+			// and only fail if nothing could be retrieved at all.
+			if len(items) == 0 {
+				return nil, err
+			}
+			break
+		}
+		if maxBytes != 0 && len(items) > 0 && size+uint64(len(item)) > maxBytes {
+			break
+		}
+		items = append(items, item)
+		size += uint64(len(item))
+	}
+	return items, nil
 }
 
 func (db *Database) Ancients() (uint64, error) {
@@ -68,11 +95,15 @@ func (db *Database) Ancients() (uint64, error) {
 }
 
 func (db *Database) Tail() (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.remote.Call(&resp, "debug_dbAncientTail")
+	return resp, err
 }
 
 func (db *Database) AncientSize(kind string) (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.remote.Call(&resp, "debug_dbAncientSize", kind)
+	return resp, err
 }
 
 func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err error) {
@@ -80,15 +111,20 @@ func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err e
 }
 
 func (db *Database) Put(key []byte, value []byte) error {
-	panic("not supported")
+	return db.write([]writeOp{{Type: "put", Key: key, Value: value}})
 }
 
 func (db *Database) Delete(key []byte) error {
-	panic("not supported")
+	return db.write([]writeOp{{Type: "delete", Key: key}})
 }
 
 func (db *Database) DeleteRange(start, end []byte) error {
-	panic("not supported")
+	return db.write([]writeOp{{Type: "deleteRange", Key: start, End: end}})
+}
+
+// write atomically applies a list of mutations on the remote node.
+func (db *Database) write(ops []writeOp) error {
+	return db.remote.Call(nil, "debug_dbWrite", ops)
 }
 
 func (db *Database) ModifyAncients(f func(ethdb.AncientWriteOp) error) (int64, error) {
@@ -108,15 +144,25 @@ func (db *Database) SyncAncient() error {
 }
 
 func (db *Database) NewBatch() ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewBatchWithSize(size int) ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
-	panic("not supported")
+	return newIterator(db, prefix, start)
+}
+
+// NewSnapshot creates a point-in-time view of the remote key-value store. The
+// snapshot is held open on the remote node until released.
+func (db *Database) NewSnapshot() (ethdb.Snapshot, error) {
+	var id rpc.ID
+	if err := db.remote.Call(&id, "debug_dbNewSnapshot"); err != nil {
+		return nil, err
+	}
+	return &snapshot{db: db, id: id}, nil
 }
 
 func (db *Database) Stat() (string, error) {
@@ -124,7 +170,7 @@ func (db *Database) Stat() (string, error) {
 }
 
 func (db *Database) AncientDatadir() (string, error) {
-	panic("not supported")
+	return "", errNotSupported
 }
 
 func (db *Database) Compact(start []byte, limit []byte) error {
@@ -41,7 +41,9 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/internal/sessions"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
@@ -508,6 +510,7 @@ func (api *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.Block
 func (api *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
 	block, err := api.b.BlockByHash(ctx, hash)
 	if block != nil {
+		MarkFinalizedResult(ctx, api.b, block.NumberU64(), hash)
 		return RPCMarshalBlock(block, true, fullTx, api.b.ChainConfig()), nil
 	}
 	return nil, err
@@ -797,12 +800,7 @@ func (api *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockN
 //
 // Note, this function doesn't make any changes in the state/blockchain and is
 // useful to execute and retrieve values.
-func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*simBlockResult, error) {
-	if len(opts.BlockStateCalls) == 0 {
-		return nil, &invalidParamsError{message: "empty input"}
-	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
-		return nil, &clientLimitExceededError{message: "too many blocks"}
-	}
+func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts SimOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*simBlockResult, error) {
 	if blockNrOrHash == nil {
 		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 		blockNrOrHash = &n
@@ -811,22 +809,7 @@ func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrO
 	if state == nil || err != nil {
 		return nil, err
 	}
-	gasCap := api.b.RPCGasCap()
-	if gasCap == 0 {
-		gasCap = gomath.MaxUint64
-	}
-	sim := &simulator{
-		b:           api.b,
-		state:       state,
-		base:        base,
-		chainConfig: api.b.ChainConfig(),
-		// Each tx and all the series of txes shouldn't consume more gas than cap
-		gp:             new(core.GasPool).AddGas(gasCap),
-		traceTransfers: opts.TraceTransfers,
-		validate:       opts.Validation,
-		fullTx:         opts.ReturnFullTransactions,
-	}
-	return sim.execute(ctx, opts.BlockStateCalls)
+	return Simulate(ctx, api.b, state, base, opts, api.b.RPCGasCap(), api.b.RPCEVMTimeout(), nil)
 }
 
 // DoEstimateGas returns the lowest possible gas limit that allows the transaction to run
@@ -1480,6 +1463,8 @@ func (api *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash commo
 	if err != nil {
 		return nil, err
 	}
+	MarkFinalizedResult(ctx, api.b, blockNumber, blockHash)
+
 	// Derive the sender.
 	return MarshalReceipt(receipt, blockHash, blockNumber, api.signer, tx, int(index)), nil
 }
@@ -1918,12 +1903,18 @@ func (api *TransactionAPI) Resend(ctx context.Context, sendArgs TransactionArgs,
 // DebugAPI is the collection of Ethereum APIs exposed over the debugging
 // namespace.
 type DebugAPI struct {
-	b Backend
+	b         Backend
+	snapshots *sessions.Store[rpc.ID, ethdb.Snapshot] // database snapshots held open for clients
 }
 
 // NewDebugAPI creates a new instance of DebugAPI.
 func NewDebugAPI(b Backend) *DebugAPI {
-	return &DebugAPI{b: b}
+	return &DebugAPI{
+		b: b,
+		snapshots: sessions.New(maxDbSnapshots, dbSnapshotTimeout, func(_ rpc.ID, snap ethdb.Snapshot) {
+			snap.Release()
+		}),
+	}
 }
 
 // GetRawHeader retrieves the RLP encoding for a single header.
@@ -2426,7 +2426,7 @@ func TestSimulateV1(t *testing.T) {
 
 	for _, tc := range testSuite {
 		t.Run(tc.name, func(t *testing.T) {
-			opts := simOpts{BlockStateCalls: tc.blocks}
+			opts := SimOpts{BlockStateCalls: tc.blocks}
 			if tc.includeTransfers != nil && *tc.includeTransfers {
 				opts.TraceTransfers = true
 			}
@@ -2629,6 +2629,93 @@ func TestSimulateV1TxSender(t *testing.T) {
 	require.Equal(t, sender2, summary[1].Transactions[0].From, "sender address mismatch")
 }
 
+func TestForkSession(t *testing.T) {
+	var (
+		sender      = common.Address{0xaa, 0xaa}
+		storageAddr = common.Address{0xbb, 0xbb}
+		hashAddr    = common.Address{0xcc, 0xcc}
+		fakeHash    = common.Hash{0x01, 0x02}
+		gspec       = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: types.GenesisAlloc{
+				sender: {Balance: big.NewInt(params.Ether)},
+				// Returns slot 0 without calldata, otherwise stores the
+				// first calldata word in it.
+				storageAddr: {Code: common.Hex2Bytes("36600f5760005460005260206000f35b60003560005500")},
+				// Returns the blockhash of the number in calldata, reverts if zero.
+				hashAddr: {Code: common.Hex2Bytes("5f35405f8114600f575f5260205ff35b5f80fd")},
+			},
+		}
+		ctx = context.Background()
+	)
+	backend := newTestBackend(t, 1, gspec, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {})
+	api := NewForkSessionAPI(backend)
+
+	name, err := api.ForkSession(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &ForkSessionConfig{
+		BlockHashes: map[hexutil.Uint64]common.Hash{0: fakeHash},
+	})
+	if err != nil {
+		t.Fatalf("failed to create fork session: %v", err)
+	}
+	// Transactions must modify the session state.
+	value := hexutil.Bytes(common.Hash{31: 0x2a}.Bytes())
+	res, err := api.ForkSendTransaction(ctx, name, TransactionArgs{From: &sender, To: &storageAddr, Input: &value}, nil)
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), res.Status, "transaction failed")
+
+	ret, err := api.ForkCall(ctx, name, TransactionArgs{From: &sender, To: &storageAddr}, nil)
+	if err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	require.Equal(t, value, ret, "storage change not visible in session")
+
+	// The canonical state must stay untouched.
+	stateDB, _, err := backend.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	require.Equal(t, common.Hash{}, stateDB.GetState(storageAddr, common.Hash{}), "canonical state modified")
+	require.Equal(t, uint64(0), stateDB.GetNonce(sender), "canonical nonce modified")
+
+	// The nonce of the sender must be tracked in the session.
+	if _, err := api.ForkSendTransaction(ctx, name, TransactionArgs{From: &sender, To: &storageAddr, Input: &value}, nil); err != nil {
+		t.Fatalf("failed to send second transaction: %v", err)
+	}
+	// BLOCKHASH must resolve against the overridden hashes.
+	number := hexutil.Bytes(common.Hash{}.Bytes())
+	ret, err = api.ForkCall(ctx, name, TransactionArgs{From: &sender, To: &hashAddr, Input: &number}, nil)
+	if err != nil {
+		t.Fatalf("failed to call blockhash contract: %v", err)
+	}
+	require.Equal(t, fakeHash.Bytes(), []byte(ret), "blockhash override not applied")
+
+	// State overrides must be applied to the session.
+	if err := api.ForkSetState(name, &override.StateOverride{storageAddr: {StateDiff: map[common.Hash]common.Hash{{}: {31: 0x07}}}}); err != nil {
+		t.Fatalf("failed to set state: %v", err)
+	}
+	ret, err = api.ForkCall(ctx, name, TransactionArgs{From: &sender, To: &storageAddr}, nil)
+	if err != nil {
+		t.Fatalf("failed to call: %v", err)
+	}
+	require.Equal(t, common.Hash{31: 0x07}.Bytes(), []byte(ret), "state override not applied")
+
+	if _, err := api.ForkSession(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &ForkSessionConfig{Name: &name}); err == nil {
+		t.Fatal("expected error for duplicate session name")
+	}
+	if err := api.ForkClose(name); err != nil {
+		t.Fatalf("failed to close session: %v", err)
+	}
+	if _, err := api.ForkCall(ctx, name, TransactionArgs{From: &sender, To: &storageAddr}, nil); !errors.Is(err, errForkSessionNotFound) {
+		t.Fatalf("expected session not found error, got %v", err)
+	}
+	if _, err := api.ForkSession(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &ForkSessionConfig{Name: &name}); err != nil {
+		t.Fatalf("failed to recreate session: %v", err)
+	}
+	api.ForkClose(name)
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -17,8 +17,38 @@
 package ethapi
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxDbIterateItems is the maximum number of entries returned by a single
+	// debug_dbIterate call.
+	maxDbIterateItems = 1024
+
+	// maxDbIterateBytes is the soft limit of keys and values returned by a single
+	// debug_dbIterate call. At least one entry is always returned.
+	maxDbIterateBytes = 4 * 1024 * 1024
+
+	// maxDbSnapshots is the maximum number of database snapshots that may be
+	// held open concurrently by remote clients.
+	maxDbSnapshots = 16
+
+	// dbSnapshotTimeout is the idle time after which an unused database snapshot
+	// is released automatically.
+	dbSnapshotTimeout = 5 * time.Minute
+)
+
+var (
+	errDbSnapshotLimit   = errors.New("too many open database snapshots")
+	errDbSnapshotUnknown = errors.New("database snapshot not found")
 )
 
 // DbGet returns the raw value of a key stored in the database.
@@ -30,6 +60,15 @@ func (api *DebugAPI) DbGet(key string) (hexutil.Bytes, error) {
 	return api.b.ChainDb().Get(blob)
 }
 
+// DbHas reports whether a key is present in the database.
+func (api *DebugAPI) DbHas(key string) (bool, error) {
+	blob, err := common.ParseHexOrString(key)
+	if err != nil {
+		return false, err
+	}
+	return api.b.ChainDb().Has(blob)
+}
+
 // DbAncient retrieves an ancient binary blob from the append-only immutable files.
 // It is a mapping to the `AncientReaderOp.Ancient` method
 func (api *DebugAPI) DbAncient(kind string, number uint64) (hexutil.Bytes, error) {
@@ -41,3 +80,156 @@ func (api *DebugAPI) DbAncient(kind string, number uint64) (hexutil.Bytes, error
 func (api *DebugAPI) DbAncients() (uint64, error) {
 	return api.b.ChainDb().Ancients()
 }
+
+// DbAncientTail returns the number of the first stored item in the ancient store.
+// It is a mapping to the `AncientReaderOp.Tail` method
+func (api *DebugAPI) DbAncientTail() (uint64, error) {
+	return api.b.ChainDb().Tail()
+}
+
+// DbAncientSize returns the ancient size of the specified category.
+// It is a mapping to the `AncientReaderOp.AncientSize` method
+func (api *DebugAPI) DbAncientSize(kind string) (uint64, error) {
+	return api.b.ChainDb().AncientSize(kind)
+}
+
+// DbStats returns the per-category statistics of the key-value store. If the
+// node maintains statistics in the background, the live values are returned,
+// otherwise the last persisted ones, if any.
+func (api *DebugAPI) DbStats() (*rawdb.DatabaseStats, error) {
+	db := api.b.ChainDb()
+	if stats := rawdb.LiveDatabaseStats(db); stats != nil {
+		return stats, nil
+	}
+	if stats := rawdb.ReadDatabaseStats(db); stats != nil {
+		return stats, nil
+	}
+	return nil, errors.New("database statistics not available")
+}
+
+// DbWriteOp is a single database mutation submitted via debug_dbWrite.
+type DbWriteOp struct {
+	Type  string        `json:"type"` // One of "put", "delete" or "deleteRange"
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value,omitempty"`
+	End   hexutil.Bytes `json:"end,omitempty"` // Exclusive end of a "deleteRange"
+}
+
+// DbWrite applies a list of mutations to the key-value store as a single atomic
+// batch. Nothing is written if any of the operations is malformed.
+func (api *DebugAPI) DbWrite(ops []DbWriteOp) error {
+	batch := api.b.ChainDb().NewBatch()
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case "put":
+			err = batch.Put(op.Key, op.Value)
+		case "delete":
+			err = batch.Delete(op.Key)
+		case "deleteRange":
+			err = batch.DeleteRange(op.Key, op.End)
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("op %d: %w", i, err)
+		}
+	}
+	return batch.Write()
+}
+
+// DbEntry is a key-value pair returned by debug_dbIterate.
+type DbEntry struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// DbIterateResult is a page of database entries returned by debug_dbIterate.
+type DbIterateResult struct {
+	Entries []DbEntry      `json:"entries"`
+	Next    *hexutil.Bytes `json:"next"` // Start key (prefix excluded) of the next page, nil if exhausted
+}
+
+// DbIterate returns a page of the key-value entries with the given prefix,
+// starting at the given key (or after, if it does not exist). The start key
+// does not include the prefix, mirroring ethdb.Iteratee.
+//
+// Pages are not served from a consistent view of the database; concurrent
+// writes on the node may or may not be reflected in subsequent pages.
+func (api *DebugAPI) DbIterate(prefix, start hexutil.Bytes, limit int) (*DbIterateResult, error) {
+	if limit <= 0 || limit > maxDbIterateItems {
+		limit = maxDbIterateItems
+	}
+	it := api.b.ChainDb().NewIterator(prefix, start)
+	defer it.Release()
+
+	var (
+		result = &DbIterateResult{Entries: []DbEntry{}}
+		size   int
+	)
+	for it.Next() {
+		if len(result.Entries) >= limit || size >= maxDbIterateBytes {
+			next := hexutil.Bytes(common.CopyBytes(it.Key()[len(prefix):]))
+			result.Next = &next
+			break
+		}
+		result.Entries = append(result.Entries, DbEntry{
+			Key:   common.CopyBytes(it.Key()),
+			Value: common.CopyBytes(it.Value()),
+		})
+		size += len(it.Key()) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DbNewSnapshot creates a point-in-time view of the key-value store and returns
+// an identifier to access it with. Snapshots must be released by the caller via
+// debug_dbReleaseSnapshot and are dropped automatically when left idle.
+func (api *DebugAPI) DbNewSnapshot() (rpc.ID, error) {
+	snapshotter, ok := api.b.ChainDb().(ethdb.Snapshotter)
+	if !ok {
+		return "", errors.New("database does not support snapshots")
+	}
+	if !api.snapshots.Reserve() {
+		return "", errDbSnapshotLimit
+	}
+	snap, err := snapshotter.NewSnapshot()
+	if err != nil {
+		api.snapshots.Cancel()
+		return "", err
+	}
+	id := rpc.NewID()
+	api.snapshots.Add(id, snap)
+	return id, nil
+}
+
+// DbSnapshotGet returns the raw value of a key stored in the given snapshot.
+func (api *DebugAPI) DbSnapshotGet(id rpc.ID, key hexutil.Bytes) (hexutil.Bytes, error) {
+	snap, ok := api.snapshots.Get(id)
+	if !ok {
+		return nil, errDbSnapshotUnknown
+	}
+	return snap.Get(key)
+}
+
+// DbSnapshotHas reports whether a key is present in the given snapshot.
+func (api *DebugAPI) DbSnapshotHas(id rpc.ID, key hexutil.Bytes) (bool, error) {
+	snap, ok := api.snapshots.Get(id)
+	if !ok {
+		return false, errDbSnapshotUnknown
+	}
+	return snap.Has(key)
+}
+
+// DbReleaseSnapshot releases a snapshot created by debug_dbNewSnapshot.
+func (api *DebugAPI) DbReleaseSnapshot(id rpc.ID) error {
+	snap, ok := api.snapshots.Remove(id)
+	if !ok {
+		return errDbSnapshotUnknown
+	}
+	snap.Release()
+	return nil
+}
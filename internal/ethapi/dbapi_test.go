@@ -0,0 +1,81 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+	"github.com/ethereum/go-ethereum/ethdb/remotedb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TestRemoteDatabase runs the database test suite against a remotedb client
+// proxying to the debug_db* methods of an in-process server.
+func TestRemoteDatabase(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+		server := rpc.NewServer()
+		t.Cleanup(server.Stop)
+
+		api := NewDebugAPI(&testBackend{db: rawdb.NewMemoryDatabase()})
+		if err := server.RegisterName("debug", api); err != nil {
+			t.Fatal(err)
+		}
+		return remotedb.New(rpc.DialInProc(server))
+	})
+}
+
+func TestRemoteDatabaseIteratorPaging(t *testing.T) {
+	var (
+		server = rpc.NewServer()
+		db     = rawdb.NewMemoryDatabase()
+	)
+	defer server.Stop()
+	if err := server.RegisterName("debug", NewDebugAPI(&testBackend{db: db})); err != nil {
+		t.Fatal(err)
+	}
+	remote := remotedb.New(rpc.DialInProc(server))
+	defer remote.Close()
+
+	// Write more entries than fit into a single page
+	batch := remote.NewBatch()
+	for i := 0; i < 3*maxDbIterateItems+1; i++ {
+		batch.Put([]byte{'p', byte(i >> 8), byte(i)}, []byte{byte(i)})
+	}
+	batch.Put([]byte("q"), []byte("outside prefix"))
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	it := remote.NewIterator([]byte("p"), []byte{0x01})
+	defer it.Release()
+
+	want := 256
+	for it.Next() {
+		if key := []byte{'p', byte(want >> 8), byte(want)}; string(it.Key()) != string(key) {
+			t.Fatalf("key mismatch: have %x, want %x", it.Key(), key)
+		}
+		want++
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if want != 3*maxDbIterateItems+1 {
+		t.Fatalf("iteration ended early: have %d, want %d", want, 3*maxDbIterateItems+1)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FinalityBackend provides the methods required to check the finality of blocks.
+type FinalityBackend interface {
+	HeaderByNumber(context.Context, rpc.BlockNumber) (*types.Header, error)
+}
+
+// MarkFinalizedResult marks the result of the RPC call carrying the context as
+// final if it only depends on finalized blocks up to the given number, allowing
+// the RPC server to cache it. If hash is non-zero, it must additionally be the
+// hash of the canonical block with the given number.
+func MarkFinalizedResult(ctx context.Context, b FinalityBackend, number uint64, hash common.Hash) {
+	if !rpc.ResultCacheEnabled(ctx) {
+		return
+	}
+	finalized, err := b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil || finalized == nil || number > finalized.Number.Uint64() {
+		return
+	}
+	if hash != (common.Hash{}) {
+		header, err := b.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil || header == nil || header.Hash() != hash {
+			return
+		}
+	}
+	rpc.MarkResultFinal(ctx)
+}
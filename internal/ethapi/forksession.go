@@ -0,0 +1,318 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/internal/sessions"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// forkSessionIdleTimeout is the time after which an unused fork session
+	// is dropped.
+	forkSessionIdleTimeout = 5 * time.Minute
+
+	// maxForkSessions is the maximum number of concurrently open fork sessions.
+	maxForkSessions = 16
+)
+
+var (
+	errForkSessionNotFound = errors.New("fork session not found")
+	errForkSessionState    = errors.New("fork session state unavailable")
+)
+
+// ForkSessionConfig are the options of a new fork session.
+type ForkSessionConfig struct {
+	// Name of the session, a random one is chosen if not given.
+	Name *string
+	// StateOverrides are applied to the base state of the session.
+	StateOverrides *override.StateOverride
+	// BlockOverrides are applied to the header of every call in the session.
+	BlockOverrides *override.BlockOverrides
+	// BlockHashes override the results of BLOCKHASH for the given numbers.
+	BlockHashes map[hexutil.Uint64]common.Hash
+}
+
+// forkSession is an overlay on a historical state, which is mutated by the
+// transactions sent to it.
+type forkSession struct {
+	lock           sync.Mutex // serializes the execution in the session
+	state          *state.StateDB
+	header         *types.Header
+	blockOverrides *override.BlockOverrides
+	blockHashes    map[uint64]common.Hash
+	precompiles    vm.PrecompiledContracts
+	txIndex        int
+}
+
+// ForkSessionAPI offers stateful call execution on top of historical states,
+// similar to a local fork of the chain.
+type ForkSessionAPI struct {
+	b        Backend
+	sessions *sessions.Store[string, *forkSession]
+}
+
+// NewForkSessionAPI creates a new fork session API.
+func NewForkSessionAPI(b Backend) *ForkSessionAPI {
+	return &ForkSessionAPI{
+		b: b,
+		sessions: sessions.New(maxForkSessions, forkSessionIdleTimeout, func(name string, _ *forkSession) {
+			log.Debug("Fork session expired", "name", name)
+		}),
+	}
+}
+
+// ForkSession creates a new session on top of the state of the given block and
+// returns its name. Transactions sent to the session modify its state, which is
+// visible to all later calls. Sessions expire when they are not used for five
+// minutes.
+//
+// The session reads the unmodified state from the node, so the state of the
+// block must remain available while the session is in use. Unless the node keeps
+// the state history, this is only the case for the recent blocks: under the path
+// scheme, the states older than the 128 most recent blocks are dropped. Once the
+// state is gone, the session fails with an error and is dropped.
+func (api *ForkSessionAPI) ForkSession(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *ForkSessionConfig) (string, error) {
+	if config == nil {
+		config = &ForkSessionConfig{}
+	}
+	// Reserve the session before preparing the state, so concurrent requests
+	// can't exceed the session limit.
+	if !api.sessions.Reserve() {
+		return "", fmt.Errorf("too many fork sessions, limit %d", maxForkSessions)
+	}
+	name, err := api.create(ctx, blockNrOrHash, config)
+	if err != nil {
+		api.sessions.Cancel()
+		return "", err
+	}
+	return name, nil
+}
+
+// create prepares a fork session and stores it in a reserved slot.
+func (api *ForkSessionAPI) create(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, config *ForkSessionConfig) (string, error) {
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return "", err
+	}
+	// Precompiles can be moved by the state overrides of the session.
+	var (
+		blockCtx = core.NewEVMBlockContext(header, NewChainContext(ctx, api.b), nil)
+		session  = &forkSession{
+			state:          state,
+			header:         types.CopyHeader(header),
+			blockOverrides: config.BlockOverrides,
+			blockHashes:    make(map[uint64]common.Hash, len(config.BlockHashes)),
+		}
+	)
+	if err := config.BlockOverrides.Apply(&blockCtx); err != nil {
+		return "", err
+	}
+	rules := api.b.ChainConfig().Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time)
+	session.precompiles = vm.ActivePrecompiledContracts(rules)
+	if err := config.StateOverrides.Apply(state, session.precompiles); err != nil {
+		return "", err
+	}
+	state.Finalise(true)
+	for number, hash := range config.BlockHashes {
+		session.blockHashes[uint64(number)] = hash
+	}
+	name := string(rpc.NewID())
+	if config.Name != nil {
+		name = *config.Name
+	}
+	if !api.sessions.Add(name, session) {
+		return "", fmt.Errorf("fork session %q already exists", name)
+	}
+	log.Debug("Created fork session", "name", name, "number", header.Number, "hash", header.Hash())
+	return name, nil
+}
+
+// ForkClose drops a fork session.
+func (api *ForkSessionAPI) ForkClose(name string) error {
+	if _, ok := api.sessions.Remove(name); !ok {
+		return errForkSessionNotFound
+	}
+	return nil
+}
+
+// session retrieves a fork session and extends its lifetime.
+func (api *ForkSessionAPI) session(name string) (*forkSession, error) {
+	session, ok := api.sessions.Get(name)
+	if !ok {
+		return nil, errForkSessionNotFound
+	}
+	return session, nil
+}
+
+// checkState drops a fork session if the state it is based on can no longer be
+// read, returning the error to report.
+func (api *ForkSessionAPI) checkState(name string, statedb *state.StateDB) error {
+	if err := statedb.Error(); err != nil {
+		log.Debug("Dropping fork session with unavailable state", "name", name, "err", err)
+		api.sessions.Remove(name)
+		return fmt.Errorf("%w, the session was dropped: %v", errForkSessionState, err)
+	}
+	return nil
+}
+
+// ForkCall executes a call in a fork session like eth_call. The state of the
+// session is not modified.
+func (api *ForkSessionAPI) ForkCall(ctx context.Context, name string, args TransactionArgs, blockOverrides *override.BlockOverrides) (hexutil.Bytes, error) {
+	session, err := api.session(name)
+	if err != nil {
+		return nil, err
+	}
+	session.lock.Lock()
+	defer session.lock.Unlock()
+
+	result, statedb, _, err := api.apply(ctx, session, args, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.checkState(name, statedb); err != nil {
+		return nil, err
+	}
+	if errors.Is(result.Err, vm.ErrExecutionReverted) {
+		return nil, newRevertError(result.Revert())
+	}
+	return result.Return(), result.Err
+}
+
+// ForkSendTransaction executes a transaction in a fork session and keeps its
+// state changes for the following calls. The sender is not required to sign,
+// and the nonce defaults to the one of the sender in the session.
+func (api *ForkSessionAPI) ForkSendTransaction(ctx context.Context, name string, args TransactionArgs, blockOverrides *override.BlockOverrides) (*simCallResult, error) {
+	session, err := api.session(name)
+	if err != nil {
+		return nil, err
+	}
+	session.lock.Lock()
+	defer session.lock.Unlock()
+
+	if args.Nonce == nil {
+		nonce := hexutil.Uint64(session.state.GetNonce(args.from()))
+		args.Nonce = &nonce
+	}
+	result, statedb, txHash, err := api.apply(ctx, session, args, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.checkState(name, statedb); err != nil {
+		return nil, err
+	}
+	statedb.Finalise(true)
+	session.state = statedb
+	session.txIndex++
+
+	callRes := &simCallResult{
+		ReturnValue: result.Return(),
+		Logs:        statedb.GetLogs(txHash, session.header.Number.Uint64(), common.Hash{}, session.header.Time),
+		GasUsed:     hexutil.Uint64(result.UsedGas),
+		Status:      hexutil.Uint64(types.ReceiptStatusSuccessful),
+	}
+	if result.Failed() {
+		callRes.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+		if errors.Is(result.Err, vm.ErrExecutionReverted) {
+			revertErr := newRevertError(result.Revert())
+			callRes.Error = &callError{Message: revertErr.Error(), Code: errCodeReverted, Data: revertErr.ErrorData().(string)}
+		} else {
+			callRes.Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+		}
+	}
+	return callRes, nil
+}
+
+// ForkSetState applies state overrides to a fork session.
+func (api *ForkSessionAPI) ForkSetState(name string, overrides *override.StateOverride) error {
+	session, err := api.session(name)
+	if err != nil {
+		return err
+	}
+	session.lock.Lock()
+	defer session.lock.Unlock()
+
+	if err := overrides.Apply(session.state, session.precompiles); err != nil {
+		return err
+	}
+	if err := api.checkState(name, session.state); err != nil {
+		return err
+	}
+	session.state.Finalise(true)
+	return nil
+}
+
+// apply executes a message on a copy of the session state, which is returned
+// along with the result and the hash of the unsigned transaction. The session
+// must be locked.
+func (api *ForkSessionAPI) apply(ctx context.Context, session *forkSession, args TransactionArgs, blockOverrides *override.BlockOverrides) (*core.ExecutionResult, *state.StateDB, common.Hash, error) {
+	blockCtx := core.NewEVMBlockContext(session.header, NewChainContext(ctx, api.b), nil)
+	if err := session.blockOverrides.Apply(&blockCtx); err != nil {
+		return nil, nil, common.Hash{}, err
+	}
+	if err := blockOverrides.Apply(&blockCtx); err != nil {
+		return nil, nil, common.Hash{}, err
+	}
+	if len(session.blockHashes) > 0 {
+		getHash := blockCtx.GetHash
+		blockCtx.GetHash = func(number uint64) common.Hash {
+			if hash, ok := session.blockHashes[number]; ok {
+				return hash
+			}
+			return getHash(number)
+		}
+	}
+	timeout := api.b.RPCEVMTimeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	gasCap := api.b.RPCGasCap()
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	if err := args.CallDefaults(gasCap, blockCtx.BaseFee, api.b.ChainConfig().ChainID); err != nil {
+		return nil, nil, common.Hash{}, err
+	}
+	var (
+		txHash  = args.ToTransaction(types.DynamicFeeTxType).Hash()
+		statedb = session.state.Copy()
+	)
+	statedb.SetTxContext(txHash, session.txIndex)
+
+	result, err := applyMessage(ctx, api.b, args, statedb, session.header, timeout, new(core.GasPool).AddGas(gasCap), &blockCtx, &vm.Config{NoBaseFee: true}, session.precompiles)
+	if err != nil {
+		return nil, nil, common.Hash{}, err
+	}
+	return result, statedb, txHash, nil
+}
@@ -57,6 +57,10 @@ type tracer struct {
 	blockHash      common.Hash
 	txHash         common.Hash
 	txIdx          uint
+
+	// callTracer receives the events of the current call, if tracing of the
+	// simulated calls was requested.
+	callTracer *tracing.Hooks
 }
 
 func newTracer(traceTransfers bool, blockNumber uint64, blockTimestamp uint64, blockHash, txHash common.Hash, txIndex uint) *tracer {
@@ -78,6 +82,76 @@ func (t *tracer) Hooks() *tracing.Hooks {
 	}
 }
 
+// forwardingHooks returns the hooks of the tracer, which additionally pass all
+// events of the executed calls on to the call tracer.
+func (t *tracer) forwardingHooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnEnter: func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+			t.onEnter(depth, typ, from, to, input, gas, value)
+			if t.callTracer != nil && t.callTracer.OnEnter != nil {
+				t.callTracer.OnEnter(depth, typ, from, to, input, gas, value)
+			}
+		},
+		OnExit: func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+			t.onExit(depth, output, gasUsed, err, reverted)
+			if t.callTracer != nil && t.callTracer.OnExit != nil {
+				t.callTracer.OnExit(depth, output, gasUsed, err, reverted)
+			}
+		},
+		OnLog: func(log *types.Log) {
+			t.onLog(log)
+			if t.callTracer != nil && t.callTracer.OnLog != nil {
+				t.callTracer.OnLog(log)
+			}
+		},
+		OnOpcode: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+			if t.callTracer != nil && t.callTracer.OnOpcode != nil {
+				t.callTracer.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+			}
+		},
+		OnFault: func(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+			if t.callTracer != nil && t.callTracer.OnFault != nil {
+				t.callTracer.OnFault(pc, op, gas, cost, scope, depth, err)
+			}
+		},
+		OnGasChange: func(old, new uint64, reason tracing.GasChangeReason) {
+			if t.callTracer != nil && t.callTracer.OnGasChange != nil {
+				t.callTracer.OnGasChange(old, new, reason)
+			}
+		},
+		OnBalanceChange: func(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+			if t.callTracer != nil && t.callTracer.OnBalanceChange != nil {
+				t.callTracer.OnBalanceChange(addr, prev, new, reason)
+			}
+		},
+		OnNonceChangeV2: func(addr common.Address, prev, new uint64, reason tracing.NonceChangeReason) {
+			if t.callTracer == nil {
+				return
+			}
+			if t.callTracer.OnNonceChangeV2 != nil {
+				t.callTracer.OnNonceChangeV2(addr, prev, new, reason)
+			} else if t.callTracer.OnNonceChange != nil {
+				t.callTracer.OnNonceChange(addr, prev, new)
+			}
+		},
+		OnCodeChangeV2: func(addr common.Address, prevCodeHash common.Hash, prevCode []byte, codeHash common.Hash, code []byte, reason tracing.CodeChangeReason) {
+			if t.callTracer == nil {
+				return
+			}
+			if t.callTracer.OnCodeChangeV2 != nil {
+				t.callTracer.OnCodeChangeV2(addr, prevCodeHash, prevCode, codeHash, code, reason)
+			} else if t.callTracer.OnCodeChange != nil {
+				t.callTracer.OnCodeChange(addr, prevCodeHash, prevCode, codeHash, code)
+			}
+		},
+		OnStorageChange: func(addr common.Address, slot common.Hash, prev, new common.Hash) {
+			if t.callTracer != nil && t.callTracer.OnStorageChange != nil {
+				t.callTracer.OnStorageChange(addr, slot, prev, new)
+			}
+		},
+	}
+}
+
 func (t *tracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 	t.logs = append(t.logs, make([]*types.Log, 0))
 	if vm.OpCode(typ) != vm.DELEGATECALL && value != nil && value.Cmp(common.Big0) > 0 {
@@ -142,11 +216,13 @@ func (t *tracer) captureTransfer(from, to common.Address, value *big.Int) {
 	t.captureLog(transferAddress, topics, common.BigToHash(value).Bytes())
 }
 
-// reset prepares the tracer for the next transaction.
-func (t *tracer) reset(txHash common.Hash, txIdx uint) {
+// reset prepares the tracer for the next transaction, whose events are passed
+// on to the given call tracer if the forwarding hooks are used.
+func (t *tracer) reset(txHash common.Hash, txIdx uint, callTracer *tracing.Hooks) {
 	t.logs = nil
 	t.txHash = txHash
 	t.txIdx = txIdx
+	t.callTracer = callTracer
 }
 
 func (t *tracer) Logs() []*types.Log {
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
@@ -61,6 +63,8 @@ type simCallResult struct {
 	GasUsed     hexutil.Uint64 `json:"gasUsed"`
 	Status      hexutil.Uint64 `json:"status"`
 	Error       *callError     `json:"error,omitempty"`
+	// Trace is the result of the call tracer, if tracing was requested.
+	Trace json.RawMessage `json:"trace,omitempty"`
 }
 
 func (r *simCallResult) MarshalJSON() ([]byte, error) {
@@ -100,30 +104,34 @@ func (r *simBlockResult) MarshalJSON() ([]byte, error) {
 	return json.Marshal(blockData)
 }
 
-// simOpts are the inputs to eth_simulateV1.
-type simOpts struct {
+// SimOpts are the inputs to eth_simulateV1.
+type SimOpts struct {
 	BlockStateCalls        []simBlock
 	TraceTransfers         bool
 	Validation             bool
 	ReturnFullTransactions bool
 }
 
+// SimCallTracer creates the tracer of a simulated call. The returned function
+// retrieves the result of the tracer after the call has been executed.
+type SimCallTracer func(header *types.Header, txHash common.Hash, txIndex int) (*tracing.Hooks, func() (json.RawMessage, error), error)
+
 // simChainHeadReader implements ChainHeaderReader which is needed as input for FinalizeAndAssemble.
 type simChainHeadReader struct {
 	context.Context
-	Backend
+	ChainContextBackend
 }
 
 func (m *simChainHeadReader) Config() *params.ChainConfig {
-	return m.Backend.ChainConfig()
+	return m.ChainContextBackend.ChainConfig()
 }
 
 func (m *simChainHeadReader) CurrentHeader() *types.Header {
-	return m.Backend.CurrentHeader()
+	return m.ChainContextBackend.CurrentHeader()
 }
 
 func (m *simChainHeadReader) GetHeader(hash common.Hash, number uint64) *types.Header {
-	header, err := m.Backend.HeaderByNumber(m.Context, rpc.BlockNumber(number))
+	header, err := m.ChainContextBackend.HeaderByNumber(m.Context, rpc.BlockNumber(number))
 	if err != nil || header == nil {
 		return nil
 	}
@@ -134,7 +142,7 @@ func (m *simChainHeadReader) GetHeader(hash common.Hash, number uint64) *types.H
 }
 
 func (m *simChainHeadReader) GetHeaderByNumber(number uint64) *types.Header {
-	header, err := m.Backend.HeaderByNumber(m.Context, rpc.BlockNumber(number))
+	header, err := m.ChainContextBackend.HeaderByNumber(m.Context, rpc.BlockNumber(number))
 	if err != nil {
 		return nil
 	}
@@ -142,7 +150,7 @@ func (m *simChainHeadReader) GetHeaderByNumber(number uint64) *types.Header {
 }
 
 func (m *simChainHeadReader) GetHeaderByHash(hash common.Hash) *types.Header {
-	header, err := m.Backend.HeaderByHash(m.Context, hash)
+	header, err := m.ChainContextBackend.HeaderByHash(m.Context, hash)
 	if err != nil {
 		return nil
 	}
@@ -152,14 +160,44 @@ func (m *simChainHeadReader) GetHeaderByHash(hash common.Hash) *types.Header {
 // simulator is a stateful object that simulates a series of blocks.
 // it is not safe for concurrent use.
 type simulator struct {
-	b              Backend
+	b              ChainContextBackend
 	state          *state.StateDB
 	base           *types.Header
 	chainConfig    *params.ChainConfig
 	gp             *core.GasPool
+	timeout        time.Duration
 	traceTransfers bool
 	validate       bool
 	fullTx         bool
+	callTracer     SimCallTracer
+}
+
+// Simulate executes a series of blocks on top of the given state and header as
+// in eth_simulateV1. If callTracer is not nil, every simulated call is traced
+// and the result of its tracer is returned along with the call.
+func Simulate(ctx context.Context, b ChainContextBackend, state *state.StateDB, base *types.Header, opts SimOpts, gasCap uint64, timeout time.Duration, callTracer SimCallTracer) ([]*simBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &invalidParamsError{message: "empty input"}
+	} else if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &clientLimitExceededError{message: "too many blocks"}
+	}
+	if gasCap == 0 {
+		gasCap = math.MaxUint64
+	}
+	sim := &simulator{
+		b:           b,
+		state:       state,
+		base:        base,
+		chainConfig: b.ChainConfig(),
+		// Each tx and all the series of txes shouldn't consume more gas than cap
+		gp:             new(core.GasPool).AddGas(gasCap),
+		timeout:        timeout,
+		traceTransfers: opts.TraceTransfers,
+		validate:       opts.Validation,
+		fullTx:         opts.ReturnFullTransactions,
+		callTracer:     callTracer,
+	}
+	return sim.execute(ctx, opts.BlockStateCalls)
 }
 
 // execute runs the simulation of a series of blocks.
@@ -169,7 +207,7 @@ func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]*simBlo
 	}
 	var (
 		cancel  context.CancelFunc
-		timeout = sim.b.RPCEVMTimeout()
+		timeout = sim.timeout
 	)
 	if timeout > 0 {
 		ctx, cancel = context.WithTimeout(ctx, timeout)
@@ -244,20 +282,22 @@ func (sim *simulator) processBlock(ctx context.Context, block *simBlock, header,
 		callResults          = make([]simCallResult, len(block.Calls))
 		receipts             = make([]*types.Receipt, len(block.Calls))
 		// Block hash will be repaired after execution.
-		tracer   = newTracer(sim.traceTransfers, blockContext.BlockNumber.Uint64(), blockContext.Time, common.Hash{}, common.Hash{}, 0)
-		vmConfig = &vm.Config{
-			NoBaseFee: !sim.validate,
-			Tracer:    tracer.Hooks(),
-		}
+		tracer = newTracer(sim.traceTransfers, blockContext.BlockNumber.Uint64(), blockContext.Time, common.Hash{}, common.Hash{}, 0)
+		hooks  = tracer.Hooks()
 		// senders is a map of transaction hashes to their senders.
 		// Transaction objects contain only the signature, and we lose track
 		// of the sender when translating the arguments into a transaction object.
 		senders = make(map[common.Hash]common.Address)
 	)
-	tracingStateDB := vm.StateDB(sim.state)
-	if hooks := tracer.Hooks(); hooks != nil {
-		tracingStateDB = state.NewHookedState(sim.state, hooks)
+	// Pass the events of the calls on to their tracers, if requested.
+	if sim.callTracer != nil {
+		hooks = tracer.forwardingHooks()
 	}
+	vmConfig := &vm.Config{
+		NoBaseFee: !sim.validate,
+		Tracer:    hooks,
+	}
+	tracingStateDB := state.NewHookedState(sim.state, hooks)
 	evm := vm.NewEVM(blockContext, tracingStateDB, sim.chainConfig, *vmConfig)
 	// It is possible to override precompiles with EVM bytecode, or
 	// move them to another address.
@@ -284,8 +324,22 @@ func (sim *simulator) processBlock(ctx context.Context, block *simBlock, header,
 		)
 		txes[i] = tx
 		senders[txHash] = call.from()
-		tracer.reset(txHash, uint(i))
+
+		var (
+			callTracer  *tracing.Hooks
+			traceResult func() (json.RawMessage, error)
+		)
+		if sim.callTracer != nil {
+			var err error
+			if callTracer, traceResult, err = sim.callTracer(header, txHash, i); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		tracer.reset(txHash, uint(i), callTracer)
 		sim.state.SetTxContext(txHash, i)
+		if callTracer != nil && callTracer.OnTxStart != nil {
+			callTracer.OnTxStart(evm.GetVMContext(), tx, call.from())
+		}
 		// EoA check is always skipped, even in validation mode.
 		msg := call.ToMessage(header.BaseFee, !sim.validate)
 		result, err := applyMessageWithEVM(ctx, evm, msg, timeout, sim.gp)
@@ -303,8 +357,16 @@ func (sim *simulator) processBlock(ctx context.Context, block *simBlock, header,
 		gasUsed += result.UsedGas
 		receipts[i] = core.MakeReceipt(evm, result, sim.state, blockContext.BlockNumber, common.Hash{}, blockContext.Time, tx, gasUsed, root)
 		blobGasUsed += receipts[i].BlobGasUsed
+		if callTracer != nil && callTracer.OnTxEnd != nil {
+			callTracer.OnTxEnd(receipts[i], nil)
+		}
 		logs := tracer.Logs()
 		callRes := simCallResult{ReturnValue: result.Return(), Logs: logs, GasUsed: hexutil.Uint64(result.UsedGas)}
+		if traceResult != nil {
+			if callRes.Trace, err = traceResult(); err != nil {
+				return nil, nil, nil, err
+			}
+		}
 		if result.Failed() {
 			callRes.Status = hexutil.Uint64(types.ReceiptStatusFailed)
 			if errors.Is(result.Err, vm.ErrExecutionReverted) {
@@ -320,6 +382,9 @@ func (sim *simulator) processBlock(ctx context.Context, block *simBlock, header,
 		}
 		callResults[i] = callRes
 	}
+	// System calls at the end of the block are not traced.
+	tracer.callTracer = nil
+
 	header.GasUsed = gasUsed
 	if sim.chainConfig.IsCancun(header.Number, header.Time) {
 		header.BlobGasUsed = &blobGasUsed
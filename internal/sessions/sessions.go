@@ -0,0 +1,146 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package sessions tracks resources held open by the node on behalf of remote
+// clients, such as database snapshots or debugging sessions.
+package sessions
+
+import (
+	"sync"
+	"time"
+)
+
+// Store holds a bounded number of sessions, dropping the ones left unused for
+// longer than the idle timeout.
+type Store[K comparable, V any] struct {
+	limit    int
+	idle     time.Duration
+	onExpire func(K, V)
+
+	lock     sync.Mutex
+	entries  map[K]*entry[V]
+	reserved int // slots of sessions being prepared
+}
+
+type entry[V any] struct {
+	value    V
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// New creates a store holding at most limit sessions. Sessions not accessed for
+// the idle timeout are dropped, and onExpire is called with them if not nil.
+func New[K comparable, V any](limit int, idle time.Duration, onExpire func(K, V)) *Store[K, V] {
+	return &Store[K, V]{
+		limit:    limit,
+		idle:     idle,
+		onExpire: onExpire,
+		entries:  make(map[K]*entry[V]),
+	}
+}
+
+// Reserve takes a slot for a new session, so the limit holds while the session
+// is prepared. It returns false if all slots are taken. A reserved slot must be
+// filled by Add or given up by Cancel.
+func (s *Store[K, V]) Reserve() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.entries)+s.reserved >= s.limit {
+		return false
+	}
+	s.reserved++
+	return true
+}
+
+// Cancel gives up a reserved slot.
+func (s *Store[K, V]) Cancel() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.reserved--
+}
+
+// Add stores a session in a reserved slot. If a session with the same key exists,
+// false is returned and the slot stays reserved.
+func (s *Store[K, V]) Add(key K, value V) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.entries[key]; ok {
+		return false
+	}
+	s.reserved--
+	e := &entry[V]{value: value, deadline: time.Now().Add(s.idle)}
+	e.timer = time.AfterFunc(s.idle, func() { s.expire(key, e) })
+	s.entries[key] = e
+	return true
+}
+
+// Get retrieves a session and extends its lifetime.
+func (s *Store[K, V]) Get(key K) (V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e.deadline = time.Now().Add(s.idle)
+	e.timer.Reset(s.idle)
+	return e.value, true
+}
+
+// Remove drops a session without calling onExpire.
+func (s *Store[K, V]) Remove(key K) (V, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e.timer.Stop()
+	delete(s.entries, key)
+	return e.value, true
+}
+
+// Len returns the number of sessions, including the reserved slots.
+func (s *Store[K, V]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.entries) + s.reserved
+}
+
+// expire drops a session after its idle timeout. The session might have been
+// removed in the meantime and its key reused, or accessed while the timer fired,
+// in which case it is retained.
+func (s *Store[K, V]) expire(key K, e *entry[V]) {
+	s.lock.Lock()
+	if s.entries[key] != e || time.Now().Before(e.deadline) {
+		s.lock.Unlock()
+		return
+	}
+	delete(s.entries, key)
+	s.lock.Unlock()
+
+	if s.onExpire != nil {
+		s.onExpire(key, e.value)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package sessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreLimit(t *testing.T) {
+	s := New[string, int](2, time.Minute, nil)
+
+	if !s.Reserve() || !s.Reserve() {
+		t.Fatal("failed to reserve slots")
+	}
+	if s.Reserve() {
+		t.Fatal("reserved slot beyond the limit")
+	}
+	if !s.Add("a", 1) {
+		t.Fatal("failed to add session")
+	}
+	// Adding a duplicate keeps the slot reserved
+	if s.Add("a", 2) {
+		t.Fatal("added duplicate session")
+	}
+	if n := s.Len(); n != 2 {
+		t.Fatalf("wrong number of sessions: %d", n)
+	}
+	s.Cancel()
+	if n := s.Len(); n != 1 {
+		t.Fatalf("wrong number of sessions: %d", n)
+	}
+	if v, ok := s.Get("a"); !ok || v != 1 {
+		t.Fatalf("wrong session: %d %v", v, ok)
+	}
+	if !s.Reserve() {
+		t.Fatal("failed to reserve released slot")
+	}
+	s.Cancel()
+
+	if v, ok := s.Remove("a"); !ok || v != 1 {
+		t.Fatalf("wrong removed session: %d %v", v, ok)
+	}
+	if _, ok := s.Get("a"); ok {
+		t.Fatal("removed session still present")
+	}
+	if n := s.Len(); n != 0 {
+		t.Fatalf("wrong number of sessions: %d", n)
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	expired := make(chan string, 2)
+	s := New[string, int](2, 100*time.Millisecond, func(key string, _ int) {
+		expired <- key
+	})
+	s.Reserve()
+	s.Add("a", 1)
+	s.Reserve()
+	s.Add("b", 2)
+
+	// Accessing a session extends its lifetime
+	deadline := time.Now().Add(300 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		s.Get("a")
+		time.Sleep(20 * time.Millisecond)
+	}
+	select {
+	case key := <-expired:
+		if key != "b" {
+			t.Fatalf("wrong session expired: %s", key)
+		}
+	default:
+		t.Fatal("idle session not expired")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Fatal("used session expired")
+	}
+	select {
+	case key := <-expired:
+		if key != "a" {
+			t.Fatalf("wrong session expired: %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("session not expired")
+	}
+	if n := s.Len(); n != 0 {
+		t.Fatalf("wrong number of sessions: %d", n)
+	}
+}
+
+func TestStoreExpiryReusedKey(t *testing.T) {
+	expired := make(chan int, 1)
+	s := New[string, int](1, 50*time.Millisecond, func(_ string, v int) {
+		expired <- v
+	})
+	s.Reserve()
+	s.Add("a", 1)
+	s.Remove("a")
+	s.Reserve()
+	s.Add("a", 2)
+
+	// The timer of the removed session must not drop the new one
+	select {
+	case v := <-expired:
+		if v != 2 {
+			t.Fatalf("wrong session expired: %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("session not expired")
+	}
+}
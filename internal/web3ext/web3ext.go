@@ -399,6 +399,38 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'simulateV1',
+			call: 'debug_simulateV1',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'forkSession',
+			call: 'debug_forkSession',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'forkCall',
+			call: 'debug_forkCall',
+			params: 3,
+		}),
+		new web3._extend.Method({
+			name: 'forkSendTransaction',
+			call: 'debug_forkSendTransaction',
+			params: 3,
+		}),
+		new web3._extend.Method({
+			name: 'forkSetState',
+			call: 'debug_forkSetState',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'forkClose',
+			call: 'debug_forkClose',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -453,6 +485,56 @@ web3._extend({
 			call: 'debug_dbAncients',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'dbAncientTail',
+			call: 'debug_dbAncientTail',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbAncientSize',
+			call: 'debug_dbAncientSize',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dbStats',
+			call: 'debug_dbStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbHas',
+			call: 'debug_dbHas',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dbIterate',
+			call: 'debug_dbIterate',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'dbWrite',
+			call: 'debug_dbWrite',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dbNewSnapshot',
+			call: 'debug_dbNewSnapshot',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'dbSnapshotGet',
+			call: 'debug_dbSnapshotGet',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'dbSnapshotHas',
+			call: 'debug_dbSnapshotHas',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'dbReleaseSnapshot',
+			call: 'debug_dbReleaseSnapshot',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'setTrieFlushInterval',
 			call: 'debug_setTrieFlushInterval',
@@ -137,6 +137,30 @@ type Config struct {
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// HTTPTLSCert and HTTPTLSKey are the paths of the PEM encoded certificate and
+	// private key used to serve the HTTP and websocket RPC endpoints over TLS. If
+	// they are empty, the endpoints are served in plain text.
+	HTTPTLSCert string `toml:",omitempty"`
+	HTTPTLSKey  string `toml:",omitempty"`
+
+	// HTTPTLSClientCA is the path of a PEM encoded certificate bundle. If set, the
+	// HTTP and websocket RPC endpoints only accept clients presenting a certificate
+	// signed by one of the contained authorities.
+	HTTPTLSClientCA string `toml:",omitempty"`
+
+	// HTTPBatchStreaming enables streaming the responses of batches as
+	// newline-delimited JSON to HTTP clients accepting it.
+	HTTPBatchStreaming bool `toml:",omitempty"`
+
+	// HTTPBatchStreamingMaxSize is the maximum number of bytes returned from a
+	// streamed batch. It replaces BatchResponseMaxSize for streamed batches, zero
+	// means no limit.
+	HTTPBatchStreamingMaxSize int `toml:",omitempty"`
+
+	// HTTPH2C makes the plain text HTTP RPC endpoint accept HTTP/2 clients with
+	// prior knowledge (h2c). It has no effect if TLS is enabled.
+	HTTPH2C bool `toml:",omitempty"`
+
 	// AuthAddr is the listening address on which authenticated APIs are provided.
 	AuthAddr string `toml:",omitempty"`
 
@@ -147,6 +171,16 @@ type Config struct {
 	// for the authenticated api. This is by default {'localhost'}.
 	AuthVirtualHosts []string `toml:",omitempty"`
 
+	// AuthTLSCert and AuthTLSKey are the paths of the PEM encoded certificate and
+	// private key used to serve the authenticated APIs over TLS.
+	AuthTLSCert string `toml:",omitempty"`
+	AuthTLSKey  string `toml:",omitempty"`
+
+	// AuthTLSClientCA is the path of a PEM encoded certificate bundle. If set, the
+	// authenticated APIs only accept clients presenting a certificate signed by one
+	// of the contained authorities, in addition to the JWT authentication.
+	AuthTLSClientCA string `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -176,6 +210,19 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// WSCompression enables negotiation of permessage-deflate compression with
+	// websocket clients.
+	WSCompression bool `toml:",omitempty"`
+
+	// WSSubscriptionBuffer is the number of notifications queued for each websocket
+	// subscription. If zero, notifications are written synchronously.
+	WSSubscriptionBuffer int `toml:",omitempty"`
+
+	// WSSubscriptionOverflow is the policy applied to subscribers falling behind by
+	// more than WSSubscriptionBuffer notifications, either "disconnect" (default)
+	// or "drop-oldest".
+	WSSubscriptionOverflow string `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -207,6 +254,21 @@ type Config struct {
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
+	// RPCAccess restricts the methods callable via the HTTP and websocket RPC
+	// endpoints, including the authenticated ones, per API key or JWT claim. If
+	// empty, all methods of the enabled modules are callable.
+	RPCAccess []rpc.AccessRule `toml:",omitempty"`
+
+	// RPCRateLimits configures the per-client request quotas of the public HTTP
+	// and websocket RPC endpoints. Clients are identified by their API key or
+	// their IP address. If empty, requests are not limited.
+	RPCRateLimits []rpc.RateLimitClass `toml:",omitempty"`
+
+	// RPCResultCacheSize is the maximum number of bytes of method call results
+	// cached by the HTTP and websocket RPC endpoints. Only results which can no
+	// longer change, e.g. of finalized blocks, are cached. Zero disables caching.
+	RPCResultCacheSize uint64 `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	EnablePersonal bool `toml:"-"`
 
@@ -39,6 +39,7 @@ type DatabaseOptions struct {
 	Cache            int    // the capacity(in megabytes) of the data caching
 	Handles          int    // number of files to be open simultaneously
 	ReadOnly         bool   // if true, no writes can be performed
+	TrackStats       bool   // if true, database statistics are maintained in the background
 }
 
 type internalOpenOptions struct {
@@ -62,6 +63,7 @@ func openDatabase(o internalOpenOptions) (ethdb.Database, error) {
 		Era:              o.EraDirectory,
 		MetricsNamespace: o.MetricsNamespace,
 		ReadOnly:         o.ReadOnly,
+		TrackStats:       o.TrackStats,
 	}
 	frdb, err := rawdb.Open(kvdb, opts)
 	if err != nil {
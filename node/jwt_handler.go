@@ -17,10 +17,12 @@
 package node
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/golang-jwt/jwt/v4"
 )
 
@@ -75,6 +77,33 @@ func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
 	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
 		http.Error(out, "future token", http.StatusUnauthorized)
 	default:
+		if claims := stringClaims(strToken); len(claims) > 0 {
+			r = r.WithContext(rpc.ContextWithJWTClaims(r.Context(), claims))
+		}
 		handler.next.ServeHTTP(out, r)
 	}
 }
+
+// stringClaims returns the string valued claims of a verified token, which are
+// used by the RPC access policy to identify the client.
+func stringClaims(token string) map[string]string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return nil
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil
+	}
+	claims := make(map[string]string)
+	for name, value := range raw {
+		if s, ok := value.(string); ok {
+			claims[name] = s
+		}
+	}
+	return claims
+}
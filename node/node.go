@@ -57,14 +57,15 @@ type Node struct {
 	state         int           // Tracks state of node lifecycle
 
 	lock          sync.Mutex
-	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	http          *httpServer //
-	ws            *httpServer //
-	httpAuth      *httpServer //
-	wsAuth        *httpServer //
-	ipc           *ipcServer  // Stores information about the ipc http server
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	lifecycles    []Lifecycle      // All registered backends, services, and auxiliary services that have a lifecycle
+	rpcAPIs       []rpc.API        // List of APIs currently provided by the node
+	http          *httpServer      //
+	ws            *httpServer      //
+	httpAuth      *httpServer      //
+	wsAuth        *httpServer      //
+	ipc           *ipcServer       // Stores information about the ipc http server
+	inprocHandler *rpc.Server      // In-process RPC request handler to process the API requests
+	rpcCache      *rpc.ResultCache // Cache of final RPC results shared by the HTTP and WS endpoints
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -108,6 +109,7 @@ func New(conf *Config) (*Node, error) {
 	node := &Node{
 		config:        conf,
 		inprocHandler: server,
+		rpcCache:      rpc.NewResultCache(conf.RPCResultCacheSize),
 		eventmux:      new(event.TypeMux),
 		log:           conf.Logger,
 		stop:          make(chan struct{}),
@@ -390,20 +392,52 @@ func (n *Node) startRPC() error {
 		openAPIs, allAPIs = n.getAPIs()
 	)
 
+	accessPolicy, err := rpc.NewAccessPolicy(n.config.RPCAccess)
+	if err != nil {
+		return err
+	}
+	rateLimiter, err := rpc.NewRateLimiter(n.config.RPCRateLimits)
+	if err != nil {
+		return err
+	}
+	subOverflow := rpc.OverflowDisconnect
+	if n.config.WSSubscriptionOverflow != "" {
+		subOverflow = rpc.SubscriptionOverflow(n.config.WSSubscriptionOverflow)
+		if !subOverflow.Valid() {
+			return fmt.Errorf("invalid websocket subscription overflow policy %q", n.config.WSSubscriptionOverflow)
+		}
+	}
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		accessPolicy:           accessPolicy,
+		rateLimiter:            rateLimiter,
+		resultCache:            n.rpcCache,
+	}
+	httpTLS, err := newTLSConfig(n.config.HTTPTLSCert, n.config.HTTPTLSKey, n.config.HTTPTLSClientCA)
+	if err != nil {
+		return err
+	}
+	authTLS, err := newTLSConfig(n.config.AuthTLSCert, n.config.AuthTLSKey, n.config.AuthTLSClientCA)
+	if err != nil {
+		return err
 	}
 
 	initHttp := func(server *httpServer, port int) error {
 		if err := server.setListenAddr(n.config.HTTPHost, port); err != nil {
 			return err
 		}
+		if err := server.setTLS(httpTLS); err != nil {
+			return err
+		}
 		if err := server.enableRPC(openAPIs, httpConfig{
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			streamBatch:        n.config.HTTPBatchStreaming,
+			streamBatchLimit:   n.config.HTTPBatchStreamingMaxSize,
+			h2c:                n.config.HTTPH2C,
 			rpcEndpointConfig:  rpcConfig,
 		}); err != nil {
 			return err
@@ -417,10 +451,16 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.WSHost, port); err != nil {
 			return err
 		}
+		if err := server.setTLS(httpTLS); err != nil {
+			return err
+		}
 		if err := server.enableWS(openAPIs, wsConfig{
 			Modules:           n.config.WSModules,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
+			compression:       n.config.WSCompression,
+			subBuffer:         n.config.WSSubscriptionBuffer,
+			subOverflow:       subOverflow,
 			rpcEndpointConfig: rpcConfig,
 		}); err != nil {
 			return err
@@ -435,11 +475,15 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.AuthAddr, port); err != nil {
 			return err
 		}
+		if err := server.setTLS(authTLS); err != nil {
+			return err
+		}
 		sharedConfig := rpcEndpointConfig{
 			jwtSecret:              secret,
 			batchItemLimit:         engineAPIBatchItemLimit,
 			batchResponseSizeLimit: engineAPIBatchResponseSizeLimit,
 			httpBodyLimit:          engineAPIBodyLimit,
+			accessPolicy:           accessPolicy,
 		}
 		err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
@@ -458,6 +502,9 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.AuthAddr, port); err != nil {
 			return err
 		}
+		if err := server.setTLS(authTLS); err != nil {
+			return err
+		}
 		if err := server.enableWS(allAPIs, wsConfig{
 			Modules:           DefaultAuthModules,
 			Origins:           DefaultAuthOrigins,
@@ -579,6 +626,15 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// APIs returns all APIs registered on the node, including the ones that require
+// authentication.
+func (n *Node) APIs() []rpc.API {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return slices.Clone(n.rpcAPIs)
+}
+
 // getAPIs return two sets of APIs, both the ones that do not require
 // authentication, and the complete set
 func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
@@ -622,6 +678,12 @@ func (n *Node) RPCHandler() (*rpc.Server, error) {
 	return n.inprocHandler, nil
 }
 
+// RPCResultCache returns the cache of final RPC results, or nil if caching is
+// disabled. Services rewinding the chain must purge it.
+func (n *Node) RPCResultCache() *rpc.ResultCache {
+	return n.rpcCache
+}
+
 // Config returns the configuration of node.
 func (n *Node) Config() *Config {
 	return n.config
@@ -666,28 +728,28 @@ func (n *Node) IPCEndpoint() string {
 // HTTPEndpoint returns the URL of the HTTP server. Note that this URL does not
 // contain the JSON-RPC path prefix set by HTTPPathPrefix.
 func (n *Node) HTTPEndpoint() string {
-	return "http://" + n.http.listenAddr()
+	return n.http.scheme("http") + "://" + n.http.listenAddr()
 }
 
 // WSEndpoint returns the current JSON-RPC over WebSocket endpoint.
 func (n *Node) WSEndpoint() string {
 	if n.http.wsAllowed() {
-		return "ws://" + n.http.listenAddr() + n.http.wsConfig.prefix
+		return n.http.scheme("ws") + "://" + n.http.listenAddr() + n.http.wsConfig.prefix
 	}
-	return "ws://" + n.ws.listenAddr() + n.ws.wsConfig.prefix
+	return n.ws.scheme("ws") + "://" + n.ws.listenAddr() + n.ws.wsConfig.prefix
 }
 
 // HTTPAuthEndpoint returns the URL of the authenticated HTTP server.
 func (n *Node) HTTPAuthEndpoint() string {
-	return "http://" + n.httpAuth.listenAddr()
+	return n.httpAuth.scheme("http") + "://" + n.httpAuth.listenAddr()
 }
 
 // WSAuthEndpoint returns the current authenticated JSON-RPC over WebSocket endpoint.
 func (n *Node) WSAuthEndpoint() string {
 	if n.httpAuth.wsAllowed() {
-		return "ws://" + n.httpAuth.listenAddr() + n.httpAuth.wsConfig.prefix
+		return n.httpAuth.scheme("ws") + "://" + n.httpAuth.listenAddr() + n.httpAuth.wsConfig.prefix
 	}
-	return "ws://" + n.wsAuth.listenAddr() + n.wsAuth.wsConfig.prefix
+	return n.wsAuth.scheme("ws") + "://" + n.wsAuth.listenAddr() + n.wsAuth.wsConfig.prefix
 }
 
 // EventMux retrieves the event multiplexer used by all the network services in
@@ -235,3 +235,84 @@ func offsetTimeAuth(secret [32]byte, offset time.Duration) rpc.HTTPAuth {
 		return nil
 	}
 }
+
+func claimAuth(secret [32]byte, claims jwt.MapClaims) rpc.HTTPAuth {
+	return func(header http.Header) error {
+		claims["iat"] = &jwt.NumericDate{Time: time.Now()}
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		s, err := token.SignedString(secret[:])
+		if err != nil {
+			return fmt.Errorf("failed to create JWT token: %w", err)
+		}
+		header.Set("Authorization", "Bearer "+s)
+		return nil
+	}
+}
+
+// Tests that the access policy restricts methods per JWT claim on the
+// authenticated endpoints and per API key on the public ones.
+func TestAuthEndpointsAccessPolicy(t *testing.T) {
+	var secret [32]byte
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatalf("failed to create jwt secret: %v", err)
+	}
+	jwtPath := filepath.Join(t.TempDir(), "jwt_secret")
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatalf("failed to prepare jwt secret file: %v", err)
+	}
+	conf := &Config{
+		HTTPHost:    "127.0.0.1",
+		WSHost:      "127.0.0.1",
+		AuthAddr:    "127.0.0.1",
+		JWTSecret:   jwtPath,
+		HTTPModules: []string{"eth"},
+		WSModules:   []string{"eth"},
+		RPCAccess: []rpc.AccessRule{
+			{Name: "consensus", Claims: map[string]string{"id": "beacon"}},
+			{Name: "partner", APIKeys: []string{"secret-key"}, Allow: []string{"eth_*"}},
+			{Name: "public", Allow: []string{"rpc_*"}},
+		},
+	}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs([]rpc.API{
+		{Namespace: "engine", Service: helloRPC("hello engine"), Authenticated: true},
+		{Namespace: "eth", Service: helloRPC("hello eth")},
+	})
+	if err := node.Start(); err != nil {
+		t.Fatalf("failed to start test node: %v", err)
+	}
+	defer node.Close()
+
+	call := func(endpoint, method string, opts ...rpc.ClientOption) error {
+		client, err := rpc.DialOptions(context.Background(), endpoint, opts...)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		var result string
+		return client.Call(&result, method)
+	}
+	for _, endpoint := range []string{node.HTTPAuthEndpoint(), node.WSAuthEndpoint()} {
+		if err := call(endpoint, "engine_helloWorld", rpc.WithHTTPAuth(claimAuth(secret, jwt.MapClaims{"id": "beacon"}))); err != nil {
+			t.Errorf("%s: call with permitted claim failed: %v", endpoint, err)
+		}
+		if err := call(endpoint, "engine_helloWorld", rpc.WithHTTPAuth(claimAuth(secret, jwt.MapClaims{"id": "other"}))); err == nil {
+			t.Errorf("%s: call with unknown claim succeeded", endpoint)
+		}
+	}
+	for _, endpoint := range []string{node.HTTPEndpoint(), node.WSEndpoint()} {
+		if err := call(endpoint, "eth_helloWorld"); err == nil {
+			t.Errorf("%s: anonymous call succeeded", endpoint)
+		}
+		if err := call(endpoint, "eth_helloWorld", rpc.WithHeader(rpc.APIKeyHeader, "secret-key")); err != nil {
+			t.Errorf("%s: call with API key failed: %v", endpoint, err)
+		}
+		if err := call(endpoint+"/key/secret-key", "eth_helloWorld"); err != nil {
+			t.Errorf("%s: call with API key in path failed: %v", endpoint, err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package node
 import (
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -42,14 +43,20 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
+	streamBatch        bool   // stream batch responses as newline-delimited JSON
+	streamBatchLimit   int    // maximum size of streamed batch responses, zero for unlimited
+	h2c                bool   // accept HTTP/2 clients with prior knowledge on plain text
 	rpcEndpointConfig
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
 type wsConfig struct {
-	Origins []string
-	Modules []string
-	prefix  string // path prefix on which to mount ws handler
+	Origins     []string
+	Modules     []string
+	prefix      string // path prefix on which to mount ws handler
+	compression bool   // negotiate permessage-deflate
+	subBuffer   int    // notifications queued per subscription
+	subOverflow rpc.SubscriptionOverflow
 	rpcEndpointConfig
 }
 
@@ -58,6 +65,9 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	accessPolicy           *rpc.AccessPolicy // optional per-method access policy
+	rateLimiter            *rpc.RateLimiter  // optional per-client request quotas
+	resultCache            *rpc.ResultCache  // optional cache of final call results
 }
 
 type rpcHandler struct {
@@ -71,9 +81,10 @@ type httpServer struct {
 	timeouts rpc.HTTPTimeouts
 	mux      http.ServeMux // registered handlers go here
 
-	mu       sync.Mutex
-	server   *http.Server
-	listener net.Listener // non-nil when server is running
+	mu        sync.Mutex
+	server    *http.Server
+	listener  net.Listener // non-nil when server is running
+	tlsConfig *tls.Config  // non-nil when served over TLS
 
 	// HTTP RPC handler things.
 
@@ -116,6 +127,34 @@ func (h *httpServer) setListenAddr(host string, port int) error {
 	return nil
 }
 
+// setTLS configures the TLS settings of the server, a nil config disables TLS.
+// The settings can only be changed while the server isn't running.
+func (h *httpServer) setTLS(config *tls.Config) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.listener != nil && config != h.tlsConfig {
+		return fmt.Errorf("HTTP server already running on %s", h.endpoint)
+	}
+	h.tlsConfig = config
+	return nil
+}
+
+// scheme returns the URL scheme of the server for the given protocol, which
+// is either "http" or "ws".
+func (h *httpServer) scheme(proto string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tlsConfig != nil {
+		if proto == "ws" {
+			return "wss"
+		}
+		return "https"
+	}
+	return proto
+}
+
 // listenAddr returns the listening address of the server.
 func (h *httpServer) listenAddr() string {
 	h.mu.Lock()
@@ -145,6 +184,13 @@ func (h *httpServer) start() error {
 		h.server.WriteTimeout = h.timeouts.WriteTimeout
 		h.server.IdleTimeout = h.timeouts.IdleTimeout
 	}
+	if h.tlsConfig == nil && h.httpConfig.h2c {
+		// Accept HTTP/2 clients with prior knowledge on plain text endpoints (h2c),
+		// allowing them to multiplex streamed batch responses.
+		h.server.Protocols = new(http.Protocols)
+		h.server.Protocols.SetHTTP1(true)
+		h.server.Protocols.SetUnencryptedHTTP2(true)
+	}
 
 	// Start the server.
 	listener, err := net.Listen("tcp", h.endpoint)
@@ -155,11 +201,16 @@ func (h *httpServer) start() error {
 		h.disableWS()
 		return err
 	}
+	httpScheme, wsScheme := "http", "ws"
+	if h.tlsConfig != nil {
+		listener = tls.NewListener(listener, h.tlsConfig)
+		httpScheme, wsScheme = "https", "wss"
+	}
 	h.listener = listener
 	go h.server.Serve(listener)
 
 	if h.wsAllowed() {
-		url := fmt.Sprintf("ws://%v", listener.Addr())
+		url := fmt.Sprintf("%s://%v", wsScheme, listener.Addr())
 		if h.wsConfig.prefix != "" {
 			url += h.wsConfig.prefix
 		}
@@ -172,6 +223,7 @@ func (h *httpServer) start() error {
 	// Log http endpoint.
 	h.log.Info("HTTP server started",
 		"endpoint", listener.Addr(), "auth", h.httpConfig.jwtSecret != nil,
+		"tls", h.tlsConfig != nil, "mtls", h.tlsConfig != nil && h.tlsConfig.ClientCAs != nil,
 		"prefix", h.httpConfig.prefix,
 		"cors", strings.Join(h.httpConfig.CorsAllowedOrigins, ","),
 		"vhosts", strings.Join(h.httpConfig.Vhosts, ","),
@@ -187,7 +239,7 @@ func (h *httpServer) start() error {
 	for _, path := range paths {
 		name := h.handlerNames[path]
 		if !logged[name] {
-			log.Info(name+" enabled", "url", "http://"+listener.Addr().String()+path)
+			log.Info(name+" enabled", "url", httpScheme+"://"+listener.Addr().String()+path)
 			logged[name] = true
 		}
 	}
@@ -227,9 +279,10 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // checkPath checks whether a given request URL matches a given path prefix.
 func checkPath(r *http.Request, path string) bool {
-	// if no prefix has been specified, request URL must be on root
+	// if no prefix has been specified, request URL must be on root or carry
+	// an API key in the path
 	if path == "" {
-		return r.URL.Path == "/"
+		return r.URL.Path == "/" || strings.HasPrefix(r.URL.Path, "/key/")
 	}
 	// otherwise, check to make sure prefix matches
 	return len(r.URL.Path) >= len(path) && r.URL.Path[:len(path)] == path
@@ -304,6 +357,10 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetAccessPolicy(config.accessPolicy)
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetResultCache(config.resultCache)
+	srv.SetHTTPBatchStreaming(config.streamBatch, config.streamBatchLimit)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
@@ -340,6 +397,11 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetAccessPolicy(config.accessPolicy)
+	srv.SetRateLimiter(config.rateLimiter)
+	srv.SetResultCache(config.resultCache)
+	srv.SetWebsocketCompression(config.compression)
+	srv.SetSubscriptionBuffer(config.subBuffer, config.subOverflow)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
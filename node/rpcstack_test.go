@@ -611,3 +611,49 @@ func (s *testService) Greet() string {
 func (s *testService) Sleep() {
 	time.Sleep(1500 * time.Millisecond)
 }
+
+// TestHTTPBatchStreamingH2C checks that plain text HTTP endpoints accept HTTP/2
+// clients with prior knowledge if enabled, and stream batch responses to them.
+func TestHTTPBatchStreamingH2C(t *testing.T) {
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: transport}
+
+	post := func(srv *httpServer) (*http.Response, error) {
+		body := `[{"jsonrpc":"2.0","id":1,"method":"test_greet","params":[]},{"jsonrpc":"2.0","id":2,"method":"test_greet","params":[]}]`
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%v", srv.listenAddr()), strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("accept", "application/x-ndjson")
+		return client.Do(req)
+	}
+	// Both h2c and streaming are disabled by default.
+	srv := createAndStartServer(t, &httpConfig{Modules: []string{"test"}}, false, &wsConfig{}, nil)
+	if resp, err := post(srv); err == nil {
+		resp.Body.Close()
+		t.Fatalf("h2c request accepted by default")
+	}
+	srv.stop()
+
+	srv = createAndStartServer(t, &httpConfig{Modules: []string{"test"}, streamBatch: true, h2c: true}, false, &wsConfig{}, nil)
+	defer srv.stop()
+	resp, err := post(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("wrong protocol %s", resp.Proto)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"jsonrpc":"2.0","id":1,"result":"Hello"}` + "\n" + `{"jsonrpc":"2.0","id":2,"result":"Hello"}` + "\n"
+	if string(respBody) != want {
+		t.Fatalf("wrong response:\nhave %s\nwant %s", respBody, want)
+	}
+}
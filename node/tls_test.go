@@ -0,0 +1,243 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+// testCA is a locally generated certificate authority issuing the server and
+// client certificates of the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	dir  string
+	path string // PEM encoded CA certificate
+	next int64
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &testCA{cert: cert, key: key, pool: x509.NewCertPool(), dir: t.TempDir(), next: 2}
+	ca.pool.AddCert(cert)
+	ca.path = filepath.Join(ca.dir, "ca.pem")
+	writePEM(t, ca.path, "CERTIFICATE", der)
+	return ca
+}
+
+// issue creates a certificate signed by the CA, returning the paths of the
+// certificate and key files along with the loaded key pair.
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string, tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(ca.next),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	ca.next++
+
+	der, err := x509.CreateCertificate(crand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, keyPath := filepath.Join(ca.dir, name+".pem"), filepath.Join(ca.dir, name+".key")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDer)
+
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath, pair
+}
+
+func writePEM(t *testing.T, path string, kind string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tlsOptions returns the client options for connecting to a TLS endpoint with
+// the given client configuration.
+func tlsOptions(config *tls.Config) []rpc.ClientOption {
+	return []rpc.ClientOption{
+		rpc.WithHTTPClient(&http.Client{Transport: &http.Transport{TLSClientConfig: config}}),
+		rpc.WithWebsocketDialer(websocket.Dialer{TLSClientConfig: config}),
+	}
+}
+
+func TestTLSEndpoints(t *testing.T) {
+	var (
+		ca                       = newTestCA(t)
+		serverCert, serverKey, _ = ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+		_, _, clientPair         = ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+		jwtPath                  = filepath.Join(t.TempDir(), "jwt_secret")
+		secret                   [32]byte
+		trusted                  = &tls.Config{RootCAs: ca.pool}
+		trustedWithCert          = &tls.Config{RootCAs: ca.pool, Certificates: []tls.Certificate{clientPair}}
+		ctx                      = context.Background()
+	)
+	if _, err := crand.Read(secret[:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jwtPath, []byte(hexutil.Encode(secret[:])), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conf := &Config{
+		HTTPHost:        "127.0.0.1",
+		WSHost:          "127.0.0.1",
+		HTTPTLSCert:     serverCert,
+		HTTPTLSKey:      serverKey,
+		AuthAddr:        "127.0.0.1",
+		AuthTLSCert:     serverCert,
+		AuthTLSKey:      serverKey,
+		AuthTLSClientCA: ca.path,
+		JWTSecret:       jwtPath,
+		HTTPModules:     []string{"eth"},
+		WSModules:       []string{"eth"},
+	}
+	node, err := New(conf)
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs([]rpc.API{
+		{Namespace: "engine", Service: helloRPC("hello engine"), Authenticated: true},
+		{Namespace: "eth", Service: helloRPC("hello eth")},
+	})
+	if err := node.Start(); err != nil {
+		t.Fatalf("failed to start test node: %v", err)
+	}
+	defer node.Close()
+
+	call := func(endpoint, method string, opts ...rpc.ClientOption) error {
+		t.Helper()
+		client, err := rpc.DialOptions(ctx, endpoint, opts...)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		var result string
+		return client.CallContext(ctx, &result, method)
+	}
+	// The public endpoints should be served over TLS
+	for _, endpoint := range []string{node.HTTPEndpoint(), node.WSEndpoint()} {
+		if !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "wss://") {
+			t.Fatalf("endpoint %s not served over TLS", endpoint)
+		}
+		if err := call(endpoint, "eth_helloWorld", tlsOptions(trusted)...); err != nil {
+			t.Fatalf("%s: call failed: %v", endpoint, err)
+		}
+		if err := call(endpoint, "eth_helloWorld"); err == nil {
+			t.Fatalf("%s: call succeeded without trusting the server certificate", endpoint)
+		}
+	}
+	if err := call(strings.Replace(node.HTTPEndpoint(), "https://", "http://", 1), "eth_helloWorld"); err == nil {
+		t.Fatal("plain text call to TLS endpoint succeeded")
+	}
+	// The authenticated endpoints additionally require a client certificate
+	auth := rpc.WithHTTPAuth(NewJWTAuth(secret))
+	for _, endpoint := range []string{node.HTTPAuthEndpoint(), node.WSAuthEndpoint()} {
+		if err := call(endpoint, "engine_helloWorld", append(tlsOptions(trustedWithCert), auth)...); err != nil {
+			t.Fatalf("%s: call failed: %v", endpoint, err)
+		}
+		if err := call(endpoint, "engine_helloWorld", append(tlsOptions(trusted), auth)...); err == nil {
+			t.Fatalf("%s: call succeeded without client certificate", endpoint)
+		}
+	}
+}
+
+func TestTLSConfigValidation(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key, _ := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+
+	tests := []struct {
+		cert, key, clientCA string
+		fail                bool
+	}{
+		{"", "", "", false},
+		{cert, key, "", false},
+		{cert, key, ca.path, false},
+		{cert, "", "", true},
+		{"", key, "", true},
+		{"", "", ca.path, true},
+		{cert, key, key, true},
+		{cert, cert, "", true},
+	}
+	for i, tt := range tests {
+		_, err := newTLSConfig(tt.cert, tt.key, tt.clientCA)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+		}
+	}
+}
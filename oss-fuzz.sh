@@ -220,6 +220,14 @@ compile_fuzzer github.com/ethereum/go-ethereum/tests/fuzzers/bls12381 \
   FuzzG2SubgroupChecks fuzz_g2_subgroup_checks\
   $repo/tests/fuzzers/bls12381/bls12381_test.go
 
+compile_fuzzer github.com/ethereum/go-ethereum/tests/fuzzers/differential \
+  FuzzTracer fuzz_differential_tracer \
+  $repo/tests/fuzzers/differential/differential_test.go
+
+compile_fuzzer github.com/ethereum/go-ethereum/tests/fuzzers/differential \
+  FuzzBlockAnalysis fuzz_differential_block_analysis \
+  $repo/tests/fuzzers/differential/differential_test.go
+
 compile_fuzzer github.com/ethereum/go-ethereum/tests/fuzzers/secp256k1 \
   Fuzz fuzzSecp256k1\
   $repo/tests/fuzzers/secp256k1/secp_test.go
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params/forks"
@@ -468,6 +469,10 @@ type ChainConfig struct {
 	AmsterdamTime *uint64 `json:"amsterdamTime,omitempty"` // Amsterdam switch time (nil = no fork, 0 = already on amsterdam)
 	VerkleTime    *uint64 `json:"verkleTime,omitempty"`    // Verkle switch time (nil = no fork, 0 = already on verkle)
 
+	// EOFTime enables the EVM Object Format (EIP-7692) on top of Prague. It is
+	// experimental and not scheduled on any public network.
+	EOFTime *uint64 `json:"eofTime,omitempty"` // EOF switch time (nil = no fork, 0 = already on eof)
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -491,6 +496,48 @@ type ChainConfig struct {
 	Ethash             *EthashConfig       `json:"ethash,omitempty"`
 	Clique             *CliqueConfig       `json:"clique,omitempty"`
 	BlobScheduleConfig *BlobScheduleConfig `json:"blobSchedule,omitempty"`
+
+	// Precompiles schedules custom precompiled contracts, which are implemented
+	// in Go and registered with the EVM by the embedding application.
+	Precompiles map[common.Address]*PrecompileConfig `json:"precompiles,omitempty"`
+}
+
+// PrecompileConfig schedules the activation of a custom precompiled contract.
+type PrecompileConfig struct {
+	Name  string   `json:"name"`            // Name the contract is registered with in the EVM
+	Block *big.Int `json:"block,omitempty"` // Activation block (nil = not block based)
+	Time  *uint64  `json:"time,omitempty"`  // Activation time (nil = not time based)
+}
+
+// IsActive returns whether the contract is active at the given block.
+func (p *PrecompileConfig) IsActive(num *big.Int, time uint64) bool {
+	return isBlockForked(p.Block, num) || isTimestampForked(p.Time, time)
+}
+
+// String implements the stringer interface.
+func (p *PrecompileConfig) String() string {
+	if p.Block != nil {
+		return fmt.Sprintf("%s #%v", p.Name, p.Block)
+	}
+	if p.Time != nil {
+		return fmt.Sprintf("%s @%v", p.Name, *p.Time)
+	}
+	return fmt.Sprintf("%s (inactive)", p.Name)
+}
+
+// sortedPrecompiles returns the addresses of the custom precompiles of the given
+// configs in ascending order.
+func sortedPrecompiles(configs ...*ChainConfig) []common.Address {
+	var addrs []common.Address
+	for _, c := range configs {
+		for addr := range c.Precompiles {
+			if !slices.Contains(addrs, addr) {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	slices.SortFunc(addrs, common.Address.Cmp)
+	return addrs
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -598,6 +645,9 @@ func (c *ChainConfig) String() string {
 	if c.VerkleTime != nil {
 		result += fmt.Sprintf(", VerkleTime: %v", *c.VerkleTime)
 	}
+	if c.EOFTime != nil {
+		result += fmt.Sprintf(", EOFTime: %v", *c.EOFTime)
+	}
 	result += "}"
 	return result
 }
@@ -693,6 +743,15 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleTime != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v blob: (%s)\n", *c.VerkleTime, c.BlobScheduleConfig.Verkle)
 	}
+	if c.EOFTime != nil {
+		banner += fmt.Sprintf(" - EOF (experimental):          @%-10v\n", *c.EOFTime)
+	}
+	if len(c.Precompiles) > 0 {
+		banner += "\nCustom precompiles:\n"
+		for _, addr := range sortedPrecompiles(c) {
+			banner += fmt.Sprintf(" - %v: %v\n", addr, c.Precompiles[addr])
+		}
+	}
 	banner += fmt.Sprintf("\nAll fork specifications can be found at https://ethereum.github.io/execution-specs/src/ethereum/forks/\n")
 	return banner
 }
@@ -871,6 +930,11 @@ func (c *ChainConfig) IsVerkle(num *big.Int, time uint64) bool {
 	return c.IsLondon(num) && isTimestampForked(c.VerkleTime, time)
 }
 
+// IsEOF returns whether time is either equal to the EOF fork time or greater.
+func (c *ChainConfig) IsEOF(num *big.Int, time uint64) bool {
+	return c.IsPrague(num, time) && isTimestampForked(c.EOFTime, time)
+}
+
 // IsVerkleGenesis checks whether the verkle fork is activated at the genesis block.
 //
 // Verkle mode is considered enabled if the verkle fork time is configured,
@@ -987,6 +1051,15 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// EOF is an extension of Prague, it can't be enabled before.
+	if c.EOFTime != nil {
+		if c.PragueTime == nil {
+			return fmt.Errorf("unsupported fork ordering: pragueTime not enabled, but eofTime enabled at timestamp %v", *c.EOFTime)
+		}
+		if *c.PragueTime > *c.EOFTime {
+			return fmt.Errorf("unsupported fork ordering: pragueTime enabled at timestamp %v, but eofTime enabled at timestamp %v", *c.PragueTime, *c.EOFTime)
+		}
+	}
 
 	// Check that all forks with blobs explicitly define the blob schedule configuration.
 	bsc := c.BlobScheduleConfig
@@ -1107,6 +1180,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if isForkTimestampIncompatible(c.EOFTime, newcfg.EOFTime, headTimestamp) {
+		return newTimestampCompatError("EOF fork timestamp", c.EOFTime, newcfg.EOFTime)
+	}
 	if isForkTimestampIncompatible(c.BPO1Time, newcfg.BPO1Time, headTimestamp) {
 		return newTimestampCompatError("BPO1 fork timestamp", c.BPO1Time, newcfg.BPO1Time)
 	}
@@ -1125,6 +1201,27 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.AmsterdamTime, newcfg.AmsterdamTime, headTimestamp) {
 		return newTimestampCompatError("Amsterdam fork timestamp", c.AmsterdamTime, newcfg.AmsterdamTime)
 	}
+	for _, addr := range sortedPrecompiles(c, newcfg) {
+		var stored, next PrecompileConfig
+		if p := c.Precompiles[addr]; p != nil {
+			stored = *p
+		}
+		if p := newcfg.Precompiles[addr]; p != nil {
+			next = *p
+		}
+		if isForkBlockIncompatible(stored.Block, next.Block, headNumber) {
+			return newBlockCompatError(fmt.Sprintf("precompile %v activation block", addr), stored.Block, next.Block)
+		}
+		if isForkTimestampIncompatible(stored.Time, next.Time, headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("precompile %v activation timestamp", addr), stored.Time, next.Time)
+		}
+		if stored.Name != next.Name && stored.IsActive(headNumber, headTimestamp) {
+			if stored.Block != nil {
+				return newBlockCompatError(fmt.Sprintf("precompile %v name", addr), stored.Block, stored.Block)
+			}
+			return newTimestampCompatError(fmt.Sprintf("precompile %v name", addr), stored.Time, stored.Time)
+		}
+	}
 	return nil
 }
 
@@ -1381,7 +1478,11 @@ type Rules struct {
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague, IsOsaka        bool
-	IsAmsterdam, IsVerkle                                   bool
+	IsAmsterdam, IsVerkle, IsEOF                            bool
+
+	// Precompiles maps the addresses of the active custom precompiles to the
+	// names they are registered with in the EVM.
+	Precompiles map[common.Address]string
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1393,6 +1494,16 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 	// disallow setting Merge out of order
 	isMerge = isMerge && c.IsLondon(num)
 	isVerkle := isMerge && c.IsVerkle(num, timestamp)
+
+	var precompiles map[common.Address]string
+	for addr, p := range c.Precompiles {
+		if p.IsActive(num, timestamp) {
+			if precompiles == nil {
+				precompiles = make(map[common.Address]string)
+			}
+			precompiles[addr] = p.Name
+		}
+	}
 	return Rules{
 		ChainID:          new(big.Int).Set(chainID),
 		IsHomestead:      c.IsHomestead(num),
@@ -1414,5 +1525,7 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsAmsterdam:      isMerge && c.IsAmsterdam(num, timestamp),
 		IsVerkle:         isVerkle,
 		IsEIP4762:        isVerkle,
+		IsEOF:            isMerge && c.IsEOF(num, timestamp),
+		Precompiles:      precompiles,
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,6 +111,34 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:        &ChainConfig{},
+			new:           &ChainConfig{Precompiles: map[common.Address]*PrecompileConfig{{0x01, 0x00}: {Name: "a", Time: newUint64(20)}}},
+			headTimestamp: 10,
+			wantErr:       nil,
+		},
+		{
+			stored:        &ChainConfig{Precompiles: map[common.Address]*PrecompileConfig{{0x01, 0x00}: {Name: "a", Time: newUint64(10)}}},
+			new:           &ChainConfig{Precompiles: map[common.Address]*PrecompileConfig{{0x01, 0x00}: {Name: "a", Time: newUint64(20)}}},
+			headTimestamp: 15,
+			wantErr: &ConfigCompatError{
+				What:         "precompile 0x0100000000000000000000000000000000000000 activation timestamp",
+				StoredTime:   newUint64(10),
+				NewTime:      newUint64(20),
+				RewindToTime: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{Precompiles: map[common.Address]*PrecompileConfig{{0x01, 0x00}: {Name: "a", Block: big.NewInt(10)}}},
+			new:       &ChainConfig{Precompiles: map[common.Address]*PrecompileConfig{{0x01, 0x00}: {Name: "b", Block: big.NewInt(10)}}},
+			headBlock: 15,
+			wantErr: &ConfigCompatError{
+				What:          "precompile 0x0100000000000000000000000000000000000000 name",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -139,6 +168,45 @@ func TestConfigRules(t *testing.T) {
 	}
 }
 
+func TestConfigRulesPrecompiles(t *testing.T) {
+	c := &ChainConfig{
+		Precompiles: map[common.Address]*PrecompileConfig{
+			{0x01, 0x00}: {Name: "block", Block: big.NewInt(10)},
+			{0x01, 0x01}: {Name: "time", Time: newUint64(500)},
+		},
+	}
+	if r := c.Rules(big.NewInt(9), false, 499); len(r.Precompiles) != 0 {
+		t.Errorf("expected no active precompiles, have %v", r.Precompiles)
+	}
+	if r := c.Rules(big.NewInt(10), false, 499); len(r.Precompiles) != 1 || r.Precompiles[common.Address{0x01, 0x00}] != "block" {
+		t.Errorf("expected block based precompile to be active, have %v", r.Precompiles)
+	}
+	if r := c.Rules(big.NewInt(10), false, 500); len(r.Precompiles) != 2 || r.Precompiles[common.Address{0x01, 0x01}] != "time" {
+		t.Errorf("expected both precompiles to be active, have %v", r.Precompiles)
+	}
+}
+
+func TestConfigRulesEOF(t *testing.T) {
+	c := *MergedTestChainConfig
+	c.EOFTime = newUint64(1000)
+	if err := c.CheckConfigForkOrder(); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.Rules(big.NewInt(0), true, 999); r.IsEOF {
+		t.Errorf("expected %v to not be eof", 999)
+	}
+	if r := c.Rules(big.NewInt(0), true, 1000); !r.IsEOF {
+		t.Errorf("expected %v to be eof", 1000)
+	}
+	if r := c.Rules(big.NewInt(0), false, 1000); r.IsEOF {
+		t.Errorf("expected pre-merge rules to not be eof")
+	}
+	c.PragueTime, c.OsakaTime = newUint64(2000), nil
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Errorf("expected error for eof before prague")
+	}
+}
+
 func TestTimestampCompatError(t *testing.T) {
 	require.Equal(t, new(ConfigCompatError).Error(), "")
 
@@ -0,0 +1,228 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	// APIKeyHeader is the HTTP header carrying the API key of a client.
+	APIKeyHeader = "X-Api-Key"
+
+	// apiKeyPathSegment precedes the API key if it's passed in the URL path
+	// of the endpoint, e.g. http://localhost:8545/key/<token>.
+	apiKeyPathSegment = "/key/"
+
+	// subscriptionPatternSeparator separates the subscribe method and the name
+	// of the subscription in method patterns, e.g. "eth_subscribe/logs".
+	subscriptionPatternSeparator = "/"
+)
+
+// AccessRule grants a group of clients access to a set of methods. Clients are
+// identified by their API key or by the claims of their JWT token. A rule that
+// specifies neither applies to all clients not matched by any other rule.
+//
+// Subscriptions are matched by the subscribe method, and by the subscribe method
+// followed by a slash and the subscription name, e.g. "eth_subscribe/newHeads".
+// Patterns like "eth_*" don't match across the slash, so they apply to all
+// subscriptions of the namespace.
+type AccessRule struct {
+	Name    string            // Name of the client group, used in logs and errors
+	APIKeys []string          `toml:",omitempty"` // API keys identifying the clients
+	Claims  map[string]string `toml:",omitempty"` // JWT claims identifying the clients, all must match
+	Allow   []string          `toml:",omitempty"` // Allowed method patterns (e.g. "eth_*"), empty allows all
+	Deny    []string          `toml:",omitempty"` // Denied method patterns, taking precedence over Allow
+}
+
+// matches reports whether the rule applies to the given client.
+func (r *AccessRule) matches(info *PeerInfo) bool {
+	if info.APIKey != "" {
+		for _, key := range r.APIKeys {
+			if key == info.APIKey {
+				return true
+			}
+		}
+	}
+	if len(r.Claims) == 0 || len(info.JWTClaims) == 0 {
+		return false
+	}
+	for name, value := range r.Claims {
+		if info.JWTClaims[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// permits reports whether the rule grants access to the given method, or to the
+// given subscription if the method is a subscribe method.
+func (r *AccessRule) permits(method, subscription string) bool {
+	names := []string{method}
+	if subscription != "" {
+		names = append(names, method+subscriptionPatternSeparator+subscription)
+	}
+	for _, pattern := range r.Deny {
+		if matchesAny(pattern, names) {
+			return false
+		}
+	}
+	if len(r.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range r.Allow {
+		if matchesAny(pattern, names) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether the pattern matches any of the given names.
+func matchesAny(pattern string, names []string) bool {
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessPolicy decides which methods a client may call. The rules are checked
+// in order and the first one matching the client applies. Clients matched by
+// no rule are denied access to all methods.
+type AccessPolicy struct {
+	rules    []AccessRule
+	fallback *AccessRule // rule for clients not matched otherwise
+}
+
+// NewAccessPolicy creates an access policy from the given rules. It returns nil
+// if no rules are given, which grants access to all methods.
+func NewAccessPolicy(rules []AccessRule) (*AccessPolicy, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	policy := new(AccessPolicy)
+	for i := range rules {
+		rule := rules[i]
+		for _, pattern := range append(append([]string{}, rule.Allow...), rule.Deny...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("access rule %q: invalid method pattern %q", rule.Name, pattern)
+			}
+		}
+		if len(rule.APIKeys) == 0 && len(rule.Claims) == 0 {
+			if policy.fallback != nil {
+				return nil, fmt.Errorf("access rule %q: multiple rules without API keys or claims", rule.Name)
+			}
+			policy.fallback = &rule
+			continue
+		}
+		policy.rules = append(policy.rules, rule)
+	}
+	return policy, nil
+}
+
+// rule returns the rule applying to the given client, or nil if none does.
+func (p *AccessPolicy) rule(info *PeerInfo) *AccessRule {
+	for i := range p.rules {
+		if p.rules[i].matches(info) {
+			return &p.rules[i]
+		}
+	}
+	return p.fallback
+}
+
+// knowsKey reports whether the given API key is listed by any of the rules.
+func (p *AccessPolicy) knowsKey(key string) bool {
+	if p == nil || key == "" {
+		return false
+	}
+	for i := range p.rules {
+		if slices.Contains(p.rules[i].APIKeys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an error if the client described by the context is not allowed
+// to call the given method. For subscribe methods, the subscription name is
+// checked as well.
+func (p *AccessPolicy) check(ctx context.Context, method, subscription string) error {
+	if p == nil {
+		return nil
+	}
+	info := PeerInfoFromContext(ctx)
+	if info.Transport == "ipc" || info.Transport == "" {
+		return nil // local connections are not restricted
+	}
+	rule := p.rule(&info)
+	if rule == nil {
+		return &accessDeniedError{method: method}
+	}
+	if !rule.permits(method, subscription) {
+		if subscription != "" {
+			method += subscriptionPatternSeparator + subscription
+		}
+		return &accessDeniedError{method: method, client: rule.Name}
+	}
+	return nil
+}
+
+// SetAccessPolicy restricts the methods remote clients may call. A nil policy
+// grants access to all methods.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetAccessPolicy(policy *AccessPolicy) {
+	s.accessPolicy = policy
+}
+
+// apiKeyFromRequest extracts the API key from the header or the URL path of
+// the given request.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if idx := strings.LastIndex(r.URL.Path, apiKeyPathSegment); idx >= 0 {
+		key := r.URL.Path[idx+len(apiKeyPathSegment):]
+		if !strings.Contains(key, "/") {
+			return key
+		}
+	}
+	return ""
+}
+
+type jwtClaimsContextKey struct{}
+
+// ContextWithJWTClaims attaches the verified JWT claims of a client to the
+// context of its HTTP request, making them available to the access policy of
+// the server handling the request.
+func ContextWithJWTClaims(ctx context.Context, claims map[string]string) context.Context {
+	return context.WithValue(ctx, jwtClaimsContextKey{}, claims)
+}
+
+// jwtClaimsFromContext returns the JWT claims attached to the context.
+func jwtClaimsFromContext(ctx context.Context) map[string]string {
+	claims, _ := ctx.Value(jwtClaimsContextKey{}).(map[string]string)
+	return claims
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/crypto/sha3"
+)
+
+// ResultCache is a size-bounded cache of method call results. Only the results
+// of calls which marked themselves final using MarkResultFinal are stored, so
+// the cache never changes what a client observes unless the chain is rewound.
+// Rewinding nodes must call Purge.
+//
+// A ResultCache may be shared by multiple servers.
+type ResultCache struct {
+	mu      sync.Mutex
+	size    uint64
+	epoch   uint64 // incremented by Purge, results computed before are dropped
+	results *lru.SizeConstrainedCache[common.Hash, json.RawMessage]
+}
+
+// NewResultCache creates a cache holding up to size bytes of results. It returns
+// nil if size is zero, which disables caching.
+func NewResultCache(size uint64) *ResultCache {
+	if size == 0 {
+		return nil
+	}
+	return &ResultCache{
+		size:    size,
+		results: lru.NewSizeConstrainedCache[common.Hash, json.RawMessage](size),
+	}
+}
+
+// Purge drops all cached results. Results of calls in progress are not stored.
+func (c *ResultCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.results = lru.NewSizeConstrainedCache[common.Hash, json.RawMessage](c.size)
+}
+
+// lookup returns the cached result of a call, along with the epoch to pass to
+// store if there is none.
+func (c *ResultCache) lookup(key common.Hash) (json.RawMessage, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results.Get(key)
+	return result, c.epoch, ok
+}
+
+// store adds the result of a call, unless the cache was purged since the call
+// was looked up.
+func (c *ResultCache) store(key common.Hash, epoch uint64, result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch == epoch {
+		c.results.Add(key, result)
+	}
+}
+
+// resultCacheKey returns the cache key of a call. The parameters are compacted,
+// so the formatting chosen by the client does not matter. The key is hashed, so
+// the size of the cache is bounded by the size of the results alone.
+func resultCacheKey(method string, params json.RawMessage) common.Hash {
+	var buf bytes.Buffer
+	buf.WriteString(method)
+	buf.WriteByte(0)
+	if err := json.Compact(&buf, params); err != nil {
+		buf.Write(params)
+	}
+	var key common.Hash
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(buf.Bytes())
+	hasher.Sum(key[:0])
+	return key
+}
+
+type resultFinalityKey struct{}
+
+// resultFinality is attached to the context of calls whose results may be cached.
+type resultFinality struct {
+	final atomic.Bool
+}
+
+// ResultCacheEnabled reports whether the result of the method call carrying the
+// given context can be cached. Methods can use this to skip finality checks.
+func ResultCacheEnabled(ctx context.Context) bool {
+	return ctx.Value(resultFinalityKey{}) != nil
+}
+
+// MarkResultFinal marks the result of the method call carrying the given context
+// as final, i.e. any later call with the same parameters returns the same result.
+// Such results are stored by the result cache of the server, if configured.
+// Calling it for other contexts has no effect.
+func MarkResultFinal(ctx context.Context) {
+	if f, ok := ctx.Value(resultFinalityKey{}).(*resultFinality); ok {
+		f.final.Store(true)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingService counts the executions of its methods.
+type countingService struct {
+	calls atomic.Int64
+}
+
+func (s *countingService) Get(ctx context.Context, key string, final bool) string {
+	s.calls.Add(1)
+	if final {
+		MarkResultFinal(ctx)
+	}
+	return strings.ToUpper(key)
+}
+
+func TestServerResultCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		service = new(countingService)
+		cache   = NewResultCache(1024)
+		srv     = NewServer()
+	)
+	if err := srv.RegisterName("test", service); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetResultCache(cache)
+	defer srv.Stop()
+
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	client, err := Dial(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	call := func(key string, final bool, wantCalls int64) {
+		t.Helper()
+		var result string
+		if err := client.Call(&result, "test_get", key, final); err != nil {
+			t.Fatal(err)
+		}
+		if result != strings.ToUpper(key) {
+			t.Fatalf("wrong result for %q: %q", key, result)
+		}
+		if have := service.calls.Load(); have != wantCalls {
+			t.Fatalf("wrong number of executions after %q: have %d, want %d", key, have, wantCalls)
+		}
+	}
+	// Results not marked final are never cached
+	call("a", false, 1)
+	call("a", false, 2)
+
+	// Final results are served from the cache
+	call("b", true, 3)
+	call("b", true, 3)
+	call("c", true, 4)
+	call("b", true, 4)
+
+	// Purging the cache forces execution again
+	cache.Purge()
+	call("b", true, 5)
+	call("b", true, 5)
+}
+
+func TestResultCacheKey(t *testing.T) {
+	a := resultCacheKey("eth_getBlockByHash", []byte(`["0x01", true]`))
+	b := resultCacheKey("eth_getBlockByHash", []byte("[ \"0x01\",\n true ]"))
+	if a != b {
+		t.Fatalf("keys differ by formatting: %x != %x", a, b)
+	}
+	if c := resultCacheKey("eth_getBlockByNumber", []byte(`["0x01",true]`)); a == c {
+		t.Fatal("keys of different methods collide")
+	}
+}
+
+func TestResultCachePurgeDuringCall(t *testing.T) {
+	var (
+		cache = NewResultCache(1024)
+		key   = resultCacheKey("test_call", nil)
+	)
+	_, epoch, _ := cache.lookup(key)
+	cache.Purge()
+	cache.store(key, epoch, []byte(`"stale"`))
+	if _, _, ok := cache.lookup(key); ok {
+		t.Fatal("result computed before purge was stored")
+	}
+	if NewResultCache(0) != nil {
+		t.Fatal("zero sized cache not disabled")
+	}
+}
+
+func TestResultCacheServers(t *testing.T) {
+	t.Parallel()
+
+	// Two servers share the cache, but only the first one exposes the method
+	var (
+		cache   = NewResultCache(1024)
+		service = new(countingService)
+		full    = NewServer()
+		partial = NewServer()
+	)
+	if err := full.RegisterName("test", service); err != nil {
+		t.Fatal(err)
+	}
+	if err := partial.RegisterName("other", new(countingService)); err != nil {
+		t.Fatal(err)
+	}
+	limiter, err := NewRateLimiter([]RateLimitClass{{Name: "test", Rate: 0.001, Burst: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	full.SetResultCache(cache)
+	full.SetRateLimiter(limiter)
+	partial.SetResultCache(cache)
+	defer full.Stop()
+	defer partial.Stop()
+
+	dial := func(srv *Server) *Client {
+		httpsrv := httptest.NewServer(srv)
+		t.Cleanup(httpsrv.Close)
+		client, err := Dial(httpsrv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(client.Close)
+		return client
+	}
+	var (
+		fullClient    = dial(full)
+		partialClient = dial(partial)
+		result        string
+	)
+	if err := fullClient.Call(&result, "test_get", "a", true); err != nil {
+		t.Fatal(err)
+	}
+	// Cached results are not served for methods the server doesn't expose
+	err = partialClient.Call(&result, "test_get", "a", true)
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != new(methodNotFoundError).ErrorCode() {
+		t.Fatalf("cached result served by a server without the method: %v", err)
+	}
+	// Cached results are subject to the rate limits
+	err = fullClient.Call(&result, "test_get", "a", true)
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != errcodeRateLimited {
+		t.Fatalf("cached result served beyond the rate limit: %v", err)
+	}
+	if have := service.calls.Load(); have != 1 {
+		t.Fatalf("wrong number of executions: have %d, want 1", have)
+	}
+}
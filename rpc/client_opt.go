@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -29,18 +30,30 @@ type ClientOption interface {
 
 type clientConfig struct {
 	// HTTP settings
-	httpClient  *http.Client
-	httpHeaders http.Header
-	httpAuth    HTTPAuth
+	httpClient      *http.Client
+	httpHeaders     http.Header
+	httpAuth        HTTPAuth
+	httpStreamBatch bool
 
 	// WebSocket options
 	wsDialer           *websocket.Dialer
 	wsMessageSizeLimit *int64 // wsMessageSizeLimit nil = default, 0 = no limit
+	wsCompression      bool
 
 	// RPC handler options
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	accessPolicy       *AccessPolicy        // only set for server connections
+	rateLimiter        *RateLimiter         // only set for server connections
+	resultCache        *ResultCache         // only set for server connections
+	subBufferSize      int                  // only set for server connections
+	subOverflow        SubscriptionOverflow // only set for server connections
+
+	// Multi-endpoint options, see DialEndpoints
+	endpointSelection   EndpointSelection
+	healthCheckInterval time.Duration
+	healthCheckMaxLag   uint64
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -75,6 +88,15 @@ func WithWebsocketMessageSizeLimit(messageSizeLimit int64) ClientOption {
 	})
 }
 
+// WithWebsocketCompression enables negotiation of permessage-deflate compression
+// for websocket connections of the RPC client. It also applies to dialers
+// configured via WithWebsocketDialer.
+func WithWebsocketCompression() ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.wsCompression = true
+	})
+}
+
 // WithHeader configures HTTP headers set by the RPC client. Headers set using this option
 // will be used for both HTTP and WebSocket connections.
 func WithHeader(key, value string) ClientOption {
@@ -114,6 +136,37 @@ func WithHTTPAuth(a HTTPAuth) ClientOption {
 	})
 }
 
+// WithHTTPBatchStreaming makes the client ask HTTP servers to stream the responses
+// of batch requests as newline-delimited JSON. Servers write the responses as the
+// calls complete instead of buffering them, so the batch response size limit of
+// the server does not apply.
+//
+// To use HTTP/2 without TLS (h2c), configure an http.Client whose transport enables
+// unencrypted HTTP/2 using WithHTTPClient.
+func WithHTTPBatchStreaming() ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.httpStreamBatch = true
+	})
+}
+
+// WithEndpointSelection sets the policy choosing the endpoint serving a request of a
+// client created by DialEndpoints. The default is RoundRobin.
+func WithEndpointSelection(selection EndpointSelection) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.endpointSelection = selection
+	})
+}
+
+// WithHealthCheck configures the health checks of a client created by DialEndpoints.
+// The endpoints are checked at the given interval, and avoided if their head block
+// lags more than maxLag blocks behind the best endpoint.
+func WithHealthCheck(interval time.Duration, maxLag uint64) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.healthCheckInterval = interval
+		cfg.healthCheckMaxLag = maxLag
+	})
+}
+
 // A HTTPAuth function is called by the client whenever a HTTP request is sent.
 // The function must be safe for concurrent use.
 //
@@ -98,6 +98,14 @@ Subscriptions are deleted when the user sends an unsubscribe request or when the
 connection which was used to create the subscription is closed. This can be initiated by
 the client and server. The server will close the connection for any write error.
 
+A service can also terminate a single subscription by calling Notifier.Close with an
+error. The subscription is removed on the server, and after the pending notifications the
+client receives an error notification, e.g. for the "blockchain" namespace:
+
+	{"jsonrpc":"2.0","method":"blockchain_subscriptionError","params":{"subscription":"0x1","error":{"code":-32000,"message":"..."}}}
+
+The error is reported by the Err channel of the client subscription.
+
 For more information about subscriptions, see https://geth.ethereum.org/docs/interacting-with-geth/rpc/pubsub
 
 # Reverse Calls
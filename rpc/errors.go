@@ -55,12 +55,16 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(accessDeniedError)
+	_ Error = new(rateLimitedError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeAccessDenied     = -32004
+	errcodeRateLimited      = -32005
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -154,3 +158,25 @@ type internalServerError struct {
 func (e *internalServerError) ErrorCode() int { return e.code }
 
 func (e *internalServerError) Error() string { return e.message }
+
+// accessDeniedError is returned when the access policy of the server does not
+// permit the client to call a method.
+type accessDeniedError struct{ method, client string }
+
+func (e *accessDeniedError) ErrorCode() int { return errcodeAccessDenied }
+
+func (e *accessDeniedError) Error() string {
+	if e.client == "" {
+		return fmt.Sprintf("access denied: method %s requires authorization", e.method)
+	}
+	return fmt.Sprintf("access denied: method %s is not permitted for %s", e.method, e.client)
+}
+
+// rateLimitedError is returned when a client exceeded its request quota.
+type rateLimitedError struct{ method, reason string }
+
+func (e *rateLimitedError) ErrorCode() int { return errcodeRateLimited }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited: %s (method %s)", e.reason, e.method)
+}
@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/internal/telemetry"
 	"github.com/ethereum/go-ethereum/log"
 	"go.opentelemetry.io/otel"
@@ -66,6 +67,12 @@ type handler struct {
 	conn                 jsonWriter                     // where responses will be sent
 	log                  log.Logger
 	allowSubscribe       bool
+	accessPolicy         *AccessPolicy        // restricts the callable methods, nil allows all
+	rateLimiter          *RateLimiter         // enforces per-client quotas, nil disables them
+	resultCache          *ResultCache         // stores final call results, nil disables caching
+	subBufferSize        int                  // notifications queued per subscription, zero sends synchronously
+	subOverflow          SubscriptionOverflow // policy applied when the queue is full
+	streamBatch          bool                 // write batch responses one by one as they complete
 	batchRequestLimit    int
 	batchResponseMaxSize int
 	tracerProvider       trace.TracerProvider
@@ -108,10 +115,11 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 // call. Calls need to be synchronized between the processing and timeout-triggering
 // goroutines.
 type batchCallBuffer struct {
-	mutex sync.Mutex
-	calls []*jsonrpcMessage
-	resp  []*jsonrpcMessage
-	wrote bool
+	mutex  sync.Mutex
+	calls  []*jsonrpcMessage
+	resp   []*jsonrpcMessage
+	wrote  bool
+	stream bool // responses are written individually as they complete
 }
 
 // nextCall returns the next unprocessed message.
@@ -139,6 +147,16 @@ func (b *batchCallBuffer) pushResponse(answer *jsonrpcMessage) {
 	b.calls = b.calls[1:]
 }
 
+// flush sends the responses added so far when streaming.
+func (b *batchCallBuffer) flush(ctx context.Context, conn jsonWriter) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.stream && !b.wrote {
+		b.writeStream(ctx, conn, false)
+	}
+}
+
 // write sends the responses.
 func (b *batchCallBuffer) write(ctx context.Context, conn jsonWriter) {
 	b.mutex.Lock()
@@ -168,11 +186,24 @@ func (b *batchCallBuffer) doWrite(ctx context.Context, conn jsonWriter, isErrorR
 		return
 	}
 	b.wrote = true // can only write once
+	if b.stream {
+		b.writeStream(ctx, conn, isErrorResponse)
+		return
+	}
 	if len(b.resp) > 0 {
 		conn.writeJSON(ctx, b.resp, isErrorResponse)
 	}
 }
 
+// writeStream writes the pending responses one by one.
+// This assumes b.mutex is held.
+func (b *batchCallBuffer) writeStream(ctx context.Context, conn jsonWriter, isErrorResponse bool) {
+	for _, resp := range b.resp {
+		conn.writeJSON(ctx, resp, isErrorResponse)
+	}
+	b.resp = b.resp[:0]
+}
+
 // handleBatch executes all messages in a batch and returns the responses.
 func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	// Emit error response for empty batches:
@@ -207,7 +238,7 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		var (
 			timer      *time.Timer
 			cancel     context.CancelFunc
-			callBuffer = &batchCallBuffer{calls: calls, resp: make([]*jsonrpcMessage, 0, len(calls))}
+			callBuffer = &batchCallBuffer{calls: calls, resp: make([]*jsonrpcMessage, 0, len(calls)), stream: h.streamBatch}
 		)
 
 		cp.ctx, cancel = context.WithCancel(cp.ctx)
@@ -244,6 +275,7 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 					break
 				}
 			}
+			callBuffer.flush(cp.ctx, h.conn)
 		}
 		if timer != nil {
 			timer.Stop()
@@ -268,6 +300,10 @@ func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage
 			break
 		}
 	}
+	if h.streamBatch {
+		h.conn.writeJSON(cp.ctx, resp, true)
+		return
+	}
 	h.conn.writeJSON(cp.ctx, []*jsonrpcMessage{resp}, true)
 }
 
@@ -443,6 +479,14 @@ func (h *handler) handleResponses(batch []*jsonrpcMessage, handleCall func(*json
 				h.handleSubscriptionResult(msg)
 				continue
 			}
+			if strings.HasSuffix(msg.Method, lagMethodSuffix) {
+				h.handleSubscriptionLag(msg)
+				continue
+			}
+			if strings.HasSuffix(msg.Method, errorMethodSuffix) {
+				h.handleSubscriptionError(msg)
+				continue
+			}
 			handleCall(msg)
 
 		default:
@@ -467,6 +511,33 @@ func (h *handler) handleSubscriptionResult(msg *jsonrpcMessage) {
 	}
 }
 
+// handleSubscriptionLag processes notifications about dropped subscription results.
+func (h *handler) handleSubscriptionLag(msg *jsonrpcMessage) {
+	var lag subscriptionLag
+	if err := json.Unmarshal(msg.Params, &lag); err != nil {
+		h.log.Debug("Dropping invalid subscription lag message")
+		return
+	}
+	if sub := h.clientSubs[lag.ID]; sub != nil {
+		sub.dropped.Add(lag.Dropped)
+		h.log.Debug("Subscription notifications dropped by server", "id", lag.ID, "dropped", lag.Dropped)
+	}
+}
+
+// handleSubscriptionError processes notifications about subscriptions terminated
+// by the server.
+func (h *handler) handleSubscriptionError(msg *jsonrpcMessage) {
+	var result subscriptionError
+	if err := json.Unmarshal(msg.Params, &result); err != nil || result.Error == nil {
+		h.log.Debug("Dropping invalid subscription error message")
+		return
+	}
+	if sub := h.clientSubs[result.ID]; sub != nil {
+		delete(h.clientSubs, result.ID)
+		sub.close(&subscriptionClosedError{result.Error})
+	}
+}
+
 // handleCallMsg executes a call message and returns the answer.
 func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	start := time.Now()
@@ -501,9 +572,6 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
-	if msg.isSubscribe() {
-		return h.handleSubscribe(cp, msg)
-	}
 	if msg.isUnsubscribe() {
 		args, err := parsePositionalArguments(msg.Params, h.unsubscribeCb.argTypes)
 		if err != nil {
@@ -511,6 +579,27 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		}
 		return h.runMethod(cp.ctx, msg, h.unsubscribeCb, args)
 	}
+	var subscription string
+	if msg.isSubscribe() {
+		// Invalid names are rejected by handleSubscribe.
+		subscription, _ = parseSubscriptionName(msg.Params)
+	}
+	if err := h.accessPolicy.check(cp.ctx, msg.Method, subscription); err != nil {
+		accessDeniedGauge.Inc(1)
+		h.log.Debug("Denied "+msg.Method, "reqid", idForLog{msg.ID}, "err", err)
+		return msg.errorResponse(err)
+	}
+	release, err := h.rateLimiter.acquire(cp.ctx, msg.Method, h.accessPolicy)
+	if err != nil {
+		rateLimitedGauge.Inc(1)
+		h.log.Debug("Rate limited "+msg.Method, "reqid", idForLog{msg.ID}, "err", err)
+		return msg.errorResponse(err)
+	}
+	defer release()
+
+	if msg.isSubscribe() {
+		return h.handleSubscribe(cp, msg)
+	}
 
 	// Check method name length
 	if len(msg.Method) > maxMethodNameLength {
@@ -523,8 +612,25 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
 
+	// Serve the result from the cache. The cache is shared between the servers
+	// of a node, so this is only done after the method was resolved in the
+	// registry of this server.
+	var (
+		cacheKey   common.Hash
+		cacheEpoch uint64
+	)
+	if h.resultCache != nil {
+		cacheKey = resultCacheKey(msg.Method, msg.Params)
+		result, epoch, ok := h.resultCache.lookup(cacheKey)
+		if ok {
+			resultCacheHitGauge.Inc(1)
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+		}
+		resultCacheMissGauge.Inc(1)
+		cacheEpoch = epoch
+	}
+
 	// Start root span for the request.
-	var err error
 	rpcInfo := telemetry.RPCInfo{
 		System:    "jsonrpc",
 		Service:   service,
@@ -548,12 +654,22 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 
 	// Start tracing span before running the method.
 	rctx, _, rSpanEnd := telemetry.StartSpanWithTracer(ctx, h.tracer(), "rpc.runMethod")
+	var finality *resultFinality
+	if h.resultCache != nil {
+		finality = new(resultFinality)
+		rctx = context.WithValue(rctx, resultFinalityKey{}, finality)
+	}
 	answer := h.runMethod(rctx, msg, callb, args)
 	if answer.Error != nil {
 		err = errors.New(answer.Error.Message)
 	}
 	rSpanEnd(err)
 
+	// Store the result if the method marked it final.
+	if finality != nil && finality.final.Load() && answer.Error == nil {
+		h.resultCache.store(cacheKey, cacheEpoch, answer.Result)
+	}
+
 	// Collect the statistics for RPC calls if metrics is enabled.
 	rpcRequestGauge.Inc(1)
 	if answer.Error != nil {
@@ -643,6 +759,17 @@ func (h *handler) unsubscribe(ctx context.Context, id ID) (bool, error) {
 	return true, nil
 }
 
+// dropServerSubscription removes a subscription terminated by the server.
+func (h *handler) dropServerSubscription(id ID) {
+	h.subLock.Lock()
+	defer h.subLock.Unlock()
+
+	if s := h.serverSubs[id]; s != nil {
+		close(s.err)
+		delete(h.serverSubs, id)
+	}
+}
+
 type idForLog struct{ json.RawMessage }
 
 func (id idForLog) String() string {
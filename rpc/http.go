@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,21 +37,23 @@ import (
 )
 
 const (
-	defaultBodyLimit = 5 * 1024 * 1024
-	contentType      = "application/json"
+	defaultBodyLimit  = 5 * 1024 * 1024
+	contentType       = "application/json"
+	streamContentType = "application/x-ndjson" // batch responses streamed as newline-delimited JSON
 )
 
 // https://www.jsonrpc.org/historical/json-rpc-over-http.html#id13
 var acceptedContentTypes = []string{contentType, "application/json-rpc", "application/jsonrequest"}
 
 type httpConn struct {
-	client    *http.Client
-	url       string
-	closeOnce sync.Once
-	closeCh   chan interface{}
-	mu        sync.Mutex // protects headers
-	headers   http.Header
-	auth      HTTPAuth
+	client      *http.Client
+	url         string
+	closeOnce   sync.Once
+	closeCh     chan interface{}
+	mu          sync.Mutex // protects headers
+	headers     http.Header
+	auth        HTTPAuth
+	streamBatch bool // request batch responses as newline-delimited JSON
 }
 
 // httpConn implements ServerCodec, but it is treated specially by Client
@@ -159,11 +162,12 @@ func newClientTransportHTTP(endpoint string, cfg *clientConfig) reconnectFunc {
 	}
 
 	hc := &httpConn{
-		client:  client,
-		headers: headers,
-		url:     endpoint,
-		auth:    cfg.httpAuth,
-		closeCh: make(chan interface{}),
+		client:      client,
+		headers:     headers,
+		url:         endpoint,
+		auth:        cfg.httpAuth,
+		streamBatch: cfg.httpStreamBatch,
+		closeCh:     make(chan interface{}),
 	}
 
 	return func(ctx context.Context) (ServerCodec, error) {
@@ -181,15 +185,15 @@ func cleanlyCloseBody(body io.ReadCloser) error {
 
 func (c *Client) sendHTTP(ctx context.Context, op *requestOp, msg interface{}) error {
 	hc := c.writeConn.(*httpConn)
-	respBody, err := hc.doRequest(ctx, msg)
+	resp, err := hc.doRequest(ctx, msg, false)
 	if err != nil {
 		return err
 	}
-	defer cleanlyCloseBody(respBody)
+	defer cleanlyCloseBody(resp.Body)
 
-	var resp jsonrpcMessage
-	batch := [1]*jsonrpcMessage{&resp}
-	if err := json.NewDecoder(respBody).Decode(&resp); err != nil {
+	var respmsg jsonrpcMessage
+	batch := [1]*jsonrpcMessage{&respmsg}
+	if err := json.NewDecoder(resp.Body).Decode(&respmsg); err != nil {
 		return err
 	}
 	op.resp <- batch[:]
@@ -198,21 +202,58 @@ func (c *Client) sendHTTP(ctx context.Context, op *requestOp, msg interface{}) e
 
 func (c *Client) sendBatchHTTP(ctx context.Context, op *requestOp, msgs []*jsonrpcMessage) error {
 	hc := c.writeConn.(*httpConn)
-	respBody, err := hc.doRequest(ctx, msgs)
+	resp, err := hc.doRequest(ctx, msgs, hc.streamBatch)
 	if err != nil {
 		return err
 	}
-	defer cleanlyCloseBody(respBody)
+	defer cleanlyCloseBody(resp.Body)
 
 	var respmsgs []*jsonrpcMessage
-	if err := json.NewDecoder(respBody).Decode(&respmsgs); err != nil {
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("content-type")); mt == streamContentType {
+		respmsgs, err = readStreamedBatch(resp.Body)
+	} else {
+		err = json.NewDecoder(resp.Body).Decode(&respmsgs)
+	}
+	if err != nil {
 		return err
 	}
 	op.resp <- respmsgs
 	return nil
 }
 
-func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadCloser, error) {
+// readStreamedBatch reads the responses of a batch streamed as newline-delimited
+// JSON. Errors concerning the whole batch may be sent as arrays.
+func readStreamedBatch(body io.Reader) ([]*jsonrpcMessage, error) {
+	var (
+		msgs []*jsonrpcMessage
+		dec  = json.NewDecoder(body)
+	)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return msgs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if isBatch(raw) {
+			var batch []*jsonrpcMessage
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, batch...)
+			continue
+		}
+		msg := new(jsonrpcMessage)
+		if err := json.Unmarshal(raw, msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// doRequest posts the message, returning the response if it was successful. If stream
+// is set, the server is asked to stream the responses of batches.
+func (hc *httpConn) doRequest(ctx context.Context, msg interface{}, stream bool) (*http.Response, error) {
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
@@ -229,6 +270,9 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 	req.Header = hc.headers.Clone()
 	hc.mu.Unlock()
 	setHeaders(req.Header, headersFromContext(ctx))
+	if stream {
+		req.Header.Set("accept", streamContentType+", "+contentType)
+	}
 
 	if hc.auth != nil {
 		if err := hc.auth(req.Header); err != nil {
@@ -254,7 +298,7 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 			Body:       body,
 		}
 	}
-	return resp.Body, nil
+	return resp, nil
 }
 
 // httpServerConn turns a HTTP connection into a Conn.
@@ -264,11 +308,21 @@ type httpServerConn struct {
 	r *http.Request
 }
 
-func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
+func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter, stream bool) ServerCodec {
 	body := io.LimitReader(r.Body, int64(s.httpBodyLimit))
 	conn := &httpServerConn{Reader: body, Writer: w, r: r}
 
 	encoder := func(v any, isErrorResponse bool) error {
+		if stream {
+			// Each streamed response is written as a line and flushed immediately.
+			// Error responses need no special treatment, the length of the stream
+			// is unknown anyway.
+			err := json.NewEncoder(conn).Encode(v)
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+			return err
+		}
 		if !isErrorResponse {
 			return json.NewEncoder(conn).Encode(v)
 		}
@@ -334,6 +388,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.APIKey = apiKeyFromRequest(r)
+	connInfo.JWTClaims = jwtClaimsFromContext(r.Context())
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
@@ -342,11 +398,30 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
-	// single request.
-	w.Header().Set("content-type", contentType)
-	codec := s.newHTTPServerConn(r, w)
+	// single request. If enabled, clients accepting newline-delimited JSON get the
+	// results of batches streamed as they complete.
+	stream := s.httpStreamBatch && acceptsStream(r)
+	if stream {
+		w.Header().Set("content-type", streamContentType)
+	} else {
+		w.Header().Set("content-type", contentType)
+	}
+	codec := s.newHTTPServerConn(r, w, stream)
 	defer codec.close()
-	s.serveSingleRequest(ctx, codec)
+	s.serveSingleRequest(ctx, codec, stream)
+}
+
+// acceptsStream reports whether the client accepts batch responses streamed as
+// newline-delimited JSON.
+func acceptsStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("accept") {
+		for _, mt := range strings.Split(accept, ",") {
+			if mt, _, err := mime.ParseMediaType(mt); err == nil && mt == streamContentType {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // validateRequest returns a non-zero response code and error message if the
@@ -19,6 +19,7 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -263,3 +264,102 @@ func TestNewContextWithHeaders(t *testing.T) {
 		t.Error("call failed:", err)
 	}
 }
+
+func TestHTTPBatchStreaming(t *testing.T) {
+	t.Parallel()
+
+	// batch calls test_echo four times, returning the number of successful
+	// calls. The calls after the first failure must fail with the response size
+	// limit error.
+	batch := func(s *Server, opts ...ClientOption) int {
+		t.Helper()
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+		c, err := DialOptions(context.Background(), ts.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		elems := make([]BatchElem, 4)
+		for i := range elems {
+			elems[i] = BatchElem{Method: "test_echo", Args: []any{"x", i, &echoArgs{S: "y"}}, Result: new(echoResult)}
+		}
+		if err := c.BatchCall(elems); err != nil {
+			t.Fatal(err)
+		}
+		ok := 0
+		for ; ok < len(elems) && elems[ok].Error == nil; ok++ {
+			if result := elems[ok].Result.(*echoResult); result.Int != ok {
+				t.Fatalf("element %d has wrong result: %+v", ok, result)
+			}
+		}
+		for i, elem := range elems[ok:] {
+			if elem.Error.Error() != errMsgResponseTooLarge {
+				t.Fatalf("element %d: wrong error: %v", ok+i, elem.Error)
+			}
+		}
+		return ok
+	}
+	s := newTestServer()
+	s.SetBatchLimits(100, 60)
+	s.SetHTTPBatchStreaming(true, 0)
+	defer s.Stop()
+
+	// The batch response size limit applies without streaming, the first
+	// responses are delivered before the limit is exceeded.
+	if n := batch(s); n != 2 {
+		t.Fatalf("wrong number of responses without streaming: %d", n)
+	}
+	// Streamed batches are not limited by the batch response size limit.
+	if n := batch(s, WithHTTPBatchStreaming()); n != 4 {
+		t.Fatalf("wrong number of streamed responses: %d", n)
+	}
+	// Streamed batches are subject to their own limit.
+	s.SetHTTPBatchStreaming(true, 100)
+	if n := batch(s, WithHTTPBatchStreaming()); n != 3 {
+		t.Fatalf("wrong number of streamed responses with limit: %d", n)
+	}
+}
+
+func TestHTTPBatchStreamingFormat(t *testing.T) {
+	t.Parallel()
+
+	post := func(s *Server) (string, string) {
+		t.Helper()
+		ts := httptest.NewServer(s)
+		defer ts.Close()
+
+		body := `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["a",1]},{"jsonrpc":"2.0","method":"test_echo","params":["b",2]},{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["c",3]}]`
+		req, _ := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		req.Header.Set("content-type", contentType)
+		req.Header.Set("accept", "application/x-ndjson, application/json;q=0.9")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Header.Get("content-type"), string(respBody)
+	}
+	// Streaming is disabled by default.
+	s := newTestServer()
+	defer s.Stop()
+	if ct, _ := post(s); ct != contentType {
+		t.Fatalf("wrong content type %q without streaming", ct)
+	}
+
+	s.SetHTTPBatchStreaming(true, 0)
+	ct, body := post(s)
+	if ct != streamContentType {
+		t.Fatalf("wrong content type %q", ct)
+	}
+	want := `{"jsonrpc":"2.0","id":1,"result":{"String":"a","Int":1,"Args":null}}` + "\n" +
+		`{"jsonrpc":"2.0","id":3,"result":{"String":"c","Int":3,"Args":null}}` + "\n"
+	if body != want {
+		t.Fatalf("wrong response:\nhave %s\nwant %s", body, want)
+	}
+}
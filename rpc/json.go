@@ -35,6 +35,8 @@ const (
 	subscribeMethodSuffix    = "_subscribe"
 	unsubscribeMethodSuffix  = "_unsubscribe"
 	notificationMethodSuffix = "_subscription"
+	lagMethodSuffix          = "_subscriptionLag"
+	errorMethodSuffix        = "_subscriptionError"
 	maxMethodNameLength      = 2048
 
 	defaultWriteTimeout = 10 * time.Second // used if context has no deadline
@@ -58,6 +60,32 @@ type jsonrpcSubscriptionNotification struct {
 	Params  subscriptionResultEnc `json:"params"`
 }
 
+// subscriptionLag is the payload of a lag notification, informing the client
+// that notifications of a subscription were dropped.
+type subscriptionLag struct {
+	ID      string `json:"subscription"`
+	Dropped uint64 `json:"dropped"`
+}
+
+type jsonrpcSubscriptionLag struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  subscriptionLag `json:"params"`
+}
+
+// subscriptionError is the payload of an error notification, informing the
+// client that the server terminated a subscription.
+type subscriptionError struct {
+	ID    string     `json:"subscription"`
+	Error *jsonError `json:"error"`
+}
+
+type jsonrpcSubscriptionError struct {
+	Version string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  subscriptionError `json:"params"`
+}
+
 // A value of this type can a JSON-RPC request, notification, successful response or
 // error response. Which one it is depends on the fields.
 type jsonrpcMessage struct {
@@ -27,6 +27,16 @@ var (
 	rpcRequestGauge        = metrics.NewRegisteredGauge("rpc/requests", nil)
 	successfulRequestGauge = metrics.NewRegisteredGauge("rpc/success", nil)
 	failedRequestGauge     = metrics.NewRegisteredGauge("rpc/failure", nil)
+	accessDeniedGauge      = metrics.NewRegisteredGauge("rpc/denied", nil)
+	rateLimitedGauge       = metrics.NewRegisteredGauge("rpc/ratelimited", nil)
+	subDroppedGauge        = metrics.NewRegisteredGauge("rpc/subscriptions/dropped", nil)
+	subOverflowGauge       = metrics.NewRegisteredGauge("rpc/subscriptions/overflow", nil)
+	resultCacheHitGauge    = metrics.NewRegisteredGauge("rpc/cache/hit", nil)
+	resultCacheMissGauge   = metrics.NewRegisteredGauge("rpc/cache/miss", nil)
+
+	// rateLimitedGaugeName is the prefix of the per-class gauges counting the
+	// calls rejected by the rate and concurrency limits.
+	rateLimitedGaugeName = "rpc/ratelimited"
 
 	// serveTimeHistName is the prefix of the per-request serving time histograms.
 	serveTimeHistName = "rpc/duration"
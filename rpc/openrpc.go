@@ -0,0 +1,443 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// openRPCVersion is the version of the OpenRPC specification implemented by the
+// discovery document.
+const openRPCVersion = "1.2.6"
+
+// OpenRPCDocument is an OpenRPC document describing the methods served by a server.
+// See https://spec.open-rpc.org for the specification.
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []*OpenRPCMethod  `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo is the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a single RPC method.
+type OpenRPCMethod struct {
+	Name           string                      `json:"name"`
+	Description    string                      `json:"description,omitempty"`
+	ParamStructure string                      `json:"paramStructure"`
+	Params         []*OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a parameter or the result of a method.
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenRPCComponents holds the schemas of named types, which are referenced from the
+// method descriptions.
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// JSONSchema is the subset of JSON schema used to describe RPC values.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+var (
+	quantitySchema = &JSONSchema{Title: "hex encoded unsigned integer", Type: "string", Pattern: "^0x(0|[1-9a-fA-F][0-9a-fA-F]*)$"}
+	bytesSchema    = &JSONSchema{Title: "hex encoded bytes", Type: "string", Pattern: "^0x([0-9a-fA-F]{2})*$"}
+	addressSchema  = &JSONSchema{Title: "hex encoded address", Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"}
+	hashSchema     = &JSONSchema{Title: "hex encoded hash", Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"}
+
+	blockNumberSchema = &JSONSchema{
+		Title: "block number or tag",
+		OneOf: []*JSONSchema{
+			quantitySchema,
+			{Title: "block tag", Type: "string", Enum: []string{"earliest", "latest", "pending", "safe", "finalized"}},
+		},
+	}
+	blockNumberOrHashSchema = &JSONSchema{
+		Title: "block number, tag or hash",
+		OneOf: []*JSONSchema{
+			blockNumberSchema,
+			hashSchema,
+			{Title: "block selector", Type: "object", Properties: map[string]*JSONSchema{
+				"blockNumber":      blockNumberSchema,
+				"blockHash":        hashSchema,
+				"requireCanonical": {Type: "boolean"},
+			}},
+		},
+	}
+
+	// knownSchemas are the schemas of types with custom JSON encodings, along with
+	// the parameter names used for them.
+	knownSchemas = map[reflect.Type]struct {
+		name   string
+		schema *JSONSchema
+	}{
+		reflect.TypeFor[hexutil.Big]():       {"quantity", quantitySchema},
+		reflect.TypeFor[hexutil.Uint64]():    {"quantity", quantitySchema},
+		reflect.TypeFor[hexutil.Uint]():      {"quantity", quantitySchema},
+		reflect.TypeFor[hexutil.Bytes]():     {"data", bytesSchema},
+		reflect.TypeFor[common.Address]():    {"address", addressSchema},
+		reflect.TypeFor[common.Hash]():       {"hash", hashSchema},
+		reflect.TypeFor[BlockNumber]():       {"blockNumber", blockNumberSchema},
+		reflect.TypeFor[BlockNumberOrHash](): {"block", blockNumberOrHashSchema},
+		reflect.TypeFor[ID]():                {"subscriptionID", &JSONSchema{Type: "string"}},
+		reflect.TypeFor[big.Int]():           {"number", &JSONSchema{Type: "integer"}},
+		reflect.TypeFor[json.RawMessage]():   {"value", &JSONSchema{}},
+	}
+
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Discover returns an OpenRPC document describing the methods the caller is allowed
+// to call on the server.
+func (s *RPCService) Discover(ctx context.Context) *OpenRPCDocument {
+	return s.server.openRPC(ctx)
+}
+
+// openRPC builds the OpenRPC document of the registered services.
+func (s *Server) openRPC(ctx context.Context) *OpenRPCDocument {
+	b := newSchemaBuilder()
+	doc := &OpenRPCDocument{
+		OpenRPC: openRPCVersion,
+		Info:    OpenRPCInfo{Title: "Ethereum JSON-RPC API", Version: "1.0.0"},
+		Methods: []*OpenRPCMethod{},
+	}
+	// Reflecting on the callbacks is slow, so only the registry is copied while
+	// holding the lock, leaving method resolution of concurrent calls unblocked.
+	s.services.mu.Lock()
+	services := make([]service, 0, len(s.services.services))
+	for _, svc := range s.services.services {
+		services = append(services, service{
+			name:          svc.name,
+			callbacks:     maps.Clone(svc.callbacks),
+			subscriptions: maps.Clone(svc.subscriptions),
+		})
+	}
+	s.services.mu.Unlock()
+
+	for _, svc := range services {
+		for name, cb := range svc.callbacks {
+			method := svc.name + serviceMethodSeparator + name
+			if s.accessPolicy.check(ctx, method, "") != nil {
+				continue
+			}
+			doc.Methods = append(doc.Methods, b.method(method, cb))
+		}
+		if len(svc.subscriptions) > 0 {
+			method := svc.name + subscribeMethodSuffix
+			maps.DeleteFunc(svc.subscriptions, func(name string, _ *callback) bool {
+				return s.accessPolicy.check(ctx, method, name) != nil
+			})
+			if len(svc.subscriptions) == 0 {
+				continue
+			}
+			doc.Methods = append(doc.Methods, b.subscribeMethod(method, svc.subscriptions))
+			doc.Methods = append(doc.Methods, &OpenRPCMethod{
+				Name:           svc.name + unsubscribeMethodSuffix,
+				Description:    "Cancels the subscription with the given id.",
+				ParamStructure: "by-position",
+				Params:         []*OpenRPCContentDescriptor{{Name: "subscriptionID", Required: true, Schema: &JSONSchema{Type: "string"}}},
+				Result:         &OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "boolean"}},
+			})
+		}
+	}
+	slices.SortFunc(doc.Methods, func(a, b *OpenRPCMethod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	doc.Components.Schemas = b.defs
+	return doc
+}
+
+// schemaBuilder derives JSON schemas from Go types, collecting the schemas of named
+// struct types as components.
+type schemaBuilder struct {
+	defs  map[string]*JSONSchema
+	names map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		defs:  make(map[string]*JSONSchema),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// method describes the given callback.
+func (b *schemaBuilder) method(name string, cb *callback) *OpenRPCMethod {
+	m := &OpenRPCMethod{
+		Name:           name,
+		ParamStructure: "by-position",
+		Params:         b.params(cb.argTypes),
+		Result:         &OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}},
+	}
+	fntype := cb.fn.Type()
+	if fntype.NumOut() > 0 && cb.errPos != 0 {
+		m.Result.Schema = b.schema(fntype.Out(0))
+	}
+	return m
+}
+
+// subscribeMethod describes the subscribe method of a namespace. The first parameter
+// selects the subscription, the others depend on it.
+func (b *schemaBuilder) subscribeMethod(name string, subs map[string]*callback) *OpenRPCMethod {
+	var (
+		names   = slices.Sorted(maps.Keys(subs))
+		options []*JSONSchema
+	)
+	for _, sub := range names {
+		if types := subs[sub].argTypes; len(types) > 0 {
+			option := *b.schema(types[0])
+			option.Title = sub
+			options = append(options, &option)
+		}
+	}
+	m := &OpenRPCMethod{
+		Name:           name,
+		Description:    "Creates a subscription to the selected events.",
+		ParamStructure: "by-position",
+		Params: []*OpenRPCContentDescriptor{
+			{Name: "subscription", Required: true, Schema: &JSONSchema{Type: "string", Enum: names}},
+		},
+		Result: &OpenRPCContentDescriptor{Name: "subscriptionID", Schema: &JSONSchema{Type: "string"}},
+	}
+	if len(options) > 0 {
+		m.Params = append(m.Params, &OpenRPCContentDescriptor{Name: "options", Schema: &JSONSchema{OneOf: options}})
+	}
+	return m
+}
+
+// params describes the given argument types. Go does not retain parameter names, so
+// they are derived from the argument types. Trailing pointer arguments are optional.
+func (b *schemaBuilder) params(types []reflect.Type) []*OpenRPCContentDescriptor {
+	var (
+		params   = make([]*OpenRPCContentDescriptor, len(types))
+		seen     = make(map[string]int)
+		required = len(types)
+	)
+	for required > 0 && types[required-1].Kind() == reflect.Ptr {
+		required--
+	}
+	for i, typ := range types {
+		name := paramName(typ, i)
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, seen[name])
+		}
+		params[i] = &OpenRPCContentDescriptor{Name: name, Required: i < required, Schema: b.schema(typ)}
+	}
+	return params
+}
+
+// paramName derives a parameter name from the type of the argument.
+func paramName(typ reflect.Type, index int) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if known, ok := knownSchemas[typ]; ok {
+		return known.name
+	}
+	name := typ.Name()
+	if name == "" || typ.PkgPath() == "" {
+		return fmt.Sprintf("arg%d", index)
+	}
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] // strip type parameters
+	}
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// schema returns the JSON schema of the given type.
+func (b *schemaBuilder) schema(typ reflect.Type) *JSONSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if known, ok := knownSchemas[typ]; ok {
+		return known.schema
+	}
+	marshaler := typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType)
+	if !marshaler && (typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType)) {
+		return &JSONSchema{Type: "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 && !marshaler {
+			return &JSONSchema{Title: "base64 encoded bytes", Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: b.schema(typ.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: b.schema(typ.Elem())}
+	case reflect.Struct:
+		return b.structSchema(typ, marshaler)
+	default:
+		// Interfaces, and types with an unknown custom encoding, may hold any value.
+		return &JSONSchema{}
+	}
+}
+
+// structSchema returns the schema of a struct type. Named structs are added to the
+// components and referenced, which also terminates recursive types.
+//
+// Types with a custom JSON encoding are assumed to encode their fields as tagged,
+// except for numbers and byte slices which are usually hex encoded.
+func (b *schemaBuilder) structSchema(typ reflect.Type, marshaler bool) *JSONSchema {
+	if marshaler && !hasTaggedFields(typ) {
+		return &JSONSchema{} // opaque encoding
+	}
+	if typ.Name() == "" {
+		return b.objectSchema(typ, marshaler)
+	}
+	name, ok := b.names[typ]
+	if !ok {
+		name = b.componentName(typ)
+		b.names[typ] = name
+		b.defs[name] = nil // reserve the name while building
+		b.defs[name] = b.objectSchema(typ, marshaler)
+	}
+	return &JSONSchema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName returns a unique component name for the given type.
+func (b *schemaBuilder) componentName(typ reflect.Type) string {
+	sanitize := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_') {
+				return r
+			}
+			return '_'
+		}, s)
+	}
+	name := sanitize(typ.Name())
+	if _, taken := b.defs[name]; taken {
+		name = sanitize(typ.String())
+	}
+	for i := 2; ; i++ {
+		if _, taken := b.defs[name]; !taken {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", sanitize(typ.String()), i)
+	}
+}
+
+// objectSchema returns the schema of the fields of a struct type.
+func (b *schemaBuilder) objectSchema(typ reflect.Type, marshaler bool) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	b.addFields(schema, typ, marshaler)
+	return schema
+}
+
+// addFields adds the JSON encoded fields of a struct type to the schema, inlining
+// embedded structs like encoding/json does.
+func (b *schemaBuilder) addFields(schema *JSONSchema, typ reflect.Type, marshaler bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ftype := field.Type
+		for ftype.Kind() == reflect.Ptr {
+			ftype = ftype.Elem()
+		}
+		if field.Anonymous && name == "" && ftype.Kind() == reflect.Struct {
+			b.addFields(schema, ftype, marshaler)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := schema.Properties[name]; ok {
+			continue // shadowed by a shallower field
+		}
+		if marshaler {
+			switch {
+			case ftype == reflect.TypeFor[big.Int]() || isUnsignedKind(ftype.Kind()):
+				schema.Properties[name] = quantitySchema
+				continue
+			case ftype == reflect.TypeFor[[]byte]():
+				schema.Properties[name] = bytesSchema
+				continue
+			}
+		}
+		schema.Properties[name] = b.schema(field.Type)
+	}
+}
+
+// hasTaggedFields reports whether the struct type has any fields with a JSON tag.
+func hasTaggedFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := typ.Field(i).Tag.Lookup("json"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnsignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
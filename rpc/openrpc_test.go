@@ -0,0 +1,128 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type openRPCTestArgs struct {
+	From   common.Address   `json:"from"`
+	Data   *hexutil.Bytes   `json:"data,omitempty"`
+	Nested *openRPCTestArgs `json:"nested"`
+	Secret string           `json:"-"`
+}
+
+type openRPCTestService struct{}
+
+func (s *openRPCTestService) GetBalance(addr common.Address, block BlockNumberOrHash) (*hexutil.Big, error) {
+	return nil, nil
+}
+
+func (s *openRPCTestService) Send(ctx context.Context, args openRPCTestArgs, hash *common.Hash, other *common.Hash) hexutil.Bytes {
+	return nil
+}
+
+func (s *openRPCTestService) Clear() error {
+	return nil
+}
+
+func TestOpenRPCDiscover(t *testing.T) {
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", new(openRPCTestService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("nftest", new(notificationTestService)); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenRPC != openRPCVersion {
+		t.Fatalf("wrong OpenRPC version %q", doc.OpenRPC)
+	}
+	methods := make(map[string]*OpenRPCMethod)
+	for _, m := range doc.Methods {
+		methods[m.Name] = m
+	}
+	for _, name := range []string{"rpc_discover", "rpc_modules", "test_getBalance", "test_send", "test_clear", "nftest_subscribe", "nftest_unsubscribe"} {
+		if methods[name] == nil {
+			t.Fatalf("method %s missing", name)
+		}
+	}
+	if !slices.IsSortedFunc(doc.Methods, func(a, b *OpenRPCMethod) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Fatal("methods not sorted")
+	}
+
+	// Parameter names and schemas are derived from the argument types.
+	checkParams := func(method string, names []string, required []bool) {
+		t.Helper()
+		m := methods[method]
+		if len(m.Params) != len(names) {
+			t.Fatalf("%s: wrong number of params %d", method, len(m.Params))
+		}
+		for i, p := range m.Params {
+			if p.Name != names[i] || p.Required != required[i] {
+				t.Errorf("%s: param %d is %q (required %v), want %q (required %v)", method, i, p.Name, p.Required, names[i], required[i])
+			}
+		}
+	}
+	checkParams("test_getBalance", []string{"address", "block"}, []bool{true, true})
+	checkParams("test_send", []string{"openRPCTestArgs", "hash", "hash2"}, []bool{true, false, false})
+	checkParams("test_clear", nil, nil)
+
+	if have := methods["test_getBalance"].Params[0].Schema; !reflect.DeepEqual(have, addressSchema) {
+		t.Errorf("wrong address schema: %+v", have)
+	}
+	if have := methods["test_getBalance"].Result.Schema; !reflect.DeepEqual(have, quantitySchema) {
+		t.Errorf("wrong result schema: %+v", have)
+	}
+	if have := methods["test_clear"].Result.Schema; have.Type != "null" {
+		t.Errorf("wrong result schema of method without result: %+v", have)
+	}
+
+	// Named structs are described as components.
+	ref := methods["test_send"].Params[0].Schema.Ref
+	if ref != "#/components/schemas/openRPCTestArgs" {
+		t.Fatalf("wrong struct reference %q", ref)
+	}
+	args := doc.Components.Schemas["openRPCTestArgs"]
+	if args == nil || args.Type != "object" {
+		t.Fatalf("struct component missing: %+v", args)
+	}
+	if len(args.Properties) != 3 || args.Properties["nested"].Ref != ref || !reflect.DeepEqual(args.Properties["data"], bytesSchema) {
+		t.Fatalf("wrong struct properties: %+v", args.Properties)
+	}
+
+	// Subscriptions are selected by the first parameter of the subscribe method.
+	sub := methods["nftest_subscribe"].Params[0].Schema
+	if !slices.Contains(sub.Enum, "someSubscription") {
+		t.Fatalf("subscription missing from %v", sub.Enum)
+	}
+}
@@ -0,0 +1,614 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckMaxLag   = 5
+
+	// failoverRetryInterval is the time between two attempts to move a subscription
+	// of a failed endpoint when no other endpoint is available.
+	failoverRetryInterval = time.Second
+)
+
+var errNoEndpoints = errors.New("no endpoints")
+
+// EndpointSelection is the policy choosing the endpoint serving a request of a
+// client connected to multiple endpoints.
+type EndpointSelection int
+
+const (
+	// RoundRobin cycles through the healthy endpoints.
+	RoundRobin EndpointSelection = iota
+
+	// LeastLatency prefers the healthy endpoint which answered the last health
+	// check the fastest.
+	LeastLatency
+)
+
+// DialEndpoints creates a new RPC client balancing requests between the servers at
+// the given URLs. The options configure the connection to each of the servers, as
+// well as the endpoint selection and health checking.
+//
+// Endpoints are checked periodically using eth_syncing and eth_blockNumber. Syncing
+// endpoints, endpoints lagging behind the others and endpoints failing requests are
+// avoided until they recover. If no endpoint is healthy, all of them are tried.
+//
+// Subscriptions and filters stick to the endpoint they were created on. When that
+// endpoint fails, subscriptions are moved to another endpoint transparently, which
+// may cause notifications to be missed or repeated. Filters cannot be moved.
+//
+// The context is used to cancel or time out the initial connection establishment,
+// which fails only if no endpoint can be reached.
+func DialEndpoints(ctx context.Context, urls []string, options ...ClientOption) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, errNoEndpoints
+	}
+	cfg := new(clientConfig)
+	for _, opt := range options {
+		opt.applyOption(cfg)
+	}
+	p := &endpointPool{
+		options:   options,
+		selection: cfg.endpointSelection,
+		interval:  cfg.healthCheckInterval,
+		maxLag:    cfg.healthCheckMaxLag,
+		filters:   make(map[string]*endpoint),
+		headers:   make(map[string]string),
+		quit:      make(chan struct{}),
+	}
+	if p.interval == 0 {
+		p.interval = defaultHealthCheckInterval
+	}
+	if p.maxLag == 0 {
+		p.maxLag = defaultHealthCheckMaxLag
+	}
+	for _, rawurl := range urls {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return nil, err
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:     rawurl,
+			notify:  u.Scheme != "http" && u.Scheme != "https",
+			healthy: true,
+		})
+	}
+	// Establish the initial connections, at least one must succeed.
+	var (
+		wg      sync.WaitGroup
+		errs    = make([]error, len(p.endpoints))
+		dialed  bool
+		lastErr error
+	)
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = p.dial(ctx, ep)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			p.endpoints[i].fail(err)
+			lastErr = err
+		} else {
+			dialed = true
+		}
+	}
+	if !dialed {
+		return nil, lastErr
+	}
+	p.checkHealth()
+	p.wg.Add(1)
+	go p.loop()
+
+	return &Client{pool: p, services: new(serviceRegistry), idgen: randomIDGenerator()}, nil
+}
+
+// endpoint is a server of a client connected to multiple endpoints.
+type endpoint struct {
+	url    string
+	notify bool // transport supports subscriptions
+
+	mu      sync.Mutex
+	client  *Client       // nil until connected
+	healthy bool          // passed the last health check and serves requests
+	latency time.Duration // duration of the last health check
+}
+
+// fail marks the endpoint unhealthy until the next successful health check.
+func (ep *endpoint) fail(err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if ep.healthy {
+		log.Debug("RPC endpoint failed", "url", ep.url, "err", err)
+	}
+	ep.healthy = false
+}
+
+// state returns the health and latency of the endpoint.
+func (ep *endpoint) state() (bool, time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.healthy, ep.latency
+}
+
+// endpointPool distributes the requests of a client between multiple endpoints.
+type endpointPool struct {
+	options   []ClientOption
+	endpoints []*endpoint
+	selection EndpointSelection
+	interval  time.Duration
+	maxLag    uint64
+	next      atomic.Uint32 // round-robin position
+
+	mu      sync.Mutex
+	filters map[string]*endpoint // endpoints of installed filters by ID
+	headers map[string]string    // set by SetHeader, applied to new connections
+
+	closeOnce sync.Once
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// dial returns the client of an endpoint, connecting to it if necessary.
+func (p *endpointPool) dial(ctx context.Context, ep *endpoint) (*Client, error) {
+	ep.mu.Lock()
+	client := ep.client
+	ep.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+	client, err := DialOptions(ctx, ep.url, p.options...)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	for key, value := range p.headers {
+		client.SetHeader(key, value)
+	}
+	p.mu.Unlock()
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	select {
+	case <-p.quit:
+		client.Close()
+		return nil, ErrClientQuit
+	default:
+	}
+	if ep.client != nil {
+		// Connected concurrently, drop the new connection.
+		client.Close()
+		return ep.client, nil
+	}
+	ep.client = client
+	return client, nil
+}
+
+// candidates returns the endpoints in the order they should be tried. Healthy
+// endpoints are ordered by the selection policy, the others are tried last.
+func (p *endpointPool) candidates() []*endpoint {
+	var (
+		healthy, unhealthy []*endpoint
+		latencies          = make(map[*endpoint]time.Duration)
+	)
+	for _, ep := range p.endpoints {
+		ok, latency := ep.state()
+		if ok {
+			healthy = append(healthy, ep)
+			latencies[ep] = latency
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	switch p.selection {
+	case LeastLatency:
+		slices.SortStableFunc(healthy, func(a, b *endpoint) int {
+			return cmp.Compare(latencies[a], latencies[b])
+		})
+	default:
+		if len(healthy) > 1 {
+			start := int(p.next.Add(1)) % len(healthy)
+			healthy = slices.Concat(healthy[start:], healthy[:start])
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// isEndpointFailure reports whether a request failed because of the endpoint,
+// meaning it may succeed on another one.
+func isEndpointFailure(err error) bool {
+	var rpcErr Error
+	switch {
+	case errors.As(err, &rpcErr), errors.Is(err, ErrNoResult), errors.Is(err, ErrClientQuit),
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// try runs fn with the client of each candidate endpoint until it succeeds or
+// fails for reasons unrelated to the endpoint.
+func (p *endpointPool) try(ctx context.Context, candidates []*endpoint, fn func(*endpoint, *Client) error) error {
+	lastErr := errNoEndpoints
+	for _, ep := range candidates {
+		if p.closed() {
+			return ErrClientQuit
+		}
+		client, err := p.dial(ctx, ep)
+		if err == nil {
+			if err = fn(ep, client); err == nil || !isEndpointFailure(err) {
+				return err
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ep.fail(err)
+		lastErr = err
+	}
+	return lastErr
+}
+
+// call performs a method call on the first available endpoint. Filter methods are
+// routed to the endpoint which installed the filter.
+func (p *endpointPool) call(ctx context.Context, result interface{}, method string, args []interface{}) error {
+	candidates := p.candidates()
+	id, isFilterAccess := filterAccess(method, args)
+	if isFilterAccess {
+		p.mu.Lock()
+		ep := p.filters[id]
+		if method[strings.IndexByte(method, '_')+1:] == "uninstallFilter" {
+			delete(p.filters, id)
+		}
+		p.mu.Unlock()
+		if ep != nil {
+			candidates = []*endpoint{ep}
+		}
+	}
+	var raw json.RawMessage
+	err := p.try(ctx, candidates, func(ep *endpoint, client *Client) error {
+		if err := client.CallContext(ctx, &raw, method, args...); err != nil {
+			return err
+		}
+		if isFilterCreation(method) {
+			var id string
+			if json.Unmarshal(raw, &id) == nil {
+				p.mu.Lock()
+				p.filters[id] = ep
+				p.mu.Unlock()
+			}
+		}
+		return nil
+	})
+	// Filters expire on the endpoint if they are not polled, forget them once
+	// the endpoint no longer knows them.
+	if isFilterAccess && isFilterNotFound(err) {
+		p.mu.Lock()
+		delete(p.filters, id)
+		p.mu.Unlock()
+	}
+	if err != nil || result == nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// batchCall sends a batch to the first available endpoint.
+func (p *endpointPool) batchCall(ctx context.Context, b []BatchElem) error {
+	return p.try(ctx, p.candidates(), func(_ *endpoint, client *Client) error {
+		return client.BatchCallContext(ctx, b)
+	})
+}
+
+// notify sends a notification to the first available endpoint.
+func (p *endpointPool) notify(ctx context.Context, method string, args []interface{}) error {
+	return p.try(ctx, p.candidates(), func(_ *endpoint, client *Client) error {
+		return client.Notify(ctx, method, args...)
+	})
+}
+
+// subscribe creates a subscription relaying the notifications of a subscription on
+// one of the endpoints, which is moved to another endpoint if it fails.
+func (p *endpointPool) subscribe(ctx context.Context, c *Client, namespace string, channel reflect.Value, args []interface{}) (*ClientSubscription, error) {
+	ep, esub, in, err := p.subscribeAny(ctx, namespace, args)
+	if err != nil {
+		return nil, err
+	}
+	sub := newClientSubscription(c, namespace, channel)
+	sub.relayed = true
+	go sub.run()
+
+	p.wg.Add(1)
+	go p.relay(sub, args, ep, esub, in)
+	return sub, nil
+}
+
+// subscribeAny subscribes on the first available endpoint supporting subscriptions.
+func (p *endpointPool) subscribeAny(ctx context.Context, namespace string, args []interface{}) (*endpoint, *ClientSubscription, chan json.RawMessage, error) {
+	var candidates []*endpoint
+	for _, ep := range p.candidates() {
+		if ep.notify {
+			candidates = append(candidates, ep)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil, ErrNotificationsUnsupported
+	}
+	var (
+		subEp *endpoint
+		sub   *ClientSubscription
+		in    = make(chan json.RawMessage)
+	)
+	err := p.try(ctx, candidates, func(ep *endpoint, client *Client) (err error) {
+		subEp = ep
+		sub, err = client.Subscribe(ctx, namespace, in, args...)
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return subEp, sub, in, nil
+}
+
+// relay forwards the notifications of an endpoint subscription to sub, moving the
+// subscription to another endpoint when the current one fails.
+func (p *endpointPool) relay(sub *ClientSubscription, args []interface{}, ep *endpoint, esub *ClientSubscription, in chan json.RawMessage) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case msg := <-in:
+			if !sub.deliver(msg) {
+				esub.Unsubscribe()
+				return
+			}
+		case <-sub.forwardDone:
+			esub.Unsubscribe()
+			return
+		case <-p.quit:
+			esub.Unsubscribe()
+			sub.close(ErrClientQuit)
+			return
+		case err := <-esub.Err():
+			if _, ok := err.(*jsonError); ok {
+				// The endpoint terminated the subscription, which is not
+				// an endpoint failure.
+				sub.close(&subscriptionClosedError{err})
+				return
+			}
+			ep.fail(fmt.Errorf("subscription failed: %v", err))
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+				ep, esub, in, err = p.subscribeAny(ctx, sub.namespace, args)
+				cancel()
+				if err == nil {
+					log.Debug("Moved RPC subscription", "url", ep.url)
+					break
+				}
+				if !isEndpointFailure(err) && err != ErrClientQuit {
+					sub.close(err)
+					return
+				}
+				select {
+				case <-time.After(failoverRetryInterval):
+				case <-sub.forwardDone:
+					return
+				case <-p.quit:
+					sub.close(ErrClientQuit)
+					return
+				}
+			}
+		}
+	}
+}
+
+// loop runs the periodic health checks.
+func (p *endpointPool) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.checkHealth()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// checkHealth checks all endpoints, marking those which fail the check, are syncing
+// or lag behind the best endpoint unhealthy.
+func (p *endpointPool) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		heads   = make([]uint64, len(p.endpoints))
+		errs    = make([]error, len(p.endpoints))
+		latency = make([]time.Duration, len(p.endpoints))
+		best    uint64
+	)
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			heads[i], errs[i] = p.checkEndpoint(ctx, ep)
+			latency[i] = time.Since(start)
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err == nil {
+			best = max(best, heads[i])
+		}
+	}
+	for i, ep := range p.endpoints {
+		err := errs[i]
+		if err == nil && heads[i]+p.maxLag < best {
+			err = fmt.Errorf("lagging behind by %d blocks", best-heads[i])
+		}
+		if err != nil {
+			ep.fail(err)
+			continue
+		}
+		ep.mu.Lock()
+		if !ep.healthy {
+			log.Debug("RPC endpoint recovered", "url", ep.url)
+		}
+		ep.healthy, ep.latency = true, latency[i]
+		ep.mu.Unlock()
+	}
+}
+
+// checkEndpoint returns the head block of an endpoint, failing if it is syncing.
+func (p *endpointPool) checkEndpoint(ctx context.Context, ep *endpoint) (uint64, error) {
+	client, err := p.dial(ctx, ep)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		syncing json.RawMessage
+		head    hexutil.Uint64
+		batch   = []BatchElem{
+			{Method: "eth_syncing", Result: &syncing},
+			{Method: "eth_blockNumber", Result: &head},
+		}
+	)
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return 0, err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return 0, elem.Error
+		}
+	}
+	if string(syncing) != "false" {
+		return 0, errors.New("syncing")
+	}
+	return uint64(head), nil
+}
+
+// setHeader sets a HTTP header on all current and future connections.
+func (p *endpointPool) setHeader(key, value string) {
+	p.mu.Lock()
+	p.headers[key] = value
+	p.mu.Unlock()
+
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if ep.client != nil {
+			ep.client.SetHeader(key, value)
+		}
+		ep.mu.Unlock()
+	}
+}
+
+// supportsSubscriptions reports whether any endpoint supports subscriptions.
+func (p *endpointPool) supportsSubscriptions() bool {
+	for _, ep := range p.endpoints {
+		if ep.notify {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *endpointPool) closed() bool {
+	select {
+	case <-p.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the health checks and closes all connections.
+func (p *endpointPool) close() {
+	p.closeOnce.Do(func() {
+		close(p.quit)
+		p.wg.Wait()
+		for _, ep := range p.endpoints {
+			ep.mu.Lock()
+			if ep.client != nil {
+				ep.client.Close()
+			}
+			ep.mu.Unlock()
+		}
+	})
+}
+
+// isFilterCreation reports whether the method installs a filter, e.g. eth_newFilter.
+func isFilterCreation(method string) bool {
+	_, name, ok := strings.Cut(method, "_")
+	return ok && strings.HasPrefix(name, "new") && strings.HasSuffix(name, "Filter")
+}
+
+// isFilterNotFound reports whether the endpoint rejected a request because the
+// accessed filter is not installed (anymore).
+func isFilterNotFound(err error) bool {
+	var rpcErr Error
+	return errors.As(err, &rpcErr) && rpcErr.Error() == "filter not found"
+}
+
+// filterAccess returns the filter ID if the method accesses an installed filter.
+func filterAccess(method string, args []interface{}) (string, bool) {
+	_, name, ok := strings.Cut(method, "_")
+	if !ok || len(args) == 0 {
+		return "", false
+	}
+	switch name {
+	case "getFilterChanges", "getFilterLogs", "uninstallFilter":
+		var id string
+		switch arg := args[0].(type) {
+		case string:
+			id = arg
+		case ID:
+			id = string(arg)
+		default:
+			return "", false
+		}
+		return id, true
+	}
+	return "", false
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// poolTestService is a minimal eth namespace identifying the serving endpoint.
+type poolTestService struct {
+	name    string
+	head    atomic.Uint64
+	syncing atomic.Bool
+	filters sync.Map // installed filter IDs
+}
+
+func (s *poolTestService) Syncing() any {
+	if s.syncing.Load() {
+		return map[string]any{"currentBlock": hexutil.Uint64(s.head.Load())}
+	}
+	return false
+}
+
+func (s *poolTestService) BlockNumber() hexutil.Uint64 { return hexutil.Uint64(s.head.Load()) }
+
+func (s *poolTestService) Name() string { return s.name }
+
+func (s *poolTestService) NewFilter() ID {
+	id := ID(s.name + "-filter")
+	s.filters.Store(id, struct{}{})
+	return id
+}
+
+func (s *poolTestService) GetFilterChanges(id ID) (string, error) {
+	if _, ok := s.filters.Load(id); !ok {
+		return "", errors.New("filter not found")
+	}
+	return s.name, nil
+}
+
+func (s *poolTestService) Names(ctx context.Context) (*Subscription, error) {
+	notifier, _ := NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				notifier.Notify(sub.ID, s.name)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// newPoolTestServer starts a server of the pool test service, returning the service,
+// a function shutting the server down and its URL.
+func newPoolTestServer(t *testing.T, name string, ws bool) (*poolTestService, func(), string) {
+	t.Helper()
+
+	service := &poolTestService{name: name}
+	service.head.Store(100)
+	server := NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	if ws {
+		httpsrv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+		t.Cleanup(httpsrv.Close)
+		stop := func() { server.Stop(); httpsrv.Close() }
+		return service, stop, "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	}
+	httpsrv := httptest.NewServer(server)
+	t.Cleanup(httpsrv.Close)
+	return service, httpsrv.Close, httpsrv.URL
+}
+
+// callNames returns the names of the endpoints serving n calls.
+func callNames(t *testing.T, client *Client, n int) map[string]int {
+	t.Helper()
+
+	names := make(map[string]int)
+	for i := 0; i < n; i++ {
+		var name string
+		if err := client.Call(&name, "eth_name"); err != nil {
+			t.Fatal(err)
+		}
+		names[name]++
+	}
+	return names
+}
+
+func TestDialEndpointsHealth(t *testing.T) {
+	t.Parallel()
+
+	a, stopA, urlA := newPoolTestServer(t, "a", false)
+	b, _, urlB := newPoolTestServer(t, "b", false)
+
+	client, err := DialEndpoints(context.Background(), []string{urlA, urlB}, WithHealthCheck(time.Hour, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	pool := client.pool
+
+	// Requests are balanced between healthy endpoints
+	if names := callNames(t, client, 10); names["a"] != 5 || names["b"] != 5 {
+		t.Fatalf("requests not balanced: %v", names)
+	}
+	// Lagging endpoints are avoided
+	b.head.Store(90)
+	pool.checkHealth()
+	if names := callNames(t, client, 10); names["a"] != 10 {
+		t.Fatalf("lagging endpoint used: %v", names)
+	}
+	// Syncing endpoints are avoided
+	b.head.Store(100)
+	a.syncing.Store(true)
+	pool.checkHealth()
+	if names := callNames(t, client, 10); names["b"] != 10 {
+		t.Fatalf("syncing endpoint used: %v", names)
+	}
+	// Failed endpoints are avoided, even before the next health check
+	a.syncing.Store(false)
+	pool.checkHealth()
+	stopA()
+	if names := callNames(t, client, 10); names["b"] != 10 {
+		t.Fatalf("failed endpoint used: %v", names)
+	}
+	if healthy, _ := pool.endpoints[0].state(); healthy {
+		t.Fatal("failed endpoint still considered healthy")
+	}
+}
+
+func TestDialEndpointsLeastLatency(t *testing.T) {
+	t.Parallel()
+
+	_, _, urlA := newPoolTestServer(t, "a", false)
+	_, _, urlB := newPoolTestServer(t, "b", false)
+
+	client, err := DialEndpoints(context.Background(), []string{urlA, urlB}, WithHealthCheck(time.Hour, 5), WithEndpointSelection(LeastLatency))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	pool := client.pool
+
+	pool.endpoints[0].latency, pool.endpoints[1].latency = 20*time.Millisecond, 10*time.Millisecond
+	if names := callNames(t, client, 10); names["b"] != 10 {
+		t.Fatalf("slower endpoint used: %v", names)
+	}
+}
+
+func TestDialEndpointsFilters(t *testing.T) {
+	t.Parallel()
+
+	_, _, urlA := newPoolTestServer(t, "a", false)
+	_, _, urlB := newPoolTestServer(t, "b", false)
+
+	client, err := DialEndpoints(context.Background(), []string{urlA, urlB}, WithHealthCheck(time.Hour, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var id ID
+	if err := client.Call(&id, "eth_newFilter"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		var name string
+		if err := client.Call(&name, "eth_getFilterChanges", id); err != nil {
+			t.Fatal(err)
+		}
+		if string(id) != name+"-filter" {
+			t.Fatalf("filter %s accessed on endpoint %s", id, name)
+		}
+	}
+}
+
+// Tests that filters unknown to the endpoint which installed them are dropped
+// from the pool.
+func TestDialEndpointsFilterExpiry(t *testing.T) {
+	t.Parallel()
+
+	serviceA, _, urlA := newPoolTestServer(t, "a", false)
+	serviceB, _, urlB := newPoolTestServer(t, "b", false)
+
+	client, err := DialEndpoints(context.Background(), []string{urlA, urlB}, WithHealthCheck(time.Hour, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var id ID
+	if err := client.Call(&id, "eth_newFilter"); err != nil {
+		t.Fatal(err)
+	}
+	// Expire the filter on the endpoint, the pool must forget about it
+	serviceA.filters.Delete(id)
+	serviceB.filters.Delete(id)
+
+	var name string
+	if err := client.Call(&name, "eth_getFilterChanges", id); err == nil || err.Error() != "filter not found" {
+		t.Fatalf("unexpected error for expired filter: %v", err)
+	}
+	client.pool.mu.Lock()
+	defer client.pool.mu.Unlock()
+	if len(client.pool.filters) != 0 {
+		t.Fatalf("expired filter retained: %v", client.pool.filters)
+	}
+}
+
+func TestDialEndpointsSubscriptionFailover(t *testing.T) {
+	t.Parallel()
+
+	_, stopA, urlA := newPoolTestServer(t, "a", true)
+	_, stopB, urlB := newPoolTestServer(t, "b", true)
+
+	client, err := DialEndpoints(context.Background(), []string{urlA, urlB}, WithHealthCheck(time.Hour, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	names := make(chan string)
+	sub, err := client.EthSubscribe(context.Background(), names, "names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	next := func() string {
+		t.Helper()
+		select {
+		case name := <-names:
+			return name
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for notification")
+		}
+		return ""
+	}
+	// Shut down the endpoint serving the subscription, it must move to the other.
+	first := next()
+	if first == "a" {
+		stopA()
+	} else {
+		stopB()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for next() == first {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not moved")
+		}
+	}
+}
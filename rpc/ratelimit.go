@@ -0,0 +1,236 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitIdleTimeout is the time after which the state of an idle client
+	// is dropped.
+	rateLimitIdleTimeout = 10 * time.Minute
+
+	// rateLimitSweepInterval is the interval between two sweeps of idle clients.
+	rateLimitSweepInterval = time.Minute
+)
+
+// RateLimitClass configures the request quotas of a class of methods. The
+// quotas apply to every client separately, where clients are identified by
+// their API key if it's known to the access policy, or by their IP address
+// otherwise.
+type RateLimitClass struct {
+	Name        string   // Name of the class, used in metrics and errors
+	Methods     []string `toml:",omitempty"` // Method patterns (e.g. "debug_trace*"), empty matches all methods
+	Rate        float64  `toml:",omitempty"` // Sustained calls per second, zero means unlimited
+	Burst       int      `toml:",omitempty"` // Maximum burst of calls, defaults to the rate
+	MaxInFlight int      `toml:",omitempty"` // Maximum concurrent calls, zero means unlimited
+}
+
+// matches reports whether the class contains the given method.
+func (c *RateLimitClass) matches(method string) bool {
+	if len(c.Methods) == 0 {
+		return true
+	}
+	for _, pattern := range c.Methods {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitClass is an initialized method class.
+type rateLimitClass struct {
+	RateLimitClass
+
+	limitedGauge  *metrics.Gauge // calls rejected by the rate limit
+	inflightGauge *metrics.Gauge // calls rejected by the concurrency limit
+}
+
+// clientQuota tracks the remaining quota of a client in a method class.
+type clientQuota struct {
+	bucket   *rate.Limiter
+	inflight int
+	lastSeen mclock.AbsTime
+}
+
+// RateLimiter enforces per-client request rate and concurrency quotas on the
+// calls served by a Server.
+type RateLimiter struct {
+	classes []*rateLimitClass
+	clock   mclock.Clock
+
+	lock      sync.Mutex
+	clients   map[string][]*clientQuota // per-client quotas indexed by class
+	lastSweep mclock.AbsTime
+}
+
+// NewRateLimiter creates a rate limiter from the given method classes. Methods
+// are assigned to the first class matching them, methods matched by no class
+// are not limited. It returns nil if no classes are given.
+func NewRateLimiter(classes []RateLimitClass) (*RateLimiter, error) {
+	return newRateLimiter(classes, mclock.System{})
+}
+
+func newRateLimiter(classes []RateLimitClass, clock mclock.Clock) (*RateLimiter, error) {
+	if len(classes) == 0 {
+		return nil, nil
+	}
+	limiter := &RateLimiter{
+		clock:     clock,
+		clients:   make(map[string][]*clientQuota),
+		lastSweep: clock.Now(),
+	}
+	for _, class := range classes {
+		for _, pattern := range class.Methods {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rate limit class %q: invalid method pattern %q", class.Name, pattern)
+			}
+		}
+		if class.Rate < 0 || class.Burst < 0 || class.MaxInFlight < 0 {
+			return nil, fmt.Errorf("rate limit class %q: negative limit", class.Name)
+		}
+		if class.Rate > 0 && class.Burst == 0 {
+			class.Burst = max(int(math.Ceil(class.Rate)), 1)
+		}
+		limiter.classes = append(limiter.classes, &rateLimitClass{
+			RateLimitClass: class,
+			limitedGauge:   metrics.GetOrRegisterGauge(fmt.Sprintf("%s/%s/rate", rateLimitedGaugeName, class.Name), nil),
+			inflightGauge:  metrics.GetOrRegisterGauge(fmt.Sprintf("%s/%s/inflight", rateLimitedGaugeName, class.Name), nil),
+		})
+	}
+	return limiter, nil
+}
+
+// clientID returns the identifier the quotas of a client are tracked under.
+// Only API keys granted access by the policy are accepted as identity, so that
+// clients can't evade their quota by presenting arbitrary keys.
+func clientID(info *PeerInfo, policy *AccessPolicy) string {
+	if policy.knowsKey(info.APIKey) {
+		return "key:" + info.APIKey
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		host = info.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// acquire checks the quota of the client described by the context for calling
+// the given method. If the call is permitted, the returned function must be
+// called once the call finished.
+func (l *RateLimiter) acquire(ctx context.Context, method string, policy *AccessPolicy) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	info := PeerInfoFromContext(ctx)
+	if info.Transport == "ipc" || info.Transport == "" {
+		return func() {}, nil // local connections are not limited
+	}
+	index := -1
+	for i, class := range l.classes {
+		if class.matches(method) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return func() {}, nil
+	}
+	class := l.classes[index]
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+	if time.Duration(now-l.lastSweep) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	id := clientID(&info, policy)
+	quotas := l.clients[id]
+	if quotas == nil {
+		quotas = make([]*clientQuota, len(l.classes))
+		l.clients[id] = quotas
+	}
+	quota := quotas[index]
+	if quota == nil {
+		quota = new(clientQuota)
+		if class.Rate > 0 {
+			quota.bucket = rate.NewLimiter(rate.Limit(class.Rate), class.Burst)
+		}
+		quotas[index] = quota
+	}
+	quota.lastSeen = now
+
+	if class.MaxInFlight > 0 && quota.inflight >= class.MaxInFlight {
+		class.inflightGauge.Inc(1)
+		return nil, &rateLimitedError{method: method, reason: fmt.Sprintf("too many concurrent %s calls", class.Name)}
+	}
+	if quota.bucket != nil && !quota.bucket.AllowN(l.wallTime(now), 1) {
+		class.limitedGauge.Inc(1)
+		return nil, &rateLimitedError{method: method, reason: fmt.Sprintf("%s call rate exceeded", class.Name)}
+	}
+	quota.inflight++
+	return func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		quota.inflight--
+	}, nil
+}
+
+// wallTime converts the monotonic time of the clock into the time used by the
+// token buckets.
+func (l *RateLimiter) wallTime(now mclock.AbsTime) time.Time {
+	return time.Unix(0, int64(now))
+}
+
+// sweep drops the state of idle clients. The caller must hold the lock.
+func (l *RateLimiter) sweep(now mclock.AbsTime) {
+	for id, quotas := range l.clients {
+		idle := true
+		for _, quota := range quotas {
+			if quota != nil && (quota.inflight > 0 || time.Duration(now-quota.lastSeen) < rateLimitIdleTimeout) {
+				idle = false
+				break
+			}
+		}
+		if idle {
+			delete(l.clients, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+// SetRateLimiter configures the per-client quotas enforced on method calls. A
+// nil limiter disables rate limiting.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
+
+func peerContext(transport, addr, key string) context.Context {
+	return context.WithValue(context.Background(), peerInfoContextKey{}, PeerInfo{Transport: transport, RemoteAddr: addr, APIKey: key})
+}
+
+func TestRateLimiter(t *testing.T) {
+	clock := new(mclock.Simulated)
+	limiter, err := newRateLimiter([]RateLimitClass{
+		{Name: "trace", Methods: []string{"debug_trace*"}, Rate: 1, Burst: 2, MaxInFlight: 1},
+		{Name: "default", Rate: 10},
+	}, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewAccessPolicy([]AccessRule{{Name: "keyed", APIKeys: []string{"secret"}}, {Name: "public"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		alice    = peerContext("http", "10.0.0.1:1000", "")
+		alice2   = peerContext("ws", "10.0.0.1:2000", "")
+		bob      = peerContext("http", "10.0.0.2:1000", "")
+		keyed    = peerContext("http", "10.0.0.1:1000", "secret")
+		unknown  = peerContext("http", "10.0.0.1:1000", "bogus")
+		local    = peerContext("ipc", "", "")
+		acquired []func()
+	)
+	acquire := func(ctx context.Context, method string, ok bool) {
+		t.Helper()
+		release, err := limiter.acquire(ctx, method, policy)
+		if ok && err != nil {
+			t.Fatalf("%s rejected: %v", method, err)
+		}
+		if !ok {
+			var rpcErr Error
+			if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeRateLimited {
+				t.Fatalf("%s not rate limited: %v", method, err)
+			}
+			return
+		}
+		acquired = append(acquired, release)
+	}
+	releaseAll := func() {
+		for _, release := range acquired {
+			release()
+		}
+		acquired = acquired[:0]
+	}
+	// The concurrency limit applies per client, identified by its address or
+	// by its API key if the access policy knows it
+	acquire(alice, "debug_traceCall", true)
+	acquire(alice2, "debug_traceTransaction", false)
+	acquire(unknown, "debug_traceTransaction", false)
+	acquire(bob, "debug_traceCall", true)
+	acquire(keyed, "debug_traceCall", true)
+	releaseAll()
+
+	// The burst of the class is exhausted after two calls until refilled
+	acquire(alice, "debug_traceCall", true)
+	releaseAll()
+	acquire(alice, "debug_traceCall", false)
+	clock.Run(time.Second)
+	acquire(alice, "debug_traceCall", true)
+	releaseAll()
+
+	// Other methods fall into the default class
+	for i := 0; i < 10; i++ {
+		acquire(alice, "eth_call", true)
+	}
+	acquire(alice, "eth_call", false)
+	acquire(bob, "eth_call", true)
+
+	// Local connections are never limited
+	for i := 0; i < 20; i++ {
+		acquire(local, "eth_call", true)
+	}
+	releaseAll()
+
+	// Idle clients are dropped eventually
+	clock.Run(rateLimitIdleTimeout + rateLimitSweepInterval)
+	acquire(bob, "eth_call", true)
+	releaseAll()
+	if len(limiter.clients) != 1 {
+		t.Fatalf("idle clients not dropped: %d tracked", len(limiter.clients))
+	}
+	for id, quotas := range limiter.clients {
+		for i, quota := range quotas {
+			if quota != nil && quota.inflight != 0 {
+				t.Fatalf("client %s class %d: %d calls still in flight", id, i, quota.inflight)
+			}
+		}
+	}
+}
+
+func TestRateLimiterConfig(t *testing.T) {
+	for _, classes := range [][]RateLimitClass{
+		{{Name: "bad", Methods: []string{"["}}},
+		{{Name: "negative", Rate: -1}},
+		{{Name: "negative", MaxInFlight: -1}},
+	} {
+		if _, err := NewRateLimiter(classes); err == nil {
+			t.Errorf("invalid classes %+v accepted", classes)
+		}
+	}
+	if limiter, err := NewRateLimiter(nil); limiter != nil || err != nil {
+		t.Errorf("empty configuration: have %v, %v", limiter, err)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	limiter, err := NewRateLimiter([]RateLimitClass{{Name: "block", Methods: []string{"test_block"}, MaxInFlight: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer()
+	srv.SetRateLimiter(limiter)
+	defer srv.Stop()
+
+	httpsrv := httptest.NewServer(srv)
+	defer httpsrv.Close()
+
+	client, err := Dial(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Occupy the only slot with a blocked call, the next one must be rejected.
+	blocked := make(chan error, 1)
+	go func() { blocked <- client.CallContext(ctx, nil, "test_block") }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		probeCtx, probeCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		err := client.CallContext(probeCtx, nil, "test_block")
+		probeCancel()
+
+		var rpcErr Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errcodeRateLimited {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("concurrent call not rate limited: %v", err)
+		}
+	}
+	// Methods outside of the class are not affected
+	if err := client.Call(nil, "test_echo", "x", 1, &echoArgs{S: "y"}); err != nil {
+		t.Fatalf("unrelated call failed: %v", err)
+	}
+	cancel()
+	<-blocked
+}
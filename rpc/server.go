@@ -57,6 +57,14 @@ type Server struct {
 	httpBodyLimit      int
 	wsReadLimit        int64
 	tracerProvider     trace.TracerProvider
+	accessPolicy       *AccessPolicy
+	rateLimiter        *RateLimiter
+	resultCache        *ResultCache
+	wsCompression      bool
+	httpStreamBatch    bool
+	httpStreamLimit    int
+	subBufferSize      int
+	subOverflow        SubscriptionOverflow
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -101,6 +109,45 @@ func (s *Server) SetWebsocketReadLimit(limit int64) {
 	s.wsReadLimit = limit
 }
 
+// SetWebsocketCompression enables negotiation of permessage-deflate compression
+// with Websocket clients.
+//
+// This method should be called before processing any requests via Websocket server.
+func (s *Server) SetWebsocketCompression(enable bool) {
+	s.wsCompression = enable
+}
+
+// SetHTTPBatchStreaming enables streaming the responses of batches as
+// newline-delimited JSON to HTTP clients asking for it. Streamed responses aren't
+// buffered, so they are not subject to the batch response size limit, but to the
+// given limit instead. A zero limit allows streamed responses of any size.
+//
+// This method should be called before processing any requests via ServeHTTP.
+func (s *Server) SetHTTPBatchStreaming(enable bool, responseLimit int) {
+	s.httpStreamBatch = enable
+	s.httpStreamLimit = responseLimit
+}
+
+// SetSubscriptionBuffer configures the number of notifications queued for each
+// subscription, and the policy applied if a subscriber falls further behind. With
+// a zero size, notifications are written synchronously by Notifier.Notify.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeListener etc.
+func (s *Server) SetSubscriptionBuffer(size int, overflow SubscriptionOverflow) {
+	s.subBufferSize = size
+	s.subOverflow = overflow
+}
+
+// SetResultCache configures the cache storing the results of method calls which
+// marked them final. A nil cache disables caching.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetResultCache(cache *ResultCache) {
+	s.resultCache = cache
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -126,6 +173,11 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		accessPolicy:       s.accessPolicy,
+		rateLimiter:        s.rateLimiter,
+		resultCache:        s.resultCache,
+		subBufferSize:      s.subBufferSize,
+		subOverflow:        s.subOverflow,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -161,8 +213,8 @@ func (s *Server) untrackCodec(codec ServerCodec) {
 
 // serveSingleRequest reads and processes a single RPC request from the given codec. This
 // is used to serve HTTP connections. Subscriptions and reverse calls are not allowed in
-// this mode.
-func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
+// this mode. If streamBatch is set, the responses to a batch are written one by one.
+func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, streamBatch bool) {
 	// Don't serve if server is stopped.
 	if !s.run.Load() {
 		return
@@ -170,6 +222,13 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit, s.tracerProvider)
 	h.allowSubscribe = false
+	if streamBatch {
+		h.streamBatch = true
+		h.batchResponseMaxSize = s.httpStreamLimit
+	}
+	h.accessPolicy = s.accessPolicy
+	h.rateLimiter = s.rateLimiter
+	h.resultCache = s.resultCache
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -256,6 +315,14 @@ type PeerInfo struct {
 		Origin    string
 		Host      string
 	}
+
+	// APIKey is the API key presented by an HTTP or WebSocket client, either in
+	// the X-Api-Key header or as the last segment of a /key/<token> URL path.
+	APIKey string
+
+	// JWTClaims contains the string claims of the verified JWT token presented
+	// by the client, if the endpoint requires JWT authentication.
+	JWTClaims map[string]string
 }
 
 type peerInfoContextKey struct{}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
@@ -291,3 +292,112 @@ func TestServerWebsocketReadLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestServerAccessPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NewAccessPolicy([]AccessRule{
+		{Name: "partner", APIKeys: []string{"partner-key"}, Allow: []string{"test_*", "nftest_*"}, Deny: []string{"test_sleep", "nftest_subscribe/hangSubscription"}},
+		{Name: "streams", APIKeys: []string{"stream-key"}, Allow: []string{"nftest_subscribe/someSubscription"}},
+		{Name: "public", Allow: []string{"test_echo"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer()
+	srv.SetAccessPolicy(policy)
+	defer srv.Stop()
+
+	wsHandler := srv.WebsocketHandler([]string{"*"})
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		srv.ServeHTTP(w, r)
+	}))
+	defer httpsrv.Close()
+	wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+	tests := []struct {
+		url     string
+		opts    []ClientOption
+		method  string
+		allowed bool
+	}{
+		// Anonymous clients fall back to the public rule
+		{httpsrv.URL, nil, "test_echo", true},
+		{httpsrv.URL, nil, "test_rets", false},
+		{wsURL, nil, "test_rets", false},
+
+		// API keys are accepted in the header and the path
+		{httpsrv.URL, []ClientOption{WithHeader(APIKeyHeader, "partner-key")}, "test_rets", true},
+		{httpsrv.URL + "/key/partner-key", nil, "test_rets", true},
+		{wsURL + "/key/partner-key", nil, "test_rets", true},
+		{httpsrv.URL + "/key/partner-key", nil, "test_sleep", false},
+
+		// Unknown API keys fall back to the public rule
+		{httpsrv.URL + "/key/unknown", nil, "test_rets", false},
+		{httpsrv.URL + "/key/unknown", nil, "test_echo", true},
+	}
+	for i, tt := range tests {
+		client, err := DialOptions(context.Background(), tt.url, tt.opts...)
+		if err != nil {
+			t.Fatalf("test %d: can't dial: %v", i, err)
+		}
+		var args []any
+		switch tt.method {
+		case "test_echo":
+			args = []any{"x", 1, &echoArgs{S: "y"}}
+		case "test_sleep":
+			args = []any{time.Duration(0)}
+		}
+		err = client.Call(nil, tt.method, args...)
+		client.Close()
+
+		if tt.allowed && err != nil {
+			t.Errorf("test %d: %s via %s failed: %v", i, tt.method, tt.url, err)
+		}
+		if !tt.allowed {
+			var rpcErr Error
+			if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeAccessDenied {
+				t.Errorf("test %d: %s via %s not denied: %v", i, tt.method, tt.url, err)
+			}
+		}
+	}
+	// Subscriptions are subject to the policy as well, by the subscribe method
+	// and by the name of the subscription
+	subTests := []struct {
+		key     string
+		name    string
+		args    []any
+		allowed bool
+	}{
+		{"", "someSubscription", []any{1, 1}, false},
+		{"partner-key", "someSubscription", []any{1, 1}, true},
+		{"partner-key", "hangSubscription", []any{1}, false},
+		{"stream-key", "someSubscription", []any{1, 1}, true},
+		{"stream-key", "failingSubscription", []any{1, 1}, false},
+	}
+	for i, tt := range subTests {
+		client, err := DialOptions(context.Background(), wsURL, WithHeader(APIKeyHeader, tt.key))
+		if err != nil {
+			t.Fatalf("subscription test %d: can't dial: %v", i, err)
+		}
+		sub, err := client.Subscribe(context.Background(), "nftest", make(chan int, 1), append([]any{tt.name}, tt.args...)...)
+		if err == nil {
+			sub.Unsubscribe()
+		}
+		client.Close()
+
+		if tt.allowed && err != nil {
+			t.Errorf("subscription test %d: %s with key %q failed: %v", i, tt.name, tt.key, err)
+		}
+		if !tt.allowed {
+			var rpcErr Error
+			if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeAccessDenied {
+				t.Errorf("subscription test %d: %s with key %q not denied: %v", i, tt.name, tt.key, err)
+			}
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -45,6 +46,10 @@ var (
 
 	// ErrSubscriptionNotFound is returned when the notification for the given id is not found
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrSubscriptionClosed is returned by Notify after the subscription was
+	// terminated by the server.
+	ErrSubscriptionClosed = errors.New("subscription closed")
 )
 
 var globalGen = randomIDGenerator()
@@ -89,6 +94,25 @@ func encodeID(b []byte) ID {
 	return ID("0x" + id)
 }
 
+// SubscriptionOverflow is the policy applied when the notification queue of a
+// subscription is full because the client doesn't keep up with reading them.
+type SubscriptionOverflow string
+
+const (
+	// OverflowDisconnect closes the connection of the slow subscriber.
+	OverflowDisconnect SubscriptionOverflow = "disconnect"
+
+	// OverflowDropOldest drops the oldest queued notification. The subscriber is
+	// informed about the number of dropped notifications by a lag notification
+	// sent before the next delivered one.
+	OverflowDropOldest SubscriptionOverflow = "drop-oldest"
+)
+
+// Valid reports whether the policy is known.
+func (o SubscriptionOverflow) Valid() bool {
+	return o == OverflowDisconnect || o == OverflowDropOldest
+}
+
 type notifierKey struct{}
 
 // NotifierFromContext returns the Notifier value stored in ctx, if any.
@@ -108,6 +132,14 @@ type Notifier struct {
 	buffer       []any
 	callReturned bool
 	activated    bool
+	closed       bool
+
+	// Notifications are queued for a background sender if the handler has a
+	// subscription buffer configured, so slow clients don't block Notify.
+	queue   []any
+	sending bool   // whether sendLoop is running
+	dropped uint64 // notifications dropped since the last lag notification
+	err     error  // write error of sendLoop, returned by subsequent Notify calls
 }
 
 // CreateSubscription returns a new subscription that is coupled to the
@@ -129,6 +161,9 @@ func (n *Notifier) CreateSubscription() *Subscription {
 
 // Notify sends a notification to the client with the given data as payload.
 // If an error occurs the RPC connection is closed and the error is returned.
+//
+// If the server buffers notifications, Notify returns as soon as the notification
+// is queued and errors of earlier deliveries are returned instead.
 func (n *Notifier) Notify(id ID, data any) error {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -138,19 +173,63 @@ func (n *Notifier) Notify(id ID, data any) error {
 	} else if n.sub.ID != id {
 		panic("Notify with wrong ID")
 	}
+	if n.closed {
+		return ErrSubscriptionClosed
+	}
 	if n.activated {
-		return n.send(n.sub, data)
+		return n.deliver(data)
 	}
 	n.buffer = append(n.buffer, data)
 	return nil
 }
 
+// Close terminates the subscription with the given error. The error is sent to
+// the client after all notifications delivered before, and subsequent calls to
+// Notify fail with ErrSubscriptionClosed.
+func (n *Notifier) Close(id ID, err error) error {
+	n.mu.Lock()
+	if n.sub == nil {
+		n.mu.Unlock()
+		panic("can't Close before subscription is created")
+	} else if n.sub.ID != id {
+		n.mu.Unlock()
+		panic("Close with wrong ID")
+	}
+	if n.closed {
+		n.mu.Unlock()
+		return ErrSubscriptionClosed
+	}
+	n.closed = true
+	taken := n.callReturned
+	n.mu.Unlock()
+
+	// The subscription is dropped before the error is sent, so the client can't
+	// unsubscribe after seeing the error. Subscriptions not yet taken by the
+	// handler are never registered. The handler lock is taken before the
+	// notifier lock when subscriptions are registered, so n.mu isn't held here.
+	if taken {
+		n.h.dropServerSubscription(id)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.activated {
+		return n.deliver(subscriptionClosed{err})
+	}
+	n.buffer = append(n.buffer, subscriptionClosed{err})
+	return nil
+}
+
 // takeSubscription returns the subscription (if one has been created). No subscription can
-// be created after this call.
+// be created after this call. Subscriptions closed before the call returned are not
+// returned, so they aren't tracked by the handler.
 func (n *Notifier) takeSubscription() *Subscription {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	n.callReturned = true
+	if n.closed {
+		return nil
+	}
 	return n.sub
 }
 
@@ -162,15 +241,89 @@ func (n *Notifier) activate() error {
 	defer n.mu.Unlock()
 
 	for _, data := range n.buffer {
-		if err := n.send(n.sub, data); err != nil {
+		if err := n.deliver(data); err != nil {
 			return err
 		}
 	}
+	n.buffer = nil
 	n.activated = true
 	return nil
 }
 
+// deliver sends a notification right away, or queues it for sendLoop if the
+// handler buffers notifications. The caller must hold n.mu.
+func (n *Notifier) deliver(data any) error {
+	limit := n.h.subBufferSize
+	if limit <= 0 {
+		return n.send(n.sub, data)
+	}
+	if n.err != nil {
+		return n.err
+	}
+	if len(n.queue) >= limit {
+		if n.h.subOverflow != OverflowDropOldest {
+			subOverflowGauge.Inc(1)
+			n.h.log.Warn("Disconnecting slow subscriber", "id", n.sub.ID, "queued", len(n.queue))
+			n.err = ErrSubscriptionQueueOverflow
+			n.queue = nil
+			if codec, ok := n.h.conn.(ServerCodec); ok {
+				go codec.close()
+			}
+			return n.err
+		}
+		subDroppedGauge.Inc(1)
+		n.queue[0] = nil
+		n.queue = n.queue[1:]
+		n.dropped++
+	}
+	n.queue = append(n.queue, data)
+	if !n.sending {
+		n.sending = true
+		go n.sendLoop()
+	}
+	return nil
+}
+
+// sendLoop writes queued notifications to the connection until the queue is
+// drained or writing fails.
+func (n *Notifier) sendLoop() {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 || n.err != nil {
+			n.sending = false
+			n.mu.Unlock()
+			return
+		}
+		data, dropped := n.queue[0], n.dropped
+		n.queue[0] = nil
+		n.queue = n.queue[1:]
+		n.dropped = 0
+		n.mu.Unlock()
+
+		var err error
+		if dropped > 0 {
+			err = n.sendLag(n.sub, dropped)
+		}
+		if err == nil {
+			err = n.send(n.sub, data)
+		}
+		if err != nil {
+			n.mu.Lock()
+			n.err, n.queue, n.sending = err, nil, false
+			n.mu.Unlock()
+			return
+		}
+	}
+}
+
+// subscriptionClosed is queued as the last notification of a subscription
+// terminated by the server.
+type subscriptionClosed struct{ err error }
+
 func (n *Notifier) send(sub *Subscription, data any) error {
+	if closed, ok := data.(subscriptionClosed); ok {
+		return n.sendError(sub, closed.err)
+	}
 	msg := jsonrpcSubscriptionNotification{
 		Version: vsn,
 		Method:  n.namespace + notificationMethodSuffix,
@@ -182,6 +335,24 @@ func (n *Notifier) send(sub *Subscription, data any) error {
 	return n.h.conn.writeJSON(context.Background(), &msg, false)
 }
 
+func (n *Notifier) sendLag(sub *Subscription, dropped uint64) error {
+	msg := jsonrpcSubscriptionLag{
+		Version: vsn,
+		Method:  n.namespace + lagMethodSuffix,
+		Params:  subscriptionLag{ID: string(sub.ID), Dropped: dropped},
+	}
+	return n.h.conn.writeJSON(context.Background(), &msg, false)
+}
+
+func (n *Notifier) sendError(sub *Subscription, err error) error {
+	msg := jsonrpcSubscriptionError{
+		Version: vsn,
+		Method:  n.namespace + errorMethodSuffix,
+		Params:  subscriptionError{ID: string(sub.ID), Error: errorMessage(err).Error},
+	}
+	return n.h.conn.writeJSON(context.Background(), &msg, false)
+}
+
 // A Subscription is created by a notifier and tied to that notifier. The client can use
 // this subscription to wait for an unsubscribe request for the client, see Err().
 type Subscription struct {
@@ -223,6 +394,9 @@ type ClientSubscription struct {
 	quit        chan error
 	forwardDone chan struct{}
 	unsubDone   chan struct{}
+
+	dropped atomic.Uint64 // notifications dropped by the server
+	relayed bool          // notifications are relayed from an endpoint subscription
 }
 
 // This is the sentinel value sent on sub.quit when Unsubscribe is called.
@@ -255,6 +429,18 @@ func (sub *ClientSubscription) Err() <-chan error {
 	return sub.err
 }
 
+// ID returns the id the server assigned to the subscription. It is empty for
+// subscriptions of a multi-endpoint client, which may move between servers.
+func (sub *ClientSubscription) ID() string {
+	return sub.subid
+}
+
+// Dropped returns the number of notifications the server dropped because the
+// subscription didn't keep up with receiving them.
+func (sub *ClientSubscription) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
 // Unsubscribe unsubscribes the notification and closes the error channel.
 // It can safely be called more than once.
 func (sub *ClientSubscription) Unsubscribe() {
@@ -278,6 +464,12 @@ func (sub *ClientSubscription) deliver(result json.RawMessage) (ok bool) {
 	}
 }
 
+// subscriptionClosedError is sent to the forwarding loop of a subscription
+// terminated by the server.
+type subscriptionClosedError struct{ err error }
+
+func (e *subscriptionClosedError) Error() string { return e.err.Error() }
+
 // close is called by the client's message dispatcher when the connection is closed.
 func (sub *ClientSubscription) close(err error) {
 	select {
@@ -321,11 +513,16 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(sub.in)},
 		{Dir: reflect.SelectSend, Chan: sub.channel},
 	}
-	buffer := list.New()
-
+	var (
+		buffer = list.New()
+		closed error // error of a subscription terminated by the server
+	)
 	for {
 		var chosen int
 		var recv reflect.Value
+		if buffer.Len() == 0 && closed != nil {
+			return false, closed
+		}
 		if buffer.Len() == 0 {
 			// Idle, omit send case.
 			chosen, recv, _ = reflect.Select(cases[:2])
@@ -341,8 +538,15 @@ func (sub *ClientSubscription) forward() (unsubscribeServer bool, err error) {
 				err = recv.Interface().(error)
 			}
 			if err == errUnsubscribed {
-				// Exiting because Unsubscribe was called, unsubscribe on server.
-				return true, nil
+				// Exiting because Unsubscribe was called, unsubscribe on server
+				// unless it already dropped the subscription.
+				return closed == nil, nil
+			}
+			if e, ok := err.(*subscriptionClosedError); ok {
+				// The server terminated the subscription, deliver the queued
+				// notifications before reporting the error.
+				closed, cases[1].Chan = e.err, reflect.Value{}
+				continue
 			}
 			return false, err
 
@@ -370,6 +574,9 @@ func (sub *ClientSubscription) unmarshal(result json.RawMessage) (interface{}, e
 }
 
 func (sub *ClientSubscription) requestUnsubscribe() error {
+	if sub.relayed {
+		return nil // the relay unsubscribes on the endpoint
+	}
 	var result interface{}
 	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
 	defer cancel()
@@ -20,16 +20,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
 )
 
 func TestNewID(t *testing.T) {
@@ -282,3 +287,187 @@ func TestNotify(t *testing.T) {
 		t.Errorf("have:\n%v\nwant:\n%v\n", have, want)
 	}
 }
+
+// gatedConn is a connection whose writes block until the gate is opened.
+type gatedConn struct {
+	gate    chan struct{}
+	writing chan struct{}
+	out     chan string
+}
+
+func newGatedConn() *gatedConn {
+	return &gatedConn{gate: make(chan struct{}), writing: make(chan struct{}, 100), out: make(chan string, 100)}
+}
+
+func (c *gatedConn) writeJSON(ctx context.Context, msg interface{}, isError bool) error {
+	c.writing <- struct{}{}
+	<-c.gate
+	blob, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.out <- string(blob)
+	return nil
+}
+
+func (c *gatedConn) closed() <-chan interface{} { return nil }
+
+func (c *gatedConn) remoteAddr() string { return "" }
+
+func TestNotifyBufferDropOldest(t *testing.T) {
+	t.Parallel()
+
+	conn := newGatedConn()
+	id := ID("test")
+	notifier := &Notifier{
+		h:         &handler{conn: conn, log: log.Root(), subBufferSize: 2, subOverflow: OverflowDropOldest},
+		namespace: "eth",
+		sub:       &Subscription{ID: id},
+		activated: true,
+	}
+	// Block the sender on the first notification, then overflow the queue.
+	notifier.Notify(id, 0)
+	<-conn.writing
+	for i := 1; i < 5; i++ {
+		if err := notifier.Notify(id, i); err != nil {
+			t.Fatalf("notification %d failed: %v", i, err)
+		}
+	}
+	close(conn.gate)
+
+	want := []string{
+		`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"test","result":0}}`,
+		`{"jsonrpc":"2.0","method":"eth_subscriptionLag","params":{"subscription":"test","dropped":2}}`,
+		`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"test","result":3}}`,
+		`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"test","result":4}}`,
+	}
+	for i, w := range want {
+		select {
+		case have := <-conn.out:
+			if have != w {
+				t.Errorf("message %d mismatch:\nhave %s\nwant %s", i, have, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for message %d", i)
+		}
+	}
+}
+
+func TestNotifyBufferDisconnect(t *testing.T) {
+	t.Parallel()
+
+	conn := newGatedConn()
+	defer close(conn.gate)
+
+	id := ID("test")
+	notifier := &Notifier{
+		h:         &handler{conn: conn, log: log.Root(), subBufferSize: 2, subOverflow: OverflowDisconnect},
+		sub:       &Subscription{ID: id},
+		activated: true,
+	}
+	notifier.Notify(id, 0)
+	<-conn.writing
+	for i := 1; i < 3; i++ {
+		if err := notifier.Notify(id, i); err != nil {
+			t.Fatalf("notification %d failed: %v", i, err)
+		}
+	}
+	for i := 3; i < 5; i++ {
+		if err := notifier.Notify(id, i); err != ErrSubscriptionQueueOverflow {
+			t.Fatalf("notification %d: wrong error %v", i, err)
+		}
+	}
+}
+
+// TestServerCloseSubscription tests that a subscription terminated by the server
+// delivers the pending notifications, followed by the error.
+func TestServerCloseSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		service = &notificationTestService{unsubscribed: make(chan string, 1)}
+		server  = newTestServer()
+	)
+	defer server.Stop()
+	if err := server.RegisterName("nftest", service); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", ch, "failingSubscription", 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case v := <-ch:
+			if v != 10+i {
+				t.Fatalf("wrong notification value %d, want %d", v, 10+i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for notification")
+		}
+	}
+	select {
+	case err := <-sub.Err():
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32602 || err.Error() != "subscription failed" {
+			t.Fatalf("wrong subscription error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for subscription error")
+	}
+	select {
+	case <-service.unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not dropped by the server")
+	}
+}
+
+func TestClientSubscriptionLag(t *testing.T) {
+	t.Parallel()
+
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req jsonrpcMessage
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": "0x1"})
+		conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "method": "nftest_subscriptionLag", "params": map[string]any{"subscription": "0x1", "dropped": 3}})
+		conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "method": "nftest_subscription", "params": map[string]any{"subscription": "0x1", "result": 7}})
+		conn.ReadJSON(&req) // wait for the client to go away
+	}))
+	defer srv.Close()
+
+	client, err := DialWebsocket(context.Background(), "ws:"+strings.TrimPrefix(srv.URL, "http:"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", ch, "someSubscription")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-ch:
+		if v != 7 {
+			t.Fatalf("wrong notification value %d", v)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for notification")
+	}
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Fatalf("wrong dropped count: have %d, want 3", dropped)
+	}
+}
@@ -0,0 +1,12 @@
+// This test checks that a subscription terminated by the server delivers the
+// pending notifications, followed by an error notification. The subscription
+// is removed, so unsubscribing fails afterwards.
+
+--> {"jsonrpc":"2.0","id":1,"method":"nftest_subscribe","params":["failingSubscription",2,1]}
+<-- {"jsonrpc":"2.0","id":1,"result":"0x1"}
+<-- {"jsonrpc":"2.0","method":"nftest_subscription","params":{"subscription":"0x1","result":1}}
+<-- {"jsonrpc":"2.0","method":"nftest_subscription","params":{"subscription":"0x1","result":2}}
+<-- {"jsonrpc":"2.0","method":"nftest_subscriptionError","params":{"subscription":"0x1","error":{"code":-32602,"message":"subscription failed"}}}
+
+--> {"jsonrpc":"2.0","id":2,"method":"nftest_unsubscribe","params":["0x1"]}
+<-- {"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"subscription not found"}}
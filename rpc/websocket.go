@@ -49,10 +49,11 @@ var wsBufferPool = new(sync.Pool)
 // To allow connections with any origin, pass "*".
 func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 	var upgrader = websocket.Upgrader{
-		ReadBufferSize:  wsReadBuffer,
-		WriteBufferSize: wsWriteBuffer,
-		WriteBufferPool: wsBufferPool,
-		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
+		ReadBufferSize:    wsReadBuffer,
+		WriteBufferSize:   wsWriteBuffer,
+		WriteBufferPool:   wsBufferPool,
+		CheckOrigin:       wsHandshakeValidator(allowedOrigins),
+		EnableCompression: s.wsCompression,
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -61,6 +62,10 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, s.wsReadLimit)
+		if wc, ok := codec.(*websocketCodec); ok {
+			wc.info.APIKey = apiKeyFromRequest(r)
+			wc.info.JWTClaims = jwtClaimsFromContext(r.Context())
+		}
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -231,6 +236,11 @@ func newClientTransportWS(endpoint string, cfg *clientConfig) (reconnectFunc, er
 			Proxy:           http.ProxyFromEnvironment,
 		}
 	}
+	if cfg.wsCompression && !dialer.EnableCompression {
+		d := *dialer
+		d.EnableCompression = true
+		dialer = &d
+	}
 
 	dialURL, header, err := wsClientHeaders(endpoint, "")
 	if err != nil {
@@ -204,6 +204,56 @@ func TestWebsocketPeerInfo(t *testing.T) {
 	}
 }
 
+// This test checks that permessage-deflate is negotiated when enabled on both ends.
+func TestWebsocketCompression(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer()
+	srv.SetWebsocketCompression(true)
+	defer srv.Stop()
+
+	var (
+		offered = make(chan string, 10)
+		handler = srv.WebsocketHandler([]string{"*"})
+		ts      = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offered <- r.Header.Get("Sec-Websocket-Extensions")
+			handler.ServeHTTP(w, r)
+		}))
+		wsURL = "ws:" + strings.TrimPrefix(ts.URL, "http:")
+	)
+	defer ts.Close()
+
+	// The server should accept the extension offered by the client.
+	conn, resp, err := (&websocket.Dialer{EnableCompression: true}).Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	<-offered
+	if ext := resp.Header.Get("Sec-Websocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+		t.Fatalf("compression not accepted by server, extensions %q", ext)
+	}
+
+	// The client should offer it when configured to, and work over the compressed
+	// connection.
+	client, err := DialOptions(context.Background(), wsURL, WithWebsocketCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if ext := <-offered; !strings.Contains(ext, "permessage-deflate") {
+		t.Fatalf("compression not offered by client, extensions %q", ext)
+	}
+	var result echoResult
+	arg := strings.Repeat("x", 64*1024)
+	if err := client.Call(&result, "test_echo", arg, 1); err != nil {
+		t.Fatal(err)
+	}
+	if result.String != arg {
+		t.Fatal("wrong echo result")
+	}
+}
+
 // This test checks that client handles WebSocket ping frames correctly.
 func TestClientWebsocketPing(t *testing.T) {
 	t.Parallel()
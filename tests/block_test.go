@@ -85,6 +85,9 @@ func TestExecutionSpecBlocktests(t *testing.T) {
 	bt.skipLoad(".*prague/eip7251_consolidations/test_system_contract_deployment.json")
 	bt.skipLoad(".*prague/eip7002_el_triggerable_withdrawals/test_system_contract_deployment.json")
 
+	// The EOF fixtures are run by TestExecutionSpecEOFBlocktests
+	bt.skipLoad("^" + executionSpecEOFFixtures + "/")
+
 	bt.walk(t, executionSpecBlockchainTestDir, func(t *testing.T, name string, test *BlockTest) {
 		execBlockTest(t, bt, test)
 	})
@@ -99,16 +102,20 @@ func execBlockTest(t *testing.T, bt *testMatcher, test *BlockTest) {
 	var (
 		snapshotConf = []bool{false, true}
 		dbschemeConf = []string{rawdb.HashScheme, rawdb.PathScheme}
+		analysisConf = []bool{false, true}
 	)
 	if testing.Short() {
 		snapshotConf = []bool{snapshotConf[rand.Int()%2]}
 		dbschemeConf = []string{dbschemeConf[rand.Int()%2]}
+		analysisConf = []bool{analysisConf[rand.Int()%2]}
 	}
 	for _, snapshot := range snapshotConf {
 		for _, dbscheme := range dbschemeConf {
-			if err := bt.checkFailure(t, test.Run(snapshot, dbscheme, true, nil, nil)); err != nil {
-				t.Errorf("test with config {snapshotter:%v, scheme:%v} failed: %v", snapshot, dbscheme, err)
-				return
+			for _, analysis := range analysisConf {
+				if err := bt.checkFailure(t, test.Run(snapshot, dbscheme, true, analysis, nil, nil)); err != nil {
+					t.Errorf("test with config {snapshotter:%v, scheme:%v, blockanalysis:%v} failed: %v", snapshot, dbscheme, analysis, err)
+					return
+				}
 			}
 		}
 	}
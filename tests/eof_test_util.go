@@ -0,0 +1,89 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// EOFTest checks the parsing and validation of EOF containers.
+type EOFTest struct {
+	Vectors map[string]*eofVector `json:"vectors"`
+}
+
+type eofVector struct {
+	Code          hexutil.Bytes         `json:"code"`
+	ContainerKind string                `json:"containerKind"`
+	Results       map[string]*eofResult `json:"results"`
+}
+
+type eofResult struct {
+	Result    bool   `json:"result"`
+	Exception string `json:"exception"`
+}
+
+// Run validates all vectors of the test for the forks with EOF enabled.
+func (t *EOFTest) Run() error {
+	names := make([]string, 0, len(t.Vectors))
+	for name := range t.Vectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		vector := t.Vectors[name]
+		for fork, expected := range vector.Results {
+			config, ok := Forks[fork]
+			if !ok || config.EOFTime == nil {
+				return UnsupportedForkError{fork}
+			}
+			_, err := vm.ValidateEOF(vector.Code, vector.ContainerKind == "INITCODE")
+			if expected.Result && err != nil {
+				return fmt.Errorf("vector %s, fork %s: unexpected error: %v", name, fork, err)
+			}
+			if !expected.Result && err == nil {
+				return fmt.Errorf("vector %s, fork %s: expected error %s, got none", name, fork, expected.Exception)
+			}
+			if !expected.Result && !matchEOFException(expected.Exception, err) {
+				return fmt.Errorf("vector %s, fork %s: error mismatch: have %v %v, want %s", name, fork, err, vm.EOFExceptions(err), expected.Exception)
+			}
+		}
+	}
+	return nil
+}
+
+// matchEOFException reports whether the validation error corresponds to one of
+// the alternatives of the expected exception, formatted like
+// "EOFException.A|EOFException.B".
+func matchEOFException(expected string, err error) bool {
+	if expected == "" {
+		return true
+	}
+	names := vm.EOFExceptions(err)
+	for _, exception := range strings.Split(expected, "|") {
+		if slices.Contains(names, strings.TrimPrefix(exception, "EOFException.")) {
+			return true
+		}
+	}
+	return false
+}
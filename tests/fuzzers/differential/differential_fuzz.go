@@ -0,0 +1,388 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package differential executes randomly generated transactions under two EVM
+// configurations and reports any divergence in the outcome. It guards changes
+// to the interpreter, which must not affect consensus, e.g. the tracing hooks
+// or the block analysis, against the plain interpreter. Further variants, like
+// custom EIP sets via ExtraEips, can be compared with Check.
+//
+//	go test -run - -fuzz FuzzBlockAnalysis ./tests/fuzzers/differential
+package differential
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+	"github.com/holiman/uint256"
+)
+
+const (
+	maxInputSize = 16 * 1024 // Larger inputs don't add much value
+	maxContracts = 4         // Number of contracts in the prestate
+	maxSnippets  = 24        // Maximum number of generated snippets per contract
+	maxTxs       = 4         // Maximum number of transactions per test case
+	minTxGas     = 100_000   // Minimum gas limit of a transaction, covering any intrinsic gas
+	maxTxGas     = 1_000_000 // Maximum gas limit of a transaction
+	defaultFork  = "Cancun"  // Fork used by variants not configuring one
+)
+
+var (
+	sender   = common.HexToAddress("0x5e4de5")
+	coinbase = common.HexToAddress("0xc014ba5e")
+
+	blockNumber = big.NewInt(1)
+	blockTime   = uint64(1)
+	baseFee     = big.NewInt(7)
+	gasPrice    = big.NewInt(10)
+)
+
+// Variant is an EVM configuration taking part in a differential run.
+type Variant struct {
+	Name   string
+	Fork   string    // Name of the fork in tests.Forks, Cancun if empty
+	Config vm.Config // Interpreter configuration, including ExtraEips
+}
+
+// testCase is a prestate along with the transactions executed on it.
+type testCase struct {
+	alloc types.GenesisAlloc
+	msgs  []*core.Message
+}
+
+// txResult is the outcome of a single transaction.
+type txResult struct {
+	Err     string // Error invalidating the transaction
+	VMErr   string // Error of the execution
+	GasUsed uint64
+	Return  []byte
+	Logs    []*types.Log
+}
+
+// execResult is the outcome of a test case.
+type execResult struct {
+	Root common.Hash
+	Txs  []txResult
+}
+
+// Check generates a test case from the given input, executes it under both
+// variants and returns an error describing the first difference found.
+func Check(input []byte, a, b Variant) error {
+	if len(input) > maxInputSize {
+		return nil
+	}
+	tc := generate(&generator{r: bytes.NewReader(input)})
+
+	resA, err := execute(tc, a)
+	if err != nil {
+		return fmt.Errorf("variant %s: %v", a.Name, err)
+	}
+	resB, err := execute(tc, b)
+	if err != nil {
+		return fmt.Errorf("variant %s: %v", b.Name, err)
+	}
+	return compare(resA, resB, a.Name, b.Name)
+}
+
+// compare returns an error describing the first difference between the two
+// execution results.
+func compare(a, b *execResult, nameA, nameB string) error {
+	for i := range a.Txs {
+		txA, txB := a.Txs[i], b.Txs[i]
+		switch {
+		case txA.Err != txB.Err:
+			return fmt.Errorf("tx %d: error mismatch, %s: %q, %s: %q", i, nameA, txA.Err, nameB, txB.Err)
+		case txA.VMErr != txB.VMErr:
+			return fmt.Errorf("tx %d: execution error mismatch, %s: %q, %s: %q", i, nameA, txA.VMErr, nameB, txB.VMErr)
+		case txA.GasUsed != txB.GasUsed:
+			return fmt.Errorf("tx %d: gas mismatch, %s: %d, %s: %d", i, nameA, txA.GasUsed, nameB, txB.GasUsed)
+		case !bytes.Equal(txA.Return, txB.Return):
+			return fmt.Errorf("tx %d: return data mismatch, %s: %x, %s: %x", i, nameA, txA.Return, nameB, txB.Return)
+		case !reflect.DeepEqual(txA.Logs, txB.Logs):
+			return fmt.Errorf("tx %d: logs mismatch, %s: %d logs, %s: %d logs", i, nameA, len(txA.Logs), nameB, len(txB.Logs))
+		}
+	}
+	if a.Root != b.Root {
+		return fmt.Errorf("state root mismatch, %s: %x, %s: %x", nameA, a.Root, nameB, b.Root)
+	}
+	return nil
+}
+
+// execute runs the transactions of the test case on top of its prestate.
+func execute(tc *testCase, variant Variant) (*execResult, error) {
+	fork := variant.Fork
+	if fork == "" {
+		fork = defaultFork
+	}
+	config, ok := tests.Forks[fork]
+	if !ok {
+		return nil, tests.UnsupportedForkError{Name: fork}
+	}
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	if err != nil {
+		return nil, err
+	}
+	for addr, acc := range tc.alloc {
+		statedb.SetBalance(addr, uint256.MustFromBig(acc.Balance), tracing.BalanceChangeUnspecified)
+		statedb.SetNonce(addr, acc.Nonce, tracing.NonceChangeUnspecified)
+		statedb.SetCode(addr, acc.Code, tracing.CodeChangeUnspecified)
+		for key, value := range acc.Storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+	statedb.Finalise(true)
+
+	var (
+		random   = common.Hash{0x01}
+		blockCtx = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetHash: func(n uint64) common.Hash {
+				return crypto.Keccak256Hash(new(big.Int).SetUint64(n).Bytes())
+			},
+			Coinbase:    coinbase,
+			GasLimit:    30_000_000,
+			BlockNumber: blockNumber,
+			Time:        blockTime,
+			Difficulty:  new(big.Int),
+			BaseFee:     baseFee,
+			BlobBaseFee: big.NewInt(1),
+			Random:      &random,
+		}
+		gp  = new(core.GasPool).AddGas(blockCtx.GasLimit)
+		evm = vm.NewEVM(blockCtx, statedb, config, variant.Config)
+		res = new(execResult)
+	)
+	for i, msg := range tc.msgs {
+		// The transaction is only needed for its hash, and for the tracer.
+		tx := types.NewTx(&types.LegacyTx{Nonce: msg.Nonce, GasPrice: msg.GasPrice, Gas: msg.GasLimit, To: msg.To, Value: msg.Value, Data: msg.Data})
+		statedb.SetTxContext(tx.Hash(), i)
+
+		hooks := variant.Config.Tracer
+		if hooks != nil && hooks.OnTxStart != nil {
+			hooks.OnTxStart(evm.GetVMContext(), tx, msg.From)
+		}
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if hooks != nil && hooks.OnTxEnd != nil {
+			var receipt *types.Receipt
+			if err == nil {
+				receipt = &types.Receipt{GasUsed: result.UsedGas}
+			}
+			hooks.OnTxEnd(receipt, err)
+		}
+		if err != nil {
+			res.Txs = append(res.Txs, txResult{Err: err.Error()})
+			continue
+		}
+		statedb.Finalise(true)
+
+		txRes := txResult{
+			GasUsed: result.UsedGas,
+			Return:  result.ReturnData,
+			Logs:    statedb.GetLogs(tx.Hash(), blockNumber.Uint64(), common.Hash{}, blockTime),
+		}
+		if result.Err != nil {
+			txRes.VMErr = result.Err.Error()
+		}
+		res.Txs = append(res.Txs, txRes)
+	}
+	res.Root = statedb.IntermediateRoot(true)
+	return res, nil
+}
+
+// generator derives the test case from the fuzzer input. Once the input is
+// exhausted, it keeps returning zeroes.
+type generator struct {
+	r *bytes.Reader
+}
+
+func (g *generator) byte() byte {
+	b, _ := g.r.ReadByte()
+	return b
+}
+
+func (g *generator) intn(n int) int {
+	return int(g.byte()) % n
+}
+
+func (g *generator) bytes(n int) []byte {
+	b := make([]byte, n)
+	g.r.Read(b)
+	return b
+}
+
+// word returns a value of up to 32 bytes, preferring small ones.
+func (g *generator) word() []byte {
+	switch g.intn(4) {
+	case 0:
+		return g.bytes(32)
+	case 1:
+		return g.bytes(1 + g.intn(8))
+	default:
+		return []byte{g.byte()}
+	}
+}
+
+// slot returns one of a few storage slots, so that writes and reads collide.
+func (g *generator) slot() int {
+	return g.intn(8)
+}
+
+func contractAddress(i int) common.Address {
+	return common.BytesToAddress([]byte{0x10, byte(i)})
+}
+
+// generate creates a prestate of a few contracts with random code and
+// transactions calling into them.
+func generate(g *generator) *testCase {
+	tc := &testCase{
+		alloc: types.GenesisAlloc{
+			sender: {Balance: new(big.Int).Mul(big.NewInt(params.Ether), big.NewInt(1000))},
+		},
+	}
+	for i := 0; i < maxContracts; i++ {
+		acc := types.Account{
+			Code:    genCode(g),
+			Balance: new(big.Int).SetBytes(g.bytes(2)),
+			Nonce:   1,
+			Storage: make(map[common.Hash]common.Hash),
+		}
+		for n := g.intn(4); n > 0; n-- {
+			acc.Storage[common.BigToHash(big.NewInt(int64(g.slot())))] = common.BytesToHash(g.word())
+		}
+		tc.alloc[contractAddress(i)] = acc
+	}
+	for i := 0; i < 1+g.intn(maxTxs); i++ {
+		msg := &core.Message{
+			From:      sender,
+			Nonce:     uint64(i),
+			Value:     new(big.Int).SetBytes(g.bytes(1)),
+			GasLimit:  minTxGas + uint64(g.intn(256))*((maxTxGas-minTxGas)/256),
+			GasPrice:  gasPrice,
+			GasFeeCap: gasPrice,
+			GasTipCap: common.Big1,
+			Data:      g.bytes(g.intn(64)),
+		}
+		if g.intn(8) == 0 {
+			// Contract creation, with generated initcode.
+			msg.Data = genCode(g)
+		} else {
+			to := contractAddress(g.intn(maxContracts))
+			msg.To = &to
+		}
+		tc.msgs = append(tc.msgs, msg)
+	}
+	return tc
+}
+
+var (
+	arithOps = []vm.OpCode{
+		vm.ADD, vm.MUL, vm.SUB, vm.DIV, vm.SDIV, vm.MOD, vm.SMOD, vm.EXP, vm.SIGNEXTEND,
+		vm.LT, vm.GT, vm.SLT, vm.SGT, vm.EQ, vm.AND, vm.OR, vm.XOR, vm.BYTE, vm.SHL, vm.SHR, vm.SAR,
+	}
+	envOps = []vm.OpCode{
+		vm.ADDRESS, vm.ORIGIN, vm.CALLER, vm.CALLVALUE, vm.CALLDATASIZE, vm.CODESIZE, vm.GASPRICE,
+		vm.RETURNDATASIZE, vm.COINBASE, vm.TIMESTAMP, vm.NUMBER, vm.PREVRANDAO, vm.GASLIMIT,
+		vm.CHAINID, vm.SELFBALANCE, vm.BASEFEE, vm.BLOBBASEFEE, vm.MSIZE, vm.GAS, vm.PC,
+	}
+	accountOps = []vm.OpCode{vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODEHASH}
+)
+
+// genCode generates a program out of snippets exercising the storage, memory,
+// logs, calls and control flow, and persisting their results in storage.
+func genCode(g *generator) []byte {
+	p := program.New()
+	for n := g.intn(maxSnippets); n > 0; n-- {
+		switch g.intn(12) {
+		case 0:
+			p.Sstore(g.slot(), g.word())
+		case 1:
+			p.Push(g.slot()).Op(vm.SLOAD).Push(g.slot()).Op(vm.SSTORE)
+		case 2:
+			p.Tstore(g.slot(), g.word())
+			p.Push(g.slot()).Op(vm.TLOAD).Push(g.slot()).Op(vm.SSTORE)
+		case 3:
+			p.Push(g.word()).Push(g.word()).Op(arithOps[g.intn(len(arithOps))])
+			p.Push(g.slot()).Op(vm.SSTORE)
+		case 4:
+			p.Op(envOps[g.intn(len(envOps))]).Push(g.slot()).Op(vm.SSTORE)
+		case 5:
+			p.Push(contractAddress(g.intn(maxContracts + 1))).Op(accountOps[g.intn(len(accountOps))])
+			p.Push(g.slot()).Op(vm.SSTORE)
+		case 6:
+			p.Mstore(g.bytes(1+g.intn(64)), uint32(g.intn(64)))
+			topics := g.intn(5)
+			for i := 0; i < topics; i++ {
+				p.Push(g.word())
+			}
+			p.Push(g.intn(96)).Push(g.intn(64)).Op(vm.LOG0 + vm.OpCode(topics))
+		case 7:
+			p.Push(g.intn(96)).Push(g.intn(64)).Op(vm.KECCAK256).Push(g.slot()).Op(vm.SSTORE)
+		case 8:
+			var (
+				gas  = uint256.NewInt(uint64(g.intn(256)) * 1000)
+				addr = contractAddress(g.intn(maxContracts + 1))
+			)
+			switch g.intn(4) {
+			case 0:
+				p.Call(gas, addr, g.intn(4), 0, g.intn(64), 0, 32)
+			case 1:
+				p.CallCode(gas, addr, g.intn(4), 0, g.intn(64), 0, 32)
+			case 2:
+				p.DelegateCall(gas, addr, 0, g.intn(64), 0, 32)
+			case 3:
+				p.StaticCall(gas, addr, 0, g.intn(64), 0, 32)
+			}
+			p.Push(g.slot()).Op(vm.SSTORE)
+		case 9:
+			// Count down from a small number in a loop.
+			p.Push(1 + g.intn(16))
+			_, loop := p.Jumpdest()
+			p.Push(1).Op(vm.SWAP1, vm.SUB, vm.DUP1).Push(loop).Op(vm.JUMPI, vm.POP)
+		case 10:
+			initcode := program.New().ReturnData(g.bytes(g.intn(32))).Bytes()
+			p.Create2(initcode, g.intn(4)).Push(g.slot()).Op(vm.SSTORE)
+		case 11:
+			// Raw opcodes, which may be invalid or underflow the stack.
+			p.Op(vm.OpCode(g.byte()))
+		}
+	}
+	switch g.intn(5) {
+	case 0:
+		p.Op(vm.STOP)
+	case 1:
+		p.Return(0, 32)
+	case 2:
+		p.Push(32).Push(0).Op(vm.REVERT)
+	case 3:
+		p.Op(vm.INVALID)
+	case 4:
+		p.Selfdestruct(contractAddress(g.intn(maxContracts)))
+	}
+	return p.Bytes()
+}
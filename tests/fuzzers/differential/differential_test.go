@@ -0,0 +1,77 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package differential
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+)
+
+// variantPairs returns the variants which must behave exactly like the plain
+// interpreter. The tracers are created anew, as they accumulate state.
+func variantPairs() map[string][2]Variant {
+	plain := Variant{Name: "plain"}
+	return map[string][2]Variant{
+		"tracer": {plain, Variant{
+			Name:   "tracer",
+			Config: vm.Config{Tracer: logger.NewStructLogger(&logger.Config{EnableMemory: true, EnableReturnData: true}).Hooks()},
+		}},
+		"blockanalysis": {plain, Variant{
+			Name:   "blockanalysis",
+			Config: vm.Config{EnableBlockAnalysis: true},
+		}},
+		"blockanalysis-prague": {
+			Variant{Name: "plain", Fork: "Prague"},
+			Variant{Name: "blockanalysis", Fork: "Prague", Config: vm.Config{EnableBlockAnalysis: true}},
+		},
+	}
+}
+
+func fuzz(t *testing.T, pair string, data []byte) {
+	variants := variantPairs()[pair]
+	if err := Check(data, variants[0], variants[1]); err != nil {
+		t.Fatalf("%s: %v", pair, err)
+	}
+}
+
+func FuzzTracer(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzz(t, "tracer", data)
+	})
+}
+
+func FuzzBlockAnalysis(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzz(t, "blockanalysis", data)
+		fuzz(t, "blockanalysis-prague", data)
+	})
+}
+
+// TestDifferential runs all variant pairs on a fixed set of random inputs.
+func TestDifferential(t *testing.T) {
+	rand := rand.New(rand.NewSource(0x5eed))
+	for i := 0; i < 200; i++ {
+		data := make([]byte, rand.Intn(2048))
+		rand.Read(data)
+		for pair := range variantPairs() {
+			fuzz(t, pair, data)
+		}
+	}
+}
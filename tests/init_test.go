@@ -33,6 +33,10 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// executionSpecEOFFixtures is the directory of the EOF fixtures of the unscheduled
+// EOFv1 fork within the execution-spec-tests state and blockchain tests.
+const executionSpecEOFFixtures = "unscheduled/eip7692_eof_v1"
+
 var (
 	baseDir                         = filepath.Join(".", "testdata")
 	blockTestDir                    = filepath.Join(baseDir, "BlockchainTests")
@@ -44,6 +48,9 @@ var (
 	executionSpecBlockchainTestDir  = filepath.Join(".", "spec-tests", "fixtures", "blockchain_tests")
 	executionSpecStateTestDir       = filepath.Join(".", "spec-tests", "fixtures", "state_tests")
 	executionSpecTransactionTestDir = filepath.Join(".", "spec-tests", "fixtures", "transaction_tests")
+	executionSpecEOFTestDir         = filepath.Join(".", "spec-tests", "fixtures", "eof_tests")
+	executionSpecEOFStateTestDir    = filepath.Join(executionSpecStateTestDir, executionSpecEOFFixtures)
+	executionSpecEOFBlockTestDir    = filepath.Join(executionSpecBlockchainTestDir, executionSpecEOFFixtures)
 	benchmarksDir                   = filepath.Join(".", "evm-benchmarks", "benchmarks")
 )
 
@@ -92,6 +92,9 @@ func TestExecutionSpecState(t *testing.T) {
 	}
 	st := new(testMatcher)
 
+	// The EOF fixtures are run by TestExecutionSpecEOFState
+	st.skipLoad("^" + executionSpecEOFFixtures + "/")
+
 	st.walk(t, executionSpecStateTestDir, func(t *testing.T, name string, test *StateTest) {
 		execStateTest(t, st, test)
 	})
@@ -101,9 +104,9 @@ func execStateTest(t *testing.T, st *testMatcher, test *StateTest) {
 	for _, subtest := range test.Subtests() {
 		key := fmt.Sprintf("%s/%d", subtest.Fork, subtest.Index)
 
-		// If -short flag is used, we don't execute all four permutations, only
+		// If -short flag is used, we don't execute all five permutations, only
 		// one.
-		executionMask := 0xf
+		executionMask := 0x1f
 		if testing.Short() {
 			executionMask = (1 << (rand.Int63() & 4))
 		}
@@ -167,6 +170,19 @@ func execStateTest(t *testing.T, st *testMatcher, test *StateTest) {
 				return result
 			})
 		})
+		t.Run(key+"/hash/trie/blockanalysis", func(t *testing.T) {
+			if executionMask&0x10 == 0 {
+				t.Skip("test (randomly) skipped due to short-tag")
+			}
+			withTrace(t, test.gasLimit(subtest), func(vmconfig vm.Config) error {
+				var result error
+				vmconfig.EnableBlockAnalysis = true
+				test.Run(subtest, vmconfig, false, rawdb.HashScheme, func(err error, state *StateTestState) {
+					result = st.checkFailure(t, err)
+				})
+				return result
+			})
+		})
 	}
 }
 